@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"unsafe"
 
 	"go.opentelemetry.io/api/registry"
@@ -27,6 +29,10 @@ type Value struct {
 	String  string
 	Bytes   []byte
 
+	Strings  []string
+	Int64s   []int64
+	Float64s []float64
+
 	// TODO Lazy value type?
 }
 
@@ -41,6 +47,9 @@ const (
 	FLOAT64
 	STRING
 	BYTES
+	STRINGS
+	INT64S
+	FLOAT64S
 )
 
 func (k Key) Bool(v bool) KeyValue {
@@ -133,6 +142,40 @@ func (k Key) Bytes(v []byte) KeyValue {
 	}
 }
 
+// Strings returns a KeyValue holding a copy of v, so that later changes
+// to v do not affect the immutable maps of the tag package.
+func (k Key) Strings(v []string) KeyValue {
+	return KeyValue{
+		Key: k,
+		Value: Value{
+			Type:    STRINGS,
+			Strings: append([]string(nil), v...),
+		},
+	}
+}
+
+// Int64s returns a KeyValue holding a copy of v.
+func (k Key) Int64s(v []int64) KeyValue {
+	return KeyValue{
+		Key: k,
+		Value: Value{
+			Type:   INT64S,
+			Int64s: append([]int64(nil), v...),
+		},
+	}
+}
+
+// Float64s returns a KeyValue holding a copy of v.
+func (k Key) Float64s(v []float64) KeyValue {
+	return KeyValue{
+		Key: k,
+		Value: Value{
+			Type:     FLOAT64S,
+			Float64s: append([]float64(nil), v...),
+		},
+	}
+}
+
 func (k Key) Int(v int) KeyValue {
 	if unsafe.Sizeof(v) == 4 {
 		return k.Int32(int32(v))
@@ -166,6 +209,57 @@ func (v Value) Emit() string {
 		return v.String
 	case BYTES:
 		return string(v.Bytes)
+	case STRINGS:
+		elems := make([]string, len(v.Strings))
+		for i, s := range v.Strings {
+			elems[i] = strconv.Quote(s)
+		}
+		return emitSlice(elems)
+	case INT64S:
+		elems := make([]string, len(v.Int64s))
+		for i, n := range v.Int64s {
+			elems[i] = strconv.FormatInt(n, 10)
+		}
+		return emitSlice(elems)
+	case FLOAT64S:
+		elems := make([]string, len(v.Float64s))
+		for i, f := range v.Float64s {
+			elems[i] = fmt.Sprint(f)
+		}
+		return emitSlice(elems)
 	}
 	return "unknown"
 }
+
+// emitSlice formats the elements of a slice value as a bracketed,
+// comma separated list, e.g., ["a","b"] or [1,2].
+func emitSlice(elems []string) string {
+	return "[" + strings.Join(elems, ",") + "]"
+}
+
+// AsInterface returns the Go value held by v with its type preserved:
+// a bool, int64, uint64, float64, string, []byte, []string, []int64 or
+// []float64. It returns nil for an invalid value.
+func (v Value) AsInterface() interface{} {
+	switch v.Type {
+	case BOOL:
+		return v.Bool
+	case INT32, INT64:
+		return v.Int64
+	case UINT32, UINT64:
+		return v.Uint64
+	case FLOAT32, FLOAT64:
+		return v.Float64
+	case STRING:
+		return v.String
+	case BYTES:
+		return v.Bytes
+	case STRINGS:
+		return v.Strings
+	case INT64S:
+		return v.Int64s
+	case FLOAT64S:
+		return v.Float64s
+	}
+	return nil
+}
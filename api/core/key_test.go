@@ -233,6 +233,85 @@ func TestBytes(t *testing.T) {
 	}
 }
 
+func TestStrings(t *testing.T) {
+	for _, testcase := range []struct {
+		name string
+		v    []string
+		want Value
+	}{
+		{
+			name: `value: []string{"foo", "bar"}`,
+			v:    []string{"foo", "bar"},
+			want: Value{
+				Type:    STRINGS,
+				Strings: []string{"foo", "bar"},
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			//proto: func (k Key) Strings(v []string) KeyValue {
+			have := Key{}.Strings(testcase.v)
+			if diff := cmp.Diff(testcase.want, have.Value); diff != "" {
+				t.Fatal(diff)
+			}
+			testcase.v[0] = "changed"
+			if diff := cmp.Diff(testcase.want, have.Value); diff != "" {
+				t.Fatalf("value changed with its argument: %s", diff)
+			}
+		})
+	}
+}
+
+func TestInt64s(t *testing.T) {
+	for _, testcase := range []struct {
+		name string
+		v    []int64
+		want Value
+	}{
+		{
+			name: "value: []int64{42, -1}",
+			v:    []int64{42, -1},
+			want: Value{
+				Type:   INT64S,
+				Int64s: []int64{42, -1},
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			//proto: func (k Key) Int64s(v []int64) KeyValue {
+			have := Key{}.Int64s(testcase.v)
+			if diff := cmp.Diff(testcase.want, have.Value); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestFloat64s(t *testing.T) {
+	for _, testcase := range []struct {
+		name string
+		v    []float64
+		want Value
+	}{
+		{
+			name: "value: []float64{42.1, 0.5}",
+			v:    []float64{42.1, 0.5},
+			want: Value{
+				Type:     FLOAT64S,
+				Float64s: []float64{42.1, 0.5},
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			//proto: func (k Key) Float64s(v []float64) KeyValue {
+			have := Key{}.Float64s(testcase.v)
+			if diff := cmp.Diff(testcase.want, have.Value); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
 func TestInt(t *testing.T) {
 	WTYPE := INT64
 	if unsafe.Sizeof(int(42)) == 4 {
@@ -404,6 +483,30 @@ func TestEmit(t *testing.T) {
 			},
 			want: "foo",
 		},
+		{
+			name: `strings`,
+			v: Value{
+				Type:    STRINGS,
+				Strings: []string{"foo", `"bar"`},
+			},
+			want: `["foo","\"bar\""]`,
+		},
+		{
+			name: `int64s`,
+			v: Value{
+				Type:   INT64S,
+				Int64s: []int64{42, -1},
+			},
+			want: "[42,-1]",
+		},
+		{
+			name: `float64s`,
+			v: Value{
+				Type:     FLOAT64S,
+				Float64s: []float64{42.1},
+			},
+			want: "[42.1]",
+		},
 	} {
 		t.Run(testcase.name, func(t *testing.T) {
 			//proto: func (v Value) Emit() string {
@@ -414,3 +517,29 @@ func TestEmit(t *testing.T) {
 		})
 	}
 }
+
+func TestAsInterface(t *testing.T) {
+	for _, testcase := range []struct {
+		name string
+		v    Value
+		want interface{}
+	}{
+		{name: `bool`, v: Key{}.Bool(true).Value, want: true},
+		{name: `int32`, v: Key{}.Int32(42).Value, want: int64(42)},
+		{name: `uint64`, v: Key{}.Uint64(42).Value, want: uint64(42)},
+		{name: `float32`, v: Key{}.Float32(0.5).Value, want: float64(0.5)},
+		{name: `string`, v: Key{}.String("foo").Value, want: "foo"},
+		{name: `strings`, v: Key{}.Strings([]string{"foo"}).Value, want: []string{"foo"}},
+		{name: `int64s`, v: Key{}.Int64s([]int64{42}).Value, want: []int64{42}},
+		{name: `float64s`, v: Key{}.Float64s([]float64{0.5}).Value, want: []float64{0.5}},
+		{name: `invalid`, v: Value{}, want: nil},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			//proto: func (v Value) AsInterface() interface{} {
+			have := testcase.v.AsInterface()
+			if diff := cmp.Diff(testcase.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
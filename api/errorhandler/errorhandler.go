@@ -0,0 +1,44 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errorhandler holds the handler of errors that the API and the
+// SDK cannot return to their callers, such as conflicting instrument
+// registrations or spans dropped by an exporter.
+package errorhandler // import "go.opentelemetry.io/api/errorhandler"
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/api/logger"
+)
+
+// Handler handles an error reported by an OpenTelemetry package.
+type Handler func(error)
+
+var handler atomic.Value // access atomically
+
+// Set replaces the handler of errors. The default handler, restored by
+// Set(nil), logs errors at the logger.Error level.
+func Set(h Handler) {
+	handler.Store(h)
+}
+
+// Handle passes err to the handler.
+func Handle(err error) {
+	if h, ok := handler.Load().(Handler); ok && h != nil {
+		h(err)
+		return
+	}
+	logger.Log(logger.Error, err.Error())
+}
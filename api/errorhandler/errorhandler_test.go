@@ -0,0 +1,32 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorhandler
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	var got error
+	Set(func(err error) { got = err })
+	defer Set(nil)
+
+	want := errors.New("dropped span")
+	Handle(want)
+	if got != want {
+		t.Errorf("handler got %v, want %v", got, want)
+	}
+}
@@ -15,6 +15,8 @@
 package event
 
 import (
+	"time"
+
 	"go.opentelemetry.io/api/core"
 )
 
@@ -27,3 +29,36 @@ type Event interface {
 	// Attributes interface returns a copy of attributes associated with the Event.
 	Attributes() []core.KeyValue
 }
+
+// Timed is implemented by events that tell when they happened, e.g.,
+// events recorded after the fact. Spans record other events at the time
+// they are added.
+type Timed interface {
+	Event
+
+	// Time returns the time the event happened.
+	Time() time.Time
+}
+
+type timedEvent struct {
+	t     time.Time
+	msg   string
+	attrs []core.KeyValue
+}
+
+// NewTimed returns an event that happened at t.
+func NewTimed(t time.Time, msg string, attrs ...core.KeyValue) Timed {
+	return &timedEvent{t: t, msg: msg, attrs: attrs}
+}
+
+func (e *timedEvent) Message() string {
+	return e.msg
+}
+
+func (e *timedEvent) Attributes() []core.KeyValue {
+	return append([]core.KeyValue(nil), e.attrs...)
+}
+
+func (e *timedEvent) Time() time.Time {
+	return e.t
+}
@@ -0,0 +1,79 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+// Severity is the importance of an event. It is recorded as the
+// SeverityKey attribute, e.g.,
+//
+//	span.Event(ctx, "retrying", event.Warn.Attribute())
+type Severity int
+
+const (
+	Debug Severity = iota - 1
+	// Info is the severity of events without a SeverityKey attribute.
+	Info
+	Warn
+	Error
+)
+
+// SeverityKey is the conventional attribute holding the severity of an
+// event.
+var SeverityKey = key.New("event.severity")
+
+var severityNames = map[Severity]string{
+	Debug: "debug",
+	Info:  "info",
+	Warn:  "warn",
+	Error: "error",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Attribute returns the SeverityKey attribute recording s.
+func (s Severity) Attribute() core.KeyValue {
+	return SeverityKey.String(s.String())
+}
+
+// ParseSeverity returns the severity named name, as returned by String.
+func ParseSeverity(name string) (Severity, bool) {
+	for s, n := range severityNames {
+		if n == name {
+			return s, true
+		}
+	}
+	return Info, false
+}
+
+// SeverityOf returns the severity recorded in attrs, or Info if they have
+// no valid SeverityKey attribute.
+func SeverityOf(attrs []core.KeyValue) Severity {
+	for _, kv := range attrs {
+		if kv.Key.Variable.Name == SeverityKey.Variable.Name {
+			s, _ := ParseSeverity(kv.Value.Emit())
+			return s
+		}
+	}
+	return Info
+}
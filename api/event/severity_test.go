@@ -0,0 +1,50 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+func TestSeverityOf(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		attrs []core.KeyValue
+		want  Severity
+	}{
+		{"none", nil, Info},
+		{"warn", []core.KeyValue{key.New("a").Int(1), Warn.Attribute()}, Warn},
+		{"debug", []core.KeyValue{Debug.Attribute()}, Debug},
+		{"invalid", []core.KeyValue{SeverityKey.String("fatal")}, Info},
+	} {
+		if got := SeverityOf(tt.attrs); got != tt.want {
+			t.Errorf("%s: SeverityOf() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []Severity{Debug, Info, Warn, Error} {
+		if got, ok := ParseSeverity(s.String()); !ok || got != s {
+			t.Errorf("ParseSeverity(%q) = %v, %v; want %v", s.String(), got, ok, s)
+		}
+	}
+	if _, ok := ParseSeverity("fatal"); ok {
+		t.Error("ParseSeverity(\"fatal\") succeeded")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logger holds the logger of the diagnostic messages of the API
+// and the SDK, such as export retries and dropped data, and the level
+// below which messages are discarded. Both can be changed at any time.
+//
+// Errors that cannot be returned to callers go to the errorhandler
+// package, whose default handler logs them here at the Error level.
+package logger // import "go.opentelemetry.io/api/logger"
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// Level is the severity of a message.
+type Level int32
+
+const (
+	// Debug is for messages about routine events, such as single
+	// dropped items.
+	Debug Level = iota
+	// Info is for messages that are useful in normal operation.
+	Info
+	// Warn is for problems the SDK recovers from, such as retried
+	// exports.
+	Warn
+	// Error is for data that was lost.
+	Error
+)
+
+var levelNames = map[Level]string{
+	Debug: "debug",
+	Info:  "info",
+	Warn:  "warn",
+	Error: "error",
+}
+
+func (l Level) String() string {
+	if s, ok := levelNames[l]; ok {
+		return s
+	}
+	return fmt.Sprintf("Level(%d)", int32(l))
+}
+
+// Logger receives the messages that are not below the configured level.
+// It must be safe for concurrent use.
+type Logger interface {
+	Log(level Level, msg string)
+}
+
+// Func is an adapter to use a function as a Logger.
+type Func func(level Level, msg string)
+
+// Log calls f(level, msg).
+func (f Func) Log(level Level, msg string) {
+	f(level, msg)
+}
+
+// holder keeps the concrete type stored in current constant.
+type holder struct {
+	l Logger
+}
+
+var (
+	current  atomic.Value // holder
+	minLevel = int32(Info)
+)
+
+// Set replaces the logger. The default logger, restored by Set(nil),
+// uses the standard logger.
+func Set(l Logger) {
+	current.Store(holder{l})
+}
+
+// SetLevel discards the messages below level from now on. The default
+// level is Info.
+func SetLevel(level Level) {
+	atomic.StoreInt32(&minLevel, int32(level))
+}
+
+// Enabled reports whether messages at level are logged. Callers can use
+// it to skip building expensive messages.
+func Enabled(level Level) bool {
+	return int32(level) >= atomic.LoadInt32(&minLevel)
+}
+
+// Log passes msg to the logger if level is enabled.
+func Log(level Level, msg string) {
+	if !Enabled(level) {
+		return
+	}
+	if h, ok := current.Load().(holder); ok && h.l != nil {
+		h.l.Log(level, msg)
+		return
+	}
+	log.Print("opentelemetry: ", msg)
+}
+
+// Logf formats a message as fmt.Sprintf does and logs it if level is
+// enabled.
+func Logf(level Level, format string, args ...interface{}) {
+	if Enabled(level) {
+		Log(level, fmt.Sprintf(format, args...))
+	}
+}
+
+// Debugf logs a message at the Debug level.
+func Debugf(format string, args ...interface{}) {
+	Logf(Debug, format, args...)
+}
+
+// Infof logs a message at the Info level.
+func Infof(format string, args ...interface{}) {
+	Logf(Info, format, args...)
+}
+
+// Warnf logs a message at the Warn level.
+func Warnf(format string, args ...interface{}) {
+	Logf(Warn, format, args...)
+}
+
+// Errorf logs a message at the Error level.
+func Errorf(format string, args ...interface{}) {
+	Logf(Error, format, args...)
+}
@@ -0,0 +1,84 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+type entry struct {
+	level Level
+	msg   string
+}
+
+func TestLevels(t *testing.T) {
+	var got []entry
+	Set(Func(func(level Level, msg string) { got = append(got, entry{level, msg}) }))
+	defer Set(nil)
+	defer SetLevel(Info)
+
+	Debugf("dropped %d", 1)
+	Infof("retrying in %v", "1s")
+	Warnf("export failed")
+	Errorf("lost %d spans", 2)
+	want := []entry{{Info, "retrying in 1s"}, {Warn, "export failed"}, {Error, "lost 2 spans"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("message %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	got = nil
+	SetLevel(Error)
+	if Enabled(Warn) {
+		t.Error("Enabled(Warn) = true at level Error")
+	}
+	Warnf("export failed")
+	SetLevel(Debug)
+	Debugf("dropped %d", 1)
+	if len(got) != 1 || got[0] != (entry{Debug, "dropped 1"}) {
+		t.Errorf("got %v, want only the debug message", got)
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	defer func(flags int) { log.SetFlags(flags) }(log.Flags())
+	log.SetFlags(0)
+
+	Set(nil)
+	Errorf("lost %d spans", 2)
+	if got, want := buf.String(), "opentelemetry: lost 2 spans\n"; got != want {
+		t.Errorf("logged %q, want %q", got, want)
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	if got := Warn.String(); got != "warn" {
+		t.Errorf("Warn.String() = %q, want warn", got)
+	}
+	if got := Level(7).String(); !strings.HasPrefix(got, "Level(") {
+		t.Errorf("Level(7).String() = %q", got)
+	}
+}
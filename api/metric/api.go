@@ -27,18 +27,74 @@ type MetricType int
 const (
 	Invalid    MetricType = iota
 	Gauge                 // Supports Set()
-	Cumulative            // Supports Inc()
+	Cumulative            // Supports Add()
+	Measure               // Supports Record()
 )
 
+// Meter binds instruments to label sets and records measurements.
 type Meter interface {
-	// TODO more Metric types
+	// GetFloat64Gauge returns gauge bound to labels. The labels of the
+	// tag.Map of ctx are added to them.
 	GetFloat64Gauge(ctx context.Context, gauge *Float64GaugeHandle, labels ...core.KeyValue) Float64Gauge
+
+	// GetInt64Counter returns counter bound to labels.
+	GetInt64Counter(ctx context.Context, counter *Int64CounterHandle, labels ...core.KeyValue) Int64Counter
+
+	// GetFloat64Measure returns measure bound to labels.
+	GetFloat64Measure(ctx context.Context, measure *Float64MeasureHandle, labels ...core.KeyValue) Float64Measure
+
+	// RecordBatch records measurements of several instruments with the
+	// same labels at once.
+	RecordBatch(ctx context.Context, labels []core.KeyValue, measurements ...Measurement)
 }
 
+// Float64Gauge is a gauge bound to a label set. Set replaces its value.
 type Float64Gauge interface {
 	Set(ctx context.Context, value float64, labels ...core.KeyValue)
 }
 
+// Int64Counter is a counter bound to a label set. Add increments its
+// value by a non-negative amount.
+type Int64Counter interface {
+	Add(ctx context.Context, value int64, labels ...core.KeyValue)
+}
+
+// Float64Measure is a measure bound to a label set. Record adds a value to
+// the distribution of its values, e.g., of request latencies.
+type Float64Measure interface {
+	Record(ctx context.Context, value float64, labels ...core.KeyValue)
+}
+
+// ExemplarRecorder is implemented by measures that can keep the span
+// context of some of the recorded values as exemplars, linking the
+// distribution of the values to the traces that produced them.
+type ExemplarRecorder interface {
+	// RecordExemplar records value like Record, with sc as its exemplar.
+	RecordExemplar(ctx context.Context, value float64, sc core.SpanContext, labels ...core.KeyValue)
+}
+
+// RecordWithExemplar records value on measure with sc as its exemplar. It
+// records value without exemplar on measures that do not implement
+// ExemplarRecorder.
+func RecordWithExemplar(ctx context.Context, measure Float64Measure, value float64, sc core.SpanContext, labels ...core.KeyValue) {
+	if er, ok := measure.(ExemplarRecorder); ok {
+		er.RecordExemplar(ctx, value, sc, labels...)
+		return
+	}
+	measure.Record(ctx, value, labels...)
+}
+
+// Measurement is a value of an instrument recorded with RecordBatch.
+type Measurement struct {
+	Handle *Handle
+	Value  core.Value
+}
+
+// RecordBatch records measurements with labels using the global meter.
+func RecordBatch(ctx context.Context, labels []core.KeyValue, measurements ...Measurement) {
+	GlobalMeter().RecordBatch(ctx, labels, measurements...)
+}
+
 type Handle struct {
 	Variable registry.Variable
 
@@ -75,6 +131,8 @@ func (mtype MetricType) String() string {
 		return "gauge"
 	case Cumulative:
 		return "cumulative"
+	case Measure:
+		return "measure"
 	default:
 		return "unknown"
 	}
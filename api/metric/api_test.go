@@ -0,0 +1,63 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+type batchMeter struct {
+	noopMeter
+	labels       []core.KeyValue
+	measurements []Measurement
+}
+
+func (m *batchMeter) RecordBatch(ctx context.Context, labels []core.KeyValue, measurements ...Measurement) {
+	m.labels = labels
+	m.measurements = measurements
+}
+
+func TestRecordBatch(t *testing.T) {
+	m := &batchMeter{}
+	SetGlobalMeter(m)
+	defer SetGlobalMeter(nil)
+
+	requests := NewInt64Counter("test.batch.requests")
+	latency := NewFloat64Measure("test.batch.latency")
+	RecordBatch(context.Background(), []core.KeyValue{key.New("route").String("/")},
+		requests.M(1), latency.M(0.25))
+
+	if len(m.labels) != 1 || len(m.measurements) != 2 {
+		t.Fatalf("got labels %v and measurements %v", m.labels, m.measurements)
+	}
+	if got := m.measurements[0]; got.Handle != &requests.Handle || got.Value.Type != core.INT64 || got.Value.Int64 != 1 {
+		t.Errorf("got counter measurement %+v", got)
+	}
+	if got := m.measurements[1]; got.Handle != &latency.Handle || got.Value.Float64 != 0.25 {
+		t.Errorf("got measure measurement %+v", got)
+	}
+	if got := latency.Type; got != Measure {
+		t.Errorf("measure type = %v, want %v", got, Measure)
+	}
+
+	SetGlobalMeter(nil)
+	if _, ok := GlobalMeter().(noopMeter); !ok {
+		t.Errorf("GlobalMeter() = %T after SetGlobalMeter(nil), want the noop meter", GlobalMeter())
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"go.opentelemetry.io/api/core"
+)
+
+type Int64CounterHandle struct {
+	Handle
+}
+
+// NewInt64Counter returns a counter named name. Calls with the same name
+// and options return the same counter.
+func NewInt64Counter(name string, mos ...Option) *Int64CounterHandle {
+	c := &Int64CounterHandle{}
+	registerMetric(name, Cumulative, mos, &c.Handle)
+	return cachedInstrument(&c.Handle, c).(*Int64CounterHandle)
+}
+
+// M returns a measurement adding value to the counter, for RecordBatch.
+func (c *Int64CounterHandle) M(value int64) Measurement {
+	return Measurement{Handle: &c.Handle, Value: core.Value{Type: core.INT64, Int64: value}}
+}
@@ -14,12 +14,23 @@
 
 package metric
 
+import (
+	"go.opentelemetry.io/api/core"
+)
+
 type Float64GaugeHandle struct {
 	Handle
 }
 
+// NewFloat64Gauge returns a gauge named name. Calls with the same name and
+// options return the same gauge.
 func NewFloat64Gauge(name string, mos ...Option) *Float64GaugeHandle {
 	g := &Float64GaugeHandle{}
 	registerMetric(name, Gauge, mos, &g.Handle)
-	return g
+	return cachedInstrument(&g.Handle, g).(*Float64GaugeHandle)
+}
+
+// M returns a measurement setting the gauge to value, for RecordBatch.
+func (g *Float64GaugeHandle) M(value float64) Measurement {
+	return Measurement{Handle: &g.Handle, Value: core.Value{Type: core.FLOAT64, Float64: value}}
 }
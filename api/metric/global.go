@@ -16,18 +16,25 @@ package metric
 
 import "sync/atomic"
 
-var global atomic.Value
+// meterHolder keeps the concrete type stored in global constant, so that
+// meters of different types can be set.
+type meterHolder struct {
+	m Meter
+}
+
+var global atomic.Value // meterHolder
 
 // GlobalMeter return meter registered with global registry.
 // If no meter is registered then an instance of noop Meter is returned.
 func GlobalMeter() Meter {
-	if t := global.Load(); t != nil {
-		return t.(Meter)
+	if h, ok := global.Load().(meterHolder); ok && h.m != nil {
+		return h.m
 	}
 	return noopMeter{}
 }
 
-// SetGlobalMeter sets provided meter as a global meter.
+// SetGlobalMeter sets provided meter as a global meter. SetGlobalMeter(nil)
+// restores the noop Meter.
 func SetGlobalMeter(t Meter) {
-	global.Store(t)
+	global.Store(meterHolder{t})
 }
@@ -0,0 +1,87 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/errorhandler"
+)
+
+var (
+	instrumentsMu sync.Mutex
+	instruments   = make(map[string]interface{})
+)
+
+// cachedInstrument returns the instrument registered earlier under the
+// name of h if its descriptor is identical, so that libraries can create
+// instruments at their call sites. Otherwise inst is registered and
+// returned. An instrument that conflicts with an earlier one is reported
+// to the errorhandler package and returned without being registered.
+func cachedInstrument(h *Handle, inst interface{}) interface{} {
+	instrumentsMu.Lock()
+	defer instrumentsMu.Unlock()
+
+	name := h.Variable.Name
+	prev, ok := instruments[name]
+	if !ok {
+		instruments[name] = inst
+		return inst
+	}
+	if reflect.TypeOf(prev) == reflect.TypeOf(inst) && sameDescriptor(handleOf(prev), h) {
+		return prev
+	}
+	errorhandler.Handle(fmt.Errorf("metric: instrument %q is already registered with a different descriptor", name))
+	return inst
+}
+
+func handleOf(inst interface{}) *Handle {
+	switch i := inst.(type) {
+	case *Float64GaugeHandle:
+		return &i.Handle
+	case *Int64CounterHandle:
+		return &i.Handle
+	case *Float64MeasureHandle:
+		return &i.Handle
+	}
+	return nil
+}
+
+func sameDescriptor(a, b *Handle) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	if a.Type != b.Type ||
+		a.Variable.Description != b.Variable.Description ||
+		a.Variable.Unit != b.Variable.Unit {
+		return false
+	}
+	return sameKeys(a.Keys, b.Keys)
+}
+
+func sameKeys(a, b []core.Key) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Variable.Name != b[i].Variable.Name {
+			return false
+		}
+	}
+	return true
+}
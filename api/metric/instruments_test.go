@@ -0,0 +1,80 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/api/errorhandler"
+	"go.opentelemetry.io/api/key"
+)
+
+func TestNewFloat64GaugeCached(t *testing.T) {
+	var errs []error
+	errorhandler.Set(func(err error) { errs = append(errs, err) })
+	defer errorhandler.Set(nil)
+
+	a := NewFloat64Gauge("test.cached", WithDescription("queue depth"), WithKeys(key.New("queue")))
+	b := NewFloat64Gauge("test.cached", WithDescription("queue depth"), WithKeys(key.New("queue")))
+	if a != b {
+		t.Error("identical registrations returned different gauges")
+	}
+	if len(errs) != 0 {
+		t.Errorf("got errors %v for identical registrations", errs)
+	}
+}
+
+func TestNewFloat64GaugeConflict(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []Option
+	}{
+		{"description", []Option{WithDescription("other")}},
+		{"unit", []Option{WithDescription("depth"), WithUnit("ms")}},
+		{"keys", []Option{WithDescription("depth"), WithKeys(key.New("other"))}},
+	} {
+		var errs []error
+		errorhandler.Set(func(err error) { errs = append(errs, err) })
+
+		name := "test.conflict." + tt.name
+		first := NewFloat64Gauge(name, WithDescription("depth"))
+		second := NewFloat64Gauge(name, tt.opts...)
+		if first == second {
+			t.Errorf("%s: conflicting registration returned the cached gauge", tt.name)
+		}
+		if len(errs) != 1 {
+			t.Errorf("%s: got %d errors, want 1", tt.name, len(errs))
+		}
+		if again := NewFloat64Gauge(name, WithDescription("depth")); again != first {
+			t.Errorf("%s: conflicting registration replaced the cached gauge", tt.name)
+		}
+	}
+	errorhandler.Set(nil)
+}
+
+func TestInstrumentKindConflict(t *testing.T) {
+	var errs []error
+	errorhandler.Set(func(err error) { errs = append(errs, err) })
+	defer errorhandler.Set(nil)
+
+	counter := NewInt64Counter("test.kind", WithDescription("requests"))
+	if again := NewInt64Counter("test.kind", WithDescription("requests")); again != counter {
+		t.Error("identical registrations returned different counters")
+	}
+	NewFloat64Measure("test.kind", WithDescription("requests"))
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want 1 for a measure named like a counter", len(errs))
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"go.opentelemetry.io/api/core"
+)
+
+type Float64MeasureHandle struct {
+	Handle
+}
+
+// NewFloat64Measure returns a measure named name. Calls with the same name
+// and options return the same measure.
+func NewFloat64Measure(name string, mos ...Option) *Float64MeasureHandle {
+	m := &Float64MeasureHandle{}
+	registerMetric(name, Measure, mos, &m.Handle)
+	return cachedInstrument(&m.Handle, m).(*Float64MeasureHandle)
+}
+
+// M returns a measurement recording value, for RecordBatch.
+func (m *Float64MeasureHandle) M(value float64) Measurement {
+	return Measurement{Handle: &m.Handle, Value: core.Value{Type: core.FLOAT64, Float64: value}}
+}
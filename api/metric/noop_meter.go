@@ -13,10 +13,29 @@ type noopMetric struct{}
 var _ Meter = noopMeter{}
 
 var _ Float64Gauge = noopMetric{}
+var _ Int64Counter = noopMetric{}
+var _ Float64Measure = noopMetric{}
 
 func (noopMeter) GetFloat64Gauge(ctx context.Context, gauge *Float64GaugeHandle, labels ...core.KeyValue) Float64Gauge {
 	return noopMetric{}
 }
 
+func (noopMeter) GetInt64Counter(ctx context.Context, counter *Int64CounterHandle, labels ...core.KeyValue) Int64Counter {
+	return noopMetric{}
+}
+
+func (noopMeter) GetFloat64Measure(ctx context.Context, measure *Float64MeasureHandle, labels ...core.KeyValue) Float64Measure {
+	return noopMetric{}
+}
+
+func (noopMeter) RecordBatch(ctx context.Context, labels []core.KeyValue, measurements ...Measurement) {
+}
+
 func (noopMetric) Set(ctx context.Context, value float64, labels ...core.KeyValue) {
 }
+
+func (noopMetric) Add(ctx context.Context, value int64, labels ...core.KeyValue) {
+}
+
+func (noopMetric) Record(ctx context.Context, value float64, labels ...core.KeyValue) {
+}
@@ -0,0 +1,110 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import "sync/atomic"
+
+// Provider hands out meters named after the instrumentation library
+// using them, e.g., "go.opentelemetry.io/plugin/othttp", so that backends
+// can tell which library owns an instrument.
+type Provider interface {
+	// Meter returns the meter of the named library. An empty name is
+	// the meter of the application itself.
+	Meter(name string, opts ...MeterOption) Meter
+}
+
+// MeterConfig holds the options of a meter requested from a Provider.
+type MeterConfig struct {
+	// Version is the version of the instrumentation library.
+	Version string
+
+	// SchemaURL identifies the version of the semantic conventions
+	// the instruments of the library follow.
+	SchemaURL string
+}
+
+// MeterOption sets an option of a meter requested from a Provider.
+type MeterOption func(*MeterConfig)
+
+// WithInstrumentationVersion sets the version of the instrumentation
+// library.
+func WithInstrumentationVersion(version string) MeterOption {
+	return func(c *MeterConfig) {
+		c.Version = version
+	}
+}
+
+// WithSchemaURL sets the URL of the schema of the semantic conventions
+// the instrumentation library follows.
+func WithSchemaURL(url string) MeterOption {
+	return func(c *MeterConfig) {
+		c.SchemaURL = url
+	}
+}
+
+// NewMeterConfig applies opts to a zero MeterConfig.
+func NewMeterConfig(opts ...MeterOption) MeterConfig {
+	var c MeterConfig
+	for _, o := range opts {
+		o(&c)
+	}
+	return c
+}
+
+// NoopProvider hands out noop meters.
+type NoopProvider struct{}
+
+var _ Provider = NoopProvider{}
+
+// Meter returns a noop Meter.
+func (NoopProvider) Meter(name string, opts ...MeterOption) Meter {
+	return noopMeter{}
+}
+
+// globalProvider is the Provider used until one is set. Its meters are
+// the global meter.
+type globalProvider struct{}
+
+func (globalProvider) Meter(name string, opts ...MeterOption) Meter {
+	return GlobalMeter()
+}
+
+// providerHolder keeps the concrete type stored in provider constant.
+type providerHolder struct {
+	p Provider
+}
+
+var provider atomic.Value // providerHolder
+
+// GlobalProvider returns the Provider set with SetGlobalProvider. Until
+// one is set, all its meters are GlobalMeter.
+func GlobalProvider() Provider {
+	if h, ok := provider.Load().(providerHolder); ok && h.p != nil {
+		return h.p
+	}
+	return globalProvider{}
+}
+
+// SetGlobalProvider sets p as the global Provider, and its unnamed meter
+// as the global meter. SetGlobalProvider(nil) restores the default
+// Provider, and the noop meter.
+func SetGlobalProvider(p Provider) {
+	provider.Store(providerHolder{p})
+	if p == nil {
+		SetGlobalMeter(nil)
+		return
+	}
+	SetGlobalMeter(p.Meter(""))
+}
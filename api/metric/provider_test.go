@@ -0,0 +1,47 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"testing"
+)
+
+type namedMeter struct {
+	noopMeter
+	name string
+	cfg  MeterConfig
+}
+
+type namedProvider struct{}
+
+func (namedProvider) Meter(name string, opts ...MeterOption) Meter {
+	return namedMeter{name: name, cfg: NewMeterConfig(opts...)}
+}
+
+func TestGlobalProvider(t *testing.T) {
+	if _, ok := GlobalProvider().Meter("lib").(noopMeter); !ok {
+		t.Errorf("Meter of the default provider = %T; want the noop meter", GlobalProvider().Meter("lib"))
+	}
+
+	SetGlobalProvider(namedProvider{})
+	defer SetGlobalProvider(nil)
+	m, ok := GlobalProvider().Meter("lib", WithInstrumentationVersion("1.2")).(namedMeter)
+	if !ok || m.name != "lib" || m.cfg.Version != "1.2" {
+		t.Errorf("Meter(lib, 1.2) = %+v; want the meter of lib 1.2", m)
+	}
+	if g, ok := GlobalMeter().(namedMeter); !ok || g.name != "" {
+		t.Errorf("GlobalMeter() = %+v; want the unnamed meter of the provider", GlobalMeter())
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+// The B3 headers of Zipkin.
+const (
+	B3SingleHeader       = "b3"
+	B3TraceIDHeader      = "X-B3-TraceId"
+	B3SpanIDHeader       = "X-B3-SpanId"
+	B3ParentSpanIDHeader = "X-B3-ParentSpanId"
+	B3SampledHeader      = "X-B3-Sampled"
+	B3FlagsHeader        = "X-B3-Flags"
+)
+
+// B3 returns a propagator for the B3 headers of Zipkin.
+//
+// It extracts the span context from the single b3 header, or from the
+// X-B3-* headers when b3 is missing, and injects the X-B3-* headers. The
+// debug flag is extracted as sampled. B3 does not carry tags: the tags of
+// the context are returned unchanged.
+func B3() TextFormatPropagator {
+	return b3{}
+}
+
+type b3 struct{}
+
+var _ TextFormatPropagator = b3{}
+
+func (b3) Injector(carrier Carrier) apitrace.Injector {
+	return b3Injector{carrier}
+}
+
+func (b3) Fields() []string {
+	return []string{B3SingleHeader, B3TraceIDHeader, B3SpanIDHeader, B3ParentSpanIDHeader, B3SampledHeader, B3FlagsHeader}
+}
+
+type b3Injector struct {
+	carrier Carrier
+}
+
+func (i b3Injector) Inject(sc core.SpanContext, tags tag.Map) {
+	if !sc.IsValid() {
+		return
+	}
+	i.carrier.Set(B3TraceIDHeader, sc.TraceIDString())
+	i.carrier.Set(B3SpanIDHeader, sc.SpanIDString())
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	i.carrier.Set(B3SampledHeader, sampled)
+}
+
+func (b3) Extract(ctx context.Context, carrier Carrier) (core.SpanContext, tag.Map) {
+	tags := tag.FromContext(ctx)
+	var sc core.SpanContext
+	var ok bool
+	if h := strings.TrimSpace(carrier.Get(B3SingleHeader)); h != "" {
+		sc, ok = parseB3Single(h)
+	} else {
+		sc, ok = parseB3Multi(carrier)
+	}
+	if !ok {
+		return core.EmptySpanContext(), tags
+	}
+	return sc, tags
+}
+
+// parseB3Single parses a b3 header:
+// {trace ID}-{span ID}[-{sampling state}[-{parent span ID}]]. A header
+// holding only a sampling state has no span context.
+func parseB3Single(h string) (core.SpanContext, bool) {
+	parts := strings.Split(h, "-")
+	if len(parts) < 2 || len(parts) > 4 {
+		return core.SpanContext{}, false
+	}
+	var sc core.SpanContext
+	if !parseB3TraceID(parts[0], &sc) || !parseB3SpanID(parts[1], &sc.SpanID) {
+		return core.SpanContext{}, false
+	}
+	if len(parts) > 2 {
+		switch parts[2] {
+		case "1", "d":
+			sc.TraceOptions = core.TraceOptionSampled
+		case "0":
+		default:
+			return core.SpanContext{}, false
+		}
+	}
+	if len(parts) > 3 {
+		var parent uint64
+		if !parseB3SpanID(parts[3], &parent) {
+			return core.SpanContext{}, false
+		}
+	}
+	return sc, sc.IsValid()
+}
+
+// parseB3Multi parses the X-B3-* headers.
+func parseB3Multi(carrier Carrier) (core.SpanContext, bool) {
+	var sc core.SpanContext
+	if !parseB3TraceID(carrier.Get(B3TraceIDHeader), &sc) || !parseB3SpanID(carrier.Get(B3SpanIDHeader), &sc.SpanID) {
+		return core.SpanContext{}, false
+	}
+	switch strings.ToLower(carrier.Get(B3SampledHeader)) {
+	case "1", "true":
+		sc.TraceOptions = core.TraceOptionSampled
+	}
+	if carrier.Get(B3FlagsHeader) == "1" {
+		// Debug implies sampled.
+		sc.TraceOptions = core.TraceOptionSampled
+	}
+	return sc, sc.IsValid()
+}
+
+// parseB3TraceID parses a trace ID of 16 or 32 lowercase hex digits into
+// sc.
+func parseB3TraceID(s string, sc *core.SpanContext) bool {
+	var ok bool
+	switch len(s) {
+	case 16:
+		sc.TraceID.High = 0
+		sc.TraceID.Low, ok = parseHex(s)
+		return ok
+	case 32:
+		var okLow bool
+		sc.TraceID.High, ok = parseHex(s[:16])
+		sc.TraceID.Low, okLow = parseHex(s[16:])
+		return ok && okLow
+	}
+	return false
+}
+
+// parseB3SpanID parses a span ID of 16 lowercase hex digits into id.
+func parseB3SpanID(s string, id *uint64) bool {
+	if len(s) != 16 {
+		return false
+	}
+	var ok bool
+	*id, ok = parseHex(s)
+	return ok
+}
@@ -0,0 +1,164 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+const (
+	// BaggageHeader is the W3C Baggage header holding propagated tags.
+	BaggageHeader = "baggage"
+
+	maxBaggageMembers = 180
+	maxBaggageLength  = 8192
+)
+
+// Baggage returns a propagator for the W3C Baggage header. It propagates
+// tags only; combine it with TraceContext using Composite to propagate
+// span contexts too.
+//
+// Values are percent-encoded. The properties of tags, set with
+// tag.Mutator.WithProperties, are propagated as member properties. Tags
+// and properties whose key is not a valid token are not propagated, and
+// members past the limits of the specification are dropped.
+func Baggage() TextFormatPropagator {
+	return baggage{}
+}
+
+type baggage struct{}
+
+var _ TextFormatPropagator = baggage{}
+
+func (baggage) Injector(carrier Carrier) apitrace.Injector {
+	return baggageInjector{carrier}
+}
+
+func (baggage) Fields() []string {
+	return []string{BaggageHeader}
+}
+
+type baggageInjector struct {
+	carrier Carrier
+}
+
+func (i baggageInjector) Inject(sc core.SpanContext, tags tag.Map) {
+	if tags == nil {
+		return
+	}
+	var members []string
+	tags.Foreach(func(kv core.KeyValue) bool {
+		if validToken(kv.Key.Variable.Name) {
+			member := kv.Key.Variable.Name + "=" + url.PathEscape(kv.Value.Emit())
+			for _, p := range tag.Properties(tags, kv.Key) {
+				if !validToken(p.Key) {
+					continue
+				}
+				member += ";" + p.Key
+				if p.Value != "" {
+					member += "=" + url.PathEscape(p.Value)
+				}
+			}
+			members = append(members, member)
+		}
+		return true
+	})
+	sort.Strings(members)
+	length := 0
+	for n, m := range members {
+		if n == maxBaggageMembers || length+len(m)+1 > maxBaggageLength {
+			members = members[:n]
+			break
+		}
+		length += len(m) + 1
+	}
+	if len(members) > 0 {
+		i.carrier.Set(BaggageHeader, strings.Join(members, ","))
+	}
+}
+
+func (baggage) Extract(ctx context.Context, carrier Carrier) (core.SpanContext, tag.Map) {
+	tags := tag.FromContext(ctx)
+	var mutators []tag.Mutator
+	for _, m := range strings.Split(carrier.Get(BaggageHeader), ",") {
+		var props []tag.Property
+		if semi := strings.IndexByte(m, ';'); semi >= 0 {
+			props = parseProperties(m[semi+1:])
+			m = m[:semi]
+		}
+		eq := strings.IndexByte(m, '=')
+		if eq < 0 {
+			continue
+		}
+		k := strings.TrimSpace(m[:eq])
+		v, err := url.PathUnescape(strings.TrimSpace(m[eq+1:]))
+		if !validToken(k) || err != nil {
+			continue
+		}
+		mutators = append(mutators, tag.Upsert(key.New(k).String(v)).WithProperties(props...))
+		if len(mutators) == maxBaggageMembers {
+			break
+		}
+	}
+	if len(mutators) > 0 {
+		tags = tags.Apply(tag.MapUpdate{MultiMutator: mutators})
+	}
+	return core.EmptySpanContext(), tags
+}
+
+// parseProperties parses the ';'-separated properties of a baggage
+// member, skipping malformed ones.
+func parseProperties(s string) []tag.Property {
+	var props []tag.Property
+	for _, p := range strings.Split(s, ";") {
+		var prop tag.Property
+		if eq := strings.IndexByte(p, '='); eq >= 0 {
+			v, err := url.PathUnescape(strings.TrimSpace(p[eq+1:]))
+			if err != nil {
+				continue
+			}
+			prop.Value = v
+			p = p[:eq]
+		}
+		prop.Key = strings.TrimSpace(p)
+		if validToken(prop.Key) {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// validToken reports whether s is an RFC 7230 token, the syntax of
+// baggage keys.
+func validToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= 0x20 || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
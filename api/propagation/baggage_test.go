@@ -0,0 +1,98 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+)
+
+func TestBaggageInject(t *testing.T) {
+	h := http.Header{}
+	tags := tag.NewMap(tag.MapUpdate{MultiKV: []core.KeyValue{
+		key.New("user").String("alice smith"),
+		key.New("tenant").Int(7),
+		key.New("bad key").String("dropped"),
+	}})
+	Baggage().Injector(h).Inject(core.SpanContext{}, tags)
+
+	if got, want := h.Get(BaggageHeader), "tenant=7,user=alice%20smith"; got != want {
+		t.Errorf("baggage = %q; want %q", got, want)
+	}
+	if got := h.Get(TraceParentHeader); got != "" {
+		t.Errorf("traceparent = %q; want none", got)
+	}
+}
+
+func TestBaggageExtract(t *testing.T) {
+	h := http.Header{}
+	h.Set(BaggageHeader, "user=alice%20smith, tenant = 7;ttl=10,invalid,(bad)=x,bad=%zz")
+	sc, tags := Baggage().Extract(context.Background(), h)
+	if sc.IsValid() {
+		t.Errorf("Extract() span context = %+v; want none", sc)
+	}
+	got := map[string]string{}
+	tags.Foreach(func(kv core.KeyValue) bool {
+		got[kv.Key.Variable.Name] = kv.Value.Emit()
+		return true
+	})
+	if len(got) != 2 || got["user"] != "alice smith" || got["tenant"] != "7" {
+		t.Errorf("Extract() tags = %v; want user and tenant", got)
+	}
+}
+
+func TestBaggagePropertiesRoundTrip(t *testing.T) {
+	h := http.Header{}
+	h.Set(BaggageHeader, "route=canary;weight=10%25;sticky;bad key=x, user=alice")
+	_, tags := Baggage().Extract(context.Background(), h)
+
+	want := []tag.Property{{Key: "weight", Value: "10%"}, {Key: "sticky"}}
+	if diff := cmp.Diff(want, tag.Properties(tags, key.New("route"))); diff != "" {
+		t.Errorf("route properties differ (-want +got):\n%s", diff)
+	}
+	if got := tag.Properties(tags, key.New("user")); got != nil {
+		t.Errorf("user properties = %v; want none", got)
+	}
+
+	out := http.Header{}
+	Baggage().Injector(out).Inject(core.SpanContext{}, tags)
+	if got, want := out.Get(BaggageHeader), "route=canary;weight=10%25;sticky,user=alice"; got != want {
+		t.Errorf("baggage = %q; want %q", got, want)
+	}
+}
+
+func TestCompositeExtract(t *testing.T) {
+	h := http.Header{}
+	h.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Set(BaggageHeader, "user=alice")
+	p := Composite(TraceContext(), Baggage())
+
+	sc, tags := p.Extract(context.Background(), h)
+	if sc != spanContext {
+		t.Errorf("Extract() = %+v; want %+v", sc, spanContext)
+	}
+	if v, ok := tags.Value(key.New("user")); !ok || v.Emit() != "alice" {
+		t.Errorf("user tag = %q, %v; want alice", v.Emit(), ok)
+	}
+	if got := p.Fields(); len(got) != 3 {
+		t.Errorf("Fields() = %v; want the fields of both propagators", got)
+	}
+}
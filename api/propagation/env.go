@@ -0,0 +1,98 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+// EnvCarrier is a Carrier of environment variables, for propagating a
+// trace to subprocesses. Each field is stored in the variable named like
+// the field in upper case with '-' replaced by '_', e.g., the traceparent
+// field in TRACEPARENT.
+type EnvCarrier map[string]string
+
+var _ Carrier = EnvCarrier(nil)
+
+// Get returns the variable of key.
+func (c EnvCarrier) Get(key string) string {
+	return c[EnvName(key)]
+}
+
+// Set sets the variable of key.
+func (c EnvCarrier) Set(key string, value string) {
+	c[EnvName(key)] = value
+}
+
+// EnvName returns the environment variable holding field.
+func EnvName(field string) string {
+	return strings.ToUpper(strings.Replace(field, "-", "_", -1))
+}
+
+// InjectEnv returns env, a list of "NAME=value" entries like the one of
+// os.Environ, with the fields of p set to the current span and the tags
+// of ctx. Variables of the fields of p already in env are replaced or,
+// if p sets no value for them, removed, so that a child does not continue
+// a stale trace. For example,
+//
+//	cmd := exec.Command("make", "test")
+//	cmd.Env = propagation.InjectEnv(ctx, p, os.Environ())
+func InjectEnv(ctx context.Context, p TextFormatPropagator, env []string) []string {
+	c := EnvCarrier{}
+	Inject(ctx, p, c)
+
+	fields := make(map[string]bool)
+	for _, f := range p.Fields() {
+		fields[EnvName(f)] = true
+	}
+	out := make([]string, 0, len(env)+len(c))
+	for _, kv := range env {
+		name := kv
+		if eq := strings.IndexByte(kv, '='); eq >= 0 {
+			name = kv[:eq]
+		}
+		if !fields[name] {
+			out = append(out, kv)
+		}
+	}
+	for _, f := range p.Fields() {
+		name := EnvName(f)
+		if v, ok := c[name]; ok {
+			out = append(out, name+"="+v)
+		}
+	}
+	return out
+}
+
+// ExtractEnv reads the fields of p from the environment of the process,
+// as set by a parent that used InjectEnv, and returns what Extract
+// returns for them. It is meant to be called at process start, e.g.,
+//
+//	ctx, opts := propagation.ExtractEnv(context.Background(), p)
+//	ctx, span := tracer.Start(ctx, "main", opts...)
+func ExtractEnv(ctx context.Context, p TextFormatPropagator) (context.Context, []apitrace.SpanOption) {
+	c := EnvCarrier{}
+	for _, f := range p.Fields() {
+		name := EnvName(f)
+		if v, ok := os.LookupEnv(name); ok {
+			c[name] = v
+		}
+	}
+	return Extract(ctx, p, c)
+}
@@ -0,0 +1,92 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+type injectingTracer struct {
+	apitrace.NoopTracer
+}
+
+func (injectingTracer) Inject(ctx context.Context, span apitrace.Span, injector apitrace.Injector) {
+	injector.Inject(span.SpanContext(), tag.FromContext(ctx))
+}
+
+type contextSpan struct {
+	apitrace.NoopSpan
+	sc core.SpanContext
+}
+
+func (s contextSpan) SpanContext() core.SpanContext { return s.sc }
+func (s contextSpan) Tracer() apitrace.Tracer       { return injectingTracer{} }
+
+func TestInjectEnv(t *testing.T) {
+	ctx := apitrace.SetCurrentSpan(context.Background(), contextSpan{sc: spanContext})
+	ctx = tag.NewContext(ctx, tag.Insert(key.New("user").String("alice")))
+	env := []string{"PATH=/bin", "TRACEPARENT=stale", "TRACESTATE=stale", "HOME=/root"}
+
+	got := InjectEnv(ctx, Composite(TraceContext(), Baggage()), env)
+	sort.Strings(got)
+	want := []string{
+		"BAGGAGE=user=alice",
+		"HOME=/root",
+		"PATH=/bin",
+		"TRACEPARENT=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"TRACESTATE=user@ot=alice",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("InjectEnv() = %q; want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("InjectEnv()[%d] = %q; want %q", i, got[i], want[i])
+		}
+	}
+
+	// Without a span, stale variables are removed.
+	got = InjectEnv(context.Background(), TraceContext(), env)
+	if len(got) != 2 || got[0] != "PATH=/bin" || got[1] != "HOME=/root" {
+		t.Errorf("InjectEnv() without a span = %q; want PATH and HOME only", got)
+	}
+}
+
+func TestExtractEnv(t *testing.T) {
+	defer os.Unsetenv("TRACEPARENT")
+	defer os.Unsetenv("BAGGAGE")
+	os.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	os.Setenv("BAGGAGE", "user=alice")
+
+	ctx, opts := ExtractEnv(context.Background(), Composite(TraceContext(), Baggage()))
+	var o apitrace.SpanOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Reference.SpanContext != spanContext || o.Reference.RelationshipType != apitrace.ChildOfRelationship {
+		t.Errorf("ExtractEnv() reference = %+v; want child of %+v", o.Reference, spanContext)
+	}
+	if v, ok := tag.FromContext(ctx).Value(key.New("user")); !ok || v.Emit() != "alice" {
+		t.Errorf("user tag = %q, %v; want alice", v.Emit(), ok)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation_test
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/api/propagation"
+	"go.opentelemetry.io/api/propagation/propagationtest"
+)
+
+func TestTraceContextInterop(t *testing.T) {
+	cases, err := propagationtest.LoadCases("propagationtest/testdata/tracecontext.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	propagationtest.Run(t, propagation.TraceContext(), cases)
+}
+
+func TestB3Interop(t *testing.T) {
+	cases, err := propagationtest.LoadCases("propagationtest/testdata/b3.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	propagationtest.Run(t, propagation.B3(), cases)
+}
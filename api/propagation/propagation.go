@@ -0,0 +1,124 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package propagation contains propagators that carry span context and
+// tags across process boundaries in text-based carriers such as HTTP
+// headers.
+package propagation // import "go.opentelemetry.io/api/propagation"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+// Carrier stores propagated fields as string key/value pairs.
+// http.Header implements Carrier.
+type Carrier interface {
+	Get(key string) string
+	Set(key string, value string)
+}
+
+// TextFormatPropagator injects span context and tags into a Carrier and
+// extracts them from it.
+type TextFormatPropagator interface {
+	// Injector returns an Injector that writes to carrier, for use with
+	// Tracer.Inject.
+	Injector(carrier Carrier) apitrace.Injector
+
+	// Extract reads the span context and tags written to carrier by a
+	// remote Injector. The span context is invalid if the carrier holds
+	// none. The tags are merged into the tag map of ctx.
+	Extract(ctx context.Context, carrier Carrier) (core.SpanContext, tag.Map)
+
+	// Fields returns the keys the propagator reads and writes.
+	Fields() []string
+}
+
+// Inject writes the current span of ctx and the tags of ctx to carrier
+// using p.
+func Inject(ctx context.Context, p TextFormatPropagator, carrier Carrier) {
+	apitrace.Inject(ctx, p.Injector(carrier))
+}
+
+// Extract reads a remote span context and tags from carrier using p. It
+// returns ctx with the tags applied, and span options that make the next
+// span started with the context a child of the remote span, e.g.,
+//
+//	ctx, opts := propagation.Extract(req.Context(), propagation.TraceContext(), req.Header)
+//	ctx, span := tracer.Start(ctx, "handle", opts...)
+//
+// No options are returned if the carrier holds no span context.
+func Extract(ctx context.Context, p TextFormatPropagator, carrier Carrier) (context.Context, []apitrace.SpanOption) {
+	sc, tags := p.Extract(ctx, carrier)
+	if tags != nil {
+		ctx = tag.WithMap(ctx, tags)
+	}
+	if !sc.IsValid() {
+		return ctx, nil
+	}
+	return ctx, []apitrace.SpanOption{apitrace.ChildOf(sc)}
+}
+
+// Composite returns a propagator that injects with each of ps and
+// extracts with each of ps in order. The span context extracted is the
+// first valid one, and the tags extracted by each propagator are seen by
+// the next.
+func Composite(ps ...TextFormatPropagator) TextFormatPropagator {
+	return composite(ps)
+}
+
+type composite []TextFormatPropagator
+
+func (c composite) Injector(carrier Carrier) apitrace.Injector {
+	injectors := make(compositeInjector, len(c))
+	for i, p := range c {
+		injectors[i] = p.Injector(carrier)
+	}
+	return injectors
+}
+
+func (c composite) Extract(ctx context.Context, carrier Carrier) (core.SpanContext, tag.Map) {
+	sc := core.EmptySpanContext()
+	tags := tag.FromContext(ctx)
+	for _, p := range c {
+		psc, ptags := p.Extract(ctx, carrier)
+		if !sc.IsValid() {
+			sc = psc
+		}
+		if ptags != nil {
+			tags = ptags
+			ctx = tag.WithMap(ctx, tags)
+		}
+	}
+	return sc, tags
+}
+
+func (c composite) Fields() []string {
+	var fields []string
+	for _, p := range c {
+		fields = append(fields, p.Fields()...)
+	}
+	return fields
+}
+
+type compositeInjector []apitrace.Injector
+
+func (ci compositeInjector) Inject(sc core.SpanContext, tags tag.Map) {
+	for _, i := range ci {
+		i.Inject(sc, tags)
+	}
+}
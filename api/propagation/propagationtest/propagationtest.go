@@ -0,0 +1,126 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package propagationtest validates propagators against interoperability
+// fixtures, such as the cases of the W3C Trace Context test suite.
+//
+// Fixtures are JSON arrays of Case. The fixtures of this package are in
+// its testdata directory: tracecontext.json for the W3C Trace Context
+// headers, and b3.json for the B3 headers, which propagation.TraceContext
+// and propagation.B3 pass. Third-party propagators can run them, or
+// fixtures of their own, with Run:
+//
+//	cases, err := propagationtest.LoadCases("testdata/b3.json")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	propagationtest.Run(t, myPropagator, cases)
+//
+// The traceparent cases of tracecontext.json are those of the W3C test
+// suite, test/test.py of github.com/w3c/trace-context, named after its
+// test methods. Its tracestate cases are left out, since span contexts do
+// not carry the tracestate of other vendors, as are its cases of repeated
+// headers, which a Case cannot hold.
+package propagationtest // import "go.opentelemetry.io/api/propagation/propagationtest"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/propagation"
+)
+
+// Case is a fixture: headers received, and the span context a propagator
+// must extract from them.
+type Case struct {
+	Name    string            `json:"name"`
+	Headers map[string]string `json:"headers"`
+
+	// Valid is whether the headers hold a valid span context. TraceID,
+	// SpanID and Sampled describe it.
+	Valid   bool   `json:"valid"`
+	TraceID string `json:"trace_id,omitempty"`
+	SpanID  string `json:"span_id,omitempty"`
+	Sampled bool   `json:"sampled,omitempty"`
+
+	// Inject holds the headers expected from injecting the extracted
+	// span context. Only the span context is checked to survive an
+	// injection and extraction when it is empty.
+	Inject map[string]string `json:"inject,omitempty"`
+}
+
+// LoadCases reads the cases of a JSON fixture file.
+func LoadCases(path string) ([]Case, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cases []Case
+	if err := json.Unmarshal(b, &cases); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+// Run runs each case as a subtest of t against p.
+func Run(t *testing.T, p propagation.TextFormatPropagator, cases []Case) {
+	t.Helper()
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			if err := Check(p, c); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Check runs c against p, and returns the first failure.
+func Check(p propagation.TextFormatPropagator, c Case) error {
+	in := http.Header{}
+	for k, v := range c.Headers {
+		in.Set(k, v)
+	}
+	sc, _ := p.Extract(context.Background(), in)
+	if sc.IsValid() != c.Valid {
+		return fmt.Errorf("extracted valid span context %v; want %v", sc.IsValid(), c.Valid)
+	}
+	if !c.Valid {
+		return nil
+	}
+	if sc.TraceIDString() != c.TraceID || sc.SpanIDString() != c.SpanID || sc.IsSampled() != c.Sampled {
+		return fmt.Errorf("extracted %s; want %s/%s sampled %v", describe(sc), c.TraceID, c.SpanID, c.Sampled)
+	}
+
+	out := http.Header{}
+	p.Injector(out).Inject(sc, nil)
+	for k, want := range c.Inject {
+		if got := out.Get(k); got != want {
+			return fmt.Errorf("injected %s %q; want %q", k, got, want)
+		}
+	}
+	if again, _ := p.Extract(context.Background(), out); again != sc {
+		return fmt.Errorf("extracted %s after injection; want %s", describe(again), describe(sc))
+	}
+	return nil
+}
+
+func describe(sc core.SpanContext) string {
+	return fmt.Sprintf("%s/%s sampled %v", sc.TraceIDString(), sc.SpanIDString(), sc.IsSampled())
+}
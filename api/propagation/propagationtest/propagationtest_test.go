@@ -0,0 +1,37 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagationtest
+
+import (
+	"testing"
+)
+
+func TestLoadCases(t *testing.T) {
+	for _, path := range []string{"testdata/tracecontext.json", "testdata/b3.json"} {
+		cases, err := LoadCases(path)
+		if err != nil {
+			t.Errorf("LoadCases(%q) = %v", path, err)
+			continue
+		}
+		for _, c := range cases {
+			if c.Name == "" || len(c.Headers) == 0 {
+				t.Errorf("%s: case %+v has no name or headers", path, c)
+			}
+			if c.Valid && (len(c.TraceID) != 32 || len(c.SpanID) != 16) {
+				t.Errorf("%s: %s: valid case without a full trace and span ID", path, c.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+const (
+	// TraceParentHeader is the W3C Trace Context header holding the
+	// trace ID, parent span ID and trace flags.
+	TraceParentHeader = "traceparent"
+
+	// TraceStateHeader is the W3C Trace Context header holding
+	// vendor-specific trace state.
+	TraceStateHeader = "tracestate"
+
+	// TraceStateVendor is the vendor of the tracestate members that hold
+	// propagated tags, e.g., "user@ot=alice".
+	TraceStateVendor = "ot"
+
+	supportedVersion  = 0
+	maxVersion        = 254
+	traceParentLength = 55
+
+	maxTraceStateMembers = 32
+	maxTraceStateLength  = 512
+)
+
+// TraceContext returns a propagator for the W3C Trace Context headers,
+// traceparent and tracestate.
+//
+// Tags are propagated as tracestate members of the TraceStateVendor
+// vendor. Tags whose key or value cannot be represented in a tracestate
+// member are not propagated. Members of other vendors are ignored, since
+// span contexts have no room to carry them.
+func TraceContext() TextFormatPropagator {
+	return traceContext{}
+}
+
+type traceContext struct{}
+
+var _ TextFormatPropagator = traceContext{}
+
+func (traceContext) Injector(carrier Carrier) apitrace.Injector {
+	return traceContextInjector{carrier}
+}
+
+func (traceContext) Fields() []string {
+	return []string{TraceParentHeader, TraceStateHeader}
+}
+
+type traceContextInjector struct {
+	carrier Carrier
+}
+
+func (i traceContextInjector) Inject(sc core.SpanContext, tags tag.Map) {
+	if !sc.IsValid() {
+		return
+	}
+	i.carrier.Set(TraceParentHeader, fmt.Sprintf("%.2x-%s-%s-%.2x",
+		supportedVersion,
+		sc.TraceIDString(),
+		sc.SpanIDString(),
+		sc.TraceOptions&core.TraceOptionSampled,
+	))
+
+	if tags == nil {
+		return
+	}
+	var members []string
+	length := 0
+	tags.Foreach(func(kv core.KeyValue) bool {
+		k := kv.Key.Variable.Name + "@" + TraceStateVendor
+		v := kv.Value.Emit()
+		if !validTraceStateKey(k) || !validTraceStateValue(v) {
+			return true
+		}
+		m := k + "=" + v
+		if len(members) == maxTraceStateMembers || length+len(m)+1 > maxTraceStateLength {
+			return false
+		}
+		members = append(members, m)
+		length += len(m) + 1
+		return true
+	})
+	if len(members) > 0 {
+		i.carrier.Set(TraceStateHeader, strings.Join(members, ","))
+	}
+}
+
+func (traceContext) Extract(ctx context.Context, carrier Carrier) (core.SpanContext, tag.Map) {
+	tags := tag.FromContext(ctx)
+	sc, ok := parseTraceParent(carrier.Get(TraceParentHeader))
+	if !ok {
+		return core.EmptySpanContext(), tags
+	}
+
+	var mutators []tag.Mutator
+	for _, m := range strings.Split(carrier.Get(TraceStateHeader), ",") {
+		m = strings.TrimSpace(m)
+		eq := strings.IndexByte(m, '=')
+		if eq < 0 {
+			continue
+		}
+		k, v := m[:eq], m[eq+1:]
+		if !strings.HasSuffix(k, "@"+TraceStateVendor) || !validTraceStateKey(k) || !validTraceStateValue(v) {
+			continue
+		}
+		mutators = append(mutators, tag.Upsert(key.New(strings.TrimSuffix(k, "@"+TraceStateVendor)).String(v)))
+		if len(mutators) == maxTraceStateMembers {
+			break
+		}
+	}
+	if len(mutators) > 0 {
+		tags = tags.Apply(tag.MapUpdate{MultiMutator: mutators})
+	}
+	return sc, tags
+}
+
+// parseTraceParent parses a traceparent header. Versions newer than the
+// supported one are parsed as far as the supported format goes, as the
+// specification requires.
+func parseTraceParent(h string) (core.SpanContext, bool) {
+	h = strings.TrimSpace(h)
+	if len(h) < traceParentLength {
+		return core.SpanContext{}, false
+	}
+	version, ok := parseHex(h[0:2])
+	if !ok || version > maxVersion || h[2] != '-' || h[35] != '-' || h[52] != '-' {
+		return core.SpanContext{}, false
+	}
+	if version == supportedVersion && len(h) != traceParentLength {
+		return core.SpanContext{}, false
+	}
+	if len(h) > traceParentLength && h[traceParentLength] != '-' {
+		return core.SpanContext{}, false
+	}
+
+	var sc core.SpanContext
+	var okHigh, okLow, okSpan bool
+	sc.TraceID.High, okHigh = parseHex(h[3:19])
+	sc.TraceID.Low, okLow = parseHex(h[19:35])
+	sc.SpanID, okSpan = parseHex(h[36:52])
+	flags, okFlags := parseHex(h[53:55])
+	if !okHigh || !okLow || !okSpan || !okFlags || !sc.IsValid() {
+		return core.SpanContext{}, false
+	}
+	sc.TraceOptions = byte(flags) & core.TraceOptionSampled
+	return sc, true
+}
+
+// parseHex parses lowercase hex digits, which is the only form the
+// specification allows.
+func parseHex(s string) (uint64, bool) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return 0, false
+		}
+	}
+	v, err := strconv.ParseUint(s, 16, 64)
+	return v, err == nil
+}
+
+// validTraceStateKey reports whether k is a multi-tenant tracestate key,
+// tenant@vendor.
+func validTraceStateKey(k string) bool {
+	at := strings.IndexByte(k, '@')
+	if at < 1 || at > 241 || len(k)-at-1 < 1 || len(k)-at-1 > 14 {
+		return false
+	}
+	tenant, vendor := k[:at], k[at+1:]
+	if !isLowerAlnum(tenant[0]) || !(vendor[0] >= 'a' && vendor[0] <= 'z') {
+		return false
+	}
+	for _, s := range []string{tenant, vendor} {
+		for i := 0; i < len(s); i++ {
+			if c := s[i]; !isLowerAlnum(c) && c != '_' && c != '-' && c != '*' && c != '/' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isLowerAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+// validTraceStateValue reports whether v is a valid tracestate value:
+// printable ASCII except ',' and '=', not ending in a space.
+func validTraceStateValue(v string) bool {
+	if v == "" || len(v) > 256 || v[len(v)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		if c := v[i]; c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
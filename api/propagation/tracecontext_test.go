@@ -0,0 +1,104 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+)
+
+var spanContext = core.SpanContext{
+	TraceID:      core.TraceID{High: 0x4bf92f3577b34da6, Low: 0xa3ce929d0e0e4736},
+	SpanID:       0x00f067aa0ba902b7,
+	TraceOptions: core.TraceOptionSampled,
+}
+
+func TestTraceContextInject(t *testing.T) {
+	h := http.Header{}
+	tags := tag.NewMap(tag.MapUpdate{MultiKV: []core.KeyValue{
+		key.New("user").String("alice"),
+		key.New("Invalid Key").String("dropped"),
+	}})
+	TraceContext().Injector(h).Inject(spanContext, tags)
+
+	if got, want := h.Get(TraceParentHeader), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"; got != want {
+		t.Errorf("traceparent = %q; want %q", got, want)
+	}
+	if got, want := h.Get(TraceStateHeader), "user@ot=alice"; got != want {
+		t.Errorf("tracestate = %q; want %q", got, want)
+	}
+}
+
+func TestTraceContextExtract(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		traceparent string
+		want        core.SpanContext
+	}{
+		{"sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", spanContext},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", core.SpanContext{
+			TraceID: spanContext.TraceID,
+			SpanID:  spanContext.SpanID,
+		}},
+		{"future version", "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-09-extra", spanContext},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", core.SpanContext{}},
+		{"trailing data", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", core.SpanContext{}},
+		{"uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01", core.SpanContext{}},
+		{"zero trace ID", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", core.SpanContext{}},
+		{"zero span ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", core.SpanContext{}},
+		{"short", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", core.SpanContext{}},
+		{"missing", "", core.SpanContext{}},
+	} {
+		h := http.Header{}
+		h.Set(TraceParentHeader, tt.traceparent)
+		got, _ := TraceContext().Extract(context.Background(), h)
+		if got != tt.want {
+			t.Errorf("%s: Extract() = %+v; want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTraceContextExtractTags(t *testing.T) {
+	h := http.Header{}
+	h.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Set(TraceStateHeader, "congo=t61rcWkgMzE, user@ot=alice,bad@ot=a=b")
+
+	ctx := tag.WithMap(context.Background(), tag.NewMap(tag.MapUpdate{
+		SingleKV: key.New("local").String("x"),
+	}))
+	_, tags := TraceContext().Extract(ctx, h)
+
+	if v, ok := tags.Value(key.New("user")); !ok || v.Emit() != "alice" {
+		t.Errorf("user tag = %v, %v; want alice", v.Emit(), ok)
+	}
+	if !tags.HasValue(key.New("local")) {
+		t.Error("tags of the context were dropped")
+	}
+	if got := tags.Len(); got != 2 {
+		t.Errorf("got %d tags; want 2", got)
+	}
+}
+
+func TestExtractOptions(t *testing.T) {
+	_, opts := Extract(context.Background(), TraceContext(), http.Header{})
+	if len(opts) != 0 {
+		t.Errorf("got %d span options without a traceparent; want 0", len(opts))
+	}
+}
@@ -12,7 +12,20 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package stats
+// Package stats records raw float64 measurements.
+//
+// Deprecated: use the instruments of go.opentelemetry.io/api/metric
+// instead. A stats measure corresponds to a metric.Float64MeasureHandle,
+// recorded with metric.RecordBatch or through a Float64Measure bound to
+// labels with Meter.GetFloat64Measure:
+//
+//	latency := metric.NewFloat64Measure("ex.com/latency")
+//	metric.RecordBatch(ctx, nil, latency.M(1.3))
+//
+// The metric API adds counters and gauges, labels bound to instruments and
+// an SDK aggregating the values. This package is kept for the streaming
+// SDK, which observes its measurements.
+package stats // import "go.opentelemetry.io/api/stats"
 
 import (
 	"context"
@@ -63,10 +76,16 @@ func SetGlobalRecorder(t Recorder) {
 	global.Store(t)
 }
 
+// Record records m with the global recorder.
+//
+// Deprecated: use metric.RecordBatch.
 func Record(ctx context.Context, m ...Measurement) {
 	GlobalRecorder().Record(ctx, m...)
 }
 
+// RecordSingle records m with the global recorder.
+//
+// Deprecated: use metric.RecordBatch.
 func RecordSingle(ctx context.Context, m Measurement) {
 	GlobalRecorder().RecordSingle(ctx, m)
 }
@@ -82,6 +101,9 @@ var (
 	WithUnit        = registry.WithUnit
 )
 
+// NewMeasure returns a measure named name.
+//
+// Deprecated: use metric.NewFloat64Measure.
 func NewMeasure(name string, opts ...registry.Option) *MeasureHandle {
 	return &MeasureHandle{
 		Variable: registry.Register(name, AnyStatistic{}, opts...),
@@ -43,6 +43,17 @@ type Mutator struct {
 
 type MeasureMetadata struct {
 	TTL int // -1 == infinite, 0 == do not propagate
+
+	// Properties are propagated along with the tag, e.g., as the
+	// properties of a W3C Baggage member.
+	Properties []Property
+}
+
+// Property is a key, with an optional value, describing a tag. A property
+// with an empty Value has only a key.
+type Property struct {
+	Key   string
+	Value string
 }
 
 func (m Mutator) WithTTL(hops int) Mutator {
@@ -50,6 +61,13 @@ func (m Mutator) WithTTL(hops int) Mutator {
 	return m
 }
 
+// WithProperties returns the mutator with the properties of its tag set
+// to props.
+func (m Mutator) WithProperties(props ...Property) Mutator {
+	m.Properties = props
+	return m
+}
+
 type MapUpdate struct {
 	SingleKV      core.KeyValue
 	MultiKV       []core.KeyValue
@@ -66,6 +84,48 @@ type Map interface {
 	Len() int
 
 	Foreach(func(kv core.KeyValue) bool)
+
+	// ForeachOrdered is like Foreach, but calls f in the order of the
+	// key names, so that serialized maps are deterministic.
+	ForeachOrdered(func(kv core.KeyValue) bool)
+
+	// Diff returns the changes turning the map into other.
+	Diff(other Map) MapDiff
+}
+
+// MapDiff is the difference between two maps. Each part is ordered by key
+// name.
+type MapDiff struct {
+	// Added holds the tags of the other map whose keys are not in the
+	// map.
+	Added []core.KeyValue
+	// Removed holds the tags of the map whose keys are not in the other
+	// map.
+	Removed []core.KeyValue
+	// Changed holds the tags of the other map whose values differ from
+	// those of the same keys in the map.
+	Changed []core.KeyValue
+}
+
+// Empty returns whether the maps compared by Diff hold the same tags.
+func (d MapDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// PropertiesGetter is implemented by maps that keep the properties of
+// their tags.
+type PropertiesGetter interface {
+	// Properties returns the properties of the tag of key k.
+	Properties(k core.Key) []Property
+}
+
+// Properties returns the properties of the tag of key k in m, or nil if
+// m does not implement PropertiesGetter.
+func Properties(m Map, k core.Key) []Property {
+	if pg, ok := m.(PropertiesGetter); ok {
+		return pg.Properties(k)
+	}
+	return nil
 }
 
 func NewEmptyMap() Map {
@@ -15,8 +15,10 @@
 package tag
 
 import (
+	"bytes"
 	"context"
 	"runtime/pprof"
+	"sort"
 
 	"go.opentelemetry.io/api/core"
 )
@@ -29,6 +31,7 @@ type tagContent struct {
 type tagMap map[core.Key]tagContent
 
 var _ Map = tagMap{}
+var _ PropertiesGetter = tagMap{}
 
 func (t tagMap) Apply(update MapUpdate) Map {
 	m := make(tagMap, len(t)+len(update.MultiKV)+len(update.MultiMutator))
@@ -62,6 +65,11 @@ func (m tagMap) Value(k core.Key) (core.Value, bool) {
 	return entry.value, ok
 }
 
+// Properties implements PropertiesGetter.
+func (m tagMap) Properties(k core.Key) []Property {
+	return m[k].meta.Properties
+}
+
 func (m tagMap) HasValue(k core.Key) bool {
 	_, has := m.Value(k)
 	return has
@@ -82,6 +90,94 @@ func (m tagMap) Foreach(f func(kv core.KeyValue) bool) {
 	}
 }
 
+func (m tagMap) ForeachOrdered(f func(kv core.KeyValue) bool) {
+	for _, kv := range m.ordered() {
+		if !f(kv) {
+			return
+		}
+	}
+}
+
+// ordered returns the tags of m ordered by key name.
+func (m tagMap) ordered() []core.KeyValue {
+	kvs := make([]core.KeyValue, 0, len(m))
+	for k, v := range m {
+		kvs = append(kvs, core.KeyValue{Key: k, Value: v.value})
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key.Variable.Name < kvs[j].Key.Variable.Name
+	})
+	return kvs
+}
+
+func (m tagMap) Diff(other Map) MapDiff {
+	var d MapDiff
+	for _, kv := range m.ordered() {
+		if !other.HasValue(kv.Key) {
+			d.Removed = append(d.Removed, kv)
+		}
+	}
+	other.ForeachOrdered(func(kv core.KeyValue) bool {
+		if v, ok := m[kv.Key]; !ok {
+			d.Added = append(d.Added, kv)
+		} else if !valueEqual(v.value, kv.Value) {
+			d.Changed = append(d.Changed, kv)
+		}
+		return true
+	})
+	return d
+}
+
+// valueEqual returns whether a and b hold the same value of the same
+// type.
+func valueEqual(a, b core.Value) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	switch a.Type {
+	case core.BOOL:
+		return a.Bool == b.Bool
+	case core.INT32, core.INT64:
+		return a.Int64 == b.Int64
+	case core.UINT32, core.UINT64:
+		return a.Uint64 == b.Uint64
+	case core.FLOAT32, core.FLOAT64:
+		return a.Float64 == b.Float64
+	case core.STRING:
+		return a.String == b.String
+	case core.BYTES:
+		return bytes.Equal(a.Bytes, b.Bytes)
+	case core.STRINGS:
+		if len(a.Strings) != len(b.Strings) {
+			return false
+		}
+		for i := range a.Strings {
+			if a.Strings[i] != b.Strings[i] {
+				return false
+			}
+		}
+	case core.INT64S:
+		if len(a.Int64s) != len(b.Int64s) {
+			return false
+		}
+		for i := range a.Int64s {
+			if a.Int64s[i] != b.Int64s[i] {
+				return false
+			}
+		}
+	case core.FLOAT64S:
+		if len(a.Float64s) != len(b.Float64s) {
+			return false
+		}
+		for i := range a.Float64s {
+			if a.Float64s[i] != b.Float64s[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (m tagMap) apply(mutator Mutator) {
 	if m == nil {
 		return
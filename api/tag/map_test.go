@@ -0,0 +1,96 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tag
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+func names(kvs []core.KeyValue) []string {
+	var s []string
+	for _, kv := range kvs {
+		s = append(s, kv.Key.Variable.Name+"="+kv.Value.Emit())
+	}
+	return s
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestForeachOrdered(t *testing.T) {
+	m := NewMap(MapUpdate{MultiKV: []core.KeyValue{
+		key.New("c").Int(3),
+		key.New("a").Int(1),
+		key.New("b").Int(2),
+	}})
+	var got []core.KeyValue
+	m.ForeachOrdered(func(kv core.KeyValue) bool {
+		got = append(got, kv)
+		return len(got) < 2
+	})
+	if want := []string{"a=1", "b=2"}; !equal(names(got), want) {
+		t.Errorf("got %v, want %v", names(got), want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a, b, c, d := key.New("a"), key.New("b"), key.New("c"), key.New("d")
+	m := NewMap(MapUpdate{MultiKV: []core.KeyValue{
+		a.Int(1),
+		b.Strings([]string{"x"}),
+		c.String("same"),
+	}})
+	other := m.Apply(MapUpdate{
+		MultiKV:      []core.KeyValue{b.Strings([]string{"y"}), d.Bool(true)},
+		MultiMutator: []Mutator{Delete(a)},
+	})
+
+	diff := m.Diff(other)
+	for _, tt := range []struct {
+		name string
+		got  []core.KeyValue
+		want []string
+	}{
+		{"Added", diff.Added, []string{"d=true"}},
+		{"Removed", diff.Removed, []string{"a=1"}},
+		{"Changed", diff.Changed, []string{`b=["y"]`}},
+	} {
+		if !equal(names(tt.got), tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, names(tt.got), tt.want)
+		}
+	}
+	if diff.Empty() {
+		t.Error("Empty() = true for different maps")
+	}
+	if d := m.Diff(m); !d.Empty() {
+		t.Errorf("Diff of a map with itself = %+v, want empty", d)
+	}
+	// Equal values of another type differ.
+	if d := m.Diff(m.Apply(MapUpdate{SingleKV: a.Int32(1)})); len(d.Changed) != 1 {
+		t.Errorf("Diff = %+v, want a changed to an int32", d)
+	}
+}
@@ -100,6 +100,12 @@ type SpanOptions struct {
 	StartTime   time.Time
 	Reference   Reference
 	RecordEvent bool
+	SpanKind    SpanKind
+
+	// Custom holds options specific to the SDK implementing the tracer,
+	// e.g., a sampler for the span, set by options the SDK defines.
+	// Other implementations ignore them.
+	Custom []interface{}
 }
 
 // Reference is used to establish relationship between newly created span and the
@@ -112,6 +118,36 @@ type Reference struct {
 
 type RelationshipType int
 
+// SpanKind describes the role of a span in the trace, e.g., whether it
+// serves a remote request or issues one.
+type SpanKind int
+
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+var spanKindNames = [...]string{
+	SpanKindUnspecified: "unspecified",
+	SpanKindInternal:    "internal",
+	SpanKindServer:      "server",
+	SpanKindClient:      "client",
+	SpanKindProducer:    "producer",
+	SpanKindConsumer:    "consumer",
+}
+
+// String returns the lower-case name of k, e.g., "server".
+func (k SpanKind) String() string {
+	if k >= 0 && int(k) < len(spanKindNames) {
+		return spanKindNames[k]
+	}
+	return "unspecified"
+}
+
 const (
 	ChildOfRelationship RelationshipType = iota
 	FollowsFromRelationship
@@ -160,6 +196,13 @@ func WithRecordEvents() SpanOption {
 	}
 }
 
+// WithSpanKind sets the kind of the span.
+func WithSpanKind(kind SpanKind) SpanOption {
+	return func(o *SpanOptions) {
+		o.SpanKind = kind
+	}
+}
+
 // ChildOf. TODO: do we need this?.
 func ChildOf(sc core.SpanContext) SpanOption {
 	return func(o *SpanOptions) {
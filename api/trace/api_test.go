@@ -26,3 +26,23 @@ func TestGlobalTracer(t *testing.T) {
 		t.Fatalf("Failed to get tracer\n")
 	}
 }
+
+func TestSpanKindString(t *testing.T) {
+	for _, tt := range []struct {
+		kind SpanKind
+		want string
+	}{
+		{SpanKindUnspecified, "unspecified"},
+		{SpanKindInternal, "internal"},
+		{SpanKindServer, "server"},
+		{SpanKindClient, "client"},
+		{SpanKindProducer, "producer"},
+		{SpanKindConsumer, "consumer"},
+		{SpanKind(-1), "unspecified"},
+		{SpanKind(42), "unspecified"},
+	} {
+		if got := tt.kind.String(); got != tt.want {
+			t.Errorf("SpanKind(%d).String() = %q, want %q", int(tt.kind), got, tt.want)
+		}
+	}
+}
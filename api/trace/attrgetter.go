@@ -0,0 +1,77 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/api/core"
+)
+
+// AttributeGetter extracts span attributes from an object handled by
+// instrumentation, such as an *http.Request. Instrumentation passes the
+// objects it starts spans for to ExtractAttributes, so that a getter can
+// add custom attributes, e.g., from an API version header, consistently
+// across integrations. Getters should ignore objects of types they do not
+// know about.
+type AttributeGetter interface {
+	Attributes(obj interface{}) []core.KeyValue
+}
+
+// AttributeGetterFunc is an AttributeGetter implemented by a function.
+type AttributeGetterFunc func(obj interface{}) []core.KeyValue
+
+// Attributes implements AttributeGetter.
+func (f AttributeGetterFunc) Attributes(obj interface{}) []core.KeyValue {
+	return f(obj)
+}
+
+// MultiAttributeGetter returns an AttributeGetter returning the
+// attributes of all getters, in order.
+func MultiAttributeGetter(getters ...AttributeGetter) AttributeGetter {
+	return AttributeGetterFunc(func(obj interface{}) []core.KeyValue {
+		var attrs []core.KeyValue
+		for _, g := range getters {
+			attrs = append(attrs, g.Attributes(obj)...)
+		}
+		return attrs
+	})
+}
+
+type attributeGetterHolder struct {
+	getter AttributeGetter
+}
+
+var globalAttributeGetter atomic.Value
+
+// SetGlobalAttributeGetter sets the AttributeGetter consulted by
+// ExtractAttributes for every instrumented object. A nil getter removes
+// it.
+func SetGlobalAttributeGetter(g AttributeGetter) {
+	globalAttributeGetter.Store(attributeGetterHolder{getter: g})
+}
+
+// ExtractAttributes returns the attributes the global AttributeGetter and
+// then getters extract from obj.
+func ExtractAttributes(obj interface{}, getters ...AttributeGetter) []core.KeyValue {
+	var attrs []core.KeyValue
+	if h, ok := globalAttributeGetter.Load().(attributeGetterHolder); ok && h.getter != nil {
+		attrs = h.getter.Attributes(obj)
+	}
+	for _, g := range getters {
+		attrs = append(attrs, g.Attributes(obj)...)
+	}
+	return attrs
+}
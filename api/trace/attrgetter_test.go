@@ -0,0 +1,75 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+type request struct {
+	version string
+}
+
+func TestExtractAttributes(t *testing.T) {
+	version := key.New("api.version")
+	route := key.New("route")
+	versionGetter := AttributeGetterFunc(func(obj interface{}) []core.KeyValue {
+		if req, ok := obj.(request); ok {
+			return []core.KeyValue{version.String(req.version)}
+		}
+		return nil
+	})
+	routeGetter := AttributeGetterFunc(func(interface{}) []core.KeyValue {
+		return []core.KeyValue{route.String("/users")}
+	})
+
+	SetGlobalAttributeGetter(versionGetter)
+	defer SetGlobalAttributeGetter(nil)
+
+	for _, tt := range []struct {
+		name    string
+		obj     interface{}
+		getters []AttributeGetter
+		want    []string
+	}{
+		{"global", request{"v2"}, nil, []string{"api.version=v2"}},
+		{"global then given", request{"v2"}, []AttributeGetter{routeGetter}, []string{"api.version=v2", "route=/users"}},
+		{"unknown type", "other", nil, nil},
+		{"multi", "other", []AttributeGetter{MultiAttributeGetter(versionGetter, routeGetter)}, []string{"route=/users"}},
+	} {
+		var got []string
+		for _, kv := range ExtractAttributes(tt.obj, tt.getters...) {
+			got = append(got, kv.Key.Variable.Name+"="+kv.Value.Emit())
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	SetGlobalAttributeGetter(nil)
+	if got := ExtractAttributes(request{"v2"}); len(got) != 0 {
+		t.Errorf("got %v after removing the global getter, want none", got)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"strconv"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/tag"
+)
+
+// CacheKey returns a key that is the same for every span of the current
+// trace, for memoizing request-scoped work such as permission checks
+// across spans. The values of the given tag keys are hashed into the key,
+// so that work depending on propagated values, e.g., a tenant ID, is not
+// shared between requests that differ in them.
+//
+// CacheKey returns false if ctx holds no valid span context.
+func CacheKey(ctx context.Context, keys ...core.Key) (string, bool) {
+	sc := CurrentSpan(ctx).SpanContext()
+	if !sc.HasTraceID() {
+		return "", false
+	}
+
+	h := fnv.New64a()
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[0:8], sc.TraceID.High)
+	binary.BigEndian.PutUint64(b[8:16], sc.TraceID.Low)
+	_, _ = h.Write(b[:])
+
+	m := tag.FromContext(ctx)
+	for _, k := range keys {
+		// Separate fields, so that ("ab", "c") and ("a", "bc") differ.
+		_, _ = h.Write([]byte{0})
+		if v, ok := m.Value(k); ok {
+			_, _ = h.Write([]byte(v.Emit()))
+		}
+	}
+	return strconv.FormatUint(h.Sum64(), 16), true
+}
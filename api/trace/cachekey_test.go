@@ -0,0 +1,69 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+)
+
+type contextSpan struct {
+	NoopSpan
+	sc core.SpanContext
+}
+
+func (s contextSpan) SpanContext() core.SpanContext {
+	return s.sc
+}
+
+func TestCacheKey(t *testing.T) {
+	tenant := key.New("tenant")
+	withSpan := func(ctx context.Context, low uint64) context.Context {
+		return SetCurrentSpan(ctx, contextSpan{sc: core.SpanContext{
+			TraceID: core.TraceID{High: 1, Low: low},
+			SpanID:  low,
+		}})
+	}
+	withTenant := func(ctx context.Context, v string) context.Context {
+		return tag.WithMap(ctx, tag.NewMap(tag.MapUpdate{SingleKV: tenant.String(v)}))
+	}
+	mustKey := func(ctx context.Context) string {
+		t.Helper()
+		k, ok := CacheKey(ctx, tenant)
+		if !ok {
+			t.Fatal("CacheKey returned false for a valid span context")
+		}
+		return k
+	}
+
+	a := mustKey(withSpan(withTenant(context.Background(), "a"), 2))
+	if b := mustKey(withSpan(withTenant(context.Background(), "a"), 2)); a != b {
+		t.Errorf("same trace and tenant: keys %q and %q differ", a, b)
+	}
+	if b := mustKey(withSpan(withTenant(context.Background(), "b"), 2)); a == b {
+		t.Errorf("different tenants: keys are both %q", a)
+	}
+	if b := mustKey(withSpan(withTenant(context.Background(), "a"), 3)); a == b {
+		t.Errorf("different traces: keys are both %q", a)
+	}
+
+	if _, ok := CacheKey(context.Background(), tenant); ok {
+		t.Error("CacheKey returned true without a span")
+	}
+}
@@ -28,6 +28,13 @@ func SetCurrentSpan(ctx context.Context, span Span) context.Context {
 	return context.WithValue(ctx, currentSpanKey, span)
 }
 
+// CurrentSpanKey returns the context key SetCurrentSpan stores the span
+// under. Implementations returning contexts of their own from Start
+// answer it from Value, instead of calling SetCurrentSpan.
+func CurrentSpanKey() interface{} {
+	return currentSpanKey
+}
+
 func CurrentSpan(ctx context.Context) Span {
 	if span, has := ctx.Value(currentSpanKey).(Span); has {
 		return span
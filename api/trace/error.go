@@ -0,0 +1,197 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+// ExceptionEvent is the message of events recorded by RecordError.
+const ExceptionEvent = "exception"
+
+// DefaultStackDepth is the number of frames recorded by WithStackTrace
+// when a non-positive depth is given.
+const DefaultStackDepth = 32
+
+var (
+	ExceptionTypeKey       = key.New("exception.type")
+	ExceptionMessageKey    = key.New("exception.message")
+	ExceptionStacktraceKey = key.New("exception.stacktrace")
+)
+
+// ErrorOption apply changes to ErrorOptions.
+type ErrorOption func(*ErrorOptions)
+
+// ErrorOptions provides options to control how an error is recorded
+// on a span.
+type ErrorOptions struct {
+	// StackDepth is the maximum number of frames in the recorded
+	// stack trace. No stack trace is recorded when it is zero.
+	StackDepth int
+
+	// FrameFilter reports whether a frame is included in the stack
+	// trace. All frames are included when it is nil.
+	FrameFilter func(runtime.Frame) bool
+
+	// Skip is the number of additional callers to skip, for use by
+	// helpers that wrap RecordError.
+	Skip int
+}
+
+// WithStackTrace attaches a stack trace of at most depth frames, taken
+// from the caller of RecordError, as the exception.stacktrace attribute.
+func WithStackTrace(depth int) ErrorOption {
+	return func(o *ErrorOptions) {
+		if depth <= 0 {
+			depth = DefaultStackDepth
+		}
+		o.StackDepth = depth
+	}
+}
+
+// WithFrameFilter only includes frames for which keep returns true in the
+// recorded stack trace, e.g., to drop runtime or vendored frames.
+func WithFrameFilter(keep func(runtime.Frame) bool) ErrorOption {
+	return func(o *ErrorOptions) {
+		o.FrameFilter = keep
+	}
+}
+
+// WithCallerSkip skips n additional callers when capturing the stack
+// trace.
+func WithCallerSkip(n int) ErrorOption {
+	return func(o *ErrorOptions) {
+		o.Skip = n
+	}
+}
+
+// RecordError records err as an exception event on span. The event
+// carries the type and message of the error and, when requested with
+// WithStackTrace, a formatted stack trace.
+func RecordError(ctx context.Context, span Span, err error, opts ...ErrorOption) {
+	recordError(ctx, span, err, false, opts)
+}
+
+// RecordPanic records a value recovered from a panic as an exception
+// event on span, with a stack trace that starts at the frame that
+// panicked. It is meant to be called by deferred functions, such as the
+// Finish method of spans, with the result of recover. The stack trace
+// has DefaultStackDepth frames unless WithStackTrace says otherwise.
+func RecordPanic(ctx context.Context, span Span, recovered interface{}, opts ...ErrorOption) {
+	if recovered == nil {
+		return
+	}
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+	opts = append([]ErrorOption{WithStackTrace(DefaultStackDepth)}, opts...)
+	recordError(ctx, span, err, true, opts)
+}
+
+func recordError(ctx context.Context, span Span, err error, panicked bool, opts []ErrorOption) {
+	if err == nil || !span.IsRecordingEvents() {
+		return
+	}
+	o := &ErrorOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	attrs := []core.KeyValue{
+		ExceptionTypeKey.String(fmt.Sprintf("%T", err)),
+		ExceptionMessageKey.String(err.Error()),
+	}
+	if o.StackDepth > 0 {
+		// Skip runtime.Callers, callers, recordError and its caller.
+		frames := callers(4 + o.Skip)
+		if panicked {
+			frames = panicFrames(frames)
+		}
+		attrs = append(attrs, ExceptionStacktraceKey.String(
+			stackTrace(frames, o.StackDepth, o.FrameFilter),
+		))
+	}
+	span.Event(ctx, ExceptionEvent, attrs...)
+}
+
+// callers returns the frames of the current goroutine's stack, skipping
+// skip frames as runtime.Callers does.
+func callers(skip int) []runtime.Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip, pcs)
+	for n == len(pcs) {
+		pcs = make([]uintptr, 2*len(pcs))
+		n = runtime.Callers(skip, pcs)
+	}
+
+	var frames []runtime.Frame
+	it := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := it.Next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
+
+// panicFrames returns the frames below the deferred call that recovered
+// a panic, starting at the frame that panicked. The frames of the runtime
+// raising the panic, such as runtime.panicmem for a nil dereference, are
+// dropped as well. frames are returned unchanged if they hold no panic.
+func panicFrames(frames []runtime.Frame) []runtime.Frame {
+	for i, frame := range frames {
+		if frame.Function != "runtime.gopanic" {
+			continue
+		}
+		for _, frame := range frames[i+1:] {
+			if !strings.HasPrefix(frame.Function, "runtime.") {
+				break
+			}
+			i++
+		}
+		return frames[i+1:]
+	}
+	return frames
+}
+
+// stackTrace formats up to depth frames in the style of
+// runtime/debug.Stack, noting how many were left out if there are more.
+func stackTrace(frames []runtime.Frame, depth int, keep func(runtime.Frame) bool) string {
+	var buf strings.Builder
+	written, elided := 0, 0
+	for _, frame := range frames {
+		if keep != nil && !keep(frame) {
+			continue
+		}
+		if written == depth {
+			elided++
+			continue
+		}
+		fmt.Fprintf(&buf, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		written++
+	}
+	if elided > 0 {
+		fmt.Fprintf(&buf, "...%d frames elided...\n", elided)
+	}
+	return buf.String()
+}
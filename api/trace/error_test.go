@@ -0,0 +1,169 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+)
+
+type eventSpan struct {
+	NoopSpan
+	msg   string
+	attrs []core.KeyValue
+}
+
+func (s *eventSpan) IsRecordingEvents() bool {
+	return true
+}
+
+func (s *eventSpan) Event(ctx context.Context, msg string, attrs ...core.KeyValue) {
+	s.msg = msg
+	s.attrs = attrs
+}
+
+func (s *eventSpan) attr(k core.Key) (string, bool) {
+	for _, kv := range s.attrs {
+		if kv.Key == k {
+			return kv.Value.String, true
+		}
+	}
+	return "", false
+}
+
+func TestRecordError(t *testing.T) {
+	span := &eventSpan{}
+	RecordError(context.Background(), span, errors.New("boom"))
+
+	if span.msg != ExceptionEvent {
+		t.Errorf("event message = %q; want %q", span.msg, ExceptionEvent)
+	}
+	if got, _ := span.attr(ExceptionMessageKey); got != "boom" {
+		t.Errorf("exception.message = %q; want %q", got, "boom")
+	}
+	if got, _ := span.attr(ExceptionTypeKey); got != "*errors.errorString" {
+		t.Errorf("exception.type = %q; want %q", got, "*errors.errorString")
+	}
+	if _, ok := span.attr(ExceptionStacktraceKey); ok {
+		t.Error("exception.stacktrace recorded without WithStackTrace")
+	}
+}
+
+func TestRecordErrorStackTrace(t *testing.T) {
+	span := &eventSpan{}
+	RecordError(context.Background(), span, errors.New("boom"), WithStackTrace(1))
+
+	got, ok := span.attr(ExceptionStacktraceKey)
+	if !ok {
+		t.Fatal("exception.stacktrace not recorded")
+	}
+	if !strings.HasPrefix(got, "go.opentelemetry.io/api/trace.TestRecordErrorStackTrace\n") {
+		t.Errorf("exception.stacktrace = %q; want caller as first frame", got)
+	}
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[2], "frames elided...") {
+		t.Errorf("exception.stacktrace = %q; want one frame and a note of the elided ones", got)
+	}
+}
+
+func TestRecordErrorFrameFilter(t *testing.T) {
+	span := &eventSpan{}
+	RecordError(context.Background(), span, errors.New("boom"),
+		WithStackTrace(0),
+		WithFrameFilter(func(f runtime.Frame) bool {
+			return !strings.HasPrefix(f.Function, "go.opentelemetry.io/")
+		}),
+	)
+
+	got, _ := span.attr(ExceptionStacktraceKey)
+	if strings.Contains(got, "go.opentelemetry.io/") {
+		t.Errorf("exception.stacktrace = %q; want filtered frames", got)
+	}
+}
+
+func TestRecordErrorDeepStack(t *testing.T) {
+	span := &eventSpan{}
+	var recurse func(n int)
+	recurse = func(n int) {
+		if n == 0 {
+			RecordError(context.Background(), span, errors.New("boom"), WithStackTrace(200))
+			return
+		}
+		recurse(n - 1)
+	}
+	recurse(100)
+
+	got, _ := span.attr(ExceptionStacktraceKey)
+	if n := strings.Count(got, "TestRecordErrorDeepStack.func1\n"); n != 101 {
+		t.Errorf("exception.stacktrace has %d recursive frames; want 101", n)
+	}
+	if strings.Contains(got, "elided") {
+		t.Errorf("exception.stacktrace = %q; want no elided frames", got)
+	}
+}
+
+//go:noinline
+func dereference(p *int) int {
+	return *p
+}
+
+func TestRecordPanic(t *testing.T) {
+	span := &eventSpan{}
+	func() {
+		defer func() {
+			RecordPanic(context.Background(), span, recover())
+		}()
+		dereference(nil)
+	}()
+
+	if got, _ := span.attr(ExceptionTypeKey); got != "runtime.errorString" {
+		t.Errorf("exception.type = %q; want %q", got, "runtime.errorString")
+	}
+	got, _ := span.attr(ExceptionStacktraceKey)
+	if !strings.HasPrefix(got, "go.opentelemetry.io/api/trace.dereference\n") {
+		t.Errorf("exception.stacktrace = %q; want the panicking frame first", got)
+	}
+}
+
+func TestRecordPanicValue(t *testing.T) {
+	span := &eventSpan{}
+	func() {
+		defer func() {
+			RecordPanic(context.Background(), span, recover())
+		}()
+		panic("boom")
+	}()
+
+	if got, _ := span.attr(ExceptionMessageKey); got != "boom" {
+		t.Errorf("exception.message = %q; want %q", got, "boom")
+	}
+	got, _ := span.attr(ExceptionStacktraceKey)
+	if !strings.HasPrefix(got, "go.opentelemetry.io/api/trace.TestRecordPanicValue.func1\n") {
+		t.Errorf("exception.stacktrace = %q; want the panicking frame first", got)
+	}
+}
+
+func TestRecordPanicNil(t *testing.T) {
+	span := &eventSpan{}
+	RecordPanic(context.Background(), span, nil)
+	if span.msg != "" {
+		t.Errorf("recorded event %q without a panic", span.msg)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+// RelationshipKey is the attribute labeling how a linked span relates to
+// the span linking it. Exporters map it to the reference types of their
+// backends where they have some.
+var RelationshipKey = key.New("relationship")
+
+// Conventional values of RelationshipKey.
+const (
+	// RelationshipFollowsFrom labels a span that was caused by the linked
+	// span but does not block it, like FollowsFrom.
+	RelationshipFollowsFrom = "follows_from"
+
+	// RelationshipBatchedFrom labels a linked span whose work is part of
+	// the batch processed by the span linking it.
+	RelationshipBatchedFrom = "batched_from"
+)
+
+// Link associates a span with another span, of the same or of another
+// trace, that is related to it without being its parent, e.g., a batch
+// job with each of the requests it processes.
+type Link struct {
+	core.SpanContext
+	Attributes []core.KeyValue
+}
+
+// NewLink returns a link to sc labeled with relationship, which is
+// usually one of the Relationship constants, followed by attrs.
+func NewLink(sc core.SpanContext, relationship string, attrs ...core.KeyValue) Link {
+	return Link{
+		SpanContext: sc,
+		Attributes:  append([]core.KeyValue{RelationshipKey.String(relationship)}, attrs...),
+	}
+}
+
+// Relationship returns the value of the RelationshipKey attribute of l,
+// or "" if it has none.
+func (l Link) Relationship() string {
+	for _, kv := range l.Attributes {
+		if kv.Key.Variable.Name == RelationshipKey.Variable.Name {
+			return kv.Value.String
+		}
+	}
+	return ""
+}
+
+// LinkAdder is implemented by spans that can be linked to other spans
+// after they started.
+type LinkAdder interface {
+	// AddLink links the span to the span of link.SpanContext.
+	AddLink(link Link)
+}
+
+// AddLink links span to the span of link.SpanContext. It does nothing for
+// spans that do not implement LinkAdder.
+func AddLink(span Span, link Link) {
+	if la, ok := span.(LinkAdder); ok {
+		la.AddLink(link)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+func TestLinkRelationship(t *testing.T) {
+	sc := core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 2}
+	l := NewLink(sc, RelationshipBatchedFrom, key.New("queue").String("orders"))
+	if got := l.Relationship(); got != RelationshipBatchedFrom {
+		t.Errorf("Relationship() = %q, want %q", got, RelationshipBatchedFrom)
+	}
+	if l.SpanContext != sc || len(l.Attributes) != 2 {
+		t.Errorf("got link %+v, want the context and both attributes", l)
+	}
+	if got := (Link{SpanContext: sc}).Relationship(); got != "" {
+		t.Errorf("Relationship() of an unlabeled link = %q, want empty", got)
+	}
+}
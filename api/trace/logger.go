@@ -0,0 +1,94 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+var (
+	// LogTraceIDKey and LogSpanIDKey are the keys of the fields of the
+	// loggers returned by LoggerFromContext.
+	LogTraceIDKey = key.New("trace_id")
+	LogSpanIDKey  = key.New("span_id")
+)
+
+// Logger emits application log records. It must be safe for concurrent
+// use.
+type Logger interface {
+	Log(msg string, fields ...core.KeyValue)
+}
+
+// LoggerBackend returns a Logger adding fields to each record, e.g., a
+// logger of a structured logging library with the fields bound.
+type LoggerBackend func(fields []core.KeyValue) Logger
+
+// loggerBackendHolder keeps the concrete type stored in loggerBackend
+// constant.
+type loggerBackendHolder struct {
+	b LoggerBackend
+}
+
+var loggerBackend atomic.Value // loggerBackendHolder
+
+// SetLoggerBackend sets the backend of the loggers returned by
+// LoggerFromContext. The default backend, restored by
+// SetLoggerBackend(nil), writes records with the standard logger, e.g.,
+//
+//	payment declined trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 reason=expired
+func SetLoggerBackend(b LoggerBackend) {
+	loggerBackend.Store(loggerBackendHolder{b})
+}
+
+// LoggerFromContext returns a logger whose records carry the trace ID and
+// span ID of the current span of ctx, so that logs can be correlated with
+// traces. Records carry no IDs when ctx has no valid span context.
+func LoggerFromContext(ctx context.Context) Logger {
+	var fields []core.KeyValue
+	if sc := CurrentSpan(ctx).SpanContext(); sc.IsValid() {
+		fields = []core.KeyValue{
+			LogTraceIDKey.String(sc.TraceIDString()),
+			LogSpanIDKey.String(sc.SpanIDString()),
+		}
+	}
+	if h, ok := loggerBackend.Load().(loggerBackendHolder); ok && h.b != nil {
+		return h.b(fields)
+	}
+	return stdLogger(fields)
+}
+
+// stdLogger writes records with the standard logger as the message
+// followed by key=value fields.
+type stdLogger []core.KeyValue
+
+func (l stdLogger) Log(msg string, fields ...core.KeyValue) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, fs := range [][]core.KeyValue{l, fields} {
+		for _, kv := range fs {
+			b.WriteByte(' ')
+			b.WriteString(kv.Key.Variable.Name)
+			b.WriteByte('=')
+			b.WriteString(kv.Value.Emit())
+		}
+	}
+	log.Print(b.String())
+}
@@ -0,0 +1,82 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+type recordingLogger struct {
+	fields  []core.KeyValue
+	records *[]string
+}
+
+func (l recordingLogger) Log(msg string, fields ...core.KeyValue) {
+	for _, kv := range append(l.fields, fields...) {
+		msg += " " + kv.Key.Variable.Name + "=" + kv.Value.Emit()
+	}
+	*l.records = append(*l.records, msg)
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	var records []string
+	SetLoggerBackend(func(fields []core.KeyValue) Logger {
+		return recordingLogger{fields: fields, records: &records}
+	})
+	defer SetLoggerBackend(nil)
+
+	ctx := SetCurrentSpan(context.Background(), contextSpan{sc: core.SpanContext{
+		TraceID: core.TraceID{High: 1, Low: 2},
+		SpanID:  3,
+	}})
+	LoggerFromContext(ctx).Log("declined", key.New("reason").String("expired"))
+	LoggerFromContext(context.Background()).Log("started")
+
+	want := []string{
+		"declined trace_id=00000000000000010000000000000002 span_id=0000000000000003 reason=expired",
+		"started",
+	}
+	if strings.Join(records, "\n") != strings.Join(want, "\n") {
+		t.Errorf("logged %q; want %q", records, want)
+	}
+}
+
+func TestLoggerFromContextDefault(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	flags := log.Flags()
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(flags)
+	}()
+
+	ctx := SetCurrentSpan(context.Background(), contextSpan{sc: core.SpanContext{
+		TraceID: core.TraceID{Low: 1},
+		SpanID:  2,
+	}})
+	LoggerFromContext(ctx).Log("hello")
+	if got, want := buf.String(), "hello trace_id=00000000000000000000000000000001 span_id=0000000000000002\n"; got != want {
+		t.Errorf("logged %q; want %q", got, want)
+	}
+}
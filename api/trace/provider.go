@@ -0,0 +1,106 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import "sync/atomic"
+
+// Provider hands out tracers named after the instrumentation library
+// using them, e.g., "go.opentelemetry.io/plugin/othttp", so that backends
+// can tell which library recorded a span.
+type Provider interface {
+	// Tracer returns the tracer of the named library. An empty name is
+	// the tracer of the application itself.
+	Tracer(name string, opts ...TracerOption) Tracer
+}
+
+// TracerConfig holds the options of a tracer requested from a Provider.
+type TracerConfig struct {
+	// Version is the version of the instrumentation library.
+	Version string
+
+	// SchemaURL identifies the version of the semantic conventions
+	// the attributes of the library follow, e.g.,
+	// "https://opentelemetry.io/schemas/1.4.0".
+	SchemaURL string
+}
+
+// TracerOption sets an option of a tracer requested from a Provider.
+type TracerOption func(*TracerConfig)
+
+// WithInstrumentationVersion sets the version of the instrumentation
+// library.
+func WithInstrumentationVersion(version string) TracerOption {
+	return func(c *TracerConfig) {
+		c.Version = version
+	}
+}
+
+// WithSchemaURL sets the URL of the schema of the semantic conventions
+// the instrumentation library follows.
+func WithSchemaURL(url string) TracerOption {
+	return func(c *TracerConfig) {
+		c.SchemaURL = url
+	}
+}
+
+// NewTracerConfig applies opts to a zero TracerConfig.
+func NewTracerConfig(opts ...TracerOption) TracerConfig {
+	var c TracerConfig
+	for _, o := range opts {
+		o(&c)
+	}
+	return c
+}
+
+// NoopProvider hands out NoopTracers.
+type NoopProvider struct{}
+
+var _ Provider = NoopProvider{}
+
+// Tracer returns a NoopTracer.
+func (NoopProvider) Tracer(name string, opts ...TracerOption) Tracer {
+	return NoopTracer{}
+}
+
+// globalProvider is the Provider used until one is set. Its tracers are
+// the global tracer.
+type globalProvider struct{}
+
+func (globalProvider) Tracer(name string, opts ...TracerOption) Tracer {
+	return GlobalTracer()
+}
+
+// providerHolder keeps the concrete type stored in provider constant.
+type providerHolder struct {
+	p Provider
+}
+
+var provider atomic.Value // providerHolder
+
+// GlobalProvider returns the Provider set with SetGlobalProvider. Until
+// one is set, all its tracers are GlobalTracer.
+func GlobalProvider() Provider {
+	if h, ok := provider.Load().(providerHolder); ok {
+		return h.p
+	}
+	return globalProvider{}
+}
+
+// SetGlobalProvider sets p as the global Provider, and its unnamed tracer
+// as the global tracer.
+func SetGlobalProvider(p Provider) {
+	provider.Store(providerHolder{p})
+	SetGlobalTracer(p.Tracer(""))
+}
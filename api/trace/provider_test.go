@@ -0,0 +1,26 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import "testing"
+
+func TestGlobalProviderDefault(t *testing.T) {
+	if _, ok := GlobalProvider().Tracer("lib").(NoopTracer); !ok {
+		t.Errorf("Tracer of the default provider = %T, want the global NoopTracer", GlobalProvider().Tracer("lib"))
+	}
+	if got := NewTracerConfig(WithInstrumentationVersion("1.2")).Version; got != "1.2" {
+		t.Errorf("Version = %q, want 1.2", got)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// StatusMessageSetter is implemented by spans that can record a
+// description of their status along with its code.
+type StatusMessageSetter interface {
+	// SetStatusWithMessage sets the status of the span to code,
+	// described by message.
+	SetStatusWithMessage(code codes.Code, message string)
+}
+
+// SetStatusWithMessage sets the status of span to code, described by
+// message. The message is dropped for spans that do not implement
+// StatusMessageSetter.
+func SetStatusWithMessage(span Span, code codes.Code, message string) {
+	if sms, ok := span.(StatusMessageSetter); ok {
+		sms.SetStatusWithMessage(code, message)
+		return
+	}
+	span.SetStatus(code)
+}
+
+// StatusClass groups status codes by who is responsible for a failure,
+// so that backends can tell failed requests from failed servers.
+type StatusClass int
+
+const (
+	// StatusClassOK is the class of codes.OK.
+	StatusClassOK StatusClass = iota
+
+	// StatusClassClientError is the class of codes reporting a problem
+	// with the request, which would fail again if retried as is.
+	StatusClassClientError
+
+	// StatusClassServerError is the class of all other codes, which
+	// report a problem while serving the request.
+	StatusClassServerError
+)
+
+// ClassOf returns the class of code.
+func ClassOf(code codes.Code) StatusClass {
+	switch code {
+	case codes.OK:
+		return StatusClassOK
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.PermissionDenied,
+		codes.FailedPrecondition, codes.OutOfRange, codes.Unauthenticated:
+		return StatusClassClientError
+	default:
+		return StatusClassServerError
+	}
+}
+
+func (c StatusClass) String() string {
+	switch c {
+	case StatusClassOK:
+		return "ok"
+	case StatusClassClientError:
+		return "client_error"
+	default:
+		return "server_error"
+	}
+}
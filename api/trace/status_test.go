@@ -0,0 +1,57 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestClassOf(t *testing.T) {
+	for _, tt := range []struct {
+		code codes.Code
+		want StatusClass
+	}{
+		{codes.OK, StatusClassOK},
+		{codes.InvalidArgument, StatusClassClientError},
+		{codes.NotFound, StatusClassClientError},
+		{codes.Unauthenticated, StatusClassClientError},
+		{codes.Internal, StatusClassServerError},
+		{codes.Unavailable, StatusClassServerError},
+		{codes.Unknown, StatusClassServerError},
+	} {
+		if got := ClassOf(tt.code); got != tt.want {
+			t.Errorf("ClassOf(%v) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+type statusSpan struct {
+	NoopSpan
+	code codes.Code
+}
+
+func (s *statusSpan) SetStatus(code codes.Code) {
+	s.code = code
+}
+
+func TestSetStatusWithMessageFallback(t *testing.T) {
+	s := &statusSpan{}
+	SetStatusWithMessage(s, codes.Unavailable, "backend down")
+	if s.code != codes.Unavailable {
+		t.Errorf("got status %v, want %v", s.code, codes.Unavailable)
+	}
+}
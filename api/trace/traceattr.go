@@ -0,0 +1,85 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/tag"
+)
+
+const (
+	// MaxTraceAttributes is the maximum number of entries in the
+	// propagated tag map. It matches the W3C tracestate member limit.
+	MaxTraceAttributes = 32
+
+	// MaxTraceAttributesSize is the maximum combined length of the keys
+	// and values in the propagated tag map. It matches the W3C
+	// tracestate header length limit.
+	MaxTraceAttributesSize = 512
+)
+
+// ErrTraceAttributesLimit is returned by WithTraceAttributes when some
+// attributes were dropped because they exceed the propagation limits.
+var ErrTraceAttributesLimit = errors.New("trace attributes exceed propagation limits")
+
+// WithTraceAttributes sets attributes that apply to the whole trace, such
+// as an experiment ID. The attributes are set on the current span, which
+// is expected to be the root span, and added to the tag map of the
+// returned context, so they are propagated to every child span, including
+// remote children through an Injector.
+//
+// Attributes that would grow the tag map beyond MaxTraceAttributes entries
+// or MaxTraceAttributesSize bytes are dropped and ErrTraceAttributesLimit
+// is returned.
+func WithTraceAttributes(ctx context.Context, attrs ...core.KeyValue) (context.Context, error) {
+	m := tag.FromContext(ctx)
+	count, size := m.Len(), 0
+	m.Foreach(func(kv core.KeyValue) bool {
+		size += traceAttributeSize(kv)
+		return true
+	})
+
+	var err error
+	mutators := make([]tag.Mutator, 0, len(attrs))
+	accepted := make([]core.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		newCount, newSize := count, size+traceAttributeSize(kv)
+		if old, ok := m.Value(kv.Key); ok {
+			newSize -= traceAttributeSize(core.KeyValue{Key: kv.Key, Value: old})
+		} else {
+			newCount++
+		}
+		if newCount > MaxTraceAttributes || newSize > MaxTraceAttributesSize {
+			err = ErrTraceAttributesLimit
+			continue
+		}
+		count, size = newCount, newSize
+		mutators = append(mutators, tag.Upsert(kv).WithTTL(-1))
+		accepted = append(accepted, kv)
+	}
+	if len(accepted) == 0 {
+		return ctx, err
+	}
+
+	CurrentSpan(ctx).SetAttributes(accepted...)
+	return tag.NewContext(ctx, mutators...), err
+}
+
+func traceAttributeSize(kv core.KeyValue) int {
+	return len(kv.Key.Variable.Name) + len(kv.Value.Emit())
+}
@@ -0,0 +1,69 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+)
+
+func TestWithTraceAttributes(t *testing.T) {
+	experiment := key.New("experiment")
+	ctx, err := WithTraceAttributes(context.Background(), experiment.String("blue"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := tag.FromContext(ctx).Value(experiment); !ok || got.String != "blue" {
+		t.Errorf("tag value = %v, %v; want blue", got, ok)
+	}
+}
+
+func TestWithTraceAttributesCountLimit(t *testing.T) {
+	ctx := context.Background()
+	var err error
+	for i := 0; i < MaxTraceAttributes+1; i++ {
+		ctx, err = WithTraceAttributes(ctx, key.New(fmt.Sprint("k", i)).String("v"))
+	}
+	if err != ErrTraceAttributesLimit {
+		t.Errorf("got error %v; want %v", err, ErrTraceAttributesLimit)
+	}
+	if got, want := tag.FromContext(ctx).Len(), MaxTraceAttributes; got != want {
+		t.Errorf("got %d tags; want %d", got, want)
+	}
+}
+
+func TestWithTraceAttributesSizeLimit(t *testing.T) {
+	big := key.New("big")
+	small := key.New("small")
+	ctx, err := WithTraceAttributes(context.Background(),
+		big.String(strings.Repeat("x", MaxTraceAttributesSize)),
+		small.String("ok"),
+	)
+	if err != ErrTraceAttributesLimit {
+		t.Errorf("got error %v; want %v", err, ErrTraceAttributesLimit)
+	}
+	m := tag.FromContext(ctx)
+	if m.HasValue(big) {
+		t.Error("oversized attribute was propagated")
+	}
+	if !m.HasValue(small) {
+		t.Error("attribute within limits was not propagated")
+	}
+}
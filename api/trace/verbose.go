@@ -0,0 +1,40 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+// VerboseRecorder is implemented by spans that can tell whether their
+// sampler chose them for verbose recording.
+type VerboseRecorder interface {
+	// IsRecordingVerbose returns true if the span is recording events and
+	// was chosen for verbose recording.
+	IsRecordingVerbose() bool
+}
+
+// IsRecordingVerbose returns true if span was chosen by its sampler for
+// verbose recording. Instrumentation can use it to add expensive
+// attributes, such as request bodies, only to a small subset of sampled
+// spans:
+//
+//	if trace.IsRecordingVerbose(span) {
+//		span.SetAttribute(bodyKey.String(string(body)))
+//	}
+//
+// It returns false for spans that do not implement VerboseRecorder.
+func IsRecordingVerbose(span Span) bool {
+	if vr, ok := span.(VerboseRecorder); ok {
+		return vr.IsRecordingVerbose()
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import "testing"
+
+type verboseSpan struct {
+	NoopSpan
+	verbose bool
+}
+
+func (s verboseSpan) IsRecordingVerbose() bool {
+	return s.verbose
+}
+
+func TestIsRecordingVerbose(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		span Span
+		want bool
+	}{
+		{"noop", NoopSpan{}, false},
+		{"verbose", verboseSpan{verbose: true}, true},
+		{"not verbose", verboseSpan{}, false},
+	} {
+		if got := IsRecordingVerbose(tt.span); got != tt.want {
+			t.Errorf("%s: IsRecordingVerbose() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
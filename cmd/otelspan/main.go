@@ -0,0 +1,270 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command otelspan runs a command in a span, so that build scripts, cron
+// jobs and shell pipelines can take part in traces:
+//
+//	otelspan -name "nightly backup" -- tar czf /backup/home.tgz /home
+//
+// The span continues the trace of the TRACEPARENT, TRACESTATE and BAGGAGE
+// environment variables if they are set, and the command gets them set to
+// the span, so that nested otelspan invocations and traced programs join
+// the same trace. The span ends when the command exits and records its
+// exit code; otelspan exits with the same code.
+//
+// Spans are sent to an OTLP/HTTP collector, or written to standard output
+// as JSON lines with -exporter=stdout, after the output of the command.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/propagation"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/exporter/trace/otlp"
+	"go.opentelemetry.io/sdk/resource"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+var (
+	commandKey  = key.New("process.command")
+	argsKey     = key.New("process.command_args")
+	exitCodeKey = key.New("process.exit_code")
+)
+
+// propagator carries the trace through the environment.
+var propagator = propagation.Composite(propagation.TraceContext(), propagation.Baggage())
+
+// attrFlags collects the repeated -attr flags.
+type attrFlags []core.KeyValue
+
+func (a *attrFlags) String() string {
+	return fmt.Sprint(*a)
+}
+
+func (a *attrFlags) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return errors.New("want key=value")
+	}
+	*a = append(*a, key.New(kv[0]).String(kv[1]))
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs otelspan with args and returns its exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("otelspan", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: otelspan [flags] [--] command [args...]")
+		fs.PrintDefaults()
+	}
+	name := fs.String("name", "", "span name (default: the base name of the command)")
+	exporter := fs.String("exporter", "otlp", "where spans go: otlp or stdout")
+	endpoint := fs.String("endpoint", otlp.DefaultEndpoint, "host:port or unix:// path of the OTLP/HTTP collector")
+	service := fs.String("service", "", "service.name of the resource (default: "+resource.EnvServiceName+" or otelspan)")
+	var attrs attrFlags
+	fs.Var(&attrs, "attr", "key=value attribute of the span; can be repeated")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	argv := fs.Args()
+	if *name == "" {
+		*name = filepath.Base(argv[0])
+	}
+
+	var e trace.SpanExporter
+	switch *exporter {
+	case "otlp":
+		e = otlp.NewExporter(otlp.WithEndpoint(*endpoint))
+	case "stdout":
+		e = jsonExporter{json.NewEncoder(stdout)}
+	default:
+		fmt.Fprintf(stderr, "otelspan: unknown exporter %q\n", *exporter)
+		return 2
+	}
+
+	ctx := context.Background()
+	res, err := resource.Detect(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "otelspan:", err)
+	}
+	if *service != "" {
+		res = resource.Merge(resource.New(resource.ServiceNameKey.String(*service)), res)
+	} else if _, ok := res.Value(resource.ServiceNameKey); !ok {
+		res = resource.Merge(res, resource.New(resource.ServiceNameKey.String("otelspan")))
+	}
+	trace.ApplyConfig(trace.Config{
+		DefaultSampler: trace.ParentBased(trace.AlwaysSample()),
+		Resource:       res,
+	})
+	p := trace.NewSimpleSpanProcessor(e)
+	trace.RegisterSpanProcessor(p)
+	defer trace.UnregisterSpanProcessor(p)
+
+	ctx, opts := propagation.ExtractEnv(ctx, propagator)
+	opts = append(opts, apitrace.WithAttributes(append([]core.KeyValue{
+		commandKey.String(argv[0]),
+		argsKey.String(strings.Join(argv[1:], " ")),
+	}, attrs...)...))
+	ctx, span := trace.Register().Start(ctx, *name, opts...)
+	defer span.Finish()
+
+	code, err := runCommand(ctx, argv, stdin, stdout, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "otelspan:", err)
+		apitrace.RecordError(ctx, span, err)
+		span.SetStatus(codes.Unknown)
+		return code
+	}
+	span.SetAttribute(exitCodeKey.Int(code))
+	if code != 0 {
+		span.SetStatus(codes.Unknown)
+	}
+	return code
+}
+
+// runCommand runs argv with the trace of ctx in its environment and
+// returns its exit code. Interrupts received meanwhile are passed on to
+// the command. The code is 127 if the command could not be started.
+func runCommand(ctx context.Context, argv []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Env = propagation.InjectEnv(ctx, propagator, os.Environ())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+	if err := cmd.Start(); err != nil {
+		return 127, err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				_ = cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// jsonSpan is the JSON form of a span written by -exporter=stdout.
+type jsonSpan struct {
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	Name         string                 `json:"name"`
+	StartTime    string                 `json:"start_time"`
+	EndTime      string                 `json:"end_time"`
+	Status       string                 `json:"status"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Resource     map[string]string      `json:"resource,omitempty"`
+}
+
+// jsonExporter writes spans as JSON lines.
+type jsonExporter struct {
+	enc *json.Encoder
+}
+
+func (e jsonExporter) ExportSpans(ctx context.Context, spans []*trace.SpanData) error {
+	for _, sd := range spans {
+		js := jsonSpan{
+			TraceID:   sd.SpanContext.TraceIDString(),
+			SpanID:    sd.SpanContext.SpanIDString(),
+			Name:      sd.Name,
+			StartTime: sd.StartTime.Format("2006-01-02T15:04:05.000000000Z07:00"),
+			EndTime:   sd.EndTime.Format("2006-01-02T15:04:05.000000000Z07:00"),
+			Status:    sd.Status.String(),
+		}
+		if len(sd.Attributes) > 0 {
+			js.Attributes = make(map[string]interface{}, len(sd.Attributes))
+			for k, v := range sd.Attributes {
+				js.Attributes[k] = jsonValue(v)
+			}
+		}
+		if sd.ParentSpanID != 0 {
+			js.ParentSpanID = fmt.Sprintf("%.16x", sd.ParentSpanID)
+		}
+		if len(sd.Resource) > 0 {
+			js.Resource = make(map[string]string, len(sd.Resource))
+			for _, kv := range sd.Resource {
+				js.Resource[kv.Key.Variable.Name] = kv.Value.Emit()
+			}
+		}
+		if err := e.enc.Encode(js); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonValue returns the value held by an attribute value v.
+func jsonValue(v interface{}) interface{} {
+	cv, ok := v.(core.Value)
+	if !ok {
+		return v
+	}
+	switch cv.Type {
+	case core.BOOL:
+		return cv.Bool
+	case core.INT32, core.INT64:
+		return cv.Int64
+	case core.UINT32, core.UINT64:
+		return cv.Uint64
+	case core.FLOAT32, core.FLOAT64:
+		return cv.Float64
+	case core.STRINGS, core.INT64S, core.FLOAT64S:
+		return cv.AsInterface()
+	}
+	return cv.Emit()
+}
@@ -0,0 +1,106 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestHelperProcess is the command run by the tests. It prints its
+// TRACEPARENT and exits with the code in OTELSPAN_EXIT_CODE.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("OTELSPAN_HELPER") != "1" {
+		return
+	}
+	fmt.Println(os.Getenv("TRACEPARENT"))
+	code := 0
+	fmt.Sscan(os.Getenv("OTELSPAN_EXIT_CODE"), &code)
+	os.Exit(code)
+}
+
+func runHelper(t *testing.T, exitCode int, args ...string) (int, string, jsonSpan) {
+	os.Setenv("OTELSPAN_HELPER", "1")
+	os.Setenv("OTELSPAN_EXIT_CODE", fmt.Sprint(exitCode))
+	defer os.Unsetenv("OTELSPAN_HELPER")
+	defer os.Unsetenv("OTELSPAN_EXIT_CODE")
+
+	var stdout, stderr bytes.Buffer
+	args = append(args, "-exporter=stdout", "-name=helper", "-attr=job=test", "--", os.Args[0], "-test.run=TestHelperProcess")
+	code := run(args, nil, &stdout, &stderr)
+
+	sc := bufio.NewScanner(&stdout)
+	var traceparent string
+	var span jsonSpan
+	if sc.Scan() {
+		traceparent = sc.Text()
+	}
+	for sc.Scan() {
+		if err := json.Unmarshal(sc.Bytes(), &span); err != nil {
+			t.Fatalf("decoding span %q: %v", sc.Text(), err)
+		}
+	}
+	return code, traceparent, span
+}
+
+func TestRun(t *testing.T) {
+	code, traceparent, span := runHelper(t, 0)
+	if code != 0 {
+		t.Errorf("exit code = %d, want 0", code)
+	}
+	if span.Name != "helper" || span.Status != "OK" || span.Attributes["job"] != "test" {
+		t.Errorf("got span %+v, want an OK helper span with job=test", span)
+	}
+	if want := "00-" + span.TraceID + "-" + span.SpanID + "-01"; traceparent != want {
+		t.Errorf("command TRACEPARENT = %q, want %q", traceparent, want)
+	}
+	if span.Resource["service.name"] != "otelspan" {
+		t.Errorf("service.name = %q, want otelspan", span.Resource["service.name"])
+	}
+}
+
+func TestRunContinuesTrace(t *testing.T) {
+	os.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	defer os.Unsetenv("TRACEPARENT")
+
+	code, _, span := runHelper(t, 3, "-service=nightly")
+	if code != 3 {
+		t.Errorf("exit code = %d, want 3", code)
+	}
+	if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || span.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("got span %+v, want a child of the TRACEPARENT span", span)
+	}
+	if span.Status == "OK" || span.Attributes["process.exit_code"] != float64(3) {
+		t.Errorf("got status %s and attributes %v, want a failed span with exit code 3", span.Status, span.Attributes)
+	}
+	if span.Resource["service.name"] != "nightly" {
+		t.Errorf("service.name = %q, want nightly", span.Resource["service.name"])
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run(nil, nil, &stderr, &stderr); code != 2 || !strings.Contains(stderr.String(), "usage:") {
+		t.Errorf("run without a command = %d, %q; want 2 and the usage", code, stderr.String())
+	}
+	if code := run([]string{"-exporter=none", "true"}, nil, &stderr, &stderr); code != 2 {
+		t.Errorf("run with an unknown exporter = %d, want 2", code)
+	}
+}
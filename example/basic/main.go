@@ -20,9 +20,9 @@ import (
 	"go.opentelemetry.io/api/key"
 	"go.opentelemetry.io/api/metric"
 	"go.opentelemetry.io/api/registry"
-	"go.opentelemetry.io/api/stats"
 	"go.opentelemetry.io/api/tag"
 	"go.opentelemetry.io/api/trace"
+	opentelemetry "go.opentelemetry.io/sdk"
 )
 
 var (
@@ -44,7 +44,7 @@ var (
 		metric.WithDescription("A gauge set to 1.0"),
 	)
 
-	measureTwo = stats.NewMeasure("ex.com/two")
+	measureTwo = metric.NewFloat64Measure("ex.com/two")
 )
 
 func main() {
@@ -77,7 +77,7 @@ func main() {
 
 				trace.CurrentSpan(ctx).Event(ctx, "Sub span event")
 
-				stats.Record(ctx, measureTwo.M(1.3))
+				meter.RecordBatch(ctx, nil, measureTwo.M(1.3))
 
 				return nil
 			},
@@ -87,6 +87,8 @@ func main() {
 		panic(err)
 	}
 
-	// TODO: How to flush?
-	// loader.Flush()
+	// Flush the telemetry buffered by the SDK loaded, if any.
+	if err := opentelemetry.Shutdown(ctx); err != nil {
+		panic(err)
+	}
 }
@@ -20,12 +20,11 @@ import (
 	"io/ioutil"
 	"net/http"
 
-	"google.golang.org/grpc/codes"
-
 	"go.opentelemetry.io/api/key"
 	"go.opentelemetry.io/api/tag"
 	"go.opentelemetry.io/api/trace"
 	"go.opentelemetry.io/plugin/httptrace"
+	"go.opentelemetry.io/plugin/othttp"
 )
 
 var (
@@ -60,7 +59,7 @@ func main() {
 			}
 			body, err = ioutil.ReadAll(res.Body)
 			res.Body.Close()
-			trace.CurrentSpan(ctx).SetStatus(codes.OK)
+			othttp.ClientStatus(trace.CurrentSpan(ctx), res.StatusCode)
 
 			return err
 		})
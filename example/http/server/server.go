@@ -49,6 +49,8 @@ func main() {
 		)
 		defer span.Finish()
 
+		httptrace.InjectResponse(span.SpanContext(), w.Header())
+
 		span.Event(ctx, "handling this...")
 
 		_, _ = io.WriteString(w, "Hello, world!\n")
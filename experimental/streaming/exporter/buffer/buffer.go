@@ -15,25 +15,38 @@
 package buffer
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 
+	"go.opentelemetry.io/api/logger"
 	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+	"go.opentelemetry.io/sdk/memlimit"
 )
 
 type Buffer struct {
 	observers []observer.Observer
 	events    chan observer.Event
+	flush     chan chan struct{}
 	dropped   uint64
 	wait      sync.WaitGroup
 	close     chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
 }
 
+var (
+	_ observer.Flusher    = (*Buffer)(nil)
+	_ observer.Shutdowner = (*Buffer)(nil)
+)
+
 func NewBuffer(size int, observers ...observer.Observer) *Buffer {
 	b := &Buffer{
 		observers: observers,
 		events:    make(chan observer.Event, size),
+		flush:     make(chan chan struct{}),
 		close:     make(chan struct{}),
+		done:      make(chan struct{}),
 	}
 	b.wait.Add(1)
 	go b.run()
@@ -41,34 +54,136 @@ func NewBuffer(size int, observers ...observer.Observer) *Buffer {
 }
 
 func (b *Buffer) Observe(data observer.Event) {
+	size := eventSize(data)
+	if !memlimit.Reserve(priority(data), size) {
+		atomic.AddUint64(&b.dropped, 1)
+		logger.Debugf("buffer: dropped %v event: memory budget exceeded", data.Type)
+		return
+	}
 	select {
 	case b.events <- data:
 	default:
+		memlimit.Release(size)
 		atomic.AddUint64(&b.dropped, 1)
+		logger.Debugf("buffer: dropped %v event: buffer is full", data.Type)
+	}
+}
+
+// priority returns the memlimit priority of the data of ev.
+func priority(ev observer.Event) memlimit.Priority {
+	switch ev.Type {
+	case observer.ADD_EVENT, observer.ADD_EVENTF:
+		return memlimit.Event
+	case observer.NEW_MEASURE, observer.NEW_METRIC, observer.RECORD_STATS:
+		return memlimit.Metric
+	}
+	if !ev.Scope.SpanContext.IsSampled() {
+		return memlimit.UnsampledSpan
 	}
+	return memlimit.SampledSpan
+}
+
+// eventSize returns the approximate size of ev, in bytes.
+func eventSize(ev observer.Event) int64 {
+	n := 256 + len(ev.String)
+	n += 64 * (len(ev.Attributes) + len(ev.Mutators) + len(ev.Stats))
+	return int64(n)
 }
 
+// Flush implements observer.Flusher. It returns once the observers have
+// received the events buffered before, and flushed them if they are
+// Flushers.
+func (b *Buffer) Flush() {
+	flushed := make(chan struct{})
+	select {
+	case b.flush <- flushed:
+		<-flushed
+	case <-b.done:
+		return
+	}
+	for _, obs := range b.observers {
+		if f, ok := obs.(observer.Flusher); ok {
+			f.Flush()
+		}
+	}
+}
+
+// Close stops the buffer once the observers have received the events
+// buffered. It is safe to call more than once.
 func (b *Buffer) Close() {
-	close(b.close)
+	b.closeOnce.Do(func() { close(b.close) })
 	b.wait.Wait()
 }
 
+// Shutdown implements observer.Shutdowner. It closes the buffer, then
+// shuts down the observers that are Shutdowners and flushes the other
+// Flushers. If ctx is done before the buffer is closed, it returns the
+// error of ctx.
+func (b *Buffer) Shutdown(ctx context.Context) error {
+	closed := make(chan struct{})
+	go func() {
+		b.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	var err error
+	for _, obs := range b.observers {
+		switch obs := obs.(type) {
+		case observer.Shutdowner:
+			if e := obs.Shutdown(ctx); e != nil && err == nil {
+				err = e
+			}
+		case observer.Flusher:
+			obs.Flush()
+		}
+	}
+	return err
+}
+
 func (b *Buffer) run() {
 	defer func() {
-		_ = recover()
+		if r := recover(); r != nil {
+			logger.Errorf("buffer: observer panicked, no more events are delivered: %v", r)
+		}
+		close(b.done)
 		b.wait.Done()
 	}()
 
 	for {
 		select {
 		case <-b.close:
+			b.drain()
 			return
+		case flushed := <-b.flush:
+			b.drain()
+			close(flushed)
 		case ev := <-b.events:
-			// TODO: This has to ensure ordered arrival,
-			// e.g., put into a heap and delay observations.
-			for _, obs := range b.observers {
-				obs.Observe(ev)
-			}
+			b.deliver(ev)
+		}
+	}
+}
+
+// drain delivers the buffered events.
+func (b *Buffer) drain() {
+	for {
+		select {
+		case ev := <-b.events:
+			b.deliver(ev)
+		default:
+			return
 		}
 	}
 }
+
+func (b *Buffer) deliver(ev observer.Event) {
+	// TODO: This has to ensure ordered arrival,
+	// e.g., put into a heap and delay observations.
+	for _, obs := range b.observers {
+		obs.Observe(ev)
+	}
+	memlimit.Release(eventSize(ev))
+}
@@ -0,0 +1,128 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framed
+
+import (
+	"encoding/json"
+	"math"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/experimental/streaming/exporter/internal/eventname"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+	"go.opentelemetry.io/internal/protowire"
+)
+
+func appendJSON(buf []byte, ev reader.Event) []byte {
+	je := jsonEvent{
+		Type:             eventname.Of(ev.Type),
+		Time:             ev.Time,
+		Sequence:         uint64(ev.Sequence),
+		Name:             ev.Name,
+		Message:          ev.Message,
+		DurationNano:     int64(ev.Duration),
+		Attributes:       labelMap(ev.Attributes),
+		Tags:             labelMap(ev.Tags),
+		ParentAttributes: labelMap(ev.ParentAttributes),
+	}
+	if ev.Type == reader.SET_STATUS {
+		je.Status = ev.Status.String()
+	}
+	if ev.Kind != apitrace.SpanKindUnspecified {
+		je.Kind = ev.Kind.String()
+	}
+	if ev.SpanContext.HasTraceID() {
+		je.TraceID = ev.SpanContext.TraceIDString()
+	}
+	if ev.SpanContext.HasSpanID() {
+		je.SpanID = ev.SpanContext.SpanIDString()
+	}
+	if ev.Parent.HasSpanID() {
+		je.ParentSpanID = ev.Parent.SpanIDString()
+	}
+	for _, m := range ev.Stats {
+		je.Stats = append(je.Stats, jsonMeasurement{
+			Measure: m.Measure.V().Name,
+			Value:   m.Value,
+			Tags:    labelMap(m.Tags),
+		})
+	}
+	// jsonEvent holds only strings, numbers and times, which always
+	// marshal.
+	b, _ := json.Marshal(je)
+	return append(buf, b...)
+}
+
+func labelMap(m tag.Map) map[string]string {
+	if m == nil || m.Len() == 0 {
+		return nil
+	}
+	labels := make(map[string]string, m.Len())
+	m.Foreach(func(kv core.KeyValue) bool {
+		labels[kv.Key.Variable.Name] = kv.Value.Emit()
+		return true
+	})
+	return labels
+}
+
+func appendProto(buf []byte, ev reader.Event) []byte {
+	e := protowire.Encoder{Buf: buf}
+	e.UintField(eventFieldType, uint64(ev.Type))
+	if !ev.Time.IsZero() {
+		e.Fixed64Field(eventFieldTime, uint64(ev.Time.UnixNano()))
+	}
+	e.UintField(eventFieldSequence, uint64(ev.Sequence))
+	if ev.SpanContext.HasTraceID() {
+		e.BytesField(eventFieldTraceID, protowire.TraceID(ev.SpanContext.TraceID.High, ev.SpanContext.TraceID.Low))
+	}
+	if ev.SpanContext.HasSpanID() {
+		e.BytesField(eventFieldSpanID, protowire.SpanID(ev.SpanContext.SpanID))
+	}
+	if ev.Parent.HasSpanID() {
+		e.BytesField(eventFieldParentSpanID, protowire.SpanID(ev.Parent.SpanID))
+	}
+	e.StringField(eventFieldName, ev.Name)
+	e.StringField(eventFieldMessage, ev.Message)
+	e.UintField(eventFieldDurationNano, uint64(ev.Duration))
+	e.UintField(eventFieldStatus, uint64(ev.Status))
+	labels(&e, eventFieldAttributes, ev.Attributes)
+	labels(&e, eventFieldTags, ev.Tags)
+	for _, m := range ev.Stats {
+		m := m
+		e.Message(eventFieldStats, func(e *protowire.Encoder) {
+			e.StringField(measurementFieldMeasure, m.Measure.V().Name)
+			e.Tag(measurementFieldValue, protowire.WireFixed64)
+			e.Fixed64(math.Float64bits(m.Value))
+			labels(e, measurementFieldTags, m.Tags)
+		})
+	}
+	labels(&e, eventFieldParentAttributes, ev.ParentAttributes)
+	e.UintField(eventFieldKind, uint64(ev.Kind))
+	return e.Buf
+}
+
+func labels(e *protowire.Encoder, field int, m tag.Map) {
+	if m == nil {
+		return
+	}
+	m.Foreach(func(kv core.KeyValue) bool {
+		e.Message(field, func(e *protowire.Encoder) {
+			e.StringField(labelFieldKey, kv.Key.Variable.Name)
+			e.StringField(labelFieldValue, kv.Value.Emit())
+		})
+		return true
+	})
+}
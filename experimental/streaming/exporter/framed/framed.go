@@ -0,0 +1,128 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package framed writes streaming events to an io.Writer as a sequence of
+// length-prefixed frames, so that any pipe, socket or file can carry the
+// stream to another process.
+//
+// Each frame is the length of the payload as a protocol buffer varint,
+// followed by the payload: the event encoded as JSON or as a protocol
+// buffer message. This is the delimited format of the protobuf
+// libraries, so consumers can use their ReadDelimited helpers, or
+// ReadFrame in Go. The protocol buffer payload is the Event message of
+// framed.proto, which, like the JSON encoding, is generated from the
+// event schema of the eventgen command.
+package framed // import "go.opentelemetry.io/experimental/streaming/exporter/framed"
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+)
+
+// Encoding is the encoding of the frame payloads.
+type Encoding int
+
+const (
+	JSON Encoding = iota
+	Protobuf
+)
+
+// MaxFrameSize is the largest payload ReadFrame accepts.
+const MaxFrameSize = 16 << 20
+
+var errFrameTooLarge = errors.New("framed: frame exceeds MaxFrameSize")
+
+// Writer is a reader.Reader writing every event it reads to an
+// io.Writer as one frame.
+type Writer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	encode func([]byte, reader.Event) []byte
+	buf    []byte
+	err    error
+}
+
+var _ reader.Reader = (*Writer)(nil)
+
+// New returns an observer writing events to w.
+func New(w io.Writer, enc Encoding) observer.Observer {
+	return reader.NewReaderObserver(NewWriter(w, enc))
+}
+
+// NewWriter returns a Writer writing events to w. Use it in place of New
+// to combine it with other readers or with reader.Filter.
+func NewWriter(w io.Writer, enc Encoding) *Writer {
+	fw := &Writer{w: w, encode: appendJSON}
+	if enc == Protobuf {
+		fw.encode = appendProto
+	}
+	return fw
+}
+
+// Read writes the frame of event. Once a write has failed, events are
+// dropped and Err returns the error.
+func (w *Writer) Read(event reader.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return
+	}
+
+	// Reserve room for the longest varint and move the prefix next to
+	// the payload once its length is known.
+	buf := append(w.buf[:0], make([]byte, binary.MaxVarintLen32)...)
+	buf = w.encode(buf, event)
+	n := len(buf) - binary.MaxVarintLen32
+	var prefix [binary.MaxVarintLen32]byte
+	p := binary.PutUvarint(prefix[:], uint64(n))
+	start := binary.MaxVarintLen32 - p
+	copy(buf[start:], prefix[:p])
+	w.buf = buf
+
+	_, w.err = w.w.Write(buf[start:])
+}
+
+// Err returns the error of the first failed write.
+func (w *Writer) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// ReadFrame reads the payload of the next frame from r. It returns
+// io.EOF when r ends between frames and io.ErrUnexpectedEOF when it ends
+// inside one.
+func ReadFrame(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > MaxFrameSize {
+		return nil, errFrameTooLarge
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return payload, nil
+}
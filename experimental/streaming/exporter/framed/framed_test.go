@@ -0,0 +1,175 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framed
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+)
+
+var testEvent = reader.Event{
+	Type:     reader.FINISH_SPAN,
+	Time:     time.Unix(1, 0),
+	Sequence: 7,
+	SpanContext: core.SpanContext{
+		TraceID: core.TraceID{High: 1, Low: 2},
+		SpanID:  3,
+	},
+	Attributes: tag.NewMap(tag.MapUpdate{
+		MultiKV: []core.KeyValue{key.New("http.route").String("/pay")},
+	}),
+	Name:     "span",
+	Duration: time.Second,
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, JSON)
+	w.Read(testEvent)
+	w.Read(reader.Event{Type: reader.START_SPAN, Name: "next"})
+	if err := w.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(&buf)
+	payload, err := ReadFrame(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonEvent
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != "finish_span" || got.Name != "span" || got.Sequence != 7 ||
+		got.DurationNano != int64(time.Second) || got.Attributes["http.route"] != "/pay" {
+		t.Errorf("got %+v, want the finished span", got)
+	}
+	if got.TraceID != testEvent.SpanContext.TraceIDString() || got.SpanID != testEvent.SpanContext.SpanIDString() {
+		t.Errorf("got IDs %s/%s, want %s/%s", got.TraceID, got.SpanID,
+			testEvent.SpanContext.TraceIDString(), testEvent.SpanContext.SpanIDString())
+	}
+
+	payload, err = ReadFrame(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != "start_span" || got.Name != "next" {
+		t.Errorf("got %+v, want the started span", got)
+	}
+
+	if _, err := ReadFrame(r); err != io.EOF {
+		t.Errorf("got error %v after the last frame, want io.EOF", err)
+	}
+}
+
+func TestProtobufRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, Protobuf)
+	w.Read(testEvent)
+	if err := w.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := ReadFrame(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := map[uint64][]byte{}
+	for len(payload) > 0 {
+		tag, n := binary.Uvarint(payload)
+		payload = payload[n:]
+		switch tag & 7 {
+		case 0:
+			v, n := binary.Uvarint(payload)
+			fields[tag>>3] = []byte{byte(v)}
+			payload = payload[n:]
+		case 1:
+			fields[tag>>3], payload = payload[:8], payload[8:]
+		case 2:
+			l, n := binary.Uvarint(payload)
+			fields[tag>>3], payload = payload[n:n+int(l)], payload[n+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&7)
+		}
+	}
+	if got := reader.EventType(fields[1][0]); got != reader.FINISH_SPAN {
+		t.Errorf("type = %d, want %d", got, reader.FINISH_SPAN)
+	}
+	if got := binary.LittleEndian.Uint64(fields[2]); got != uint64(time.Second) {
+		t.Errorf("time_unix_nano = %d, want %d", got, time.Second)
+	}
+	wantTraceID := []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2}
+	if got := fields[4]; !bytes.Equal(got, wantTraceID) {
+		t.Errorf("trace_id = %x, want %x", got, wantTraceID)
+	}
+	if got := string(fields[7]); got != "span" {
+		t.Errorf("name = %q, want span", got)
+	}
+}
+
+func TestReadFrameTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	NewWriter(&buf, JSON).Read(testEvent)
+	frame := buf.Bytes()
+
+	for _, n := range []int{1, len(frame) / 2, len(frame) - 1} {
+		_, err := ReadFrame(bufio.NewReader(bytes.NewReader(frame[:n])))
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("frame cut to %d of %d bytes: got error %v, want io.ErrUnexpectedEOF", n, len(frame), err)
+		}
+	}
+	// A length prefix cut inside its varint.
+	_, err := ReadFrame(bufio.NewReader(bytes.NewReader([]byte{0x80})))
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated length: got error %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestReadFrameTooLarge(t *testing.T) {
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], MaxFrameSize+1)
+	_, err := ReadFrame(bufio.NewReader(bytes.NewReader(prefix[:n])))
+	if err != errFrameTooLarge {
+		t.Errorf("got error %v, want errFrameTooLarge", err)
+	}
+}
+
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestWriterErr(t *testing.T) {
+	writeErr := errors.New("broken pipe")
+	w := NewWriter(failingWriter{writeErr}, JSON)
+	w.Read(testEvent)
+	w.Read(testEvent)
+	if err := w.Err(); err != writeErr {
+		t.Errorf("got error %v, want %v", err, writeErr)
+	}
+}
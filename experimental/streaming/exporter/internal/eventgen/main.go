@@ -0,0 +1,177 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command eventgen generates the code and definitions derived from the
+// schema of streaming events in schema.go: the event type constants of
+// the observer and reader packages, the event names of the eventname
+// package, and the JSON and protocol buffer encodings of the framed
+// package. It is run by go generate in the observer package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const license = `// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by eventgen from schema.go; DO NOT EDIT.
+`
+
+var funcs = template.FuncMap{
+	"lowerFirst": func(s string) string {
+		return strings.ToLower(s[:1]) + s[1:]
+	},
+}
+
+var eventTypeTmpl = template.Must(template.New("eventtype").Parse(license + `
+package {{.Package}}
+
+import "strconv"
+
+const (
+{{- range $i, $t := .Types}}
+	{{$t.Name}}{{if eq $i 0}} EventType = iota{{end}}
+{{- end}}
+)
+
+var eventTypeNames = [...]string{
+{{- range .Types}}
+	{{.Name}}: "{{.Name}}",
+{{- end}}
+}
+
+// String returns the name of the constant of t.
+func (t EventType) String() string {
+	if t < 0 || int(t) >= len(eventTypeNames) {
+		return "EventType(" + strconv.FormatInt(int64(t), 10) + ")"
+	}
+	return eventTypeNames[t]
+}
+`))
+
+var namesTmpl = template.Must(template.New("names").Parse(license + `
+package eventname
+
+import "go.opentelemetry.io/experimental/streaming/exporter/reader"
+
+var names = map[reader.EventType]string{
+{{- range .}}{{if .WireName}}
+	reader.{{.Name}}: "{{.WireName}}",
+{{- end}}{{end}}
+}
+`))
+
+var framedTmpl = template.Must(template.New("framed").Funcs(funcs).Parse(license + `
+package framed
+
+import "time"
+
+// Field numbers of the protocol buffer messages of framed.proto.
+const (
+{{- range .}}{{$m := .}}{{range .Fields}}
+	{{lowerFirst $m.Name}}Field{{.GoName}} = {{.Number}}
+{{- end}}{{end}}
+)
+{{range .}}{{if .JSONStruct}}
+type {{.JSONStruct}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.JSONType}} ` + "`" + `json:"{{.JSON}}"` + "`" + `
+{{- end}}
+}
+{{end}}{{end}}`))
+
+var protoTmpl = template.Must(template.New("proto").Parse(license + `
+syntax = "proto3";
+
+package opentelemetry.experimental.streaming.framed;
+{{range .}}
+message {{.Name}} {
+{{- range .Fields}}
+  {{.Proto}} {{.Name}} = {{.Number}};{{with .Comment}} // {{.}}{{end}}
+{{- end}}
+}
+{{end}}`))
+
+func main() {
+	dir := flag.String("dir", ".", "root `directory` of the streaming exporters")
+	flag.Parse()
+
+	files, err := generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, b := range files {
+		if err := ioutil.WriteFile(filepath.Join(*dir, name), b, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// generate returns the contents of the generated files by their path
+// relative to the root of the streaming exporters.
+func generate() (map[string][]byte, error) {
+	var readerTypes []eventType
+	for _, t := range eventTypes {
+		if t.Reader {
+			readerTypes = append(readerTypes, t)
+		}
+	}
+
+	files := map[string][]byte{}
+	for _, f := range []struct {
+		name string
+		tmpl *template.Template
+		data interface{}
+	}{
+		{"observer/eventtype.go", eventTypeTmpl, map[string]interface{}{"Package": "observer", "Types": eventTypes}},
+		{"reader/eventtype.go", eventTypeTmpl, map[string]interface{}{"Package": "reader", "Types": readerTypes}},
+		{"internal/eventname/names.go", namesTmpl, eventTypes},
+		{"framed/schema.go", framedTmpl, messages},
+		{"framed/framed.proto", protoTmpl, messages},
+	} {
+		var buf bytes.Buffer
+		if err := f.tmpl.Execute(&buf, f.data); err != nil {
+			return nil, fmt.Errorf("%s: %v", f.name, err)
+		}
+		b := buf.Bytes()
+		if filepath.Ext(f.name) == ".go" {
+			var err error
+			if b, err = format.Source(b); err != nil {
+				return nil, fmt.Errorf("%s: %v", f.name, err)
+			}
+		}
+		files[f.name] = b
+	}
+	return files, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// eventType is a type of streaming event. All types are observer event
+// types; reader event types are numbered in the same order.
+type eventType struct {
+	// Name is the name of the constant of the type.
+	Name string
+
+	// Reader is true for the types of events passed to readers.
+	Reader bool
+
+	// WireName is the name of the type in the output of the exporters,
+	// if it has one.
+	WireName string
+}
+
+var eventTypes = []eventType{
+	{Name: "INVALID", Reader: true},
+	{Name: "START_SPAN", Reader: true, WireName: "start_span"},
+	{Name: "FINISH_SPAN", Reader: true, WireName: "finish_span"},
+	{Name: "ADD_EVENT", Reader: true, WireName: "add_event"},
+	{Name: "ADD_EVENTF"},
+	{Name: "NEW_SCOPE"},
+	{Name: "NEW_MEASURE"},
+	{Name: "NEW_METRIC"},
+	{Name: "MODIFY_ATTR", Reader: true, WireName: "modify_attr"},
+	{Name: "RECORD_STATS", Reader: true, WireName: "record_stats"},
+	{Name: "SET_STATUS", Reader: true, WireName: "set_status"},
+}
+
+// message is a protocol buffer message of the framed encoding.
+type message struct {
+	Name string
+
+	// JSONStruct is the name of the struct encoding the message as JSON,
+	// if it has one.
+	JSONStruct string
+
+	Fields []field
+}
+
+type field struct {
+	Name    string // In the protocol buffer definition
+	Number  int
+	Proto   string // Protocol buffer type
+	Comment string
+
+	GoName   string // In the JSON struct and the field number constants
+	JSON     string // JSON struct tag
+	JSONType string // Go type in the JSON struct
+}
+
+var messages = []message{
+	{
+		Name:       "Event",
+		JSONStruct: "jsonEvent",
+		Fields: []field{
+			{"type", 1, "int32", "reader.EventType", "Type", "type", "string"},
+			{"time_unix_nano", 2, "fixed64", "", "Time", "time", "time.Time"},
+			{"sequence", 3, "uint64", "", "Sequence", "sequence", "uint64"},
+			{"trace_id", 4, "bytes", "", "TraceID", "trace_id,omitempty", "string"},
+			{"span_id", 5, "bytes", "", "SpanID", "span_id,omitempty", "string"},
+			{"parent_span_id", 6, "bytes", "", "ParentSpanID", "parent_span_id,omitempty", "string"},
+			{"name", 7, "string", "", "Name", "name,omitempty", "string"},
+			{"message", 8, "string", "event message or status description", "Message", "message,omitempty", "string"},
+			{"duration_nano", 9, "int64", "", "DurationNano", "duration_nano,omitempty", "int64"},
+			{"status", 10, "uint32", "grpc/codes.Code", "Status", "status,omitempty", "string"},
+			{"attributes", 11, "repeated Label", "", "Attributes", "attributes,omitempty", "map[string]string"},
+			{"tags", 12, "repeated Label", "", "Tags", "tags,omitempty", "map[string]string"},
+			{"stats", 13, "repeated Measurement", "", "Stats", "stats,omitempty", "[]jsonMeasurement"},
+			{"parent_attributes", 14, "repeated Label", "", "ParentAttributes", "parent_attributes,omitempty", "map[string]string"},
+			{"kind", 15, "int32", "trace.SpanKind", "Kind", "kind,omitempty", "string"},
+		},
+	},
+	{
+		// Labels are encoded as JSON objects.
+		Name: "Label",
+		Fields: []field{
+			{Name: "key", Number: 1, Proto: "string", GoName: "Key"},
+			{Name: "value", Number: 2, Proto: "string", GoName: "Value"},
+		},
+	},
+	{
+		Name:       "Measurement",
+		JSONStruct: "jsonMeasurement",
+		Fields: []field{
+			{"measure", 1, "string", "", "Measure", "measure", "string"},
+			{"value", 2, "double", "", "Value", "value", "float64"},
+			{"tags", 3, "repeated Label", "", "Tags", "tags,omitempty", "map[string]string"},
+		},
+	},
+}
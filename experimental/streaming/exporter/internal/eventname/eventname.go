@@ -0,0 +1,32 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eventname names the types of reader events in the output of
+// the streaming exporters.
+package eventname // import "go.opentelemetry.io/experimental/streaming/exporter/internal/eventname"
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+)
+
+// Of returns the name of t, e.g., "start_span", or its number for types
+// without a name.
+func Of(t reader.EventType) string {
+	if name, ok := names[t]; ok {
+		return name
+	}
+	return strconv.Itoa(int(t))
+}
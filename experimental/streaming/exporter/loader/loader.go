@@ -18,7 +18,6 @@ import (
 	"fmt"
 	"os"
 	"plugin"
-	"time"
 
 	"go.opentelemetry.io/experimental/streaming/exporter/observer"
 )
@@ -50,7 +49,8 @@ func init() {
 	observer.RegisterObserver(f())
 }
 
+// Flush flushes the registered observers, including the one of the
+// plugin.
 func Flush() {
-	// TODO implement for exporter/{stdout,stderr,buffer}
-	time.Sleep(1 * time.Second)
+	observer.Flush()
 }
@@ -0,0 +1,199 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/api/logger"
+)
+
+// DefaultDispatchQueueSize is the number of events a Dispatcher queues
+// for each observer when NewDispatcher is given a size of zero.
+const DefaultDispatchQueueSize = 2048
+
+// Dispatcher is an Observer that hands events to other observers
+// asynchronously. Each observer has its own bounded queue drained by its
+// own goroutine, so a slow observer, e.g., one exporting over the
+// network, neither blocks Record nor delays the other observers. Events
+// arrive at each observer in the order they were observed; events
+// observed while the queue of an observer is full are dropped for that
+// observer only.
+//
+// Register it in place of the observers it wraps:
+//
+//	d := observer.NewDispatcher(0, exporterA, exporterB)
+//	observer.RegisterObserver(d)
+//	defer d.Close()
+type Dispatcher struct {
+	queues []*dispatchQueue
+	done   chan struct{}
+	once   sync.Once
+	wait   sync.WaitGroup
+}
+
+type dispatchQueue struct {
+	observer Observer
+	items    chan dispatchItem
+	exited   chan struct{}
+	dropped  uint64 // access atomically
+}
+
+// dispatchItem is an event to observe, or a request to close flushed
+// once the events queued before are observed.
+type dispatchItem struct {
+	event   Event
+	flushed chan struct{}
+}
+
+var (
+	_ Observer   = (*Dispatcher)(nil)
+	_ Flusher    = (*Dispatcher)(nil)
+	_ Shutdowner = (*Dispatcher)(nil)
+)
+
+// NewDispatcher returns a Dispatcher queueing up to size events for each
+// of observers, DefaultDispatchQueueSize if size is not positive.
+func NewDispatcher(size int, observers ...Observer) *Dispatcher {
+	if size <= 0 {
+		size = DefaultDispatchQueueSize
+	}
+	d := &Dispatcher{done: make(chan struct{})}
+	for _, o := range observers {
+		q := &dispatchQueue{
+			observer: o,
+			items:    make(chan dispatchItem, size),
+			exited:   make(chan struct{}),
+		}
+		d.queues = append(d.queues, q)
+		d.wait.Add(1)
+		go d.run(q)
+	}
+	return d
+}
+
+// Observe queues data for every observer of d without blocking.
+func (d *Dispatcher) Observe(data Event) {
+	select {
+	case <-d.done:
+		return
+	default:
+	}
+	for _, q := range d.queues {
+		select {
+		case q.items <- dispatchItem{event: data}:
+		default:
+			atomic.AddUint64(&q.dropped, 1)
+			logger.Debugf("observer: dropped %v event for %T: queue is full", data.Type, q.observer)
+		}
+	}
+}
+
+// Dropped returns the number of events d dropped for o because its queue
+// was full.
+func (d *Dispatcher) Dropped(o Observer) uint64 {
+	for _, q := range d.queues {
+		if q.observer == o {
+			return atomic.LoadUint64(&q.dropped)
+		}
+	}
+	return 0
+}
+
+// Flush implements Flusher. It returns once the observers have received
+// the events queued before, and flushed them if they are Flushers.
+func (d *Dispatcher) Flush() {
+	for _, q := range d.queues {
+		flushed := make(chan struct{})
+		select {
+		case q.items <- dispatchItem{flushed: flushed}:
+		case <-q.exited:
+			continue
+		}
+		select {
+		case <-flushed:
+		case <-q.exited:
+			continue
+		}
+		if f, ok := q.observer.(Flusher); ok {
+			f.Flush()
+		}
+	}
+}
+
+// Close stops d from queueing events and returns once the observers have
+// received the events queued before. Unregister d first so that it is
+// not given events it drops.
+func (d *Dispatcher) Close() {
+	d.once.Do(func() { close(d.done) })
+	d.wait.Wait()
+}
+
+// Shutdown implements Shutdowner. It closes d, then shuts down the
+// observers that are Shutdowners and flushes the other Flushers. If ctx
+// is done before d is closed, it returns the error of ctx.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	closed := make(chan struct{})
+	go func() {
+		d.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	observers := make([]Observer, len(d.queues))
+	for i, q := range d.queues {
+		observers[i] = q.observer
+	}
+	return shutdownAll(ctx, observers)
+}
+
+func (d *Dispatcher) run(q *dispatchQueue) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("observer: %T panicked, no more events are delivered to it: %v", q.observer, r)
+		}
+		close(q.exited)
+		d.wait.Done()
+	}()
+
+	for {
+		select {
+		case item := <-q.items:
+			q.handle(item)
+		case <-d.done:
+			for {
+				select {
+				case item := <-q.items:
+					q.handle(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *dispatchQueue) handle(item dispatchItem) {
+	if item.flushed != nil {
+		close(item.flushed)
+		return
+	}
+	q.observer.Observe(item.event)
+}
@@ -0,0 +1,121 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observer
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type countingObserver struct {
+	mu     sync.Mutex
+	seqs   []EventID
+	block  chan struct{}
+	inside chan struct{}
+}
+
+func (o *countingObserver) Observe(ev Event) {
+	if o.block != nil {
+		select {
+		case o.inside <- struct{}{}:
+		default:
+		}
+		<-o.block
+	}
+	o.mu.Lock()
+	o.seqs = append(o.seqs, ev.Sequence)
+	o.mu.Unlock()
+}
+
+func TestDispatcherSlowObserver(t *testing.T) {
+	fast := &countingObserver{}
+	slow := &countingObserver{block: make(chan struct{}), inside: make(chan struct{}, 1)}
+	d := NewDispatcher(2, fast, slow)
+
+	// The slow observer holds the first event and queues two more; the
+	// other seven are dropped for it whatever the fast one does.
+	d.Observe(Event{Sequence: 1})
+	<-slow.inside
+	for i := 2; i <= 10; i++ {
+		d.Observe(Event{Sequence: EventID(i)})
+	}
+	close(slow.block)
+	d.Close()
+
+	if got := d.Dropped(slow); got != 7 {
+		t.Errorf("Dropped(slow) = %d, want 7", got)
+	}
+	for i, seq := range slow.seqs {
+		if seq != EventID(i+1) {
+			t.Errorf("slow observer got %v, want events 1 to 3 in order", slow.seqs)
+			break
+		}
+	}
+	if len(slow.seqs) != 3 {
+		t.Errorf("slow observer got %d events, want 3", len(slow.seqs))
+	}
+	if n := len(fast.seqs) + int(d.Dropped(fast)); n != 10 {
+		t.Errorf("fast observer got %d events and %d dropped, want 10 in total", len(fast.seqs), d.Dropped(fast))
+	}
+
+	d.Observe(Event{Sequence: 11})
+	if n := len(slow.seqs); n != 3 {
+		t.Errorf("slow observer got %d events after Close, want 3", n)
+	}
+}
+
+type flushingObserver struct {
+	countingObserver
+	flushes   int
+	shutdowns int
+}
+
+func (o *flushingObserver) Flush() {
+	o.flushes++
+}
+
+func (o *flushingObserver) Shutdown(ctx context.Context) error {
+	o.shutdowns++
+	return nil
+}
+
+func TestDispatcherFlushAndShutdown(t *testing.T) {
+	o := &flushingObserver{}
+	d := NewDispatcher(0, o)
+	RegisterObserver(d)
+	for i := 1; i <= 5; i++ {
+		Record(Event{Sequence: EventID(i)})
+	}
+
+	Flush()
+	o.mu.Lock()
+	n := len(o.seqs)
+	o.mu.Unlock()
+	if n != 5 || o.flushes != 1 {
+		t.Errorf("got %d events and %d flushes after Flush, want 5 and 1", n, o.flushes)
+	}
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	if o.shutdowns != 1 {
+		t.Errorf("observer shut down %d times, want 1", o.shutdowns)
+	}
+	Record(Event{})
+	if len(o.seqs) != 5 {
+		t.Errorf("got %d events after Shutdown, want 5", len(o.seqs))
+	}
+}
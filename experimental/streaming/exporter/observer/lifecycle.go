@@ -0,0 +1,78 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observer
+
+import (
+	"context"
+
+	opentelemetry "go.opentelemetry.io/sdk"
+)
+
+// Flusher is implemented by observers that buffer events.
+type Flusher interface {
+	// Flush passes the buffered events on and returns once it has.
+	Flush()
+}
+
+// Shutdowner is implemented by observers that buffer events or hold
+// resources to release when the process exits.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+func init() {
+	opentelemetry.RegisterShutdownHook(Shutdown)
+}
+
+// Flush flushes the registered observers implementing Flusher.
+func Flush() {
+	Foreach(func(o Observer) {
+		if f, ok := o.(Flusher); ok {
+			f.Flush()
+		}
+	})
+}
+
+// Shutdown unregisters all observers, then shuts down those implementing
+// Shutdowner and flushes the other Flushers. It returns the first error.
+//
+// It is called by opentelemetry.Shutdown.
+func Shutdown(ctx context.Context) error {
+	var all []Observer
+	Foreach(func(o Observer) {
+		all = append(all, o)
+	})
+	for _, o := range all {
+		UnregisterObserver(o)
+	}
+	return shutdownAll(ctx, all)
+}
+
+// shutdownAll shuts down the Shutdowners of observers, and flushes the
+// other Flushers.
+func shutdownAll(ctx context.Context, observers []Observer) error {
+	var err error
+	for _, o := range observers {
+		switch o := o.(type) {
+		case Shutdowner:
+			if e := o.Shutdown(ctx); e != nil && err == nil {
+				err = e
+			}
+		case Flusher:
+			o.Flush()
+		}
+	}
+	return err
+}
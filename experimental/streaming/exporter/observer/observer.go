@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/api/core"
 	"go.opentelemetry.io/api/stats"
 	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
 )
 
 type EventType int
@@ -39,8 +40,9 @@ type ScopeID struct {
 // TODO: this Event is confusing with event.Event.
 type Event struct {
 	// Automatic fields
-	Sequence EventID   // Auto-filled
-	Time     time.Time // Auto-filled
+	Sequence  EventID       // Auto-filled
+	Time      time.Time     // Auto-filled
+	Monotonic time.Duration // Auto-filled, see Record
 
 	// Type, Scope, Context
 	Type    EventType       // All events
@@ -48,15 +50,16 @@ type Event struct {
 	Context context.Context // core.FromContext() and scope.Active()
 
 	// Arguments (type-specific)
-	Attribute  core.KeyValue   // SET_ATTRIBUTE
-	Attributes []core.KeyValue // SET_ATTRIBUTES
-	Mutator    tag.Mutator     // SET_ATTRIBUTE
-	Mutators   []tag.Mutator   // SET_ATTRIBUTES
-	Recovered  interface{}     // FINISH_SPAN
-	Status     codes.Code      // SET_STATUS
+	Attribute  core.KeyValue     // SET_ATTRIBUTE
+	Attributes []core.KeyValue   // SET_ATTRIBUTES
+	Mutator    tag.Mutator       // SET_ATTRIBUTE
+	Mutators   []tag.Mutator     // SET_ATTRIBUTES
+	Recovered  interface{}       // FINISH_SPAN
+	Status     codes.Code        // SET_STATUS
+	Kind       apitrace.SpanKind // START_SPAN
 
 	// Values
-	String  string // START_SPAN, EVENT, ...
+	String  string // START_SPAN, EVENT, SET_STATUS, ...
 	Float64 float64
 	Parent  ScopeID // START_SPAN
 	Stats   []stats.Measurement
@@ -69,33 +72,31 @@ type Observer interface {
 
 type observersMap map[Observer]struct{}
 
-//go:generate stringer -type=EventType
-const (
-	// TODO: rename these NOUN_VERB
-	INVALID EventType = iota
-	START_SPAN
-	FINISH_SPAN
-	ADD_EVENT
-	ADD_EVENTF
-	NEW_SCOPE
-	NEW_MEASURE
-	NEW_METRIC
-	MODIFY_ATTR
-	RECORD_STATS
-	SET_STATUS
-)
+// The EventType constants are generated from the event schema of the
+// eventgen command.
+//go:generate go run ../internal/eventgen -dir ..
 
 var (
 	observerMu sync.Mutex
 	observers  atomic.Value
 
 	sequenceNum uint64
+
+	// epoch is the reference point of Event.Monotonic. It carries the
+	// monotonic clock reading taken when the process started.
+	epoch = time.Now()
 )
 
 func NextEventID() EventID {
 	return EventID(atomic.AddUint64(&sequenceNum, 1))
 }
 
+// LastEventID returns the sequence number of the last event recorded, or
+// zero if none was.
+func LastEventID() EventID {
+	return EventID(atomic.LoadUint64(&sequenceNum))
+}
+
 // RegisterObserver adds to the list of Observers that will receive sampled
 // trace spans.
 //
@@ -128,12 +129,21 @@ func UnregisterObserver(e Observer) {
 	observerMu.Unlock()
 }
 
+// Record passes event to the registered observers, filling in its
+// automatic fields. Monotonic is the offset of the event from the process
+// start according to the monotonic clock, so differences between events
+// are not affected by steps of the wall clock. When the caller supplies
+// Time, the offset is adjusted by how far in the past Time lies.
 func Record(event Event) EventID {
 	if event.Sequence == 0 {
 		event.Sequence = NextEventID()
 	}
+	now := time.Now()
 	if event.Time.IsZero() {
-		event.Time = time.Now()
+		event.Time = now
+	}
+	if event.Monotonic == 0 {
+		event.Monotonic = now.Sub(epoch) - now.Sub(event.Time)
 	}
 
 	observers, _ := observers.Load().(observersMap)
@@ -0,0 +1,46 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observer
+
+import (
+	"testing"
+	"time"
+)
+
+type lastEventObserver struct {
+	last Event
+}
+
+func (o *lastEventObserver) Observe(ev Event) {
+	o.last = ev
+}
+
+func TestRecordMonotonic(t *testing.T) {
+	o := &lastEventObserver{}
+	RegisterObserver(o)
+	defer UnregisterObserver(o)
+
+	Record(Event{})
+	first := o.last.Monotonic
+	if first <= 0 {
+		t.Fatalf("Monotonic = %v, want positive", first)
+	}
+
+	// A caller supplied time an hour ago is offset accordingly.
+	Record(Event{Time: time.Now().Add(-time.Hour).Round(0)})
+	if got := first - o.last.Monotonic; got < 59*time.Minute || got > 61*time.Minute {
+		t.Errorf("Monotonic of an event an hour ago is %v before the previous one, want about an hour", got)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+
+	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+)
+
+// Eviction is the policy choosing which entry of the state of a reader
+// observer is evicted when it is full.
+type Eviction int
+
+const (
+	// FIFO evicts the oldest entry.
+	FIFO Eviction = iota
+	// LRU evicts the entry least recently used, e.g., the scope of the
+	// span that has been inactive the longest.
+	LRU
+)
+
+// Limits bounds the state a reader observer keeps about the events it
+// saw, so that spans that never finish do not grow it forever. Events
+// referring to an evicted entry are dropped.
+type Limits struct {
+	// MaxScopes is the maximum number of live spans and scopes. Zero
+	// means no limit.
+	MaxScopes int
+
+	// MaxMeasures is the maximum number of measures, and of metrics.
+	// Zero means no limit.
+	MaxMeasures int
+
+	Eviction Eviction
+}
+
+// NewBoundedReaderObserver returns an observer like NewReaderObserver
+// whose state is bounded by limits.
+func NewBoundedReaderObserver(limits Limits, readers ...Reader) observer.Observer {
+	ro := NewReaderObserver(readers...).(*readerObserver)
+	ro.scopes = newStateMap(limits.MaxScopes, limits.Eviction, &ro.evicted)
+	ro.measures = newStateMap(limits.MaxMeasures, limits.Eviction, &ro.evicted)
+	ro.metrics = newStateMap(limits.MaxMeasures, limits.Eviction, &ro.evicted)
+	return ro
+}
+
+// EvictedEntries returns the number of entries evicted from the state of
+// an observer returned by NewBoundedReaderObserver.
+func EvictedEntries(o observer.Observer) uint64 {
+	if ro := readerObserverOf(o); ro != nil {
+		return atomic.LoadUint64(&ro.evicted)
+	}
+	return 0
+}
+
+// stateMap maps event IDs to the state of a reader observer. It is
+// unbounded unless made by newStateMap with a positive size.
+type stateMap struct {
+	mu sync.Mutex
+	m  map[observer.EventID]interface{}
+
+	lru      *simplelru.LRU
+	eviction Eviction
+	// deleting is set while Delete removes an entry, which the LRU
+	// reports as evicted.
+	deleting bool
+}
+
+func newStateMap(size int, eviction Eviction, evicted *uint64) *stateMap {
+	s := &stateMap{eviction: eviction}
+	if size > 0 {
+		s.lru, _ = simplelru.NewLRU(size, func(interface{}, interface{}) {
+			if !s.deleting {
+				atomic.AddUint64(evicted, 1)
+			}
+		})
+	}
+	return s
+}
+
+func (s *stateMap) Load(id observer.EventID) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.lru == nil:
+		v, ok := s.m[id]
+		return v, ok
+	case s.eviction == LRU:
+		return s.lru.Get(id)
+	default:
+		return s.lru.Peek(id)
+	}
+}
+
+func (s *stateMap) Store(id observer.EventID, v interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lru != nil {
+		s.lru.Add(id, v)
+		return
+	}
+	if s.m == nil {
+		s.m = make(map[observer.EventID]interface{})
+	}
+	s.m[id] = v
+}
+
+func (s *stateMap) Delete(id observer.EventID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lru != nil {
+		s.deleting = true
+		s.lru.Remove(id)
+		s.deleting = false
+		return
+	}
+	delete(s.m, id)
+}
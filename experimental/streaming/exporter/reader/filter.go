@@ -0,0 +1,124 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"go.opentelemetry.io/api/event"
+)
+
+// EventTypeSet is a set of EventTypes that a Reader subscribes to.
+type EventTypeSet uint64
+
+// AllEventTypes contains every EventType.
+const AllEventTypes = ^EventTypeSet(0)
+
+// subscriber is implemented by Readers that only want a subset of
+// the event types.
+type subscriber interface {
+	EventTypes() EventTypeSet
+}
+
+type filterReader struct {
+	types  EventTypeSet
+	reader Reader
+}
+
+// NewEventTypeSet returns a set containing the given event types.
+func NewEventTypeSet(types ...EventType) EventTypeSet {
+	var s EventTypeSet
+	for _, t := range types {
+		s |= 1 << uint(t)
+	}
+	return s
+}
+
+// Contains returns true if t is in the set.
+func (s EventTypeSet) Contains(t EventType) bool {
+	return s&(1<<uint(t)) != 0
+}
+
+// Filter returns a Reader that passes only events of the given types
+// to r. When every Reader of an observer is filtered, the observer
+// skips building events that no Reader has subscribed to, e.g.,
+//
+//	reader.NewReaderObserver(reader.Filter(r, reader.START_SPAN, reader.FINISH_SPAN))
+//
+// never materializes the attributes of ADD_EVENT events.
+func Filter(r Reader, types ...EventType) Reader {
+	return &filterReader{
+		types:  NewEventTypeSet(types...),
+		reader: r,
+	}
+}
+
+func (f *filterReader) Read(event Event) {
+	if f.types.Contains(event.Type) {
+		f.reader.Read(event)
+	}
+}
+
+func (f *filterReader) EventTypes() EventTypeSet {
+	return f.types
+}
+
+type severityReader struct {
+	min    event.Severity
+	reader Reader
+}
+
+// MinSeverity returns a Reader that passes ADD_EVENT events to r only if
+// their severity is at least min, e.g., to forward only warnings and
+// errors. Other events are passed unchanged.
+func MinSeverity(r Reader, min event.Severity) Reader {
+	return &severityReader{min: min, reader: r}
+}
+
+func (s *severityReader) Read(ev Event) {
+	if ev.Type == ADD_EVENT && Severity(ev) < s.min {
+		return
+	}
+	s.reader.Read(ev)
+}
+
+func (s *severityReader) EventTypes() EventTypeSet {
+	return subscriptions([]Reader{s.reader})
+}
+
+// Severity returns the severity of ev, read from its event.SeverityKey
+// attribute. It is event.Info if there is none.
+func Severity(ev Event) event.Severity {
+	if ev.Attributes == nil {
+		return event.Info
+	}
+	v, ok := ev.Attributes.Value(event.SeverityKey)
+	if !ok {
+		return event.Info
+	}
+	s, _ := event.ParseSeverity(v.Emit())
+	return s
+}
+
+// subscriptions returns the union of the event types wanted by readers.
+func subscriptions(readers []Reader) EventTypeSet {
+	var s EventTypeSet
+	for _, r := range readers {
+		if sub, ok := r.(subscriber); ok {
+			s |= sub.EventTypes()
+		} else {
+			s |= AllEventTypes
+		}
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/api/event"
+	"go.opentelemetry.io/api/tag"
+)
+
+type recordingReader struct {
+	events []Event
+}
+
+func (r *recordingReader) Read(event Event) {
+	r.events = append(r.events, event)
+}
+
+var allTypes = []EventType{START_SPAN, FINISH_SPAN, ADD_EVENT, MODIFY_ATTR, RECORD_STATS, SET_STATUS}
+
+func TestFilter(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		types []EventType
+	}{
+		{"none", nil},
+		{"spans", []EventType{START_SPAN, FINISH_SPAN}},
+		{"events", []EventType{ADD_EVENT}},
+		{"duplicates", []EventType{SET_STATUS, SET_STATUS}},
+		{"all", allTypes},
+	} {
+		want := map[EventType]bool{}
+		for _, typ := range tt.types {
+			want[typ] = true
+		}
+
+		rr := &recordingReader{}
+		f := Filter(rr, tt.types...)
+		for _, typ := range allTypes {
+			f.Read(Event{Type: typ})
+		}
+
+		got := map[EventType]bool{}
+		for _, ev := range rr.events {
+			if !want[ev.Type] {
+				t.Errorf("%s: event type %d passed, want it excluded", tt.name, ev.Type)
+			}
+			got[ev.Type] = true
+		}
+		for typ := range want {
+			if !got[typ] {
+				t.Errorf("%s: event type %d excluded, want it passed", tt.name, typ)
+			}
+		}
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	spans := Filter(&recordingReader{}, START_SPAN, FINISH_SPAN)
+	events := Filter(&recordingReader{}, ADD_EVENT)
+	for _, tt := range []struct {
+		name     string
+		readers  []Reader
+		included []EventType
+		excluded []EventType
+	}{
+		{"no readers", nil, nil, allTypes},
+		{"filtered", []Reader{spans}, []EventType{START_SPAN, FINISH_SPAN}, []EventType{ADD_EVENT, MODIFY_ATTR, RECORD_STATS, SET_STATUS}},
+		{"union", []Reader{spans, events}, []EventType{START_SPAN, FINISH_SPAN, ADD_EVENT}, []EventType{MODIFY_ATTR, RECORD_STATS, SET_STATUS}},
+		{"unfiltered", []Reader{spans, &recordingReader{}}, allTypes, nil},
+	} {
+		s := subscriptions(tt.readers)
+		for _, typ := range tt.included {
+			if !s.Contains(typ) {
+				t.Errorf("%s: subscriptions exclude event type %d", tt.name, typ)
+			}
+		}
+		for _, typ := range tt.excluded {
+			if s.Contains(typ) {
+				t.Errorf("%s: subscriptions include event type %d", tt.name, typ)
+			}
+		}
+	}
+}
+
+func TestMinSeverity(t *testing.T) {
+	var r recordingReader
+	f := MinSeverity(Filter(&r, ADD_EVENT, START_SPAN), event.Warn)
+	for _, s := range []event.Severity{event.Debug, event.Info, event.Warn, event.Error} {
+		f.Read(Event{Type: ADD_EVENT, Message: s.String(), Attributes: tag.NewMap(tag.MapUpdate{SingleKV: s.Attribute()})})
+	}
+	f.Read(Event{Type: ADD_EVENT, Message: "none"})
+	f.Read(Event{Type: START_SPAN})
+
+	var got []string
+	for _, ev := range r.events {
+		got = append(got, ev.Message)
+	}
+	if len(got) != 3 || got[0] != "warn" || got[1] != "error" || got[2] != "" {
+		t.Errorf("got events %q, want warn, error and the span start", got)
+	}
+	if want := NewEventTypeSet(ADD_EVENT, START_SPAN); subscriptions([]Reader{f}) != want {
+		t.Errorf("subscriptions = %b, want those of the wrapped reader %b", subscriptions([]Reader{f}), want)
+	}
+}
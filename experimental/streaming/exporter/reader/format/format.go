@@ -20,6 +20,7 @@ import (
 
 	"go.opentelemetry.io/api/core"
 	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
 	"go.opentelemetry.io/experimental/streaming/exporter/reader"
 
 	// TODO this should not be an SDK dependency; move conventional tags into the API.
@@ -48,6 +49,10 @@ func AppendEvent(buf *strings.Builder, data reader.Event) {
 	switch data.Type {
 	case reader.START_SPAN:
 		buf.WriteString("start ")
+		if data.Kind != apitrace.SpanKindUnspecified {
+			buf.WriteString(data.Kind.String())
+			buf.WriteString(" ")
+		}
 		buf.WriteString(data.Name)
 
 		if !data.Parent.HasSpanID() {
@@ -58,7 +63,7 @@ func AppendEvent(buf *strings.Builder, data reader.Event) {
 				f(false)(parentSpanIDKey.String(data.SpanContext.SpanIDString()))
 			}
 			if data.ParentAttributes != nil {
-				data.ParentAttributes.Foreach(f(false))
+				data.ParentAttributes.ForeachOrdered(f(false))
 			}
 			buf.WriteString(" >")
 		}
@@ -75,7 +80,7 @@ func AppendEvent(buf *strings.Builder, data reader.Event) {
 		buf.WriteString("event: ")
 		buf.WriteString(data.Message)
 		buf.WriteString(" (")
-		data.Attributes.Foreach(func(kv core.KeyValue) bool {
+		data.Attributes.ForeachOrdered(func(kv core.KeyValue) bool {
 			buf.WriteString(" " + kv.Key.Variable.Name + "=" + kv.Value.Emit())
 			return true
 		})
@@ -93,7 +98,7 @@ func AppendEvent(buf *strings.Builder, data reader.Event) {
 
 			buf.WriteString(" {")
 			i := 0
-			s.Tags.Foreach(func(kv core.KeyValue) bool {
+			s.Tags.ForeachOrdered(func(kv core.KeyValue) bool {
 				if i != 0 {
 					buf.WriteString(",")
 				}
@@ -108,6 +113,10 @@ func AppendEvent(buf *strings.Builder, data reader.Event) {
 	case reader.SET_STATUS:
 		buf.WriteString("set status ")
 		buf.WriteString(data.Status.String())
+		if data.Message != "" {
+			buf.WriteString(": ")
+			buf.WriteString(data.Message)
+		}
 
 	default:
 		buf.WriteString(fmt.Sprintf("WAT? %d", data.Type))
@@ -116,10 +125,10 @@ func AppendEvent(buf *strings.Builder, data reader.Event) {
 	// Attach the scope (span) attributes and context tags.
 	buf.WriteString(" [")
 	if data.Attributes != nil {
-		data.Attributes.Foreach(f(false))
+		data.Attributes.ForeachOrdered(f(false))
 	}
 	if data.Tags != nil {
-		data.Tags.Foreach(f(true))
+		data.Tags.ForeachOrdered(f(true))
 	}
 	if data.SpanContext.HasSpanID() {
 		f(false)(sdk.SpanIDKey.String(data.SpanContext.SpanIDString()))
@@ -0,0 +1,156 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/experimental/streaming/exporter/internal/eventname"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+)
+
+// TimeFormatUnixNano formats times as the number of nanoseconds since
+// the Unix epoch, when given as the TimeFormat of JSONOptions.
+const TimeFormatUnixNano = "unix_nano"
+
+// JSONOptions configures EventToJSON.
+type JSONOptions struct {
+	// Pretty indents the JSON object over several lines.
+	Pretty bool
+
+	// TimeFormat is the layout of the ts field, as for time.Format, or
+	// TimeFormatUnixNano. It defaults to time.RFC3339Nano.
+	TimeFormat string
+}
+
+// jsonEvent is the JSON object of an event. Its field names match the
+// keys of AppendLogfmt.
+type jsonEvent struct {
+	Time         interface{}            `json:"ts"`
+	Type         string                 `json:"type"`
+	Name         string                 `json:"name,omitempty"`
+	Kind         string                 `json:"kind,omitempty"`
+	Duration     string                 `json:"dur,omitempty"`
+	Message      string                 `json:"msg,omitempty"`
+	Status       string                 `json:"status,omitempty"`
+	Stats        map[string]float64     `json:"stats,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	SpanID       string                 `json:"span_id,omitempty"`
+	TraceID      string                 `json:"trace_id,omitempty"`
+}
+
+// EventToJSON returns data as a JSON object followed by a newline, e.g.,
+//
+//	{"ts":"2019-07-01T12:00:00.5Z","type":"finish_span","name":"hello","dur":"1.5ms",...}
+//
+// so that events can be piped into jq or log processors that parse JSON.
+// Tags are merged into the attributes, which take precedence.
+func EventToJSON(data reader.Event, opts JSONOptions) string {
+	ev := jsonEvent{
+		Time: formatJSONTime(data.Time, opts.TimeFormat),
+		Type: eventname.Of(data.Type),
+	}
+	switch data.Type {
+	case reader.START_SPAN:
+		ev.Name = data.Name
+		if data.Kind != apitrace.SpanKindUnspecified {
+			ev.Kind = data.Kind.String()
+		}
+		if data.Parent.HasSpanID() {
+			ev.ParentSpanID = data.Parent.SpanIDString()
+		}
+	case reader.FINISH_SPAN:
+		ev.Name = data.Name
+		ev.Duration = data.Duration.String()
+	case reader.ADD_EVENT:
+		ev.Message = data.Message
+	case reader.RECORD_STATS:
+		ev.Stats = make(map[string]float64, len(data.Stats))
+		for _, s := range data.Stats {
+			// JSON has no representation of NaN and infinities.
+			if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+				continue
+			}
+			ev.Stats[s.Measure.V().Name] = s.Value
+		}
+	case reader.SET_STATUS:
+		ev.Status = data.Status.String()
+		ev.Message = data.Message
+	}
+
+	add := func(kv core.KeyValue) bool {
+		if ev.Attributes == nil {
+			ev.Attributes = make(map[string]interface{})
+		}
+		ev.Attributes[kv.Key.Variable.Name] = jsonValue(kv.Value)
+		return true
+	}
+	if data.Tags != nil {
+		data.Tags.Foreach(add)
+	}
+	if data.Attributes != nil {
+		data.Attributes.Foreach(add)
+	}
+	if data.SpanContext.HasSpanID() {
+		ev.SpanID = data.SpanContext.SpanIDString()
+	}
+	if data.SpanContext.HasTraceID() {
+		ev.TraceID = data.SpanContext.TraceIDString()
+	}
+
+	var b []byte
+	if opts.Pretty {
+		b, _ = json.MarshalIndent(ev, "", "  ")
+	} else {
+		b, _ = json.Marshal(ev)
+	}
+	return string(b) + "\n"
+}
+
+// jsonValue returns the Go value held by v so that numbers, booleans and
+// slices keep their JSON types. Bytes and floats JSON cannot represent
+// are formatted as strings.
+func jsonValue(v core.Value) interface{} {
+	switch v.Type {
+	case core.BYTES:
+		return v.Emit()
+	case core.FLOAT32, core.FLOAT64:
+		if math.IsNaN(v.Float64) || math.IsInf(v.Float64, 0) {
+			return v.Emit()
+		}
+	case core.FLOAT64S:
+		for _, f := range v.Float64s {
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return v.Emit()
+			}
+		}
+	}
+	return v.AsInterface()
+}
+
+func formatJSONTime(t time.Time, layout string) interface{} {
+	switch layout {
+	case TimeFormatUnixNano:
+		return t.UnixNano()
+	case "":
+		layout = time.RFC3339Nano
+	}
+	return t.UTC().Format(layout)
+}
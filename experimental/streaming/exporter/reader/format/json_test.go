@@ -0,0 +1,90 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+)
+
+func TestEventToJSON(t *testing.T) {
+	ev := reader.Event{
+		Type:       reader.FINISH_SPAN,
+		Time:       time.Date(2019, 7, 1, 12, 0, 0, 500000000, time.UTC),
+		Name:       "hello",
+		Duration:   1500 * time.Microsecond,
+		Attributes: tag.NewMap(tag.MapUpdate{MultiKV: []core.KeyValue{key.New("user id").String(`"a"`)}}),
+		SpanContext: core.SpanContext{
+			TraceID: core.TraceID{High: 1, Low: 2},
+			SpanID:  3,
+		},
+	}
+	for _, tt := range []struct {
+		name string
+		opts JSONOptions
+		want string
+	}{
+		{
+			"default",
+			JSONOptions{},
+			`{"ts":"2019-07-01T12:00:00.5Z","type":"finish_span","name":"hello","dur":"1.5ms","attributes":{"user id":"\"a\""},"span_id":"0000000000000003","trace_id":"00000000000000010000000000000002"}` + "\n",
+		},
+		{
+			"unix nano",
+			JSONOptions{TimeFormat: TimeFormatUnixNano},
+			`{"ts":1561982400500000000,"type":"finish_span",`,
+		},
+		{
+			"layout",
+			JSONOptions{TimeFormat: time.Kitchen},
+			`{"ts":"12:00PM","type":"finish_span",`,
+		},
+		{
+			"pretty",
+			JSONOptions{Pretty: true},
+			"{\n  \"ts\": \"2019-07-01T12:00:00.5Z\",\n  \"type\": \"finish_span\",\n",
+		},
+	} {
+		if got := EventToJSON(ev, tt.opts); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: got %q, want prefix %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEventToJSONAttributeTypes(t *testing.T) {
+	ev := reader.Event{
+		Type: reader.ADD_EVENT,
+		Time: time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC),
+		Attributes: tag.NewMap(tag.MapUpdate{MultiKV: []core.KeyValue{
+			key.New("a").Int64(-1),
+			key.New("b").Float64(0.5),
+			key.New("c").Bool(true),
+			key.New("d").Strings([]string{"x", "y"}),
+			key.New("e").Int64s([]int64{1, 2}),
+			key.New("f").Float64s([]float64{math.NaN()}),
+		}}),
+	}
+	want := `"attributes":{"a":-1,"b":0.5,"c":true,"d":["x","y"],"e":[1,2],"f":"[NaN]"}`
+	if got := EventToJSON(ev, JSONOptions{}); !strings.Contains(got, want) {
+		t.Errorf("got %q, want it to contain %q", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/experimental/streaming/exporter/internal/eventname"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+	"go.opentelemetry.io/experimental/streaming/sdk"
+)
+
+// AppendLogfmt appends data to buf as a single logfmt line, e.g.,
+//
+//	ts=2019-07-01T12:00:00.5Z type=finish_span name=hello dur=1.5ms span_id=... trace_id=...
+//
+// so that events can be ingested by log pipelines that parse logfmt.
+func AppendLogfmt(buf *strings.Builder, data reader.Event) {
+	buf.WriteString("ts=")
+	buf.WriteString(data.Time.UTC().Format(time.RFC3339Nano))
+
+	appendLogfmtPair(buf, "type", eventname.Of(data.Type))
+
+	switch data.Type {
+	case reader.START_SPAN:
+		appendLogfmtPair(buf, "name", data.Name)
+		if data.Kind != apitrace.SpanKindUnspecified {
+			appendLogfmtPair(buf, "kind", data.Kind.String())
+		}
+		if data.Parent.HasSpanID() {
+			appendLogfmtPair(buf, parentSpanIDKey.Variable.Name, data.Parent.SpanIDString())
+		}
+	case reader.FINISH_SPAN:
+		appendLogfmtPair(buf, "name", data.Name)
+		appendLogfmtPair(buf, "dur", data.Duration.String())
+	case reader.ADD_EVENT:
+		appendLogfmtPair(buf, "msg", data.Message)
+	case reader.RECORD_STATS:
+		for _, s := range data.Stats {
+			appendLogfmtPair(buf, s.Measure.V().Name, strconv.FormatFloat(s.Value, 'g', -1, 64))
+		}
+	case reader.SET_STATUS:
+		appendLogfmtPair(buf, "status", data.Status.String())
+		if data.Message != "" {
+			appendLogfmtPair(buf, "msg", data.Message)
+		}
+	}
+
+	f := func(skipIf bool) func(kv core.KeyValue) bool {
+		return func(kv core.KeyValue) bool {
+			if skipIf && data.Attributes.HasValue(kv.Key) {
+				return true
+			}
+			appendLogfmtPair(buf, kv.Key.Variable.Name, kv.Value.Emit())
+			return true
+		}
+	}
+	if data.Attributes != nil {
+		data.Attributes.ForeachOrdered(f(false))
+	}
+	if data.Tags != nil {
+		data.Tags.ForeachOrdered(f(true))
+	}
+	if data.SpanContext.HasSpanID() {
+		appendLogfmtPair(buf, sdk.SpanIDKey.Variable.Name, data.SpanContext.SpanIDString())
+	}
+	if data.SpanContext.HasTraceID() {
+		appendLogfmtPair(buf, sdk.TraceIDKey.Variable.Name, data.SpanContext.TraceIDString())
+	}
+	buf.WriteString("\n")
+}
+
+// EventToLogfmt returns data formatted by AppendLogfmt.
+func EventToLogfmt(data reader.Event) string {
+	var buf strings.Builder
+	AppendLogfmt(&buf, data)
+	return buf.String()
+}
+
+// appendLogfmtPair appends " k=v", sanitizing k and quoting v if needed.
+func appendLogfmtPair(buf *strings.Builder, k, v string) {
+	buf.WriteString(" ")
+	buf.WriteString(logfmtKey(k))
+	buf.WriteString("=")
+	if v == "" || strings.ContainsAny(v, " =\"\\") || strings.IndexFunc(v, isControl) >= 0 {
+		buf.WriteString(strconv.Quote(v))
+		return
+	}
+	buf.WriteString(v)
+}
+
+// logfmtKey returns k with the characters that would end or corrupt a
+// logfmt key replaced by underscores. Keys cannot be quoted, as logfmt
+// parsers only accept quoting in values.
+func logfmtKey(k string) string {
+	if k == "" {
+		return "_"
+	}
+	if strings.IndexFunc(k, isInvalidKeyRune) < 0 {
+		return k
+	}
+	return strings.Map(func(r rune) rune {
+		if isInvalidKeyRune(r) {
+			return '_'
+		}
+		return r
+	}, k)
+}
+
+func isInvalidKeyRune(r rune) bool {
+	return r == ' ' || r == '=' || r == '"' || r == '\\' || isControl(r)
+}
+
+func isControl(r rune) bool {
+	return r < ' ' || r == 0x7f
+}
@@ -0,0 +1,85 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+)
+
+func TestAppendLogfmtPair(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		k, v string
+		want string
+	}{
+		{"plain", "k", "v", " k=v"},
+		{"empty value", "k", "", ` k=""`},
+		{"space in value", "k", "a b", ` k="a b"`},
+		{"equals in value", "k", "a=b", ` k="a=b"`},
+		{"quote in value", "k", `say "hi"`, ` k="say \"hi\""`},
+		{"backslash in value", "k", `a\b`, ` k="a\\b"`},
+		{"newline in value", "k", "a\nb", ` k="a\nb"`},
+		{"unicode value", "k", "héllo", " k=héllo"},
+		{"space in key", "http method", "GET", " http_method=GET"},
+		{"equals in key", "a=b", "v", " a_b=v"},
+		{"quote in key", `a"b`, "v", " a_b=v"},
+		{"backslash in key", `a\b`, "v", " a_b=v"},
+		{"control in key", "a\tb\x7f", "v", " a_b_=v"},
+		{"empty key", "", "v", " _=v"},
+		{"dotted key", "http.status_code", "200", " http.status_code=200"},
+	} {
+		var buf strings.Builder
+		appendLogfmtPair(&buf, tt.k, tt.v)
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEventToLogfmt(t *testing.T) {
+	attrs := tag.NewMap(tag.MapUpdate{MultiKV: []core.KeyValue{
+		key.New("user id").String("a b"),
+		key.New("x=y").Int64(1),
+	}})
+	got := EventToLogfmt(reader.Event{
+		Type:       reader.ADD_EVENT,
+		Time:       time.Date(2019, 7, 1, 12, 0, 0, 500000000, time.UTC),
+		Message:    `said "hi"`,
+		Attributes: attrs,
+		SpanContext: core.SpanContext{
+			TraceID: core.TraceID{High: 1, Low: 2},
+			SpanID:  3,
+		},
+	})
+	want := `ts=2019-07-01T12:00:00.5Z type=add_event msg="said \"hi\""`
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("got %q, want prefix %q", got, want)
+	}
+	for _, pair := range []string{` user_id="a b"`, ` x_y=1`, ` span_id=0000000000000003`, ` trace_id=00000000000000010000000000000002`} {
+		if !strings.Contains(got, pair) {
+			t.Errorf("got %q, want it to contain %q", got, pair)
+		}
+	}
+	if !strings.HasSuffix(got, "\n") || strings.Count(got, "\n") != 1 {
+		t.Errorf("got %q, want a single line", got)
+	}
+}
@@ -16,14 +16,16 @@ package reader
 
 import (
 	"fmt"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc/codes"
 
 	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/errorhandler"
 	"go.opentelemetry.io/api/stats"
 	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
 	"go.opentelemetry.io/experimental/streaming/exporter/observer"
 )
 
@@ -47,8 +49,9 @@ type Event struct {
 
 	Duration time.Duration
 	Name     string
-	Message  string
+	Message  string // ADD_EVENT message or SET_STATUS description
 	Status   codes.Code
+	Kind     apitrace.SpanKind // START_SPAN
 }
 
 type Measurement struct {
@@ -58,21 +61,32 @@ type Measurement struct {
 }
 
 type readerObserver struct {
+	dropped uint64 // access atomically
+	evicted uint64 // access atomically
+
 	readers []Reader
 
+	// types is the set of event types wanted by any reader.
+	types EventTypeSet
+
 	// core.EventID -> *readerSpan or *readerScope
-	scopes sync.Map
+	scopes *stateMap
 
 	// core.EventID -> *readerMeasure
-	measures sync.Map
+	measures *stateMap
 
 	// core.EventID -> *readerMetric
-	metrics sync.Map
+	metrics *stateMap
+
+	// reorder passes the observed events to orderedObserve in the
+	// order of their sequence numbers.
+	reorder *reorderBuffer
 }
 
 type readerSpan struct {
 	name        string
 	start       time.Time
+	startMono   time.Duration
 	startTags   tag.Map
 	spanContext core.SpanContext
 	status      codes.Code
@@ -94,29 +108,22 @@ type readerScope struct {
 	attributes tag.Map
 }
 
-const (
-	INVALID EventType = iota
-	START_SPAN
-	FINISH_SPAN
-	ADD_EVENT
-	MODIFY_ATTR
-	RECORD_STATS
-	SET_STATUS
-)
-
 // NewReaderObserver returns an implementation that computes the
 // necessary state needed by a reader to process events in memory.
 // Practically, this means tracking live metric handles and scope
 // attribute sets.
+//
+// Events are passed to the readers in the order of their sequence
+// numbers, as NewOrderedReaderObserver does with DefaultReorderWindow and
+// DefaultReorderTimeout.
 func NewReaderObserver(readers ...Reader) observer.Observer {
-	return &readerObserver{
-		readers: readers,
-	}
+	return NewOrderedReaderObserver(DefaultReorderWindow, DefaultReorderTimeout, readers...)
 }
 
+// Observe passes event on in the order of the sequence numbers of the
+// events, holding it back while an earlier event is missing.
 func (ro *readerObserver) Observe(event observer.Event) {
-	// TODO this should check for out-of-order events and buffer.
-	ro.orderedObserve(event)
+	ro.reorder.observe(event)
 }
 
 func (ro *readerObserver) orderedObserve(event observer.Event) {
@@ -137,18 +144,24 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 		span := &readerSpan{
 			name:        event.String,
 			start:       event.Time,
+			startMono:   event.Monotonic,
 			startTags:   tag.FromContext(event.Context),
 			spanContext: event.Scope.SpanContext,
 			readerScope: &readerScope{},
 		}
 
-		rattrs, _ := ro.readScope(event.Scope)
+		rattrs, _, ok := ro.readScope(event.Scope)
+		if !ok {
+			ro.drop(event, "scope %d not found", event.Scope.EventID)
+			return
+		}
 
 		span.readerScope.span = span
 		span.readerScope.attributes = rattrs
 
 		read.Name = span.name
 		read.Type = START_SPAN
+		read.Kind = event.Kind
 		read.SpanContext = span.spanContext
 		read.Attributes = rattrs
 
@@ -158,7 +171,11 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 
 			// Note: No parent attributes in the event for remote parents.
 		} else {
-			pattrs, pspan := ro.readScope(event.Parent)
+			pattrs, pspan, ok := ro.readScope(event.Parent)
+			if !ok {
+				ro.drop(event, "parent scope %d not found", event.Parent.EventID)
+				return
+			}
 
 			if pspan != nil {
 				// Local parent
@@ -170,16 +187,17 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 		ro.scopes.Store(event.Sequence, span)
 
 	case observer.FINISH_SPAN:
-		attrs, span := ro.readScope(event.Scope)
+		attrs, span, _ := ro.readScope(event.Scope)
 		if span == nil {
-			panic(fmt.Sprint("span not found", event.Scope))
+			ro.drop(event, "span %d not found", event.Scope.EventID)
+			return
 		}
 
 		read.Name = span.name
 		read.Type = FINISH_SPAN
 
 		read.Attributes = attrs
-		read.Duration = event.Time.Sub(span.start)
+		read.Duration = spanDuration(span, event)
 		read.Tags = span.startTags
 		read.SpanContext = span.spanContext
 
@@ -196,7 +214,8 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 		} else {
 			parentI, has := ro.scopes.Load(sid.EventID)
 			if !has {
-				panic("parent scope not found")
+				ro.drop(event, "parent scope %d not found", sid.EventID)
+				return
 			}
 			if parent, ok := parentI.(*readerScope); ok {
 				m = parent.attributes
@@ -222,7 +241,7 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 
 		ro.scopes.Store(event.Sequence, sc)
 
-		if event.Type == observer.NEW_SCOPE {
+		if event.Type == observer.NEW_SCOPE || !ro.types.Contains(MODIFY_ATTR) {
 			return
 		}
 
@@ -244,7 +263,8 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 	case observer.NEW_METRIC:
 		measureI, has := ro.measures.Load(event.Scope.EventID)
 		if !has {
-			panic("metric measure not found")
+			ro.drop(event, "measure %d not found", event.Scope.EventID)
+			return
 		}
 		metric := &readerMetric{
 			readerMeasure: measureI.(*readerMeasure),
@@ -253,10 +273,17 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 		return
 
 	case observer.ADD_EVENT:
+		if !ro.types.Contains(ADD_EVENT) {
+			return
+		}
 		read.Type = ADD_EVENT
 		read.Message = event.String
 
-		attrs, span := ro.readScope(event.Scope)
+		attrs, span, ok := ro.readScope(event.Scope)
+		if !ok {
+			ro.drop(event, "scope %d not found", event.Scope.EventID)
+			return
+		}
 		read.Attributes = attrs.Apply(tag.MapUpdate{
 			MultiKV: event.Attributes,
 		})
@@ -265,9 +292,16 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 		}
 
 	case observer.RECORD_STATS:
+		if !ro.types.Contains(RECORD_STATS) {
+			return
+		}
 		read.Type = RECORD_STATS
 
-		_, span := ro.readScope(event.Scope)
+		_, span, ok := ro.readScope(event.Scope)
+		if !ok {
+			ro.drop(event, "scope %d not found", event.Scope.EventID)
+			return
+		}
 		if span != nil {
 			read.SpanContext = span.spanContext
 		}
@@ -281,14 +315,20 @@ func (ro *readerObserver) orderedObserve(event observer.Event) {
 	case observer.SET_STATUS:
 		read.Type = SET_STATUS
 		read.Status = event.Status
-		_, span := ro.readScope(event.Scope)
+		read.Message = event.String
+		_, span, ok := ro.readScope(event.Scope)
+		if !ok {
+			ro.drop(event, "scope %d not found", event.Scope.EventID)
+			return
+		}
 		if span != nil {
 			span.status = event.Status
 			read.SpanContext = span.spanContext
 		}
 
 	default:
-		panic(fmt.Sprint("Unhandled case: ", event.Type))
+		ro.drop(event, "unknown event type")
+		return
 	}
 
 	for _, reader := range ro.readers {
@@ -314,27 +354,64 @@ func (ro *readerObserver) readMeasureScope(m stats.Measure) (tag.Map, *readerSpa
 	return nil, nil
 }
 
-func (ro *readerObserver) readScope(id observer.ScopeID) (tag.Map, *readerSpan) {
+// readScope returns the attributes and span of a scope, and false if the
+// scope is unknown.
+func (ro *readerObserver) readScope(id observer.ScopeID) (tag.Map, *readerSpan, bool) {
 	if id.EventID == 0 {
-		return tag.NewEmptyMap(), nil
+		return tag.NewEmptyMap(), nil, true
 	}
 	ev, has := ro.scopes.Load(id.EventID)
 	if !has {
-		panic(fmt.Sprintln("scope not found", id.EventID))
+		return tag.NewEmptyMap(), nil, false
 	}
 	if sp, ok := ev.(*readerScope); ok {
-		return sp.attributes, sp.span
+		return sp.attributes, sp.span, true
 	} else if sp, ok := ev.(*readerSpan); ok {
-		return sp.attributes, sp
+		return sp.attributes, sp, true
+	}
+	return tag.NewEmptyMap(), nil, true
+}
+
+// spanDuration returns the time between the start of span and its finish
+// event. It prefers the monotonic offsets set by observer.Record and falls
+// back to the wall clock for events that were not recorded through it.
+func spanDuration(span *readerSpan, finish observer.Event) time.Duration {
+	if span.startMono != 0 && finish.Monotonic != 0 {
+		return finish.Monotonic - span.startMono
 	}
-	return tag.NewEmptyMap(), nil
+	return finish.Time.Sub(span.start)
+}
+
+// drop counts event as dropped and reports why to the errorhandler
+// package, whose default handler logs it.
+func (ro *readerObserver) drop(event observer.Event, format string, args ...interface{}) {
+	atomic.AddUint64(&ro.dropped, 1)
+	errorhandler.Handle(fmt.Errorf("reader: dropped %v event %d: %s", event.Type, event.Sequence, fmt.Sprintf(format, args...)))
+}
+
+// DroppedEvents returns the number of events an observer returned by
+// NewReaderObserver or NewOrderedReaderObserver dropped because they
+// referred to unknown scopes or measures, or were of unknown types.
+func DroppedEvents(o observer.Observer) uint64 {
+	if ro := readerObserverOf(o); ro != nil {
+		return atomic.LoadUint64(&ro.dropped)
+	}
+	return 0
+}
+
+// readerObserverOf returns the readerObserver of o, or nil if o is not an
+// observer of this package.
+func readerObserverOf(o observer.Observer) *readerObserver {
+	ro, _ := o.(*readerObserver)
+	return ro
 }
 
 func (ro *readerObserver) cleanupSpan(id observer.EventID) {
 	for id != 0 {
 		ev, has := ro.scopes.Load(id)
 		if !has {
-			panic(fmt.Sprintln("scope not found", id))
+			// Already cleaned up with another span of the chain.
+			return
 		}
 		ro.scopes.Delete(id)
 
@@ -0,0 +1,126 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/errorhandler"
+	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+)
+
+func TestObserveDropsInvalidEvents(t *testing.T) {
+	var errs []error
+	errorhandler.Set(func(err error) { errs = append(errs, err) })
+	defer errorhandler.Set(nil)
+
+	rr := &recordingReader{}
+	o := NewReaderObserver(rr)
+	unknown := observer.ScopeID{EventID: 99}
+	for _, ev := range []observer.Event{
+		{Sequence: 1, Type: observer.FINISH_SPAN, Scope: unknown},
+		{Sequence: 2, Type: observer.ADD_EVENT, Scope: unknown},
+		{Sequence: 3, Type: observer.MODIFY_ATTR, Scope: unknown},
+		{Sequence: 4, Type: observer.NEW_METRIC, Scope: unknown},
+		{Sequence: 5, Type: observer.SET_STATUS, Scope: unknown},
+		{Sequence: 6, Type: observer.EventType(100)},
+	} {
+		o.Observe(ev)
+	}
+
+	if len(rr.events) != 0 {
+		t.Errorf("read %d events, want all dropped", len(rr.events))
+	}
+	if got := DroppedEvents(o); got != 6 {
+		t.Errorf("DroppedEvents() = %d, want 6", got)
+	}
+	if len(errs) != 6 {
+		t.Errorf("handled %d errors, want 6: %v", len(errs), errs)
+	}
+}
+
+func TestBoundedReaderObserver(t *testing.T) {
+	errorhandler.Set(func(error) {})
+	defer errorhandler.Set(nil)
+
+	for _, tt := range []struct {
+		eviction Eviction
+		// finished is whether the span of the first scope can still
+		// finish after a third one started.
+		finished bool
+	}{
+		{FIFO, false},
+		{LRU, true},
+	} {
+		rr := &recordingReader{}
+		o := NewBoundedReaderObserver(Limits{MaxScopes: 2, Eviction: tt.eviction}, rr)
+		// The events are observed in the order they are recorded.
+		ids := nextEvents(5)
+		first := spanEvents(ids[0])
+		first[1].Sequence, first[2].Sequence = ids[2], ids[4]
+		second, third := spanEvents(ids[1]), spanEvents(ids[3])
+		o.Observe(first[0])
+		o.Observe(second[0])
+		// Using the first span makes it the most recently used.
+		o.Observe(first[1])
+		o.Observe(third[0])
+		o.Observe(first[2])
+
+		if got := EvictedEntries(o); got != 1 {
+			t.Errorf("%v: EvictedEntries() = %d, want 1", tt.eviction, got)
+		}
+		last := rr.events[len(rr.events)-1]
+		if finished := last.Type == FINISH_SPAN; finished != tt.finished {
+			t.Errorf("%v: first span finished %v, want %v", tt.eviction, finished, tt.finished)
+		}
+		// Finished spans release their scopes without counting as
+		// evicted.
+		if got := EvictedEntries(o); got != 1 {
+			t.Errorf("%v: EvictedEntries() = %d after a span finished, want 1", tt.eviction, got)
+		}
+	}
+}
+
+func TestSpanDurationIgnoresWallClockSteps(t *testing.T) {
+	start := time.Date(2019, 9, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name             string
+		startMono, mono  time.Duration
+		finishTime       time.Time
+		expectedDuration time.Duration
+	}{
+		// The wall clock stepped back an hour while the span ran.
+		{"monotonic", time.Second, 3 * time.Second, start.Add(-time.Hour), 2 * time.Second},
+		{"wall clock", 0, 0, start.Add(time.Second), time.Second},
+	} {
+		rr := &recordingReader{}
+		o := NewReaderObserver(rr)
+		events := spanEvents(1)
+		events[0].Time, events[0].Monotonic = start, tt.startMono
+		events[2].Time, events[2].Monotonic = tt.finishTime, tt.mono
+		for _, ev := range events {
+			o.Observe(ev)
+		}
+
+		last := rr.events[len(rr.events)-1]
+		if last.Type != FINISH_SPAN {
+			t.Fatalf("%s: last event is %v, want FINISH_SPAN", tt.name, last.Type)
+		}
+		if last.Duration != tt.expectedDuration {
+			t.Errorf("%s: Duration = %v, want %v", tt.name, last.Duration, tt.expectedDuration)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+)
+
+const (
+	// DefaultReorderWindow is the default number of events a reader
+	// observer holds back waiting for a missing one.
+	DefaultReorderWindow = 1024
+
+	// DefaultReorderTimeout is the default time a reader observer waits
+	// for a missing event.
+	DefaultReorderTimeout = 100 * time.Millisecond
+)
+
+// NewOrderedReaderObserver returns an observer like NewReaderObserver
+// holding back at most window events, for at most timeout, while an
+// earlier event is missing.
+//
+// Concurrent producers may record events out of order. The observer
+// passes them to its readers in the order of their sequence numbers, so
+// that a scope is always known before the events that refer to it. Events
+// following a missing one are held back until it arrives, for at most
+// timeout, or until window events are held back. The missing event is
+// then skipped, and passed on as soon as it arrives, as are events
+// recorded before the observer was created. Non-positive window and
+// timeout select DefaultReorderWindow and DefaultReorderTimeout.
+//
+// The observer expects every event recorded after its creation, so it
+// should be registered with observer.RegisterObserver right away.
+func NewOrderedReaderObserver(window int, timeout time.Duration, readers ...Reader) observer.Observer {
+	if window <= 0 {
+		window = DefaultReorderWindow
+	}
+	if timeout <= 0 {
+		timeout = DefaultReorderTimeout
+	}
+	ro := &readerObserver{
+		readers:  readers,
+		types:    subscriptions(readers),
+		scopes:   &stateMap{},
+		measures: &stateMap{},
+		metrics:  &stateMap{},
+	}
+	ro.reorder = &reorderBuffer{
+		deliver: ro.orderedObserve,
+		window:  window,
+		timeout: timeout,
+		next:    observer.LastEventID() + 1,
+		pending: make(map[observer.EventID]pendingEvent),
+	}
+	return ro
+}
+
+type pendingEvent struct {
+	event   observer.Event
+	arrival time.Time
+}
+
+// reorderBuffer passes events to deliver in the order of their sequence
+// numbers.
+type reorderBuffer struct {
+	deliver func(observer.Event)
+	window  int
+	timeout time.Duration
+
+	mu sync.Mutex
+	// next is the sequence number of the next event to pass on. It
+	// starts after the last event recorded when the buffer was created,
+	// rather than at the first event observed, which may well not be
+	// the first event recorded since.
+	next    observer.EventID
+	pending map[observer.EventID]pendingEvent
+	timer   *time.Timer
+}
+
+func (r *reorderBuffer) observe(event observer.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event.Sequence < r.next {
+		// Too late to be ordered: its place was skipped, or it was
+		// recorded before the buffer was created. Events without a
+		// sequence number cannot be ordered either.
+		r.deliver(event)
+		return
+	}
+	r.pending[event.Sequence] = pendingEvent{event: event, arrival: time.Now()}
+	r.flush(time.Now())
+}
+
+// flush passes on the pending events that are next in order, skipping
+// missing events once the window is full or the oldest pending event
+// timed out. It arms the timer while events are held back. r.mu must be
+// held.
+func (r *reorderBuffer) flush(now time.Time) {
+	for {
+		for {
+			p, ok := r.pending[r.next]
+			if !ok {
+				break
+			}
+			delete(r.pending, r.next)
+			r.next++
+			r.deliver(p.event)
+		}
+		if len(r.pending) == 0 {
+			return
+		}
+		first, oldest := r.firstPending()
+		if len(r.pending) < r.window && now.Sub(oldest) < r.timeout {
+			if r.timer == nil {
+				r.timer = time.AfterFunc(r.timeout-now.Sub(oldest), r.expire)
+			}
+			return
+		}
+		r.next = first
+	}
+}
+
+// firstPending returns the lowest sequence number and the earliest arrival
+// of the pending events. r.mu must be held.
+func (r *reorderBuffer) firstPending() (observer.EventID, time.Time) {
+	var first observer.EventID
+	var oldest time.Time
+	for seq, p := range r.pending {
+		if first == 0 || seq < first {
+			first = seq
+		}
+		if oldest.IsZero() || p.arrival.Before(oldest) {
+			oldest = p.arrival
+		}
+	}
+	return first, oldest
+}
+
+func (r *reorderBuffer) expire() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timer = nil
+	r.flush(time.Now())
+}
@@ -0,0 +1,138 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+)
+
+func spanEvents(first observer.EventID) []observer.Event {
+	scope := observer.ScopeID{EventID: first}
+	return []observer.Event{
+		{Sequence: first, Type: observer.START_SPAN, Context: context.Background(), String: "op"},
+		{Sequence: first + 1, Type: observer.ADD_EVENT, Scope: scope, String: "hello"},
+		{Sequence: first + 2, Type: observer.FINISH_SPAN, Scope: scope},
+	}
+}
+
+func readTypes(events []Event) []EventType {
+	var types []EventType
+	for _, ev := range events {
+		types = append(types, ev.Type)
+	}
+	return types
+}
+
+// nextEvents allocates n sequence numbers, as events recorded by
+// observer.Record get.
+func nextEvents(n int) []observer.EventID {
+	ids := make([]observer.EventID, n)
+	for i := range ids {
+		ids[i] = observer.NextEventID()
+	}
+	return ids
+}
+
+func TestOrderedReaderObserver(t *testing.T) {
+	rr := &recordingReader{}
+	o := NewOrderedReaderObserver(0, time.Hour, rr)
+	events := spanEvents(nextEvents(3)[0])
+	o.Observe(events[0])
+	o.Observe(events[2])
+	if len(rr.events) != 1 {
+		t.Fatalf("read %d events before the missing one arrived, want 1", len(rr.events))
+	}
+	o.Observe(events[1])
+
+	got := readTypes(rr.events)
+	if want := []EventType{START_SPAN, ADD_EVENT, FINISH_SPAN}; len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("read %v, want %v", got, want)
+	}
+}
+
+func TestReaderObserverOrdersFirstEvents(t *testing.T) {
+	rr := &recordingReader{}
+	before := observer.NextEventID()
+	o := NewReaderObserver(rr)
+	events := spanEvents(nextEvents(3)[0])
+	// The first event observed is not the first recorded: it waits for
+	// the start of its span.
+	o.Observe(events[1])
+	if len(rr.events) != 0 {
+		t.Fatalf("read %d events before the first one arrived, want 0", len(rr.events))
+	}
+	o.Observe(events[0])
+	o.Observe(events[2])
+	if got := readTypes(rr.events); len(got) != 3 || got[0] != START_SPAN || got[1] != ADD_EVENT {
+		t.Errorf("read %v, want the span in order", got)
+	}
+	if n := DroppedEvents(o); n != 0 {
+		t.Errorf("dropped %d events, want 0", n)
+	}
+
+	// Events recorded before the observer was created are passed on.
+	o.Observe(observer.Event{Sequence: before, Type: observer.SET_STATUS})
+	if len(rr.events) != 4 {
+		t.Errorf("read %d events, want the early one passed on", len(rr.events))
+	}
+}
+
+func TestOrderedReaderObserverWindow(t *testing.T) {
+	rr := &recordingReader{}
+	o := NewOrderedReaderObserver(2, time.Hour, rr)
+	ids := nextEvents(4)
+	o.Observe(observer.Event{Sequence: ids[0], Type: observer.SET_STATUS})
+	// ids[1] is missing: ids[2] waits, and ids[3] fills the window.
+	o.Observe(observer.Event{Sequence: ids[2], Type: observer.SET_STATUS})
+	if len(rr.events) != 1 {
+		t.Fatalf("read %d events, want the third held back", len(rr.events))
+	}
+	o.Observe(observer.Event{Sequence: ids[3], Type: observer.SET_STATUS})
+	if len(rr.events) != 3 {
+		t.Fatalf("read %d events, want the second skipped once the window is full", len(rr.events))
+	}
+	// A skipped event is passed on when it arrives.
+	o.Observe(observer.Event{Sequence: ids[1], Type: observer.SET_STATUS})
+	if len(rr.events) != 4 || rr.events[3].Sequence != ids[1] {
+		t.Errorf("read %d events, want the late one last", len(rr.events))
+	}
+}
+
+type chanReader chan Event
+
+func (c chanReader) Read(event Event) {
+	c <- event
+}
+
+func TestOrderedReaderObserverTimeout(t *testing.T) {
+	c := make(chanReader, 2)
+	o := NewOrderedReaderObserver(0, 10*time.Millisecond, c)
+	ids := nextEvents(3)
+	o.Observe(observer.Event{Sequence: ids[0], Type: observer.SET_STATUS})
+	<-c
+	o.Observe(observer.Event{Sequence: ids[2], Type: observer.SET_STATUS})
+	select {
+	case ev := <-c:
+		if ev.Sequence != ids[2] {
+			t.Errorf("read event %d, want %d", ev.Sequence, ids[2])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event held back after the timeout")
+	}
+}
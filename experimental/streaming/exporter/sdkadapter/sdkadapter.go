@@ -0,0 +1,227 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sdkadapter connects the span exporters of the SDK in sdk/trace
+// and the readers of the streaming SDK, so that an exporter written for
+// one pipeline can be used with the other.
+//
+// NewObserver and NewReader feed the spans of the streaming SDK to a
+// trace.Exporter, e.g.,
+//
+//	observer.RegisterObserver(sdkadapter.NewObserver(zipkinExporter))
+//
+// and NewExporter replays the spans of sdk/trace to a reader.Reader as
+// the events the streaming SDK would have produced for them.
+package sdkadapter // import "go.opentelemetry.io/experimental/streaming/exporter/sdkadapter"
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+	"go.opentelemetry.io/experimental/streaming/exporter/spandata"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+type spanExporter struct {
+	exporter trace.Exporter
+}
+
+// NewObserver returns an observer.Observer that passes every finished
+// span to e.
+func NewObserver(e trace.Exporter) observer.Observer {
+	return spandata.NewReaderObserver(&spanExporter{exporter: e})
+}
+
+// NewReader returns a reader.Reader that collects the events of each span
+// and passes the span to e when it finishes.
+func NewReader(e trace.Exporter) reader.Reader {
+	return spandata.NewReader(&spanExporter{exporter: e})
+}
+
+// Read implements spandata.Reader.
+func (s *spanExporter) Read(span *spandata.Span) {
+	if sd := SpanData(span); sd != nil {
+		s.exporter.ExportSpan(sd)
+	}
+}
+
+// SpanData converts the events of a finished span into SpanData. It
+// returns nil if the events do not start with START_SPAN.
+//
+// The streaming SDK reports the attributes of the span with every event,
+// so the attributes of a message event are those that the span did not
+// have, or had with another value, when the event was added.
+func SpanData(span *spandata.Span) *trace.SpanData {
+	if len(span.Events) == 0 || span.Events[0].Type != reader.START_SPAN {
+		return nil
+	}
+	start := span.Events[0]
+	sd := &trace.SpanData{
+		SpanContext:  start.SpanContext,
+		ParentSpanID: start.Parent.SpanID,
+		SpanKind:     start.Kind,
+		Name:         start.Name,
+		StartTime:    start.Time,
+		EndTime:      start.Time,
+		Status:       codes.OK,
+		// Only local parents come with their attributes.
+		HasRemoteParent: start.Parent.HasSpanID() && start.ParentAttributes == nil,
+	}
+
+	attrs := start.Attributes
+	for _, ev := range span.Events[1:] {
+		switch ev.Type {
+		case reader.MODIFY_ATTR:
+			attrs = ev.Attributes
+		case reader.ADD_EVENT:
+			sd.AddMessageEvent(ev.Time, ev.Message, eventAttributes(attrs, ev.Attributes)...)
+		case reader.SET_STATUS:
+			sd.Status = ev.Status
+			sd.StatusMessage = ev.Message
+		case reader.FINISH_SPAN:
+			sd.EndTime = start.Time.Add(ev.Duration)
+			attrs = ev.Attributes
+		}
+	}
+	if attrs != nil && attrs.Len() > 0 {
+		sd.Attributes = make(map[string]interface{}, attrs.Len())
+		attrs.Foreach(func(kv core.KeyValue) bool {
+			sd.Attributes[kv.Key.Variable.Name] = kv.Value
+			return true
+		})
+	}
+	return sd
+}
+
+// eventAttributes returns the attributes in event that are not in span.
+func eventAttributes(span, event tag.Map) []core.KeyValue {
+	if event == nil {
+		return nil
+	}
+	var attrs []core.KeyValue
+	event.Foreach(func(kv core.KeyValue) bool {
+		if span != nil {
+			if v, ok := span.Value(kv.Key); ok && v.Type == kv.Value.Type && v.Emit() == kv.Value.Emit() {
+				return true
+			}
+		}
+		attrs = append(attrs, kv)
+		return true
+	})
+	return attrs
+}
+
+type readerExporter struct {
+	reader reader.Reader
+}
+
+var _ trace.Exporter = (*readerExporter)(nil)
+
+// NewExporter returns a trace.Exporter that passes each span to r as a
+// START_SPAN event carrying the span attributes, an ADD_EVENT per message
+// event, a SET_STATUS event unless the status is OK, and a FINISH_SPAN
+// event.
+func NewExporter(r reader.Reader) trace.Exporter {
+	return &readerExporter{reader: r}
+}
+
+// ExportSpan implements trace.Exporter.
+func (e *readerExporter) ExportSpan(sd *trace.SpanData) {
+	attrs := tag.NewMap(tag.MapUpdate{MultiKV: keyValues(sd.Attributes)})
+	start := reader.Event{
+		Type:        reader.START_SPAN,
+		Time:        sd.StartTime,
+		SpanContext: sd.SpanContext,
+		Name:        sd.Name,
+		Kind:        sd.SpanKind,
+		Attributes:  attrs,
+		Tags:        tag.NewEmptyMap(),
+	}
+	if sd.ParentSpanID != 0 {
+		start.Parent = core.SpanContext{
+			TraceID:      sd.SpanContext.TraceID,
+			SpanID:       sd.ParentSpanID,
+			TraceOptions: sd.SpanContext.TraceOptions,
+		}
+		if !sd.HasRemoteParent {
+			start.ParentAttributes = tag.NewEmptyMap()
+		}
+	}
+	e.reader.Read(start)
+
+	for it := sd.Events(); it.Next(); {
+		ev := it.Event()
+		e.reader.Read(reader.Event{
+			Type:        reader.ADD_EVENT,
+			Time:        ev.Time(),
+			SpanContext: sd.SpanContext,
+			Message:     ev.Message(),
+			Attributes:  attrs.Apply(tag.MapUpdate{MultiKV: ev.Attributes()}),
+			Tags:        tag.NewEmptyMap(),
+		})
+	}
+
+	if sd.Status != codes.OK {
+		e.reader.Read(reader.Event{
+			Type:        reader.SET_STATUS,
+			Time:        sd.EndTime,
+			SpanContext: sd.SpanContext,
+			Status:      sd.Status,
+			Message:     sd.StatusMessage,
+			Attributes:  attrs,
+			Tags:        tag.NewEmptyMap(),
+		})
+	}
+
+	e.reader.Read(reader.Event{
+		Type:        reader.FINISH_SPAN,
+		Time:        sd.EndTime,
+		SpanContext: sd.SpanContext,
+		Name:        sd.Name,
+		Duration:    sd.EndTime.Sub(sd.StartTime),
+		Attributes:  attrs,
+		Tags:        tag.NewEmptyMap(),
+	})
+}
+
+// keyValues converts the attributes of SpanData, which hold core.Values
+// when recorded by the SDK, into key-values.
+func keyValues(attrs map[string]interface{}) []core.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]core.KeyValue, 0, len(attrs))
+	for name, v := range attrs {
+		k := key.New(name)
+		switch v := v.(type) {
+		case core.Value:
+			kvs = append(kvs, core.KeyValue{Key: k, Value: v})
+		case string:
+			kvs = append(kvs, k.String(v))
+		case bool:
+			kvs = append(kvs, k.Bool(v))
+		case int64:
+			kvs = append(kvs, k.Int64(v))
+		default:
+			kvs = append(kvs, k.String(fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
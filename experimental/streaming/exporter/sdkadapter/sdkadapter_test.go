@@ -0,0 +1,152 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkadapter
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+type exporterFunc func(*trace.SpanData)
+
+func (f exporterFunc) ExportSpan(sd *trace.SpanData) { f(sd) }
+
+type readerFunc func(reader.Event)
+
+func (f readerFunc) Read(ev reader.Event) { f(ev) }
+
+func testSpan() *trace.SpanData {
+	start := time.Unix(100, 0)
+	sd := &trace.SpanData{
+		SpanContext: core.SpanContext{
+			TraceID:      core.TraceID{High: 1, Low: 2},
+			SpanID:       3,
+			TraceOptions: core.TraceOptionSampled,
+		},
+		ParentSpanID:    4,
+		HasRemoteParent: true,
+		SpanKind:        apitrace.SpanKindClient,
+		Name:            "op",
+		StartTime:       start,
+		EndTime:         start.Add(time.Second),
+		Status:          codes.NotFound,
+		StatusMessage:   "no such user",
+		Attributes: map[string]interface{}{
+			"route": key.New("route").String("/users").Value,
+		},
+	}
+	sd.AddMessageEvent(start.Add(time.Millisecond), "retry", key.New("attempt").Int64(2))
+	sd.AddMessageEvent(start.Add(2*time.Millisecond), "done")
+	return sd
+}
+
+func TestExporterEvents(t *testing.T) {
+	var types []reader.EventType
+	var events []reader.Event
+	NewExporter(readerFunc(func(ev reader.Event) {
+		types = append(types, ev.Type)
+		events = append(events, ev)
+	})).ExportSpan(testSpan())
+
+	want := []reader.EventType{reader.START_SPAN, reader.ADD_EVENT, reader.ADD_EVENT, reader.SET_STATUS, reader.FINISH_SPAN}
+	if len(types) != len(want) {
+		t.Fatalf("got event types %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("got event types %v, want %v", types, want)
+		}
+	}
+	if start := events[0]; start.Name != "op" || start.Parent.SpanID != 4 || start.ParentAttributes != nil {
+		t.Errorf("got start event %+v", start)
+	}
+	retry := events[1]
+	if v, ok := retry.Attributes.Value(key.New("attempt")); !ok || v.Int64 != 2 {
+		t.Errorf("retry event is missing its attribute: %v", retry.Attributes)
+	}
+	if _, ok := retry.Attributes.Value(key.New("route")); !ok {
+		t.Error("retry event is missing the span attributes")
+	}
+	if finish := events[4]; finish.Duration != time.Second {
+		t.Errorf("finish duration = %v, want 1s", finish.Duration)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var got *trace.SpanData
+	r := NewReader(exporterFunc(func(sd *trace.SpanData) { got = sd }))
+	want := testSpan()
+	NewExporter(r).ExportSpan(want)
+
+	if got == nil {
+		t.Fatal("no span exported")
+	}
+	if got.SpanContext != want.SpanContext || got.ParentSpanID != want.ParentSpanID ||
+		got.HasRemoteParent != want.HasRemoteParent || got.Name != want.Name ||
+		got.Status != want.Status || got.StatusMessage != want.StatusMessage || got.SpanKind != want.SpanKind {
+		t.Errorf("got span %+v, want %+v", got, want)
+	}
+	if !got.StartTime.Equal(want.StartTime) || !got.EndTime.Equal(want.EndTime) {
+		t.Errorf("got times %v to %v, want %v to %v", got.StartTime, got.EndTime, want.StartTime, want.EndTime)
+	}
+	if v, ok := got.Attributes["route"].(core.Value); len(got.Attributes) != 1 || !ok || v.String != "/users" {
+		t.Errorf("got attributes %v, want route=/users", got.Attributes)
+	}
+
+	it := got.Events()
+	if it.Len() != 2 {
+		t.Fatalf("got %d events, want 2", it.Len())
+	}
+	it.Next()
+	retry := it.Event()
+	if attrs := retry.Attributes(); retry.Message() != "retry" || len(attrs) != 1 || attrs[0].Key.Variable.Name != "attempt" {
+		t.Errorf("got event %q with attributes %v, want retry with attempt", retry.Message(), attrs)
+	}
+	it.Next()
+	if done := it.Event(); done.Message() != "done" || len(done.Attributes()) != 0 {
+		t.Errorf("got event %q with attributes %v, want done without attributes", done.Message(), done.Attributes())
+	}
+}
+
+func TestRoundTripLocalParent(t *testing.T) {
+	var got *trace.SpanData
+	sd := testSpan()
+	sd.HasRemoteParent = false
+	sd.Status = codes.OK
+	NewExporter(NewReader(exporterFunc(func(sd *trace.SpanData) { got = sd }))).ExportSpan(sd)
+	if got == nil || got.HasRemoteParent || got.Status != codes.OK {
+		t.Errorf("got span %+v, want a local parent and OK status", got)
+	}
+}
+
+func TestSpanDataWithoutStart(t *testing.T) {
+	exported := false
+	r := NewReader(exporterFunc(func(*trace.SpanData) { exported = true }))
+	r.Read(reader.Event{
+		Type:        reader.FINISH_SPAN,
+		SpanContext: core.SpanContext{SpanID: 1},
+	})
+	if exported {
+		t.Error("exported a span that never started")
+	}
+}
@@ -15,6 +15,10 @@
 package spandata
 
 import (
+	"sync/atomic"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+
 	"go.opentelemetry.io/api/core"
 	"go.opentelemetry.io/experimental/streaming/exporter/observer"
 	"go.opentelemetry.io/experimental/streaming/exporter/reader"
@@ -31,13 +35,59 @@ type Span struct {
 type spanReader struct {
 	spans   map[core.SpanContext]*Span
 	readers []Reader
+
+	// lru replaces spans in readers made by NewBoundedReader.
+	lru      *simplelru.LRU
+	finished bool
+
+	evicted uint64 // access atomically
+	dropped uint64 // access atomically
 }
 
 func NewReaderObserver(readers ...Reader) observer.Observer {
-	return reader.NewReaderObserver(&spanReader{
+	return reader.NewReaderObserver(NewReader(readers...))
+}
+
+// NewReader returns a reader.Reader that collects the events of each span
+// and passes the span to readers when it finishes.
+func NewReader(readers ...Reader) reader.Reader {
+	return &spanReader{
 		spans:   map[core.SpanContext]*Span{},
 		readers: readers,
+	}
+}
+
+// NewBoundedReader returns a reader like NewReader that collects the
+// events of at most maxSpans unfinished spans. The span that received an
+// event the least recently is evicted to make room for a new one, so
+// that spans that never finish do not grow memory forever.
+func NewBoundedReader(maxSpans int, readers ...Reader) reader.Reader {
+	s := &spanReader{readers: readers}
+	s.lru, _ = simplelru.NewLRU(maxSpans, func(interface{}, interface{}) {
+		if !s.finished {
+			atomic.AddUint64(&s.evicted, 1)
+		}
 	})
+	return s
+}
+
+// EvictedSpans returns the number of unfinished spans a reader returned
+// by NewBoundedReader evicted.
+func EvictedSpans(r reader.Reader) uint64 {
+	if s, ok := r.(*spanReader); ok {
+		return atomic.LoadUint64(&s.evicted)
+	}
+	return 0
+}
+
+// DroppedEvents returns the number of events a reader returned by
+// NewReader or NewBoundedReader dropped because their span was unknown,
+// e.g., evicted.
+func DroppedEvents(r reader.Reader) uint64 {
+	if s, ok := r.(*spanReader); ok {
+		return atomic.LoadUint64(&s.dropped)
+	}
+	return 0
 }
 
 func (s *spanReader) Read(data reader.Event) {
@@ -48,11 +98,11 @@ func (s *spanReader) Read(data reader.Event) {
 	var span *Span
 	if data.Type == reader.START_SPAN {
 		span = &Span{Events: make([]reader.Event, 0, 4)}
-		s.spans[data.SpanContext] = span
+		s.store(data.SpanContext, span)
 	} else {
-		span = s.spans[data.SpanContext]
+		span = s.load(data.SpanContext)
 		if span == nil {
-			// TODO count and report this.
+			atomic.AddUint64(&s.dropped, 1)
 			return
 		}
 	}
@@ -63,6 +113,35 @@ func (s *spanReader) Read(data reader.Event) {
 		for _, r := range s.readers {
 			r.Read(span)
 		}
-		delete(s.spans, data.SpanContext)
+		s.delete(data.SpanContext)
+	}
+}
+
+func (s *spanReader) store(sc core.SpanContext, span *Span) {
+	if s.lru != nil {
+		s.lru.Add(sc, span)
+		return
+	}
+	s.spans[sc] = span
+}
+
+func (s *spanReader) load(sc core.SpanContext) *Span {
+	if s.lru != nil {
+		if v, ok := s.lru.Get(sc); ok {
+			return v.(*Span)
+		}
+		return nil
+	}
+	return s.spans[sc]
+}
+
+func (s *spanReader) delete(sc core.SpanContext) {
+	if s.lru != nil {
+		// Remove calls the eviction callback too.
+		s.finished = true
+		s.lru.Remove(sc)
+		s.finished = false
+		return
 	}
+	delete(s.spans, sc)
 }
@@ -15,19 +15,106 @@
 package stdout
 
 import (
+	"io"
 	"os"
+	"strings"
+	"sync"
 
+	"go.opentelemetry.io/api/event"
 	"go.opentelemetry.io/experimental/streaming/exporter/observer"
 	"go.opentelemetry.io/experimental/streaming/exporter/reader"
 	"go.opentelemetry.io/experimental/streaming/exporter/reader/format"
 )
 
-type stdoutLog struct{}
+type stdoutLog struct {
+	format func(reader.Event) string
+	color  bool
+
+	// w is written to instead of os.Stdout when set, under mu.
+	mu sync.Mutex
+	w  io.Writer
+}
 
 func New() observer.Observer {
-	return reader.NewReaderObserver(&stdoutLog{})
+	return reader.NewReaderObserver(&stdoutLog{format: format.EventToString})
+}
+
+// NewColor returns an observer that prints events to stdout like New,
+// coloring span events by severity for terminals: debug events are dim,
+// warnings yellow and errors red.
+func NewColor() observer.Observer {
+	return reader.NewReaderObserver(&stdoutLog{format: format.EventToString, color: true})
+}
+
+// NewLogfmt returns an observer that prints events to stdout in logfmt.
+func NewLogfmt() observer.Observer {
+	return reader.NewReaderObserver(&stdoutLog{format: format.EventToLogfmt})
+}
+
+// JSONOption configures the observer of NewJSON.
+type JSONOption func(*format.JSONOptions)
+
+// WithPrettyPrint indents the JSON objects over several lines.
+func WithPrettyPrint() JSONOption {
+	return func(o *format.JSONOptions) {
+		o.Pretty = true
+	}
+}
+
+// WithTimeFormat sets the layout of timestamps, as for time.Format, or
+// format.TimeFormatUnixNano. It defaults to time.RFC3339Nano.
+func WithTimeFormat(layout string) JSONOption {
+	return func(o *format.JSONOptions) {
+		o.TimeFormat = layout
+	}
+}
+
+// NewJSON returns an observer that prints events to stdout as JSON
+// objects, one per line unless pretty-printed, e.g., for jq.
+func NewJSON(opts ...JSONOption) observer.Observer {
+	var o format.JSONOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return reader.NewReaderObserver(&stdoutLog{format: func(data reader.Event) string {
+		return format.EventToJSON(data, o)
+	}})
+}
+
+// NewWriter returns an observer that writes events formatted by f, e.g.,
+// format.EventToLogfmt, to w, such as a RotatingFile or a test buffer.
+// Writes are serialized, so w need not be safe for concurrent use.
+func NewWriter(w io.Writer, f func(reader.Event) string) observer.Observer {
+	return reader.NewReaderObserver(&stdoutLog{format: f, w: w})
 }
 
 func (s *stdoutLog) Read(data reader.Event) {
-	os.Stdout.WriteString(format.EventToString(data))
+	line := s.format(data)
+	if s.color && data.Type == reader.ADD_EVENT {
+		line = colorize(line, reader.Severity(data))
+	}
+	if s.w == nil {
+		os.Stdout.WriteString(line)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	io.WriteString(s.w, line)
+}
+
+// ANSI escape sequences of the colors of severities.
+var severityColors = map[event.Severity]string{
+	event.Debug: "\x1b[2m",
+	event.Warn:  "\x1b[33m",
+	event.Error: "\x1b[31m",
+}
+
+// colorize colors line, keeping its trailing newline out of the color.
+func colorize(line string, s event.Severity) string {
+	color, ok := severityColors[s]
+	if !ok {
+		return line
+	}
+	body := strings.TrimSuffix(line, "\n")
+	return color + body + "\x1b[0m" + line[len(body):]
 }
@@ -0,0 +1,50 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stdout
+
+import (
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/api/event"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader/format"
+)
+
+func TestColorize(t *testing.T) {
+	for _, tt := range []struct {
+		severity event.Severity
+		want     string
+	}{
+		{event.Info, "retry [ ]\n"},
+		{event.Warn, "\x1b[33mretry [ ]\x1b[0m\n"},
+		{event.Error, "\x1b[31mretry [ ]\x1b[0m\n"},
+	} {
+		if got := colorize("retry [ ]\n", tt.severity); got != tt.want {
+			t.Errorf("colorize(%v) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf strings.Builder
+	s := &stdoutLog{format: format.EventToLogfmt, w: &buf}
+	s.Read(reader.Event{Type: reader.ADD_EVENT, Message: "hello"})
+	s.Read(reader.Event{Type: reader.ADD_EVENT, Message: "bye"})
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "msg=hello") || !strings.Contains(lines[1], "msg=bye") {
+		t.Errorf("wrote %q, want a line per event", buf.String())
+	}
+}
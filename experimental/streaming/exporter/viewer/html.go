@@ -0,0 +1,130 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package viewer
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+)
+
+type indexRow struct {
+	ID       string
+	Root     string
+	Spans    int
+	Start    string
+	Duration time.Duration
+}
+
+type waterfallRow struct {
+	*spanData
+	Indent int
+	Offset float64
+	Width  float64
+}
+
+var (
+	indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html><head><title>Traces</title></head><body>
+<h1>Recent traces</h1>
+<table>
+<tr><th>Trace</th><th>Root</th><th>Spans</th><th>Start</th><th>Duration</th></tr>
+{{range .}}<tr>
+<td><a href="trace?id={{.ID}}"><code>{{.ID}}</code></a></td>
+<td>{{.Root}}</td><td>{{.Spans}}</td><td>{{.Start}}</td><td>{{.Duration}}</td>
+</tr>{{end}}
+</table>
+</body></html>
+`))
+
+	traceTemplate = template.Must(template.New("trace").Parse(`<!DOCTYPE html>
+<html><head><title>Trace {{.ID}}</title>
+<style>
+.row { display: flex; font-family: monospace; }
+.name { width: 30%; white-space: nowrap; overflow: hidden; }
+.lane { width: 70%; height: 1.2em; position: relative; }
+.bar { position: absolute; height: 100%; background: #4a90d9; min-width: 1px; }
+.error .bar { background: #d9534f; }
+</style></head><body>
+<p><a href="./">All traces</a></p>
+<h1>Trace <code>{{.ID}}</code></h1>
+{{range .Rows}}<div class="row{{if .Status}} error{{end}}" title="{{if .Status}}{{.Status}}{{with .Message}}: {{.}}{{end}} {{end}}{{range .Attributes}}{{.Key.Variable.Name}}={{.Value.Emit}} {{end}}">
+<div class="name" style="padding-left: {{.Indent}}em">{{.Name}} ({{.Duration}})</div>
+<div class="lane"><div class="bar" style="left: {{printf "%.2f" .Offset}}%; width: {{printf "%.2f" .Width}}%"></div></div>
+</div>
+{{end}}
+</body></html>
+`))
+)
+
+func traceIDString(id core.TraceID) string {
+	return core.SpanContext{TraceID: id}.TraceIDString()
+}
+
+func (v *Viewer) serveIndex(w http.ResponseWriter) {
+	var rows []indexRow
+	for _, td := range v.snapshot() {
+		start, end := td.bounds()
+		spans, _ := td.waterfall()
+		row := indexRow{
+			ID:       traceIDString(td.id),
+			Spans:    len(td.spans),
+			Start:    start.Format(time.RFC3339Nano),
+			Duration: end.Sub(start),
+		}
+		if len(spans) > 0 {
+			row.Root = spans[0].Name
+		}
+		rows = append(rows, row)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = indexTemplate.Execute(w, rows)
+}
+
+// rows lays out the trace as a waterfall, with each bar's offset and width
+// given as a percentage of the whole trace.
+func (td traceData) rows() []waterfallRow {
+	start, end := td.bounds()
+	total := float64(end.Sub(start))
+	spans, depths := td.waterfall()
+	rows := make([]waterfallRow, len(spans))
+	for i, sd := range spans {
+		rows[i] = waterfallRow{spanData: sd, Indent: depths[i]}
+		if total > 0 {
+			rows[i].Offset = 100 * float64(sd.Start.Sub(start)) / total
+			rows[i].Width = 100 * float64(sd.Duration) / total
+		}
+	}
+	return rows
+}
+
+func (v *Viewer) serveTrace(w http.ResponseWriter, id string) {
+	for _, td := range v.snapshot() {
+		if traceIDString(td.id) != id {
+			continue
+		}
+		rows := td.rows()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = traceTemplate.Execute(w, struct {
+			ID   string
+			Rows []waterfallRow
+		}{id, rows})
+		return
+	}
+	http.Error(w, fmt.Sprintf("trace %q not found", id), http.StatusNotFound)
+}
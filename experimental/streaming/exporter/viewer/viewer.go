@@ -0,0 +1,208 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package viewer is an exporter that keeps the most recent traces in
+// memory and serves them as HTML, so traces can be inspected during
+// development without running a collector or tracing backend.
+package viewer
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+	"go.opentelemetry.io/experimental/streaming/exporter/spandata"
+)
+
+// DefaultMaxTraces is the number of traces kept when New is called with a
+// non-positive limit.
+const DefaultMaxTraces = 100
+
+// Viewer is an observer that records finished spans and serves them over
+// HTTP. The index page lists the recent traces and each trace is shown as
+// a waterfall of its spans.
+type Viewer struct {
+	observer  observer.Observer
+	maxTraces int
+
+	mu     sync.Mutex
+	traces map[core.TraceID]*traceData
+	// order holds trace IDs from the oldest to the most recent.
+	order []core.TraceID
+}
+
+type traceData struct {
+	id    core.TraceID
+	spans []*spanData
+}
+
+type spanData struct {
+	Name       string
+	SpanID     uint64
+	ParentID   uint64
+	Start      time.Time
+	Duration   time.Duration
+	Status     codes.Code
+	Message    string
+	Attributes []core.KeyValue
+}
+
+var _ observer.Observer = (*Viewer)(nil)
+
+// New returns a Viewer that keeps the last maxTraces traces.
+func New(maxTraces int) *Viewer {
+	if maxTraces <= 0 {
+		maxTraces = DefaultMaxTraces
+	}
+	v := &Viewer{
+		maxTraces: maxTraces,
+		traces:    map[core.TraceID]*traceData{},
+	}
+	v.observer = spandata.NewReaderObserver(v)
+	return v
+}
+
+// Observe implements observer.Observer.
+func (v *Viewer) Observe(event observer.Event) {
+	v.observer.Observe(event)
+}
+
+// Read implements spandata.Reader.
+func (v *Viewer) Read(span *spandata.Span) {
+	sd := &spanData{}
+	for _, ev := range span.Events {
+		switch ev.Type {
+		case reader.START_SPAN:
+			sd.Name = ev.Name
+			sd.SpanID = ev.SpanContext.SpanID
+			sd.ParentID = ev.Parent.SpanID
+			sd.Start = ev.Time
+		case reader.SET_STATUS:
+			sd.Status = ev.Status
+			sd.Message = ev.Message
+		case reader.FINISH_SPAN:
+			sd.Duration = ev.Duration
+			ev.Attributes.Foreach(func(kv core.KeyValue) bool {
+				sd.Attributes = append(sd.Attributes, kv)
+				return true
+			})
+		}
+	}
+	if len(span.Events) == 0 {
+		return
+	}
+	traceID := span.Events[0].SpanContext.TraceID
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	td, ok := v.traces[traceID]
+	if !ok {
+		if len(v.order) == v.maxTraces {
+			delete(v.traces, v.order[0])
+			v.order = v.order[1:]
+		}
+		td = &traceData{id: traceID}
+		v.traces[traceID] = td
+		v.order = append(v.order, traceID)
+	}
+	td.spans = append(td.spans, sd)
+}
+
+// ListenAndServe serves the viewer on addr, e.g., "localhost:16686".
+func (v *Viewer) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, v)
+}
+
+// ServeHTTP serves the trace list at the root and individual traces at
+// /trace?id=<trace ID>.
+func (v *Viewer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		v.serveIndex(w)
+	case "/trace":
+		v.serveTrace(w, r.URL.Query().Get("id"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// snapshot returns copies of the recorded traces, most recent first.
+func (v *Viewer) snapshot() []traceData {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	traces := make([]traceData, 0, len(v.order))
+	for i := len(v.order) - 1; i >= 0; i-- {
+		td := v.traces[v.order[i]]
+		traces = append(traces, traceData{
+			id:    td.id,
+			spans: append([]*spanData(nil), td.spans...),
+		})
+	}
+	return traces
+}
+
+// bounds returns the earliest start and latest end of the spans.
+func (td traceData) bounds() (time.Time, time.Time) {
+	var start, end time.Time
+	for i, sd := range td.spans {
+		if i == 0 || sd.Start.Before(start) {
+			start = sd.Start
+		}
+		if e := sd.Start.Add(sd.Duration); i == 0 || e.After(end) {
+			end = e
+		}
+	}
+	return start, end
+}
+
+// waterfall orders the spans depth-first from the roots, children sorted
+// by start time, and returns the depth of each span.
+func (td traceData) waterfall() ([]*spanData, []int) {
+	known := map[uint64]bool{}
+	for _, sd := range td.spans {
+		known[sd.SpanID] = true
+	}
+	children := map[uint64][]*spanData{}
+	for _, sd := range td.spans {
+		parent := sd.ParentID
+		if !known[parent] {
+			// Roots, remote parents and parents that were evicted.
+			parent = 0
+		}
+		children[parent] = append(children[parent], sd)
+	}
+
+	var spans []*spanData
+	var depths []int
+	var walk func(parent uint64, depth int)
+	walk = func(parent uint64, depth int) {
+		kids := children[parent]
+		sort.Slice(kids, func(i, j int) bool {
+			return kids[i].Start.Before(kids[j].Start)
+		})
+		for _, sd := range kids {
+			spans = append(spans, sd)
+			depths = append(depths, depth)
+			walk(sd.SpanID, depth+1)
+		}
+	}
+	walk(0, 0)
+	return spans, depths
+}
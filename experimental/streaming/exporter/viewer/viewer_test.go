@@ -0,0 +1,204 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package viewer
+
+import (
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/experimental/streaming/exporter/reader"
+	"go.opentelemetry.io/experimental/streaming/exporter/spandata"
+)
+
+var epoch = time.Unix(1000, 0)
+
+func span(name string, id, parent uint64, start, duration time.Duration) *spanData {
+	return &spanData{
+		Name:     name,
+		SpanID:   id,
+		ParentID: parent,
+		Start:    epoch.Add(start),
+		Duration: duration,
+	}
+}
+
+func TestBounds(t *testing.T) {
+	td := traceData{spans: []*spanData{
+		span("late", 2, 1, 30*time.Millisecond, 10*time.Millisecond),
+		span("root", 1, 0, 10*time.Millisecond, 20*time.Millisecond),
+		span("long", 3, 1, 15*time.Millisecond, 50*time.Millisecond),
+	}}
+	start, end := td.bounds()
+	if want := epoch.Add(10 * time.Millisecond); !start.Equal(want) {
+		t.Errorf("start = %v, want %v", start, want)
+	}
+	if want := epoch.Add(65 * time.Millisecond); !end.Equal(want) {
+		t.Errorf("end = %v, want %v", end, want)
+	}
+}
+
+func TestWaterfall(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		spans  []*spanData
+		order  []string
+		depths []int
+	}{
+		{
+			name:   "single",
+			spans:  []*spanData{span("root", 1, 0, 0, time.Second)},
+			order:  []string{"root"},
+			depths: []int{0},
+		},
+		{
+			name: "nested",
+			spans: []*spanData{
+				span("grandchild", 3, 2, 2, 1),
+				span("child", 2, 1, 1, 3),
+				span("root", 1, 0, 0, 5),
+			},
+			order:  []string{"root", "child", "grandchild"},
+			depths: []int{0, 1, 2},
+		},
+		{
+			name: "siblings by start",
+			spans: []*spanData{
+				span("root", 1, 0, 0, 10),
+				span("second", 3, 1, 5, 1),
+				span("first", 2, 1, 1, 1),
+				span("first.child", 4, 2, 2, 1),
+			},
+			order:  []string{"root", "first", "first.child", "second"},
+			depths: []int{0, 1, 2, 1},
+		},
+		{
+			name: "missing parent",
+			spans: []*spanData{
+				span("root", 1, 0, 0, 10),
+				span("orphan", 3, 99, 5, 1),
+				span("child", 2, 1, 1, 1),
+			},
+			order:  []string{"root", "child", "orphan"},
+			depths: []int{0, 1, 0},
+		},
+	} {
+		spans, depths := traceData{spans: tt.spans}.waterfall()
+		var order []string
+		for _, sd := range spans {
+			order = append(order, sd.Name)
+		}
+		if strings.Join(order, ",") != strings.Join(tt.order, ",") {
+			t.Errorf("%s: order = %v, want %v", tt.name, order, tt.order)
+		}
+		for i := range tt.depths {
+			if i >= len(depths) || depths[i] != tt.depths[i] {
+				t.Errorf("%s: depths = %v, want %v", tt.name, depths, tt.depths)
+				break
+			}
+		}
+	}
+}
+
+func TestRows(t *testing.T) {
+	td := traceData{spans: []*spanData{
+		span("root", 1, 0, 0, 100*time.Millisecond),
+		span("child", 2, 1, 25*time.Millisecond, 50*time.Millisecond),
+		span("tail", 3, 2, 60*time.Millisecond, 40*time.Millisecond),
+	}}
+	want := []struct {
+		name          string
+		indent        int
+		offset, width float64
+	}{
+		{"root", 0, 0, 100},
+		{"child", 1, 25, 50},
+		{"tail", 2, 60, 40},
+	}
+	rows := td.rows()
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.Name != w.name || r.Indent != w.indent {
+			t.Errorf("row %d = %s at indent %d, want %s at indent %d", i, r.Name, r.Indent, w.name, w.indent)
+		}
+		if math.Abs(r.Offset-w.offset) > 1e-9 || math.Abs(r.Width-w.width) > 1e-9 {
+			t.Errorf("%s: offset, width = %v, %v, want %v, %v", r.Name, r.Offset, r.Width, w.offset, w.width)
+		}
+	}
+}
+
+func TestRowsZeroDuration(t *testing.T) {
+	td := traceData{spans: []*spanData{
+		span("root", 1, 0, 0, 0),
+		span("child", 2, 1, 0, 0),
+	}}
+	for _, r := range td.rows() {
+		if r.Offset != 0 || r.Width != 0 {
+			t.Errorf("%s: offset, width = %v, %v, want 0, 0", r.Name, r.Offset, r.Width)
+		}
+	}
+}
+
+func startFinish(traceID core.TraceID, id, parent uint64, name string) *spandata.Span {
+	sc := core.SpanContext{TraceID: traceID, SpanID: id}
+	return &spandata.Span{Events: []reader.Event{
+		{Type: reader.START_SPAN, Time: epoch, Name: name, SpanContext: sc, Parent: core.SpanContext{TraceID: traceID, SpanID: parent}},
+		{Type: reader.FINISH_SPAN, Time: epoch.Add(time.Second), Duration: time.Second, SpanContext: sc, Attributes: tag.NewEmptyMap()},
+	}}
+}
+
+func TestEviction(t *testing.T) {
+	v := New(2)
+	for i := uint64(1); i <= 3; i++ {
+		v.Read(startFinish(core.TraceID{Low: i}, i, 0, "root"))
+	}
+	traces := v.snapshot()
+	if len(traces) != 2 {
+		t.Fatalf("kept %d traces, want 2", len(traces))
+	}
+	if traces[0].id.Low != 3 || traces[1].id.Low != 2 {
+		t.Errorf("kept traces %v and %v, want 3 and 2", traces[0].id.Low, traces[1].id.Low)
+	}
+}
+
+func TestServeTrace(t *testing.T) {
+	v := New(0)
+	id := core.TraceID{High: 1, Low: 2}
+	v.Read(startFinish(id, 1, 0, "outer"))
+	v.Read(startFinish(id, 2, 1, "inner"))
+
+	rec := httptest.NewRecorder()
+	v.ServeHTTP(rec, httptest.NewRequest("GET", "/trace?id="+traceIDString(id), nil))
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	body := rec.Body.String()
+	if i, j := strings.Index(body, "outer"), strings.Index(body, "inner"); i < 0 || j < i {
+		t.Errorf("want outer before inner in body:\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	v.ServeHTTP(rec, httptest.NewRequest("GET", "/trace?id=missing", nil))
+	if rec.Code != 404 {
+		t.Errorf("missing trace status = %d, want 404", rec.Code)
+	}
+}
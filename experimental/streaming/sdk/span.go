@@ -16,6 +16,7 @@ package sdk
 
 import (
 	"context"
+	"sync"
 
 	"google.golang.org/grpc/codes"
 
@@ -24,30 +25,55 @@ import (
 	"go.opentelemetry.io/api/tag"
 	apitrace "go.opentelemetry.io/api/trace"
 	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+	sdktrace "go.opentelemetry.io/sdk/trace"
 )
 
 type span struct {
 	tracer  *tracer
 	initial observer.ScopeID
+
+	// observed is false for unsampled spans whose lifecycle is not
+	// observed, see SetObserveUnsampled.
+	observed bool
+
+	// limits are the span limits of the sdk/trace configuration when
+	// the span started.
+	limits sdktrace.SpanLimits
+
+	mu     sync.Mutex
+	events int
+	keys   map[string]struct{}
 }
 
+var _ apitrace.StatusMessageSetter = (*span)(nil)
+
 // SpancContext returns span context of the span. Return SpanContext is usable
 // even after the span is finished.
 func (sp *span) SpanContext() core.SpanContext {
 	return sp.initial.SpanContext
 }
 
-// IsRecordingEvents returns true is the span is active and recording events is enabled.
+// IsRecordingEvents returns true if the span is sampled. Attributes and
+// events of unsampled spans are not recorded.
 func (sp *span) IsRecordingEvents() bool {
-	return false
+	return sp.initial.SpanContext.IsSampled()
 }
 
 // SetStatus sets the status of the span.
 func (sp *span) SetStatus(status codes.Code) {
+	sp.SetStatusWithMessage(status, "")
+}
+
+// SetStatusWithMessage implements apitrace.StatusMessageSetter.
+func (sp *span) SetStatusWithMessage(status codes.Code, message string) {
+	if !sp.observed {
+		return
+	}
 	observer.Record(observer.Event{
 		Type:   observer.SET_STATUS,
 		Scope:  sp.ScopeID(),
 		Status: status,
+		String: message,
 	})
 }
 
@@ -56,6 +82,9 @@ func (sp *span) ScopeID() observer.ScopeID {
 }
 
 func (sp *span) SetAttribute(attribute core.KeyValue) {
+	if !sp.IsRecordingEvents() || !sp.admitKey(attribute.Key) {
+		return
+	}
 	observer.Record(observer.Event{
 		Type:      observer.MODIFY_ATTR,
 		Scope:     sp.ScopeID(),
@@ -64,6 +93,13 @@ func (sp *span) SetAttribute(attribute core.KeyValue) {
 }
 
 func (sp *span) SetAttributes(attributes ...core.KeyValue) {
+	if !sp.IsRecordingEvents() {
+		return
+	}
+	attributes = sp.admitAttributes(attributes)
+	if len(attributes) == 0 {
+		return
+	}
 	observer.Record(observer.Event{
 		Type:       observer.MODIFY_ATTR,
 		Scope:      sp.ScopeID(),
@@ -72,6 +108,9 @@ func (sp *span) SetAttributes(attributes ...core.KeyValue) {
 }
 
 func (sp *span) ModifyAttribute(mutator tag.Mutator) {
+	if !sp.IsRecordingEvents() || !sp.admitMutator(mutator) {
+		return
+	}
 	observer.Record(observer.Event{
 		Type:    observer.MODIFY_ATTR,
 		Scope:   sp.ScopeID(),
@@ -80,20 +119,35 @@ func (sp *span) ModifyAttribute(mutator tag.Mutator) {
 }
 
 func (sp *span) ModifyAttributes(mutators ...tag.Mutator) {
+	if !sp.IsRecordingEvents() {
+		return
+	}
+	admitted := mutators[:0:0]
+	for _, m := range mutators {
+		if sp.admitMutator(m) {
+			admitted = append(admitted, m)
+		}
+	}
+	if len(admitted) == 0 {
+		return
+	}
 	observer.Record(observer.Event{
 		Type:     observer.MODIFY_ATTR,
 		Scope:    sp.ScopeID(),
-		Mutators: mutators,
+		Mutators: admitted,
 	})
 }
 
 func (sp *span) Finish() {
 	recovered := recover()
-	observer.Record(observer.Event{
-		Type:      observer.FINISH_SPAN,
-		Scope:     sp.ScopeID(),
-		Recovered: recovered,
-	})
+	apitrace.RecordPanic(context.Background(), sp, recovered)
+	if sp.observed {
+		observer.Record(observer.Event{
+			Type:      observer.FINISH_SPAN,
+			Scope:     sp.ScopeID(),
+			Recovered: recovered,
+		})
+	}
 	if recovered != nil {
 		panic(recovered)
 	}
@@ -104,19 +158,92 @@ func (sp *span) Tracer() apitrace.Tracer {
 }
 
 func (sp *span) AddEvent(ctx context.Context, event event.Event) {
+	if !sp.IsRecordingEvents() || !sp.admitEvent() {
+		return
+	}
 	observer.Record(observer.Event{
 		Type:       observer.ADD_EVENT,
 		String:     event.Message(),
-		Attributes: event.Attributes(),
+		Attributes: sp.eventAttributes(event.Attributes()),
 		Context:    ctx,
 	})
 }
 
 func (sp *span) Event(ctx context.Context, msg string, attrs ...core.KeyValue) {
+	if !sp.IsRecordingEvents() || !sp.admitEvent() {
+		return
+	}
 	observer.Record(observer.Event{
 		Type:       observer.ADD_EVENT,
 		String:     msg,
-		Attributes: attrs,
+		Attributes: sp.eventAttributes(attrs),
 		Context:    ctx,
 	})
 }
+
+// admitEvent reports whether another event fits in
+// SpanLimits.MaxEventsPerSpan. Unlike sdk/trace, which evicts the oldest
+// events when the span finishes, the streaming SDK has already observed
+// them, so newer events are dropped instead.
+func (sp *span) admitEvent() bool {
+	max := sp.limits.MaxEventsPerSpan
+	if max <= 0 {
+		return true
+	}
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.events >= max {
+		return false
+	}
+	sp.events++
+	return true
+}
+
+// eventAttributes returns the first SpanLimits.MaxAttributesPerEvent of
+// attrs.
+func (sp *span) eventAttributes(attrs []core.KeyValue) []core.KeyValue {
+	if max := sp.limits.MaxAttributesPerEvent; max > 0 && len(attrs) > max {
+		return attrs[:max]
+	}
+	return attrs
+}
+
+// admitKey reports whether an attribute with key k may be set, i.e.,
+// whether k was set before or another key fits in
+// SpanLimits.MaxAttributesPerSpan.
+func (sp *span) admitKey(k core.Key) bool {
+	max := sp.limits.MaxAttributesPerSpan
+	if max <= 0 {
+		return true
+	}
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if _, ok := sp.keys[k.Variable.Name]; ok {
+		return true
+	}
+	if len(sp.keys) >= max {
+		return false
+	}
+	if sp.keys == nil {
+		sp.keys = map[string]struct{}{}
+	}
+	sp.keys[k.Variable.Name] = struct{}{}
+	return true
+}
+
+func (sp *span) admitAttributes(attrs []core.KeyValue) []core.KeyValue {
+	if sp.limits.MaxAttributesPerSpan <= 0 {
+		return attrs
+	}
+	admitted := attrs[:0:0]
+	for _, kv := range attrs {
+		if sp.admitKey(kv.Key) {
+			admitted = append(admitted, kv)
+		}
+	}
+	return admitted
+}
+
+func (sp *span) admitMutator(m tag.Mutator) bool {
+	return m.MutatorOp == tag.DELETE || sp.admitKey(m.Key)
+}
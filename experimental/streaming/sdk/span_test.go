@@ -0,0 +1,263 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+	sdktrace "go.opentelemetry.io/sdk/trace"
+)
+
+type recordingObserver struct {
+	types  []observer.EventType
+	events []observer.Event
+}
+
+func (o *recordingObserver) Observe(event observer.Event) {
+	o.types = append(o.types, event.Type)
+	o.events = append(o.events, event)
+}
+
+func (o *recordingObserver) count(t observer.EventType) int {
+	n := 0
+	for _, typ := range o.types {
+		if typ == t {
+			n++
+		}
+	}
+	return n
+}
+
+func TestUnsampledSpanSuppression(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		options   byte
+		observe   bool
+		want      int
+		lifecycle int
+	}{
+		{"sampled", core.TraceOptionSampled, false, 1, 1},
+		{"unsampled", 0, false, 0, 0},
+		{"unsampled observed", 0, true, 0, 1},
+	} {
+		SetObserveUnsampled(tt.observe)
+		obs := &recordingObserver{}
+		observer.RegisterObserver(obs)
+
+		parent := core.SpanContext{
+			TraceID:      core.TraceID{High: 1, Low: 2},
+			SpanID:       3,
+			TraceOptions: tt.options,
+		}
+		ctx, span := New().Start(context.Background(), "span", apitrace.ChildOf(parent))
+		if span.IsRecordingEvents() != (tt.want != 0) {
+			t.Errorf("%s: IsRecordingEvents() = %v", tt.name, span.IsRecordingEvents())
+		}
+		span.SetAttribute(key.New("a").String("1"))
+		span.SetAttributes(key.New("b").String("2"))
+		span.Event(ctx, "event")
+		span.Finish()
+
+		observer.UnregisterObserver(obs)
+		SetObserveUnsampled(false)
+
+		if span.SpanContext().SpanID == 0 {
+			t.Errorf("%s: got no span context, want one to propagate", tt.name)
+		}
+		if got := obs.count(observer.MODIFY_ATTR); got != 2*tt.want {
+			t.Errorf("%s: observed %d MODIFY_ATTR events, want %d", tt.name, got, 2*tt.want)
+		}
+		if got := obs.count(observer.ADD_EVENT); got != tt.want {
+			t.Errorf("%s: observed %d ADD_EVENT events, want %d", tt.name, got, tt.want)
+		}
+		if obs.count(observer.START_SPAN) != tt.lifecycle || obs.count(observer.FINISH_SPAN) != tt.lifecycle {
+			t.Errorf("%s: observed %v, want %d START_SPAN and FINISH_SPAN events", tt.name, obs.types, tt.lifecycle)
+		}
+	}
+}
+
+func TestWithResourcesMerges(t *testing.T) {
+	obs := &recordingObserver{}
+	observer.RegisterObserver(obs)
+	tr := New().WithService("checkout").WithResources(ServiceKey.String("payments"), ComponentKey.String("db"))
+	observer.UnregisterObserver(obs)
+
+	if len(obs.events) != 2 {
+		t.Fatalf("observed %d events, want one NEW_SCOPE per call", len(obs.events))
+	}
+	last := obs.events[1]
+	if last.Type != observer.NEW_SCOPE || last.Scope.EventID != 0 {
+		t.Errorf("got %v with parent %d, want a NEW_SCOPE without parent", last.Type, last.Scope.EventID)
+	}
+	got := map[string]string{}
+	for _, kv := range last.Attributes {
+		got[kv.Key.Variable.Name] = kv.Value.Emit()
+	}
+	if len(got) != 2 || got["service"] != "payments" || got["component"] != "db" {
+		t.Errorf("got resource %v, want service=payments and component=db", got)
+	}
+	if tr.(*tracer).resources != last.Sequence {
+		t.Errorf("tracer scope = %d, want %d", tr.(*tracer).resources, last.Sequence)
+	}
+}
+
+func TestSetStatusWithMessage(t *testing.T) {
+	obs := &recordingObserver{}
+	observer.RegisterObserver(obs)
+	_, span := New().Start(context.Background(), "span")
+	apitrace.SetStatusWithMessage(span, codes.Unavailable, "backend down")
+	observer.UnregisterObserver(obs)
+
+	for _, ev := range obs.events {
+		if ev.Type != observer.SET_STATUS {
+			continue
+		}
+		if ev.Status != codes.Unavailable || ev.String != "backend down" {
+			t.Errorf("got status %v %q, want %v %q", ev.Status, ev.String, codes.Unavailable, "backend down")
+		}
+		return
+	}
+	t.Errorf("observed %v, want a SET_STATUS event", obs.types)
+}
+
+func TestStartSpanKind(t *testing.T) {
+	obs := &recordingObserver{}
+	observer.RegisterObserver(obs)
+	_, span := New().Start(context.Background(), "span", apitrace.WithSpanKind(apitrace.SpanKindProducer))
+	span.Finish()
+	observer.UnregisterObserver(obs)
+
+	for _, ev := range obs.events {
+		if ev.Type == observer.START_SPAN {
+			if ev.Kind != apitrace.SpanKindProducer {
+				t.Errorf("START_SPAN kind = %v, want %v", ev.Kind, apitrace.SpanKindProducer)
+			}
+			return
+		}
+	}
+	t.Errorf("observed %v, want a START_SPAN event", obs.types)
+}
+
+func TestSetIDGenerator(t *testing.T) {
+	SetIDGenerator(sdktrace.NewDeterministicIDGenerator(1))
+	defer SetIDGenerator(nil)
+
+	want := sdktrace.NewDeterministicIDGenerator(1)
+	wantSpanID := want.NewSpanID()
+	wantTraceID := want.NewTraceID()
+	_, span := New().Start(context.Background(), "span")
+	if sc := span.SpanContext(); sc.SpanID != wantSpanID || sc.TraceID != wantTraceID {
+		t.Errorf("got span context %+v, want the IDs of the deterministic generator", sc)
+	}
+}
+
+func TestSpanLimitsFromConfig(t *testing.T) {
+	prev := sdktrace.ConfigSnapshot()
+	if prev.MaxSpanNameLength == 0 {
+		prev.MaxSpanNameLength = sdktrace.NoSpanNameLimit
+	}
+	defer sdktrace.ApplyConfig(prev)
+	sdktrace.ApplyConfig(sdktrace.Config{
+		MaxEventsPerSpan:      2,
+		MaxAttributesPerSpan:  2,
+		MaxAttributesPerEvent: 1,
+		MaxSpanNameLength:     8,
+	})
+
+	obs := &recordingObserver{}
+	observer.RegisterObserver(obs)
+	ctx, span := New().Start(context.Background(), "a long span name")
+	span.SetAttributes(key.New("a").String("1"), key.New("b").String("2"), key.New("c").String("3"))
+	span.SetAttribute(key.New("a").String("4"))
+	span.SetAttribute(key.New("d").String("5"))
+	for i := 0; i < 3; i++ {
+		span.Event(ctx, "event", key.New("x").Int(i), key.New("y").Int(i))
+	}
+	span.Finish()
+	observer.UnregisterObserver(obs)
+
+	var keys []string
+	for _, ev := range obs.events {
+		switch ev.Type {
+		case observer.START_SPAN:
+			if ev.String != "a lon..." {
+				t.Errorf("span name = %q, want %q", ev.String, "a lon...")
+			}
+		case observer.MODIFY_ATTR:
+			for _, kv := range append(ev.Attributes, ev.Attribute) {
+				if kv.Key.Variable.Name != "" {
+					keys = append(keys, kv.Key.Variable.Name)
+				}
+			}
+		case observer.ADD_EVENT:
+			if len(ev.Attributes) != 1 {
+				t.Errorf("event has %d attributes, want 1", len(ev.Attributes))
+			}
+		}
+	}
+	if got := obs.count(observer.ADD_EVENT); got != 2 {
+		t.Errorf("observed %d ADD_EVENT events, want 2", got)
+	}
+	if want := []string{"a", "b", "a"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("set attributes %v, want %v", keys, want)
+	}
+}
+
+func TestSetSampler(t *testing.T) {
+	SetSampler(func(p sdktrace.SamplingParameters) sdktrace.SamplingDecision {
+		return sdktrace.SamplingDecision{
+			Sample:     p.Name == "sampled",
+			Attributes: []core.KeyValue{key.New("sampler").String("test")},
+		}
+	})
+	defer SetSampler(nil)
+
+	obs := &recordingObserver{}
+	observer.RegisterObserver(obs)
+	ctx, root := New().Start(context.Background(), "sampled")
+	_, child := New().Start(ctx, "child")
+	_, other := New().Start(context.Background(), "other")
+	observer.UnregisterObserver(obs)
+
+	if !root.SpanContext().IsSampled() || !child.SpanContext().IsSampled() {
+		t.Errorf("root and child sampled = %v, %v; want both, the child following its local parent",
+			root.SpanContext().IsSampled(), child.SpanContext().IsSampled())
+	}
+	if other.SpanContext().IsSampled() {
+		t.Error("span rejected by the sampler is sampled")
+	}
+	if got := obs.count(observer.START_SPAN); got != 2 {
+		t.Errorf("observed %d START_SPAN events, want 2", got)
+	}
+	var sampler string
+	for _, ev := range obs.events {
+		for _, kv := range ev.Attributes {
+			if kv.Key.Variable.Name == "sampler" {
+				sampler = kv.Value.Emit()
+			}
+		}
+	}
+	if sampler != "test" {
+		t.Errorf("sampler attribute = %q, want %q", sampler, "test")
+	}
+}
@@ -16,7 +16,7 @@ package sdk
 
 import (
 	"context"
-	"math/rand"
+	"sync/atomic"
 
 	"go.opentelemetry.io/api/core"
 	"go.opentelemetry.io/api/key"
@@ -24,9 +24,14 @@ import (
 	"go.opentelemetry.io/api/trace"
 	apitrace "go.opentelemetry.io/api/trace"
 	"go.opentelemetry.io/experimental/streaming/exporter/observer"
+	"go.opentelemetry.io/sdk/resource"
+	sdktrace "go.opentelemetry.io/sdk/trace"
 )
 
 type tracer struct {
+	resource *resource.Resource
+
+	// resources is the scope holding the attributes of resource.
 	resources observer.EventID
 }
 
@@ -42,15 +47,103 @@ var (
 	)
 )
 
+// idGeneratorHolder keeps the concrete type stored in idGenerator
+// constant.
+type idGeneratorHolder struct {
+	gen sdktrace.IDGenerator
+}
+
+var (
+	idGenerator        atomic.Value // idGeneratorHolder
+	defaultIDGenerator = sdktrace.NewIDGenerator()
+)
+
+// SetIDGenerator replaces the generator of the IDs of new spans, e.g.,
+// with sdktrace.NewDeterministicIDGenerator in tests. The default
+// generator, restored by SetIDGenerator(nil), is seeded from crypto/rand.
+func SetIDGenerator(gen sdktrace.IDGenerator) {
+	idGenerator.Store(idGeneratorHolder{gen})
+}
+
+func currentIDGenerator() sdktrace.IDGenerator {
+	if h, ok := idGenerator.Load().(idGeneratorHolder); ok && h.gen != nil {
+		return h.gen
+	}
+	return defaultIDGenerator
+}
+
+// samplerHolder keeps the concrete type stored in sampler constant.
+type samplerHolder struct {
+	s sdktrace.Sampler
+}
+
+var (
+	sampler atomic.Value // samplerHolder
+
+	observeUnsampled int32 // access atomically
+)
+
+// SetSampler replaces the sampler deciding whether root spans, and spans
+// with a remote parent, are sampled. Other spans are sampled with their
+// parent. SetSampler(nil) restores the default, which samples root spans
+// and follows the decision of remote parents.
+//
+// Only sampled spans are observed. Unsampled spans still carry their
+// span context, so that it is propagated to the processes they call.
+func SetSampler(s sdktrace.Sampler) {
+	sampler.Store(samplerHolder{s})
+}
+
+func currentSampler() sdktrace.Sampler {
+	if h, ok := sampler.Load().(samplerHolder); ok && h.s != nil {
+		return h.s
+	}
+	return defaultSampler
+}
+
+func defaultSampler(p sdktrace.SamplingParameters) sdktrace.SamplingDecision {
+	if p.ParentContext.HasTraceID() {
+		return sdktrace.SamplingDecision{Sample: p.ParentContext.IsSampled()}
+	}
+	return sdktrace.SamplingDecision{Sample: true}
+}
+
+// SetObserveUnsampled sets whether the START_SPAN, SET_STATUS and
+// FINISH_SPAN events of unsampled spans are observed, e.g., so that
+// readers see the context of every span. Their attributes and events
+// never are.
+func SetObserveUnsampled(observe bool) {
+	var v int32
+	if observe {
+		v = 1
+	}
+	atomic.StoreInt32(&observeUnsampled, v)
+}
+
 func New() trace.Tracer {
 	return &tracer{}
 }
 
+// NewWithResource returns a tracer whose spans are scoped by the
+// attributes of r.
+func NewWithResource(r *resource.Resource) trace.Tracer {
+	return withResource(r)
+}
+
+// WithResources returns a tracer with the resource of t merged with
+// attributes, which take precedence. The spans are scoped by a single
+// scope holding the merged attributes.
 func (t *tracer) WithResources(attributes ...core.KeyValue) apitrace.Tracer {
-	s := observer.NewScope(observer.ScopeID{
-		EventID: t.resources,
-	}, attributes...)
+	return withResource(resource.Merge(resource.New(attributes...), t.resource))
+}
+
+func withResource(r *resource.Resource) *tracer {
+	if r.Len() == 0 {
+		return &tracer{}
+	}
+	s := observer.NewScope(observer.ScopeID{}, r.Attributes()...)
 	return &tracer{
+		resource:  r,
 		resources: s.EventID,
 	}
 }
@@ -71,7 +164,7 @@ func (t *tracer) WithSpan(ctx context.Context, name string, body func(context.Co
 
 	if err := body(ctx); err != nil {
 		span.SetAttribute(ErrorKey.Bool(true))
-		span.Event(ctx, "span error", MessageKey.String(err.Error()))
+		apitrace.RecordError(ctx, span, err)
 		return err
 	}
 	return nil
@@ -80,7 +173,8 @@ func (t *tracer) WithSpan(ctx context.Context, name string, body func(context.Co
 func (t *tracer) Start(ctx context.Context, name string, opts ...apitrace.SpanOption) (context.Context, apitrace.Span) {
 	var child core.SpanContext
 
-	child.SpanID = rand.Uint64()
+	ids := currentIDGenerator()
+	child.SpanID = ids.NewSpanID()
 
 	o := &apitrace.SpanOptions{}
 
@@ -88,6 +182,9 @@ func (t *tracer) Start(ctx context.Context, name string, opts ...apitrace.SpanOp
 		opt(o)
 	}
 
+	limits := sdktrace.ConfigSnapshot().SpanLimits()
+	name = limits.TruncateName(name)
+
 	var parentScope observer.ScopeID
 
 	if o.Reference.HasTraceID() {
@@ -96,34 +193,55 @@ func (t *tracer) Start(ctx context.Context, name string, opts ...apitrace.SpanOp
 		parentScope.SpanContext = apitrace.CurrentSpan(ctx).SpanContext()
 	}
 
+	remote := o.Reference.HasTraceID()
 	if parentScope.HasTraceID() {
 		parent := parentScope.SpanContext
 		child.TraceID.High = parent.TraceID.High
 		child.TraceID.Low = parent.TraceID.Low
+		child.TraceOptions = parent.TraceOptions
 	} else {
-		child.TraceID.High = rand.Uint64()
-		child.TraceID.Low = rand.Uint64()
+		child.TraceID = ids.NewTraceID()
 	}
 
-	childScope := observer.ScopeID{
-		SpanContext: child,
-		EventID:     t.resources,
+	attrs := o.Attributes
+	if !parentScope.HasTraceID() || remote {
+		decision := currentSampler()(sdktrace.SamplingParameters{
+			ParentContext:   parentScope.SpanContext,
+			TraceID:         child.TraceID,
+			SpanID:          child.SpanID,
+			Name:            name,
+			HasRemoteParent: remote,
+			Kind:            o.SpanKind,
+			Attributes:      o.Attributes,
+			Resource:        t.resource.Attributes(),
+		})
+		child.TraceOptions = 0
+		if decision.Sample {
+			child.TraceOptions = core.TraceOptionSampled
+			attrs = append(attrs[:len(attrs):len(attrs)], decision.Attributes...)
+		}
 	}
 
 	span := &span{
-		tracer: t,
-		initial: observer.ScopeID{
+		tracer:   t,
+		limits:   limits,
+		initial:  observer.ScopeID{SpanContext: child},
+		observed: child.IsSampled() || atomic.LoadInt32(&observeUnsampled) != 0,
+	}
+	if span.observed {
+		childScope := observer.ScopeID{
 			SpanContext: child,
-			EventID: observer.Record(observer.Event{
-				Time:    o.StartTime,
-				Type:    observer.START_SPAN,
-				Scope:   observer.NewScope(childScope, o.Attributes...),
-				Context: ctx,
-				Parent:  parentScope,
-				String:  name,
-			},
-			),
-		},
+			EventID:     t.resources,
+		}
+		span.initial.EventID = observer.Record(observer.Event{
+			Time:    o.StartTime,
+			Type:    observer.START_SPAN,
+			Scope:   observer.NewScope(childScope, attrs...),
+			Context: ctx,
+			Parent:  parentScope,
+			String:  name,
+			Kind:    o.SpanKind,
+		})
 	}
 	return trace.SetCurrentSpan(ctx, span), span
 }
@@ -0,0 +1,63 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package internal contains helpers shared by the exporters.
+package internal // import "go.opentelemetry.io/exporter/internal"
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const unixScheme = "unix://"
+
+// ParseEndpoint returns the network and address to dial for an exporter
+// endpoint. Endpoints of the form unix:///path/to/socket refer to Unix
+// domain sockets, e.g., of a sidecar collector. Any other endpoint is a
+// TCP host:port.
+func ParseEndpoint(endpoint string) (network, address string) {
+	if strings.HasPrefix(endpoint, unixScheme) {
+		return "unix", strings.TrimPrefix(endpoint, unixScheme)
+	}
+	return "tcp", endpoint
+}
+
+// DialEndpoint returns a dial function that connects to endpoint,
+// ignoring the address it is called with. It is suitable for gRPC
+// dialers and HTTP transports, which otherwise only support TCP.
+func DialEndpoint(endpoint string) func(ctx context.Context, addr string) (net.Conn, error) {
+	network, address := ParseEndpoint(endpoint)
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, address)
+	}
+}
+
+// HTTPTransport returns an HTTP transport for endpoint. Requests to a
+// Unix domain socket endpoint are all sent to the socket, whatever their
+// URL's host. Proxies configured in the environment are only used for
+// TCP endpoints, because a proxy cannot reach a local socket.
+func HTTPTransport(endpoint string) *http.Transport {
+	if network, _ := ParseEndpoint(endpoint); network == "tcp" {
+		return &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+	dial := DialEndpoint(endpoint)
+	return &http.Transport{
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dial(ctx, addr)
+		},
+	}
+}
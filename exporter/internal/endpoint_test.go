@@ -0,0 +1,81 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint    string
+		wantNetwork string
+		wantAddress string
+	}{
+		{"localhost:55678", "tcp", "localhost:55678"},
+		{"unix:///var/run/otel.sock", "unix", "/var/run/otel.sock"},
+	}
+	for _, tt := range tests {
+		network, address := ParseEndpoint(tt.endpoint)
+		if network != tt.wantNetwork || address != tt.wantAddress {
+			t.Errorf("ParseEndpoint(%q) = %q, %q; want %q, %q",
+				tt.endpoint, network, address, tt.wantNetwork, tt.wantAddress)
+		}
+	}
+}
+
+func TestHTTPTransportProxy(t *testing.T) {
+	if HTTPTransport("localhost:55678").Proxy == nil {
+		t.Error("TCP transport has no proxy function, want the environment's")
+	}
+	if HTTPTransport("unix:///var/run/otel.sock").Proxy != nil {
+		t.Error("Unix socket transport has a proxy function, want none")
+	}
+}
+
+func TestHTTPTransportUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "endpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sock := filepath.Join(dir, "collector.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets not supported: %v", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Shutdown(context.Background())
+
+	client := &http.Client{Transport: HTTPTransport("unix://" + sock)}
+	res, err := client.Post("http://collector/v1/trace", "application/x-protobuf", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got status %d, want %d", res.StatusCode, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"math"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/internal/protowire"
+)
+
+// KeyValue encodes the fields of an OTLP KeyValue message. Attribute
+// values of SpanData and metric labels are core.Values; other types are
+// encoded as strings.
+func KeyValue(e *protowire.Encoder, k string, v interface{}) {
+	e.StringField(1, k)
+	e.Message(2, func(e *protowire.Encoder) {
+		cv, ok := v.(core.Value)
+		if !ok {
+			e.LengthDelimited(1, []byte(fmt.Sprint(v)))
+			return
+		}
+		// Members of a oneof are written even when they hold the zero
+		// value.
+		switch cv.Type {
+		case core.BOOL:
+			e.Tag(2, protowire.WireVarint)
+			if cv.Bool {
+				e.Varint(1)
+			} else {
+				e.Varint(0)
+			}
+		case core.INT32, core.INT64:
+			e.Tag(3, protowire.WireVarint)
+			e.Varint(uint64(cv.Int64))
+		case core.UINT32, core.UINT64:
+			e.Tag(3, protowire.WireVarint)
+			e.Varint(cv.Uint64)
+		case core.FLOAT32, core.FLOAT64:
+			e.Tag(4, protowire.WireFixed64)
+			e.Fixed64(math.Float64bits(cv.Float64))
+		case core.BYTES:
+			e.LengthDelimited(7, cv.Bytes)
+		case core.STRINGS:
+			e.Message(5, func(e *protowire.Encoder) {
+				for _, s := range cv.Strings {
+					e.Message(1, func(e *protowire.Encoder) {
+						e.LengthDelimited(1, []byte(s))
+					})
+				}
+			})
+		case core.INT64S:
+			e.Message(5, func(e *protowire.Encoder) {
+				for _, n := range cv.Int64s {
+					e.Message(1, func(e *protowire.Encoder) {
+						e.Tag(3, protowire.WireVarint)
+						e.Varint(uint64(n))
+					})
+				}
+			})
+		case core.FLOAT64S:
+			e.Message(5, func(e *protowire.Encoder) {
+				for _, f := range cv.Float64s {
+					e.Message(1, func(e *protowire.Encoder) {
+						e.Tag(4, protowire.WireFixed64)
+						e.Fixed64(math.Float64bits(f))
+					})
+				}
+			})
+		default:
+			e.LengthDelimited(1, []byte(cv.Emit()))
+		}
+	})
+}
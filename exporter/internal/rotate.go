@@ -0,0 +1,106 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// RotatingTransport wraps t so that no connection is used for longer than
+// interval. Idle connections are closed once every interval, and a
+// connection older than interval that is picked for a request is closed
+// once the request is done. The next request then dials again, resolving
+// the endpoint anew, so that exporters pick up collectors that moved,
+// e.g., behind a headless Kubernetes service, without restarting the
+// process, even when they export often enough to never leave connections
+// idle. A non-positive interval returns t unchanged.
+func RotatingTransport(t *http.Transport, interval time.Duration) http.RoundTripper {
+	if interval <= 0 {
+		return t
+	}
+	return &rotatingTransport{
+		Transport: t,
+		interval:  interval,
+		now:       time.Now,
+		rotated:   time.Now(),
+		dialed:    make(map[net.Conn]time.Time),
+	}
+}
+
+type rotatingTransport struct {
+	*http.Transport
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	rotated time.Time
+	// dialed records when the connections in use were first seen. Entries
+	// of connections that were closed without being seen again are pruned
+	// on rotation.
+	dialed map[net.Conn]time.Time
+}
+
+func (t *rotatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if now := t.now(); now.Sub(t.rotated) >= t.interval {
+		t.rotated = now
+		t.Transport.CloseIdleConnections()
+		for c, dialed := range t.dialed {
+			// Connections this old were either idle and are closed
+			// now, or were picked since and marked to be closed.
+			if now.Sub(dialed) >= 3*t.interval {
+				delete(t.dialed, c)
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	// The transport reads Close only after the connection is picked, so
+	// setting it from GotConn makes it close an old connection after
+	// this request. WithContext copies the request, as RoundTrip must
+	// not modify it.
+	var r *http.Request
+	ctx := httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if t.expired(info.Conn) {
+				r.Close = true
+			}
+		},
+	})
+	r = req.WithContext(ctx)
+	return t.Transport.RoundTrip(r)
+}
+
+// expired reports whether c is older than the interval, and forgets it if
+// so.
+func (t *rotatingTransport) expired(c net.Conn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	dialed, ok := t.dialed[c]
+	if !ok {
+		t.dialed[c] = now
+		return false
+	}
+	if now.Sub(dialed) < t.interval {
+		return false
+	}
+	delete(t.dialed, c)
+	return true
+}
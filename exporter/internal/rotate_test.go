@@ -0,0 +1,108 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRotatingTransport(t *testing.T) {
+	var conns int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	now := time.Now()
+	rt := RotatingTransport(HTTPTransport(strings.TrimPrefix(srv.URL, "http://")), time.Minute).(*rotatingTransport)
+	rt.now = func() time.Time { return now }
+	rt.rotated = now
+	client := &http.Client{Transport: rt}
+
+	get := func() {
+		t.Helper()
+		res, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		res.Body.Close()
+	}
+
+	get()
+	get()
+	if got := atomic.LoadInt32(&conns); got != 1 {
+		t.Errorf("got %d connections before the interval elapsed; want 1", got)
+	}
+	now = now.Add(time.Minute)
+	get()
+	if got := atomic.LoadInt32(&conns); got != 2 {
+		t.Errorf("got %d connections after the interval elapsed; want 2", got)
+	}
+}
+
+func TestRotatingTransportBusyConnection(t *testing.T) {
+	var conns int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	now := time.Now()
+	rt := RotatingTransport(HTTPTransport(strings.TrimPrefix(srv.URL, "http://")), time.Minute).(*rotatingTransport)
+	rt.now = func() time.Time { return now }
+	rt.rotated = now
+	client := &http.Client{Transport: rt}
+
+	get := func() {
+		t.Helper()
+		res, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		res.Body.Close()
+	}
+
+	get()
+	// The connection is in use when the interval elapses, so closing
+	// idle connections does not catch it, but it has to be closed
+	// after its next request.
+	rt.mu.Lock()
+	rt.rotated = now.Add(time.Minute)
+	rt.mu.Unlock()
+	now = now.Add(time.Minute)
+	get()
+	get()
+	if got := atomic.LoadInt32(&conns); got != 2 {
+		t.Errorf("got %d connections; want the old one closed after its lifetime and 2 in total", got)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"math"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/exporter/internal"
+	"go.opentelemetry.io/internal/protowire"
+	"go.opentelemetry.io/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+// aggregationTemporalityDelta is the OTLP temporality of the records of
+// the SDK, which start a new period at every collection.
+const aggregationTemporalityDelta = 1
+
+// MarshalMetrics encodes records as an OTLP ExportMetricsServiceRequest
+// message in the protocol buffer wire format. The resource attributes
+// describe the process that produced the records.
+//
+// Counters are encoded as monotonic delta sums, gauges as gauges and
+// measures as delta histograms, with the exemplars of their buckets.
+func MarshalMetrics(resource []core.KeyValue, records []push.Record) []byte {
+	var e protowire.Encoder
+	// ExportMetricsServiceRequest.resource_metrics
+	e.Message(1, func(e *protowire.Encoder) {
+		// ResourceMetrics.resource
+		e.Message(1, func(e *protowire.Encoder) {
+			for _, kv := range resource {
+				// Resource.attributes
+				e.Message(1, func(e *protowire.Encoder) { internal.KeyValue(e, kv.Key.Variable.Name, kv.Value) })
+			}
+		})
+		for _, group := range byLibrary(records) {
+			// ResourceMetrics.scope_metrics
+			e.Message(2, func(e *protowire.Encoder) {
+				lib := group[0].Library
+				if lib != (push.Library{}) {
+					// ScopeMetrics.scope
+					e.Message(1, func(e *protowire.Encoder) {
+						e.StringField(1, lib.Name)
+						e.StringField(2, lib.Version)
+					})
+				}
+				for _, r := range group {
+					// ScopeMetrics.metrics
+					e.Message(2, func(e *protowire.Encoder) { metricMessage(e, r) })
+				}
+				// ScopeMetrics.schema_url
+				e.StringField(3, lib.SchemaURL)
+			})
+		}
+	})
+	return e.Buf
+}
+
+// byLibrary groups records by instrumentation library, in the order the
+// libraries first appear.
+func byLibrary(records []push.Record) [][]push.Record {
+	var groups [][]push.Record
+	index := make(map[push.Library]int)
+	for _, r := range records {
+		i, ok := index[r.Library]
+		if !ok {
+			i = len(groups)
+			index[r.Library] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], r)
+	}
+	return groups
+}
+
+// metricMessage encodes the fields of an OTLP Metric message.
+func metricMessage(e *protowire.Encoder, r push.Record) {
+	e.StringField(1, r.Handle.Variable.Name)
+	e.StringField(2, r.Handle.Variable.Description)
+	e.StringField(3, string(r.Handle.Variable.Unit))
+	switch {
+	case r.Distribution != nil:
+		// Metric.histogram
+		e.Message(9, func(e *protowire.Encoder) {
+			// Histogram.data_points
+			e.Message(1, func(e *protowire.Encoder) { histogramDataPoint(e, r) })
+			// Histogram.aggregation_temporality
+			e.UintField(2, aggregationTemporalityDelta)
+		})
+	case r.Handle.Type == metric.Cumulative:
+		// Metric.sum
+		e.Message(7, func(e *protowire.Encoder) {
+			// Sum.data_points
+			e.Message(1, func(e *protowire.Encoder) { numberDataPoint(e, r) })
+			// Sum.aggregation_temporality
+			e.UintField(2, aggregationTemporalityDelta)
+			// Sum.is_monotonic
+			e.UintField(3, 1)
+		})
+	default:
+		// Metric.gauge
+		e.Message(5, func(e *protowire.Encoder) {
+			// Gauge.data_points
+			e.Message(1, func(e *protowire.Encoder) { numberDataPoint(e, r) })
+		})
+	}
+}
+
+// numberDataPoint encodes the fields of an OTLP NumberDataPoint message.
+func numberDataPoint(e *protowire.Encoder, r push.Record) {
+	labels(e, 7, r.Labels)
+	e.Fixed64Field(2, unixNano(r.StartTime))
+	e.Fixed64Field(3, unixNano(r.EndTime))
+	// Members of a oneof are written even when they hold the zero value.
+	switch r.Value.Type {
+	case core.FLOAT32, core.FLOAT64:
+		// NumberDataPoint.as_double
+		e.Tag(4, protowire.WireFixed64)
+		e.Fixed64(math.Float64bits(r.Value.Float64))
+	case core.UINT32, core.UINT64:
+		// NumberDataPoint.as_int
+		e.Tag(6, protowire.WireFixed64)
+		e.Fixed64(r.Value.Uint64)
+	default:
+		// NumberDataPoint.as_int
+		e.Tag(6, protowire.WireFixed64)
+		e.Fixed64(uint64(r.Value.Int64))
+	}
+}
+
+// histogramDataPoint encodes the fields of an OTLP HistogramDataPoint
+// message.
+func histogramDataPoint(e *protowire.Encoder, r push.Record) {
+	d := r.Distribution
+	labels(e, 9, r.Labels)
+	e.Fixed64Field(2, unixNano(r.StartTime))
+	e.Fixed64Field(3, unixNano(r.EndTime))
+	e.Fixed64Field(4, d.Count)
+	// HistogramDataPoint.sum is optional, hence written when zero.
+	e.Tag(5, protowire.WireFixed64)
+	e.Fixed64(math.Float64bits(d.Sum))
+	// HistogramDataPoint.bucket_counts
+	var counts protowire.Encoder
+	for _, c := range d.Counts {
+		counts.Fixed64(c)
+	}
+	e.BytesField(6, counts.Buf)
+	// HistogramDataPoint.explicit_bounds
+	var bounds protowire.Encoder
+	for _, b := range d.Boundaries {
+		bounds.Fixed64(math.Float64bits(b))
+	}
+	e.BytesField(7, bounds.Buf)
+	for _, x := range d.Exemplars {
+		if x.Time.IsZero() {
+			continue
+		}
+		// HistogramDataPoint.exemplars
+		e.Message(8, func(e *protowire.Encoder) { exemplar(e, x) })
+	}
+}
+
+// exemplar encodes the fields of an OTLP Exemplar message.
+func exemplar(e *protowire.Encoder, x histogram.Exemplar) {
+	e.Fixed64Field(2, unixNano(x.Time))
+	// Exemplar.as_double
+	e.Tag(3, protowire.WireFixed64)
+	e.Fixed64(math.Float64bits(x.Value))
+	e.BytesField(4, protowire.SpanID(x.SpanContext.SpanID))
+	e.BytesField(5, protowire.TraceID(x.SpanContext.TraceID.High, x.SpanContext.TraceID.Low))
+}
+
+// labels encodes kvs as the KeyValue messages of field.
+func labels(e *protowire.Encoder, field int, kvs []core.KeyValue) {
+	for _, kv := range kvs {
+		e.Message(field, func(e *protowire.Encoder) { internal.KeyValue(e, kv.Key.Variable.Name, kv.Value) })
+	}
+}
+
+func unixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
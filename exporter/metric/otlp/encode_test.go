@@ -0,0 +1,156 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/internal/protowire"
+	"go.opentelemetry.io/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+// fields decodes the top-level fields of a message, keyed by field
+// number. Varint and fixed64 values are returned as 8 little-endian bytes.
+func fields(t *testing.T, b []byte) map[int][][]byte {
+	t.Helper()
+	m := make(map[int][][]byte)
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		b = b[n:]
+		var v []byte
+		switch tag & 7 {
+		case protowire.WireVarint:
+			x, n := binary.Uvarint(b)
+			v = make([]byte, 8)
+			binary.LittleEndian.PutUint64(v, x)
+			b = b[n:]
+		case protowire.WireFixed64:
+			v, b = b[:8], b[8:]
+		case protowire.WireBytes:
+			l, n := binary.Uvarint(b)
+			v, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&7)
+		}
+		m[int(tag>>3)] = append(m[int(tag>>3)], v)
+	}
+	return m
+}
+
+func u64(b []byte) uint64 {
+	return binary.LittleEndian.Uint64(b)
+}
+
+func TestMarshalMetrics(t *testing.T) {
+	start := time.Unix(1, 0)
+	end := start.Add(time.Second)
+	h := histogram.New(1)
+	h.Update(0.5)
+	h.UpdateWithExemplar(2, core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 2})
+	d := h.Checkpoint()
+	lib := push.Library{Name: "lib", Version: "1.0"}
+	records := []push.Record{
+		{
+			Handle:    &metric.NewInt64Counter("otlp.test.requests").Handle,
+			Labels:    []core.KeyValue{key.New("path").String("/")},
+			Value:     core.Value{Type: core.INT64, Int64: 3},
+			Library:   lib,
+			StartTime: start,
+			EndTime:   end,
+		},
+		{
+			Handle:  &metric.NewFloat64Gauge("otlp.test.queue").Handle,
+			Value:   core.Value{Type: core.FLOAT64, Float64: 0},
+			Library: lib,
+		},
+		{
+			Handle:       &metric.NewFloat64Measure("otlp.test.latency").Handle,
+			Distribution: &d,
+			StartTime:    start,
+			EndTime:      end,
+		},
+	}
+	b := MarshalMetrics([]core.KeyValue{key.New("service.name").String("svc")}, records)
+
+	resourceMetrics := fields(t, fields(t, b)[1][0])
+	if len(resourceMetrics[1]) != 1 {
+		t.Error("missing resource")
+	}
+	scopes := resourceMetrics[2]
+	if len(scopes) != 2 {
+		t.Fatalf("got %d scope metrics, want one per library", len(scopes))
+	}
+	scope := fields(t, scopes[0])
+	if got := string(fields(t, scope[1][0])[1][0]); got != "lib" {
+		t.Errorf("scope name = %q, want lib", got)
+	}
+	metrics := scope[2]
+	if len(metrics) != 2 {
+		t.Fatalf("got %d metrics of lib, want 2", len(metrics))
+	}
+
+	counter := fields(t, metrics[0])
+	if got := string(counter[1][0]); got != "otlp.test.requests" {
+		t.Errorf("counter name = %q", got)
+	}
+	sum := fields(t, counter[7][0])
+	if u64(sum[2][0]) != aggregationTemporalityDelta || u64(sum[3][0]) != 1 {
+		t.Error("counter is not a monotonic delta sum")
+	}
+	point := fields(t, sum[1][0])
+	if len(point[7]) != 1 {
+		t.Errorf("got %d counter attributes, want 1", len(point[7]))
+	}
+	if u64(point[2][0]) != uint64(start.UnixNano()) || u64(point[3][0]) != uint64(end.UnixNano()) {
+		t.Error("counter times do not match the period")
+	}
+	if u64(point[6][0]) != 3 {
+		t.Errorf("counter as_int = %d, want 3", u64(point[6][0]))
+	}
+
+	gauge := fields(t, metrics[1])
+	point = fields(t, fields(t, gauge[5][0])[1][0])
+	if len(point[4]) != 1 || math.Float64frombits(u64(point[4][0])) != 0 {
+		t.Error("gauge does not have its zero as_double")
+	}
+
+	measure := fields(t, fields(t, scopes[1])[2][0])
+	hist := fields(t, measure[9][0])
+	point = fields(t, hist[1][0])
+	if u64(point[4][0]) != 2 || math.Float64frombits(u64(point[5][0])) != 2.5 {
+		t.Error("histogram count or sum do not match")
+	}
+	if counts := point[6][0]; len(counts) != 16 || u64(counts[:8]) != 1 || u64(counts[8:]) != 1 {
+		t.Errorf("histogram bucket_counts = %x", counts)
+	}
+	if bounds := point[7][0]; len(bounds) != 8 || math.Float64frombits(u64(bounds)) != 1 {
+		t.Errorf("histogram explicit_bounds = %x", bounds)
+	}
+	if len(point[8]) != 1 {
+		t.Fatalf("got %d exemplars, want the one of the second bucket", len(point[8]))
+	}
+	x := fields(t, point[8][0])
+	if math.Float64frombits(u64(x[3][0])) != 2 || !bytes.Equal(x[4][0], protowire.SpanID(2)) || !bytes.Equal(x[5][0], protowire.TraceID(0, 1)) {
+		t.Error("exemplar value or span context do not match")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlp contains a push.Exporter that sends metrics to an
+// OpenTelemetry collector using the OTLP/HTTP protocol with binary
+// protobuf payloads, encoded by hand like those of the trace exporter.
+//
+//	p := metric.NewProvider()
+//	c := push.New(p, []push.Exporter{otlp.NewExporter()})
+//	c.Start()
+//	defer c.Stop()
+package otlp // import "go.opentelemetry.io/exporter/metric/otlp"
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/exporter/internal"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+const (
+	// DefaultEndpoint is the address of a local collector's OTLP/HTTP
+	// receiver.
+	DefaultEndpoint = "localhost:4318"
+
+	metricsPath = "/v1/metrics"
+
+	// maxErrorMessageSize caps how much of a failed response is included
+	// in the returned error.
+	maxErrorMessageSize = 1024
+)
+
+// Exporter is a push.Exporter that sends metrics to an OTLP/HTTP
+// receiver.
+type Exporter struct {
+	endpoint  string
+	tlsConfig *tls.Config
+	resource  []core.KeyValue
+	client    *http.Client
+	url       string
+
+	reresolveInterval time.Duration
+}
+
+var _ push.Exporter = (*Exporter)(nil)
+
+// Option configures an Exporter.
+type Option func(*Exporter)
+
+// WithEndpoint sets the host:port of the collector, or a unix:// path to
+// the collector's Unix domain socket. It defaults to DefaultEndpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(e *Exporter) {
+		e.endpoint = endpoint
+	}
+}
+
+// WithTLSConfig makes the exporter connect to the collector over HTTPS
+// using config. Metrics are sent in plain text by default.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(e *Exporter) {
+		e.tlsConfig = config
+	}
+}
+
+// WithResource sets the attributes describing the process that produces
+// the metrics, such as service.name.
+func WithResource(attrs ...core.KeyValue) Option {
+	return func(e *Exporter) {
+		e.resource = attrs
+	}
+}
+
+// WithReresolveInterval makes the exporter close its connections to the
+// collector once they are interval old, so that the endpoint is resolved
+// again on the next export. It is disabled by default.
+func WithReresolveInterval(interval time.Duration) Option {
+	return func(e *Exporter) {
+		e.reresolveInterval = interval
+	}
+}
+
+// NewExporter returns an Exporter configured with opts.
+func NewExporter(opts ...Option) *Exporter {
+	e := &Exporter{endpoint: DefaultEndpoint}
+	for _, opt := range opts {
+		opt(e)
+	}
+	transport := internal.HTTPTransport(e.endpoint)
+	transport.TLSClientConfig = e.tlsConfig
+	e.client = &http.Client{
+		Transport: internal.RotatingTransport(transport, e.reresolveInterval),
+	}
+
+	scheme := "http"
+	if e.tlsConfig != nil {
+		scheme = "https"
+	}
+	host := e.endpoint
+	if network, _ := internal.ParseEndpoint(e.endpoint); network == "unix" {
+		// The transport dials the socket itself, so the host only has
+		// to be valid, which a socket path is not.
+		host = "otlp"
+	}
+	e.url = scheme + "://" + host + metricsPath
+	return e
+}
+
+// Export implements push.Exporter. It sends records to the collector in
+// a single request.
+func (e *Exporter) Export(ctx context.Context, records []push.Record) error {
+	body := MarshalMetrics(e.resource, records)
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	res, err := e.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorMessageSize))
+		return fmt.Errorf("otlp: collector responded %s: %s", res.Status, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	return nil
+}
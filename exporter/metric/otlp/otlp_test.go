@@ -0,0 +1,42 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+func TestExportPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != metricsPath {
+			t.Errorf("request path = %q, want %q", r.URL.Path, metricsPath)
+		}
+		http.Error(w, "invalid metric", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	e := NewExporter(WithEndpoint(strings.TrimPrefix(srv.URL, "http://")))
+	err := e.Export(context.Background(), []push.Record{{Handle: &metric.NewFloat64Gauge("otlp.test.path").Handle}})
+	if err == nil || !strings.Contains(err.Error(), "invalid metric") {
+		t.Errorf("got error %v, want the collector's message", err)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stdout contains a push.Exporter that prints metrics as JSON
+// objects, one per record and line unless pretty-printed, e.g., for jq.
+//
+//	p := metric.NewProvider()
+//	c := push.New(p, []push.Exporter{stdout.New()})
+//	c.Start()
+//	defer c.Stop()
+package stdout // import "go.opentelemetry.io/exporter/metric/stdout"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+// Exporter is a push.Exporter that writes records as JSON objects.
+type Exporter struct {
+	pretty bool
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+var _ push.Exporter = (*Exporter)(nil)
+
+// Option configures an Exporter.
+type Option func(*Exporter)
+
+// WithWriter writes the records to w instead of os.Stdout. Writes are
+// serialized, so w need not be safe for concurrent use.
+func WithWriter(w io.Writer) Option {
+	return func(e *Exporter) {
+		e.w = w
+	}
+}
+
+// WithPrettyPrint indents the JSON objects over several lines.
+func WithPrettyPrint() Option {
+	return func(e *Exporter) {
+		e.pretty = true
+	}
+}
+
+// New returns an Exporter configured with opts.
+func New(opts ...Option) *Exporter {
+	e := &Exporter{w: os.Stdout}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+type jsonRecord struct {
+	Name      string                 `json:"name"`
+	Kind      string                 `json:"kind"`
+	Library   string                 `json:"library,omitempty"`
+	Version   string                 `json:"version,omitempty"`
+	Labels    map[string]interface{} `json:"labels,omitempty"`
+	Value     interface{}            `json:"value,omitempty"`
+	Histogram *jsonHistogram         `json:"histogram,omitempty"`
+	Start     string                 `json:"start,omitempty"`
+	End       string                 `json:"end,omitempty"`
+}
+
+type jsonHistogram struct {
+	Boundaries []float64   `json:"boundaries"`
+	Counts     []uint64    `json:"counts"`
+	Sum        interface{} `json:"sum"`
+	Count      uint64      `json:"count"`
+
+	Exemplars []jsonExemplar `json:"exemplars,omitempty"`
+}
+
+type jsonExemplar struct {
+	Bucket  int         `json:"bucket"`
+	Value   interface{} `json:"value"`
+	TraceID string      `json:"trace_id"`
+	SpanID  string      `json:"span_id"`
+	Time    string      `json:"time"`
+}
+
+// Export implements push.Exporter.
+func (e *Exporter) Export(ctx context.Context, records []push.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	enc := json.NewEncoder(e.w)
+	if e.pretty {
+		enc.SetIndent("", "\t")
+	}
+	for _, r := range records {
+		if err := enc.Encode(toJSON(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toJSON(r push.Record) jsonRecord {
+	jr := jsonRecord{
+		Name:    r.Handle.Variable.Name,
+		Kind:    r.Handle.Type.String(),
+		Library: r.Library.Name,
+		Version: r.Library.Version,
+		Start:   formatTime(r.StartTime),
+		End:     formatTime(r.EndTime),
+	}
+	if len(r.Labels) > 0 {
+		jr.Labels = make(map[string]interface{}, len(r.Labels))
+		for _, kv := range r.Labels {
+			jr.Labels[kv.Key.Variable.Name] = jsonValue(kv.Value)
+		}
+	}
+	if d := r.Distribution; d != nil {
+		jr.Histogram = &jsonHistogram{
+			Boundaries: d.Boundaries,
+			Counts:     d.Counts,
+			Sum:        jsonFloat(d.Sum),
+			Count:      d.Count,
+		}
+		for i, x := range d.Exemplars {
+			if x.Time.IsZero() {
+				continue
+			}
+			jr.Histogram.Exemplars = append(jr.Histogram.Exemplars, jsonExemplar{
+				Bucket:  i,
+				Value:   jsonFloat(x.Value),
+				TraceID: x.SpanContext.TraceIDString(),
+				SpanID:  x.SpanContext.SpanIDString(),
+				Time:    formatTime(x.Time),
+			})
+		}
+	} else {
+		jr.Value = jsonValue(r.Value)
+	}
+	return jr
+}
+
+// jsonValue returns v as a value encoding/json can encode. Bytes and
+// non-finite floats, which JSON cannot represent, are emitted as strings.
+func jsonValue(v core.Value) interface{} {
+	switch v.Type {
+	case core.BYTES:
+		return v.Emit()
+	case core.FLOAT32, core.FLOAT64:
+		return jsonFloat(v.Float64)
+	case core.FLOAT64S:
+		for _, f := range v.Float64s {
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return v.Emit()
+			}
+		}
+	}
+	return v.AsInterface()
+}
+
+func jsonFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return core.Value{Type: core.FLOAT64, Float64: f}.Emit()
+	}
+	return f
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
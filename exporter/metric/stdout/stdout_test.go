@@ -0,0 +1,88 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stdout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+func TestExport(t *testing.T) {
+	start := time.Unix(1, 0)
+	h := histogram.New(1)
+	h.Update(0.5)
+	h.Update(math.Inf(1))
+	d := h.Checkpoint()
+	records := []push.Record{
+		{
+			Handle:    &metric.NewInt64Counter("stdout.test.requests").Handle,
+			Labels:    []core.KeyValue{key.New("path").String("/")},
+			Value:     core.Value{Type: core.INT64, Int64: 0},
+			Library:   push.Library{Name: "lib"},
+			StartTime: start,
+			EndTime:   start.Add(time.Second),
+		},
+		{
+			Handle:       &metric.NewFloat64Measure("stdout.test.latency").Handle,
+			Distribution: &d,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := New(WithWriter(&buf)).Export(context.Background(), records); err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(&buf)
+	var counter, measure map[string]interface{}
+	if err := dec.Decode(&counter); err != nil {
+		t.Fatal(err)
+	}
+	if err := dec.Decode(&measure); err != nil {
+		t.Fatal(err)
+	}
+
+	for k, want := range map[string]interface{}{
+		"name":    "stdout.test.requests",
+		"kind":    "cumulative",
+		"library": "lib",
+		"value":   0.0,
+		"start":   "1970-01-01T00:00:01Z",
+		"end":     "1970-01-01T00:00:02Z",
+	} {
+		if counter[k] != want {
+			t.Errorf("counter %s = %v, want %v", k, counter[k], want)
+		}
+	}
+	if labels, _ := counter["labels"].(map[string]interface{}); labels["path"] != "/" {
+		t.Errorf("counter labels = %v", counter["labels"])
+	}
+	hist, _ := measure["histogram"].(map[string]interface{})
+	if hist["count"] != 2.0 || hist["sum"] != "+Inf" {
+		t.Errorf("measure histogram = %v, want count 2 and the sum as a string", hist)
+	}
+	if _, ok := measure["start"]; ok {
+		t.Error("measure without a period has a start time")
+	}
+}
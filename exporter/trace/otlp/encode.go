@@ -0,0 +1,140 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/exporter/internal"
+	"go.opentelemetry.io/internal/protowire"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// statusCodeError is the OTLP status code of failed spans.
+const statusCodeError = 2
+
+// MarshalSpans encodes spans as an OTLP ExportTraceServiceRequest
+// message in the protocol buffer wire format. The resource attributes
+// describe the process that produced the spans.
+//
+// The message is encoded by hand rather than with generated code, so
+// that binaries using this package do not link the protobuf runtime.
+func MarshalSpans(resource []core.KeyValue, spans []*trace.SpanData) []byte {
+	var e protowire.Encoder
+	// ExportTraceServiceRequest.resource_spans
+	e.Message(1, func(e *protowire.Encoder) {
+		// ResourceSpans.resource
+		e.Message(1, func(e *protowire.Encoder) {
+			for _, kv := range resource {
+				// Resource.attributes
+				e.Message(1, func(e *protowire.Encoder) { internal.KeyValue(e, kv.Key.Variable.Name, kv.Value) })
+			}
+		})
+		for _, group := range byLibrary(spans) {
+			// ResourceSpans.scope_spans
+			e.Message(2, func(e *protowire.Encoder) {
+				lib := group[0].InstrumentationLibrary
+				if lib != (trace.InstrumentationLibrary{}) {
+					// ScopeSpans.scope
+					e.Message(1, func(e *protowire.Encoder) {
+						e.StringField(1, lib.Name)
+						e.StringField(2, lib.Version)
+					})
+				}
+				for _, sd := range group {
+					// ScopeSpans.spans
+					e.Message(2, func(e *protowire.Encoder) { span(e, sd) })
+				}
+				// ScopeSpans.schema_url
+				e.StringField(3, lib.SchemaURL)
+			})
+		}
+	})
+	return e.Buf
+}
+
+// byLibrary groups spans by instrumentation library, in the order the
+// libraries first appear.
+func byLibrary(spans []*trace.SpanData) [][]*trace.SpanData {
+	var groups [][]*trace.SpanData
+	index := make(map[trace.InstrumentationLibrary]int)
+	for _, sd := range spans {
+		i, ok := index[sd.InstrumentationLibrary]
+		if !ok {
+			i = len(groups)
+			index[sd.InstrumentationLibrary] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], sd)
+	}
+	return groups
+}
+
+func span(e *protowire.Encoder, sd *trace.SpanData) {
+	e.BytesField(1, protowire.TraceID(sd.SpanContext.TraceID.High, sd.SpanContext.TraceID.Low))
+	e.BytesField(2, protowire.SpanID(sd.SpanContext.SpanID))
+	if sd.ParentSpanID != 0 {
+		e.BytesField(4, protowire.SpanID(sd.ParentSpanID))
+	}
+	e.StringField(5, sd.Name)
+	// The values of SpanKind are those of the OTLP enum.
+	e.UintField(6, uint64(sd.SpanKind))
+	e.Fixed64Field(7, unixNano(sd.StartTime.UnixNano()))
+	e.Fixed64Field(8, unixNano(sd.EndTime.UnixNano()))
+	for k, v := range sd.Attributes {
+		e.Message(9, func(e *protowire.Encoder) { internal.KeyValue(e, k, v) })
+	}
+	e.UintField(10, uint64(sd.DroppedAttributeCount))
+	for it := sd.Events(); it.Next(); {
+		ev := it.Event()
+		e.Message(11, func(e *protowire.Encoder) {
+			e.Fixed64Field(1, unixNano(ev.Time().UnixNano()))
+			e.StringField(2, ev.Message())
+			for _, kv := range ev.Attributes() {
+				e.Message(3, func(e *protowire.Encoder) { internal.KeyValue(e, kv.Key.Variable.Name, kv.Value) })
+			}
+			e.UintField(4, uint64(ev.DroppedAttributeCount()))
+		})
+	}
+	e.UintField(12, uint64(sd.DroppedMessageEventCount))
+	for _, l := range sd.Links {
+		e.Message(13, func(e *protowire.Encoder) {
+			e.BytesField(1, protowire.TraceID(l.TraceID.High, l.TraceID.Low))
+			e.BytesField(2, protowire.SpanID(l.SpanID))
+			for _, kv := range l.Attributes {
+				e.Message(4, func(e *protowire.Encoder) { internal.KeyValue(e, kv.Key.Variable.Name, kv.Value) })
+			}
+		})
+	}
+	e.UintField(14, uint64(sd.DroppedLinkCount))
+	if sd.Status != codes.OK {
+		msg := sd.StatusMessage
+		if msg == "" {
+			msg = sd.Status.String()
+		}
+		e.Message(15, func(e *protowire.Encoder) {
+			e.StringField(2, msg)
+			e.UintField(3, statusCodeError)
+		})
+	}
+}
+
+func unixNano(ns int64) uint64 {
+	if ns < 0 {
+		return 0
+	}
+	return uint64(ns)
+}
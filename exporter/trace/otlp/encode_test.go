@@ -0,0 +1,200 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/internal/protowire"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// fields decodes the top-level fields of a message, keyed by field
+// number. Varint and fixed64 values are returned as 8 little-endian bytes.
+func fields(t *testing.T, b []byte) map[int][][]byte {
+	t.Helper()
+	m := make(map[int][][]byte)
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		b = b[n:]
+		var v []byte
+		switch tag & 7 {
+		case protowire.WireVarint:
+			x, n := binary.Uvarint(b)
+			v = make([]byte, 8)
+			binary.LittleEndian.PutUint64(v, x)
+			b = b[n:]
+		case protowire.WireFixed64:
+			v, b = b[:8], b[8:]
+		case protowire.WireBytes:
+			l, n := binary.Uvarint(b)
+			v, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&7)
+		}
+		m[int(tag>>3)] = append(m[int(tag>>3)], v)
+	}
+	return m
+}
+
+func TestMarshalSpans(t *testing.T) {
+	start := time.Unix(1, 0)
+	sd := &trace.SpanData{
+		SpanContext: core.SpanContext{
+			TraceID: core.TraceID{High: 1, Low: 2},
+			SpanID:  3,
+		},
+		ParentSpanID: 4,
+		SpanKind:     apitrace.SpanKindServer,
+		Name:         "span",
+		StartTime:    start,
+		EndTime:      start.Add(time.Second),
+		Attributes: map[string]interface{}{
+			"enabled": key.New("enabled").Bool(false).Value,
+		},
+		Status:        codes.NotFound,
+		StatusMessage: "no such user",
+	}
+	b := MarshalSpans([]core.KeyValue{key.New("service.name").String("svc")}, []*trace.SpanData{sd})
+
+	resourceSpans := fields(t, fields(t, b)[1][0])
+	resource := fields(t, resourceSpans[1][0])
+	attr := fields(t, resource[1][0])
+	if got := string(attr[1][0]); got != "service.name" {
+		t.Errorf("resource attribute key = %q, want service.name", got)
+	}
+
+	span := fields(t, fields(t, resourceSpans[2][0])[2][0])
+	wantTraceID := []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2}
+	if got := span[1][0]; !bytes.Equal(got, wantTraceID) {
+		t.Errorf("trace_id = %x, want %x", got, wantTraceID)
+	}
+	if got := binary.BigEndian.Uint64(span[4][0]); got != 4 {
+		t.Errorf("parent_span_id = %d, want 4", got)
+	}
+	if got := string(span[5][0]); got != "span" {
+		t.Errorf("name = %q, want span", got)
+	}
+	if got := binary.LittleEndian.Uint64(span[6][0]); got != 2 {
+		t.Errorf("kind = %d, want 2 (SPAN_KIND_SERVER)", got)
+	}
+	if got := binary.LittleEndian.Uint64(span[8][0]); got != uint64(2*time.Second) {
+		t.Errorf("end_time_unix_nano = %d, want %d", got, 2*time.Second)
+	}
+
+	value := fields(t, fields(t, span[9][0])[2][0])
+	if v, ok := value[2]; !ok || binary.LittleEndian.Uint64(v[0]) != 0 {
+		t.Errorf("bool_value = %v, want an explicit false", value)
+	}
+
+	status := fields(t, span[15][0])
+	if got := binary.LittleEndian.Uint64(status[3][0]); got != statusCodeError {
+		t.Errorf("status code = %d, want %d", got, statusCodeError)
+	}
+	if got := string(status[2][0]); got != "no such user" {
+		t.Errorf("status message = %q, want %q", got, "no such user")
+	}
+}
+
+func TestMarshalArrayAttributes(t *testing.T) {
+	sd := &trace.SpanData{
+		SpanContext: core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 2},
+		Attributes: map[string]interface{}{
+			"ids": key.New("ids").Int64s([]int64{7, 8}).Value,
+		},
+	}
+	b := MarshalSpans(nil, []*trace.SpanData{sd})
+	span := fields(t, fields(t, fields(t, fields(t, b)[1][0])[2][0])[2][0])
+
+	array := fields(t, fields(t, fields(t, span[9][0])[2][0])[5][0])
+	if len(array[1]) != 2 {
+		t.Fatalf("got %d array values, want 2", len(array[1]))
+	}
+	for i, want := range []uint64{7, 8} {
+		if got := binary.LittleEndian.Uint64(fields(t, array[1][i])[3][0]); got != want {
+			t.Errorf("values[%d].int_value = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestMarshalSpanLinks(t *testing.T) {
+	sd := &trace.SpanData{
+		SpanContext: core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 2},
+		Name:        "batch",
+		Links: []apitrace.Link{{
+			SpanContext: core.SpanContext{TraceID: core.TraceID{High: 5, Low: 6}, SpanID: 7},
+			Attributes:  []core.KeyValue{key.New("request").Int(1)},
+		}},
+		DroppedLinkCount: 3,
+	}
+	b := MarshalSpans(nil, []*trace.SpanData{sd})
+	span := fields(t, fields(t, fields(t, fields(t, b)[1][0])[2][0])[2][0])
+
+	if len(span[13]) != 1 {
+		t.Fatalf("got %d links, want 1", len(span[13]))
+	}
+	link := fields(t, span[13][0])
+	wantTraceID := []byte{0, 0, 0, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 6}
+	if got := link[1][0]; !bytes.Equal(got, wantTraceID) {
+		t.Errorf("link trace_id = %x, want %x", got, wantTraceID)
+	}
+	if got := binary.BigEndian.Uint64(link[2][0]); got != 7 {
+		t.Errorf("link span_id = %d, want 7", got)
+	}
+	if got := string(fields(t, link[4][0])[1][0]); got != "request" {
+		t.Errorf("link attribute key = %q, want request", got)
+	}
+	if got := binary.LittleEndian.Uint64(span[14][0]); got != 3 {
+		t.Errorf("dropped_links_count = %d, want 3", got)
+	}
+}
+
+func TestMarshalSpansByLibrary(t *testing.T) {
+	httpLib := trace.InstrumentationLibrary{Name: "othttp", Version: "0.1", SchemaURL: "https://opentelemetry.io/schemas/1.4.0"}
+	spans := []*trace.SpanData{
+		{Name: "app"},
+		{Name: "request", InstrumentationLibrary: httpLib},
+		{Name: "app2"},
+	}
+	b := MarshalSpans(nil, spans)
+	scopes := fields(t, fields(t, b)[1][0])[2]
+	if len(scopes) != 2 {
+		t.Fatalf("got %d scope_spans, want 2", len(scopes))
+	}
+
+	app := fields(t, scopes[0])
+	if _, ok := app[1]; ok || len(app[2]) != 2 {
+		t.Errorf("application scope_spans = %v, want 2 spans without a scope", app)
+	}
+	lib := fields(t, scopes[1])
+	scope := fields(t, lib[1][0])
+	if string(scope[1][0]) != "othttp" || string(scope[2][0]) != "0.1" {
+		t.Errorf("scope = %q %q, want othttp 0.1", scope[1][0], scope[2][0])
+	}
+	if got := string(lib[3][0]); got != httpLib.SchemaURL {
+		t.Errorf("schema_url = %q, want %q", got, httpLib.SchemaURL)
+	}
+	if got := string(fields(t, lib[2][0])[5][0]); got != "request" {
+		t.Errorf("library span name = %q, want request", got)
+	}
+}
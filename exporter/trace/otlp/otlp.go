@@ -0,0 +1,233 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlp contains an exporter that sends spans to an OpenTelemetry
+// collector using the OTLP/HTTP protocol with binary protobuf payloads.
+//
+// The payloads are encoded by a small hand-written encoder instead of
+// generated protobuf code, which keeps binaries where size matters, such
+// as CLIs and embedded agents, small.
+//
+//	exporter := otlp.NewExporter(otlp.WithEndpoint("unix:///var/run/otel.sock"))
+//	bsp, err := trace.NewBatchSpanProcessor(exporter)
+//	...
+//	trace.RegisterSpanProcessor(bsp)
+//	defer opentelemetry.Shutdown(ctx)
+//
+// The exporter is a trace.Shutdowner: trace.Shutdown, which
+// opentelemetry.Shutdown calls, closes its connections once the buffered
+// spans are exported.
+package otlp // import "go.opentelemetry.io/exporter/trace/otlp"
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/exporter/internal"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+const (
+	// DefaultEndpoint is the address of a local collector's OTLP/HTTP
+	// receiver.
+	DefaultEndpoint = "localhost:4318"
+
+	tracesPath = "/v1/traces"
+
+	// maxErrorMessageSize caps how much of a failed response is included
+	// in the returned error.
+	maxErrorMessageSize = 1024
+)
+
+var errShutdown = errors.New("otlp: exporter is shut down")
+
+// Exporter is a trace.Exporter that sends spans to an OTLP/HTTP receiver.
+type Exporter struct {
+	endpoint  string
+	tlsConfig *tls.Config
+	resource  []core.KeyValue
+	client    *http.Client
+	url       string
+
+	debug        io.Writer
+	debugPayload bool
+
+	reresolveInterval time.Duration
+
+	// mu is held for reading by exports in flight, and for writing by
+	// Shutdown, which waits for them.
+	mu       sync.RWMutex
+	shutdown bool
+}
+
+var (
+	_ trace.ContextExporter = (*Exporter)(nil)
+	_ trace.SpanExporter    = (*Exporter)(nil)
+	_ trace.Shutdowner      = (*Exporter)(nil)
+)
+
+// Option configures an Exporter.
+type Option func(*Exporter)
+
+// WithEndpoint sets the host:port of the collector, or a unix:// path to
+// the collector's Unix domain socket. It defaults to DefaultEndpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(e *Exporter) {
+		e.endpoint = endpoint
+	}
+}
+
+// WithTLSConfig makes the exporter connect to the collector over HTTPS
+// using config. Spans are sent in plain text by default.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(e *Exporter) {
+		e.tlsConfig = config
+	}
+}
+
+// WithResource sets the attributes describing the process that produces
+// the spans, such as service.name.
+func WithResource(attrs ...core.KeyValue) Option {
+	return func(e *Exporter) {
+		e.resource = attrs
+	}
+}
+
+// WithDebugTap writes a summary of every request to w before it is sent,
+// followed by a hex dump of the payload if payload is true, to diagnose
+// requests rejected by the collector without capturing packets.
+func WithDebugTap(w io.Writer, payload bool) Option {
+	return func(e *Exporter) {
+		e.debug = w
+		e.debugPayload = payload
+	}
+}
+
+// WithReresolveInterval makes the exporter close its connections to the
+// collector once they are interval old, so that the endpoint is resolved
+// again on the next export and collectors behind DNS that changed are
+// picked up. It is disabled by default.
+func WithReresolveInterval(interval time.Duration) Option {
+	return func(e *Exporter) {
+		e.reresolveInterval = interval
+	}
+}
+
+// NewExporter returns an Exporter configured with opts.
+func NewExporter(opts ...Option) *Exporter {
+	e := &Exporter{endpoint: DefaultEndpoint}
+	for _, opt := range opts {
+		opt(e)
+	}
+	transport := internal.HTTPTransport(e.endpoint)
+	transport.TLSClientConfig = e.tlsConfig
+	e.client = &http.Client{
+		Transport: internal.RotatingTransport(transport, e.reresolveInterval),
+	}
+
+	scheme := "http"
+	if e.tlsConfig != nil {
+		scheme = "https"
+	}
+	host := e.endpoint
+	if network, _ := internal.ParseEndpoint(e.endpoint); network == "unix" {
+		// The transport dials the socket itself, so the host only has
+		// to be valid, which a socket path is not.
+		host = "otlp"
+	}
+	e.url = scheme + "://" + host + tracesPath
+	return e
+}
+
+// ExportSpan sends a span to the collector. Errors are dropped; register
+// the Exporter with trace.RegisterExporter to have them reported and the
+// export bounded by the configured timeout.
+func (e *Exporter) ExportSpan(sd *trace.SpanData) {
+	_ = e.ExportSpanWithContext(context.Background(), sd)
+}
+
+// ExportSpanWithContext sends a span to the collector.
+func (e *Exporter) ExportSpanWithContext(ctx context.Context, sd *trace.SpanData) error {
+	return e.ExportSpans(ctx, []*trace.SpanData{sd})
+}
+
+// ExportSpans sends spans to the collector in a single request.
+func (e *Exporter) ExportSpans(ctx context.Context, spans []*trace.SpanData) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.shutdown {
+		return errShutdown
+	}
+	body := MarshalSpans(e.resource, spans)
+	if e.debug != nil {
+		e.tap(spans, body)
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	res, err := e.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorMessageSize))
+		return fmt.Errorf("otlp: collector responded %s: %s", res.Status, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	return nil
+}
+
+// Shutdown implements trace.Shutdowner. It waits for the exports in
+// flight, then closes the connections to the collector. Later exports
+// fail. If ctx is done first, Shutdown returns its error and the
+// connections are closed once the exports are done.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.shutdown = true
+		e.client.CloseIdleConnections()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *Exporter) tap(spans []*trace.SpanData, body []byte) {
+	fmt.Fprintf(e.debug, "otlp: POST %s: %d spans, %d bytes\n", e.url, len(spans), len(body))
+	if e.debugPayload {
+		d := hex.Dumper(e.debug)
+		_, _ = d.Write(body)
+		_ = d.Close()
+	}
+}
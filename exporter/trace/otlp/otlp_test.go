@@ -0,0 +1,110 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/sdk/trace"
+)
+
+func TestDebugTap(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != tracesPath {
+			t.Errorf("request path = %q, want %q", r.URL.Path, tracesPath)
+		}
+		http.Error(w, "invalid span", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var tap bytes.Buffer
+	e := NewExporter(
+		WithEndpoint(strings.TrimPrefix(srv.URL, "http://")),
+		WithDebugTap(&tap, true),
+	)
+	err := e.ExportSpanWithContext(context.Background(), &trace.SpanData{Name: "span"})
+	if err == nil || !strings.Contains(err.Error(), "invalid span") {
+		t.Errorf("got error %v, want the collector's message", err)
+	}
+
+	out := tap.String()
+	if !strings.Contains(out, "1 spans") {
+		t.Errorf("tap output %q does not summarize the request", out)
+	}
+	if !strings.Contains(out, "|") {
+		t.Errorf("tap output %q does not include a payload dump", out)
+	}
+}
+
+func TestExportSpansHost(t *testing.T) {
+	var host string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	}))
+	defer srv.Close()
+
+	endpoint := strings.TrimPrefix(srv.URL, "http://")
+	e := NewExporter(WithEndpoint(endpoint))
+	if err := e.ExportSpanWithContext(context.Background(), &trace.SpanData{Name: "span"}); err != nil {
+		t.Fatal(err)
+	}
+	if host != endpoint {
+		t.Errorf("got Host %q, want %q", host, endpoint)
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	var gotTLS bool
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTLS = r.TLS != nil
+	}))
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	e := NewExporter(
+		WithEndpoint(strings.TrimPrefix(srv.URL, "https://")),
+		WithTLSConfig(&tls.Config{RootCAs: pool}),
+	)
+	if err := e.ExportSpanWithContext(context.Background(), &trace.SpanData{Name: "span"}); err != nil {
+		t.Fatal(err)
+	}
+	if !gotTLS {
+		t.Error("spans were not sent over TLS")
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	e := NewExporter(WithEndpoint(strings.TrimPrefix(srv.URL, "http://")))
+	if err := e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	if err := e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}}); err != errShutdown {
+		t.Errorf("export after Shutdown returned %v, want %v", err, errShutdown)
+	}
+}
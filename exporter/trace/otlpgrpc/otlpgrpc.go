@@ -0,0 +1,300 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlpgrpc contains an exporter that sends spans to an
+// OpenTelemetry collector using the OTLP/gRPC protocol.
+//
+// The request is encoded by otlp.MarshalSpans and sent with a codec that
+// passes the encoded bytes through, so no generated protobuf code is
+// linked.
+//
+//	exporter, err := otlpgrpc.NewExporter(
+//		otlpgrpc.WithEndpoint("collector:4317"),
+//		otlpgrpc.WithTLSCredentials(credentials.NewTLS(&tls.Config{})),
+//		otlpgrpc.WithHeaders(map[string]string{"api-key": key}),
+//		otlpgrpc.WithCompressor("gzip"),
+//	)
+//	...
+//	bsp, err := trace.NewBatchSpanProcessor(exporter)
+//	...
+//	trace.RegisterSpanProcessor(bsp)
+//	defer opentelemetry.Shutdown(ctx)
+//
+// The exporter is a trace.Shutdowner: trace.Shutdown, which
+// opentelemetry.Shutdown calls, closes its connection once the buffered
+// spans are exported.
+package otlpgrpc // import "go.opentelemetry.io/exporter/trace/otlpgrpc"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the "gzip" compressor
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/logger"
+	"go.opentelemetry.io/exporter/internal"
+	"go.opentelemetry.io/exporter/trace/otlp"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+const (
+	// DefaultEndpoint is the address of a local collector's OTLP/gRPC
+	// receiver.
+	DefaultEndpoint = "localhost:4317"
+
+	exportMethod = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+
+	// minRetryBackoff is the shortest wait between export attempts, so
+	// that retries do not hammer a collector that is unavailable.
+	minRetryBackoff = 100 * time.Millisecond
+)
+
+var errShutdown = errors.New("otlpgrpc: exporter is shut down")
+
+// Exporter is a trace.Exporter that sends spans to an OTLP/gRPC receiver.
+type Exporter struct {
+	conn     *grpc.ClientConn
+	resource []core.KeyValue
+	headers  metadata.MD
+
+	maxAttempts int
+	backoff     time.Duration
+
+	// mu is held for reading by exports in flight, and for writing by
+	// Shutdown, which waits for them.
+	mu       sync.RWMutex
+	shutdown bool
+	closeErr error
+}
+
+var (
+	_ trace.ContextExporter = (*Exporter)(nil)
+	_ trace.SpanExporter    = (*Exporter)(nil)
+	_ trace.Shutdowner      = (*Exporter)(nil)
+)
+
+type config struct {
+	endpoint    string
+	creds       credentials.TransportCredentials
+	compressor  string
+	maxDelay    time.Duration
+	dialOptions []grpc.DialOption
+	exporter    *Exporter
+}
+
+// Option configures an Exporter.
+type Option func(*config)
+
+// WithEndpoint sets the host:port of the collector, or a unix:// path to
+// the collector's Unix domain socket. It defaults to DefaultEndpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithTLSCredentials secures the connection to the collector with creds.
+// Without it, the connection is not encrypted.
+func WithTLSCredentials(creds credentials.TransportCredentials) Option {
+	return func(c *config) {
+		c.creds = creds
+	}
+}
+
+// WithHeaders sets headers sent with every export, such as the API keys
+// of hosted collectors.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *config) {
+		c.exporter.headers = metadata.New(headers)
+	}
+}
+
+// WithCompressor compresses requests with the named gRPC compressor.
+// "gzip" is always available; others have to be registered with
+// encoding.RegisterCompressor.
+func WithCompressor(name string) Option {
+	return func(c *config) {
+		c.compressor = name
+	}
+}
+
+// WithReconnectionBackoff caps the delay between attempts to reconnect to
+// the collector after the connection is lost. The delay starts at one
+// second and grows exponentially up to maxDelay, which defaults to two
+// minutes.
+func WithReconnectionBackoff(maxDelay time.Duration) Option {
+	return func(c *config) {
+		c.maxDelay = maxDelay
+	}
+}
+
+// WithRetry makes the exporter try to send spans up to maxAttempts times
+// when the collector is unavailable or asks the client to back off. The
+// wait before the second attempt is backoff, but at least 100ms, and
+// doubles for every attempt after it. Spans are sent once by default.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(c *config) {
+		c.exporter.maxAttempts = maxAttempts
+		c.exporter.backoff = backoff
+	}
+}
+
+// WithResource sets the attributes describing the process that produces
+// the spans, such as service.name.
+func WithResource(attrs ...core.KeyValue) Option {
+	return func(c *config) {
+		c.exporter.resource = attrs
+	}
+}
+
+// WithDialOptions adds options used to dial the collector, after those
+// set by the other options.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *config) {
+		c.dialOptions = append(c.dialOptions, opts...)
+	}
+}
+
+// NewExporter returns an Exporter configured with opts. The connection
+// to the collector is established in the background, so NewExporter does
+// not fail when the collector is not yet reachable.
+func NewExporter(opts ...Option) (*Exporter, error) {
+	c := &config{
+		endpoint: DefaultEndpoint,
+		exporter: &Exporter{maxAttempts: 1},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithContextDialer(internal.DialEndpoint(c.endpoint)),
+	}
+	if c.creds != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(c.creds))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+	if c.compressor != "" {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(c.compressor)))
+	}
+	if c.maxDelay > 0 {
+		dialOpts = append(dialOpts, grpc.WithBackoffMaxDelay(c.maxDelay))
+	}
+	dialOpts = append(dialOpts, c.dialOptions...)
+
+	conn, err := grpc.Dial(c.endpoint, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("otlpgrpc: %v", err)
+	}
+	c.exporter.conn = conn
+	return c.exporter, nil
+}
+
+// Shutdown implements trace.Shutdowner. It waits for the exports in
+// flight, then closes the connection to the collector. Later exports
+// fail. If ctx is done first, Shutdown returns its error and the
+// connection is closed once the exports are done.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if !e.shutdown {
+			e.shutdown = true
+			e.closeErr = e.conn.Close()
+		}
+		done <- e.closeErr
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop is like Shutdown, without a deadline.
+func (e *Exporter) Stop() error {
+	return e.Shutdown(context.Background())
+}
+
+// ExportSpan sends a span to the collector. Errors are dropped; register
+// the Exporter with trace.RegisterExporter to have them reported and the
+// export bounded by the configured timeout.
+func (e *Exporter) ExportSpan(sd *trace.SpanData) {
+	_ = e.ExportSpanWithContext(context.Background(), sd)
+}
+
+// ExportSpanWithContext sends a span to the collector.
+func (e *Exporter) ExportSpanWithContext(ctx context.Context, sd *trace.SpanData) error {
+	return e.ExportSpans(ctx, []*trace.SpanData{sd})
+}
+
+// ExportSpans sends spans to the collector in a single request, retrying
+// as configured with WithRetry.
+func (e *Exporter) ExportSpans(ctx context.Context, spans []*trace.SpanData) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.shutdown {
+		return errShutdown
+	}
+	if len(e.headers) > 0 {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		ctx = metadata.NewOutgoingContext(ctx, metadata.Join(md, e.headers))
+	}
+	req := rawMessage(otlp.MarshalSpans(e.resource, spans))
+
+	backoff := e.backoff
+	if backoff < minRetryBackoff {
+		backoff = minRetryBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		var res rawMessage
+		err := e.conn.Invoke(ctx, exportMethod, &req, &res, grpc.ForceCodec(rawCodec{}))
+		if err == nil || !retryable(err) || attempt >= e.maxAttempts {
+			return err
+		}
+		logger.Warnf("otlpgrpc: export attempt %d failed, retrying in %v: %v", attempt, backoff, err)
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// retryable reports whether an export that failed with err may succeed
+// when retried, following the OTLP specification. Canceled is not
+// retried, since it is the caller that gave up on the export.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded, codes.ResourceExhausted,
+		codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpgrpc
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/exporter/trace/otlp"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+type request struct {
+	method string
+	md     metadata.MD
+	body   []byte
+}
+
+// startCollector serves OTLP/gRPC on a local port. Requests are sent to
+// the returned channel and answered with the next error of errs, or
+// success once errs is exhausted.
+func startCollector(t *testing.T, errs ...error) (string, <-chan request, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := make(chan request, 10)
+	srv := grpc.NewServer(
+		grpc.CustomCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+			var body rawMessage
+			if err := stream.RecvMsg(&body); err != nil {
+				return err
+			}
+			method, _ := grpc.MethodFromServerStream(stream)
+			md, _ := metadata.FromIncomingContext(stream.Context())
+			requests <- request{method: method, md: md, body: body}
+			if len(errs) > 0 {
+				err := errs[0]
+				errs = errs[1:]
+				return err
+			}
+			return stream.SendMsg(&rawMessage{})
+		}),
+	)
+	go func() { _ = srv.Serve(lis) }()
+	return lis.Addr().String(), requests, srv.Stop
+}
+
+func TestExportSpans(t *testing.T) {
+	addr, requests, stop := startCollector(t)
+	defer stop()
+
+	resource := key.New("service.name").String("checkout")
+	e, err := NewExporter(
+		WithEndpoint(addr),
+		WithHeaders(map[string]string{"api-key": "secret"}),
+		WithCompressor("gzip"),
+		WithResource(resource),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Stop()
+
+	spans := []*trace.SpanData{{Name: "span"}}
+	if err := e.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatal(err)
+	}
+	req := <-requests
+	if req.method != exportMethod {
+		t.Errorf("got method %q, want %q", req.method, exportMethod)
+	}
+	if got := req.md.Get("api-key"); len(got) != 1 || got[0] != "secret" {
+		t.Errorf("got api-key header %q, want secret", got)
+	}
+	if want := otlp.MarshalSpans([]core.KeyValue{resource}, spans); !bytes.Equal(req.body, want) {
+		t.Errorf("got request %x, want %x", req.body, want)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	addr, requests, stop := startCollector(t)
+	defer stop()
+
+	e, err := NewExporter(WithEndpoint(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}}); err != nil {
+		t.Fatal(err)
+	}
+	<-requests
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown() = %v, want nil", err)
+	}
+	if err := e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}}); err != errShutdown {
+		t.Errorf("export after Shutdown returned %v, want %v", err, errShutdown)
+	}
+}
+
+func TestExportSpansRetry(t *testing.T) {
+	addr, requests, stop := startCollector(t,
+		status.Error(codes.Unavailable, "restarting"),
+		status.Error(codes.ResourceExhausted, "slow down"),
+	)
+	defer stop()
+
+	e, err := NewExporter(WithEndpoint(addr), WithRetry(3, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Stop()
+
+	if err := e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}}); err != nil {
+		t.Fatalf("export failed after retries: %v", err)
+	}
+	if n := len(requests); n != 3 {
+		t.Errorf("got %d attempts, want 3", n)
+	}
+}
+
+func TestExportSpansPermanentError(t *testing.T) {
+	addr, requests, stop := startCollector(t, status.Error(codes.InvalidArgument, "bad span"))
+	defer stop()
+
+	e, err := NewExporter(WithEndpoint(addr), WithRetry(3, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Stop()
+
+	err = e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got error %v, want InvalidArgument", err)
+	}
+	if n := len(requests); n != 1 {
+		t.Errorf("got %d attempts, want 1", n)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	for _, tt := range []struct {
+		code codes.Code
+		want bool
+	}{
+		{codes.Unavailable, true},
+		{codes.ResourceExhausted, true},
+		{codes.DeadlineExceeded, true},
+		{codes.Canceled, false},
+		{codes.InvalidArgument, false},
+		{codes.Unauthenticated, false},
+	} {
+		if got := retryable(status.Error(tt.code, "")); got != tt.want {
+			t.Errorf("retryable(%v) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestExportSpansMinimumBackoff(t *testing.T) {
+	addr, _, stop := startCollector(t, status.Error(codes.Unavailable, "restarting"))
+	defer stop()
+
+	e, err := NewExporter(WithEndpoint(addr), WithRetry(2, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Stop()
+
+	start := time.Now()
+	if err := e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}}); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < minRetryBackoff {
+		t.Errorf("retried after %v, want at least %v", d, minRetryBackoff)
+	}
+}
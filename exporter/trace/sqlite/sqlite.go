@@ -0,0 +1,254 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlite contains an exporter that stores finished spans in a
+// SQLite database, so that telemetry of local or test runs can be
+// analyzed with plain SQL.
+//
+// The exporter works with any database/sql driver for SQLite. This module
+// does not depend on one, so the application has to add a driver to its
+// own go.mod and import it; without a driver, sql.Open fails with
+// "sql: unknown driver". For example, with github.com/mattn/go-sqlite3:
+//
+//	import _ "github.com/mattn/go-sqlite3"
+//
+//	db, err := sql.Open("sqlite3", "traces.db")
+//	...
+//	exporter, err := sqlite.NewExporter(db)
+//	...
+//	trace.RegisterExporter(exporter)
+//
+// Spans are stored using the following schema. Trace and span IDs are
+// lowercase hex strings, times are Unix nanoseconds and kinds are the
+// names of apitrace.SpanKind, such as "server". Attribute values keep
+// their type, so they can be compared numerically.
+//
+//	spans(trace_id, span_id, parent_span_id, name, kind, start_time, end_time,
+//	      duration, status_code, status_message, has_remote_parent, child_span_count,
+//	      dropped_attribute_count, dropped_event_count, dropped_link_count)
+//	span_attributes(trace_id, span_id, key, value)
+//	span_resources(trace_id, span_id, key, value)
+//	span_events(trace_id, span_id, event_index, time, message)
+//	span_event_attributes(trace_id, span_id, event_index, key, value)
+//	span_links(trace_id, span_id, link_index, linked_trace_id, linked_span_id)
+//	span_link_attributes(trace_id, span_id, link_index, key, value)
+//
+// For example, the slowest spans of a run are found with
+//
+//	SELECT name, duration FROM spans ORDER BY duration DESC LIMIT 10;
+package sqlite // import "go.opentelemetry.io/exporter/trace/sqlite"
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS spans (
+	trace_id                TEXT NOT NULL,
+	span_id                 TEXT NOT NULL,
+	parent_span_id          TEXT,
+	name                    TEXT NOT NULL,
+	kind                    TEXT NOT NULL,
+	start_time              INTEGER NOT NULL,
+	end_time                INTEGER NOT NULL,
+	duration                INTEGER NOT NULL,
+	status_code             INTEGER NOT NULL,
+	status_message          TEXT NOT NULL,
+	has_remote_parent       INTEGER NOT NULL,
+	child_span_count        INTEGER NOT NULL,
+	dropped_attribute_count INTEGER NOT NULL,
+	dropped_event_count     INTEGER NOT NULL,
+	dropped_link_count      INTEGER NOT NULL,
+	PRIMARY KEY (trace_id, span_id)
+);
+CREATE TABLE IF NOT EXISTS span_attributes (
+	trace_id TEXT NOT NULL,
+	span_id  TEXT NOT NULL,
+	key      TEXT NOT NULL,
+	value
+);
+CREATE TABLE IF NOT EXISTS span_resources (
+	trace_id TEXT NOT NULL,
+	span_id  TEXT NOT NULL,
+	key      TEXT NOT NULL,
+	value
+);
+CREATE TABLE IF NOT EXISTS span_events (
+	trace_id    TEXT NOT NULL,
+	span_id     TEXT NOT NULL,
+	event_index INTEGER NOT NULL,
+	time        INTEGER NOT NULL,
+	message     TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS span_event_attributes (
+	trace_id    TEXT NOT NULL,
+	span_id     TEXT NOT NULL,
+	event_index INTEGER NOT NULL,
+	key         TEXT NOT NULL,
+	value
+);
+CREATE TABLE IF NOT EXISTS span_links (
+	trace_id        TEXT NOT NULL,
+	span_id         TEXT NOT NULL,
+	link_index      INTEGER NOT NULL,
+	linked_trace_id TEXT NOT NULL,
+	linked_span_id  TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS span_link_attributes (
+	trace_id   TEXT NOT NULL,
+	span_id    TEXT NOT NULL,
+	link_index INTEGER NOT NULL,
+	key        TEXT NOT NULL,
+	value
+);
+CREATE INDEX IF NOT EXISTS span_attributes_span ON span_attributes (trace_id, span_id);
+CREATE INDEX IF NOT EXISTS span_events_span ON span_events (trace_id, span_id);
+CREATE INDEX IF NOT EXISTS span_links_span ON span_links (trace_id, span_id);
+CREATE INDEX IF NOT EXISTS span_links_linked ON span_links (linked_trace_id, linked_span_id);
+`
+
+// Exporter is a trace.ContextExporter that inserts every exported span
+// into a SQLite database.
+type Exporter struct {
+	db *sql.DB
+}
+
+var _ trace.ContextExporter = (*Exporter)(nil)
+
+// NewExporter returns an Exporter writing to db, creating the tables
+// if they do not exist yet.
+func NewExporter(db *sql.DB) (*Exporter, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("sqlite: creating schema: %v", err)
+	}
+	return &Exporter{db: db}, nil
+}
+
+// ExportSpan stores s, ignoring errors. It is only called when the
+// Exporter is used outside of the SDK's context-aware export path.
+func (e *Exporter) ExportSpan(s *trace.SpanData) {
+	_ = e.ExportSpanWithContext(context.Background(), s)
+}
+
+// ExportSpanWithContext stores s in a single transaction.
+func (e *Exporter) ExportSpanWithContext(ctx context.Context, s *trace.SpanData) (err error) {
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	traceID := s.SpanContext.TraceIDString()
+	spanID := s.SpanContext.SpanIDString()
+	var parentID interface{}
+	if s.ParentSpanID != 0 {
+		parentID = fmt.Sprintf("%.16x", s.ParentSpanID)
+	}
+
+	if _, err = tx.ExecContext(ctx, `INSERT INTO spans VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		traceID, spanID, parentID, s.Name, s.SpanKind.String(),
+		s.StartTime.UnixNano(), s.EndTime.UnixNano(), int64(s.EndTime.Sub(s.StartTime)),
+		int64(s.Status), s.StatusMessage, s.HasRemoteParent, s.ChildSpanCount,
+		s.DroppedAttributeCount, s.DroppedMessageEventCount, s.DroppedLinkCount,
+	); err != nil {
+		return err
+	}
+
+	for k, v := range s.Attributes {
+		if _, err = tx.ExecContext(ctx, `INSERT INTO span_attributes VALUES (?, ?, ?, ?)`,
+			traceID, spanID, k, sqlValue(v),
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, kv := range s.Resource {
+		if _, err = tx.ExecContext(ctx, `INSERT INTO span_resources VALUES (?, ?, ?, ?)`,
+			traceID, spanID, kv.Key.Variable.Name, sqlValue(kv.Value),
+		); err != nil {
+			return err
+		}
+	}
+
+	for it := s.Events(); it.Next(); {
+		i, ev := it.Index(), it.Event()
+		if _, err = tx.ExecContext(ctx, `INSERT INTO span_events VALUES (?, ?, ?, ?, ?)`,
+			traceID, spanID, i, ev.Time().UnixNano(), ev.Message(),
+		); err != nil {
+			return err
+		}
+		for _, kv := range ev.Attributes() {
+			if _, err = tx.ExecContext(ctx, `INSERT INTO span_event_attributes VALUES (?, ?, ?, ?, ?)`,
+				traceID, spanID, i, kv.Key.Variable.Name, sqlValue(kv.Value),
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, link := range s.Links {
+		if _, err = tx.ExecContext(ctx, `INSERT INTO span_links VALUES (?, ?, ?, ?, ?)`,
+			traceID, spanID, i, link.TraceIDString(), link.SpanIDString(),
+		); err != nil {
+			return err
+		}
+		for _, kv := range link.Attributes {
+			if _, err = tx.ExecContext(ctx, `INSERT INTO span_link_attributes VALUES (?, ?, ?, ?, ?)`,
+				traceID, spanID, i, kv.Key.Variable.Name, sqlValue(kv.Value),
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sqlValue converts an attribute value into a value SQLite stores with
+// its natural type.
+func sqlValue(v interface{}) interface{} {
+	cv, ok := v.(core.Value)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	switch cv.Type {
+	case core.BOOL:
+		return cv.Bool
+	case core.INT32, core.INT64:
+		return cv.Int64
+	case core.UINT32, core.UINT64:
+		// SQLite integers are signed 64-bit.
+		if int64(cv.Uint64) >= 0 {
+			return int64(cv.Uint64)
+		}
+		return cv.Emit()
+	case core.FLOAT32, core.FLOAT64:
+		return cv.Float64
+	case core.STRING:
+		return cv.String
+	case core.BYTES:
+		return cv.Bytes
+	}
+	return cv.Emit()
+}
@@ -0,0 +1,341 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// fakeDriver is a database/sql driver that records the statements it
+// executes instead of running them. Each DSN names its own recorder.
+type fakeDriver struct{}
+
+type fakeDB struct {
+	mu        sync.Mutex
+	execs     []fakeExec
+	failOn    string
+	commits   int
+	rollbacks int
+}
+
+type fakeExec struct {
+	query string
+	args  []driver.Value
+}
+
+var (
+	fakeDBsMu sync.Mutex
+	fakeDBs   = map[string]*fakeDB{}
+)
+
+func init() {
+	sql.Register("sqlitefake", fakeDriver{})
+}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+	db, ok := fakeDBs[dsn]
+	if !ok {
+		return nil, errors.New("fake: unknown dsn " + dsn)
+	}
+	return &fakeConn{db: db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{db: c.db}, nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if s.db.failOn != "" && strings.Contains(s.query, s.db.failOn) {
+		return nil, errors.New("fake: exec failed")
+	}
+	s.db.execs = append(s.db.execs, fakeExec{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("fake: queries are not supported")
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.rollbacks++
+	return nil
+}
+
+func openFake(t *testing.T) (*sql.DB, *fakeDB) {
+	t.Helper()
+	fake := &fakeDB{}
+	fakeDBsMu.Lock()
+	fakeDBs[t.Name()] = fake
+	fakeDBsMu.Unlock()
+	db, err := sql.Open("sqlitefake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, fake
+}
+
+// inserts returns the executed inserts into table.
+func (db *fakeDB) inserts(table string) [][]driver.Value {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var rows [][]driver.Value
+	for _, e := range db.execs {
+		if strings.HasPrefix(e.query, "INSERT INTO "+table+" ") {
+			rows = append(rows, e.args)
+		}
+	}
+	return rows
+}
+
+type captureExporter struct {
+	spans chan *trace.SpanData
+}
+
+func (e *captureExporter) ExportSpan(s *trace.SpanData) {
+	e.spans <- s
+}
+
+// record returns the data of a span ended after f ran on it.
+func record(t *testing.T, f func(ctx context.Context, span apitrace.Span)) *trace.SpanData {
+	t.Helper()
+	capture := &captureExporter{spans: make(chan *trace.SpanData, 1)}
+	trace.RegisterExporter(capture)
+	defer trace.UnregisterExporter(capture)
+
+	// A sampled remote parent makes the default sampler record the span.
+	parent := core.SpanContext{
+		TraceID:      core.TraceID{High: 1, Low: 2},
+		SpanID:       3,
+		TraceOptions: core.TraceOptionSampled,
+	}
+	ctx, span := trace.Register().Start(context.Background(), "op", apitrace.ChildOf(parent), apitrace.WithSpanKind(apitrace.SpanKindServer))
+	f(ctx, span)
+	span.Finish()
+	return <-capture.spans
+}
+
+func TestNewExporterCreatesSchema(t *testing.T) {
+	db, fake := openFake(t)
+	if _, err := NewExporter(db); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.execs) != 1 || fake.execs[0].query != schema {
+		t.Errorf("got execs %v, want only the schema", fake.execs)
+	}
+}
+
+func TestNewExporterError(t *testing.T) {
+	db, fake := openFake(t)
+	fake.failOn = "CREATE TABLE"
+	if _, err := NewExporter(db); err == nil || !strings.HasPrefix(err.Error(), "sqlite: ") {
+		t.Errorf("got error %v, want a sqlite: error", err)
+	}
+}
+
+func TestExportSpan(t *testing.T) {
+	db, fake := openFake(t)
+	e, err := NewExporter(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd := record(t, func(ctx context.Context, span apitrace.Span) {
+		span.SetAttribute(key.New("http.route").String("/users"))
+		span.Event(ctx, "retry", key.New("attempt").Int64(2))
+		span.Event(ctx, "done")
+		apitrace.SetStatusWithMessage(span, codes.NotFound, "no such user")
+	})
+	if err := e.ExportSpanWithContext(context.Background(), sd); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := fake.inserts("spans")
+	if len(spans) != 1 {
+		t.Fatalf("inserted %d spans, want 1", len(spans))
+	}
+	row := spans[0]
+	if row[0] != "00000000000000010000000000000002" || row[1] != sd.SpanContext.SpanIDString() {
+		t.Errorf("got ids %v, %v", row[0], row[1])
+	}
+	if row[2] != "0000000000000003" || row[3] != "op" {
+		t.Errorf("got parent %v and name %v, want 0000000000000003 and op", row[2], row[3])
+	}
+	if row[5] != sd.StartTime.UnixNano() || row[6] != sd.EndTime.UnixNano() {
+		t.Errorf("got times %v, %v", row[5], row[6])
+	}
+	if row[4] != "server" {
+		t.Errorf("kind = %v, want server", row[4])
+	}
+	if row[8] != int64(codes.NotFound) || row[9] != "no such user" {
+		t.Errorf("got status %v, %v, want NotFound and its message", row[8], row[9])
+	}
+	if row[10] != true {
+		t.Errorf("has_remote_parent = %v, want true", row[10])
+	}
+
+	attrs := fake.inserts("span_attributes")
+	if len(attrs) != 1 || attrs[0][2] != "http.route" || attrs[0][3] != "/users" {
+		t.Errorf("got span attributes %v", attrs)
+	}
+	events := fake.inserts("span_events")
+	if len(events) != 2 ||
+		events[0][2] != int64(0) || events[0][4] != "retry" ||
+		events[1][2] != int64(1) || events[1][4] != "done" {
+		t.Errorf("got span events %v", events)
+	}
+	if len(events) > 0 && events[0][3] != sd.MessageEvents[0].Time().UnixNano() {
+		t.Errorf("got event time %v, want %d", events[0][3], sd.MessageEvents[0].Time().UnixNano())
+	}
+	eventAttrs := fake.inserts("span_event_attributes")
+	if len(eventAttrs) != 1 || eventAttrs[0][2] != int64(0) || eventAttrs[0][3] != "attempt" || eventAttrs[0][4] != int64(2) {
+		t.Errorf("got span event attributes %v", eventAttrs)
+	}
+	if fake.commits != 1 || fake.rollbacks != 0 {
+		t.Errorf("got %d commits and %d rollbacks, want 1 and 0", fake.commits, fake.rollbacks)
+	}
+}
+
+func TestExportSpanLinksAndResource(t *testing.T) {
+	db, fake := openFake(t)
+	e, err := NewExporter(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linked := core.SpanContext{TraceID: core.TraceID{Low: 5}, SpanID: 6}
+	sd := &trace.SpanData{
+		Name:        "batch",
+		SpanContext: core.SpanContext{SpanID: 1},
+		Links: []apitrace.Link{
+			{SpanContext: linked, Attributes: []core.KeyValue{key.New("reason").String("batched")}},
+		},
+		Resource: []core.KeyValue{key.New("service.name").String("svc")},
+	}
+	if err := e.ExportSpanWithContext(context.Background(), sd); err != nil {
+		t.Fatal(err)
+	}
+
+	links := fake.inserts("span_links")
+	if len(links) != 1 || links[0][2] != int64(0) || links[0][3] != linked.TraceIDString() || links[0][4] != linked.SpanIDString() {
+		t.Errorf("got span links %v", links)
+	}
+	linkAttrs := fake.inserts("span_link_attributes")
+	if len(linkAttrs) != 1 || linkAttrs[0][3] != "reason" || linkAttrs[0][4] != "batched" {
+		t.Errorf("got span link attributes %v", linkAttrs)
+	}
+	resources := fake.inserts("span_resources")
+	if len(resources) != 1 || resources[0][2] != "service.name" || resources[0][3] != "svc" {
+		t.Errorf("got span resources %v", resources)
+	}
+}
+
+func TestExportSpanRootHasNullParent(t *testing.T) {
+	db, fake := openFake(t)
+	e, err := NewExporter(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd := &trace.SpanData{Name: "root", SpanContext: core.SpanContext{SpanID: 1}}
+	if err := e.ExportSpanWithContext(context.Background(), sd); err != nil {
+		t.Fatal(err)
+	}
+	if spans := fake.inserts("spans"); len(spans) != 1 || spans[0][2] != nil {
+		t.Errorf("got spans %v, want one with a NULL parent", spans)
+	}
+}
+
+func TestExportSpanRollsBack(t *testing.T) {
+	db, fake := openFake(t)
+	e, err := NewExporter(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.failOn = "span_events"
+	sd := record(t, func(ctx context.Context, span apitrace.Span) {
+		span.Event(ctx, "event")
+	})
+	if err := e.ExportSpanWithContext(context.Background(), sd); err == nil {
+		t.Error("got no error, want the insert error")
+	}
+	if fake.commits != 0 || fake.rollbacks != 1 {
+		t.Errorf("got %d commits and %d rollbacks, want 0 and 1", fake.commits, fake.rollbacks)
+	}
+}
+
+func TestSQLValue(t *testing.T) {
+	k := key.New("k")
+	for _, tt := range []struct {
+		in   interface{}
+		want interface{}
+	}{
+		{k.Bool(true).Value, true},
+		{k.Int32(-3).Value, int64(-3)},
+		{k.Int64(1 << 40).Value, int64(1 << 40)},
+		{k.Uint32(7).Value, int64(7)},
+		{k.Uint64(1 << 63).Value, "9223372036854775808"},
+		{k.Float64(1.5).Value, 1.5},
+		{k.String("s").Value, "s"},
+		{"plain", "plain"},
+		{int64(4), "4"},
+	} {
+		if got := sqlValue(tt.in); got != tt.want {
+			t.Errorf("sqlValue(%v) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// span is a span in the Zipkin v2 JSON format. Times are in microseconds.
+type span struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Kind          string            `json:"kind,omitempty"`
+	Name          string            `json:"name,omitempty"`
+	Timestamp     int64             `json:"timestamp,omitempty"`
+	Duration      int64             `json:"duration,omitempty"`
+	LocalEndpoint *endpoint         `json:"localEndpoint,omitempty"`
+	Annotations   []annotation      `json:"annotations,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+type endpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+type annotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// Tags recording the status of failed spans, as in the OpenTelemetry to
+// Zipkin mapping.
+const (
+	statusCodeTag = "otel.status_code"
+	errorTag      = "error"
+)
+
+// kinds maps span kinds to Zipkin kinds. Zipkin has no kind for internal
+// spans.
+var kinds = map[apitrace.SpanKind]string{
+	apitrace.SpanKindServer:   "SERVER",
+	apitrace.SpanKindClient:   "CLIENT",
+	apitrace.SpanKindProducer: "PRODUCER",
+	apitrace.SpanKindConsumer: "CONSUMER",
+}
+
+func toZipkin(sd *trace.SpanData, local *endpoint) span {
+	zs := span{
+		TraceID:       sd.SpanContext.TraceIDString(),
+		ID:            sd.SpanContext.SpanIDString(),
+		Kind:          kinds[sd.SpanKind],
+		Name:          sd.Name,
+		Timestamp:     micros(sd.StartTime),
+		Duration:      sd.EndTime.Sub(sd.StartTime).Nanoseconds() / 1e3,
+		LocalEndpoint: local,
+	}
+	if sd.ParentSpanID != 0 {
+		zs.ParentID = fmt.Sprintf("%.16x", sd.ParentSpanID)
+	}
+	// Zipkin rejects zero durations; round sub-microsecond spans up.
+	if zs.Duration == 0 && sd.EndTime.After(sd.StartTime) {
+		zs.Duration = 1
+	}
+	for it := sd.Events(); it.Next(); {
+		ev := it.Event()
+		value := ev.Message()
+		for _, kv := range ev.Attributes() {
+			value += " " + kv.Key.Variable.Name + "=" + kv.Value.Emit()
+		}
+		zs.Annotations = append(zs.Annotations, annotation{
+			Timestamp: micros(ev.Time()),
+			Value:     value,
+		})
+	}
+	if len(sd.Attributes) > 0 || sd.Status != codes.OK {
+		zs.Tags = make(map[string]string, len(sd.Attributes)+2)
+	}
+	for k, v := range sd.Attributes {
+		zs.Tags[k] = tagValue(v)
+	}
+	if sd.Status != codes.OK {
+		zs.Tags[statusCodeTag] = "ERROR"
+		zs.Tags[errorTag] = sd.StatusMessage
+		if sd.StatusMessage == "" {
+			zs.Tags[errorTag] = sd.Status.String()
+		}
+	}
+	return zs
+}
+
+type emitter interface {
+	Emit() string
+}
+
+func tagValue(v interface{}) string {
+	if e, ok := v.(emitter); ok {
+		return e.Emit()
+	}
+	return fmt.Sprint(v)
+}
+
+func micros(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / 1e3
+}
@@ -0,0 +1,212 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zipkin contains an exporter that sends spans to a Zipkin
+// server in the Zipkin v2 JSON format, so that the SDK can report to an
+// existing Zipkin installation without a translating collector.
+//
+//	exporter := zipkin.NewExporter(
+//		zipkin.WithEndpoint("http://zipkin:9411/api/v2/spans"),
+//		zipkin.WithServiceName("checkout"),
+//	)
+//	bsp, err := trace.NewBatchSpanProcessor(exporter)
+//	...
+//	trace.RegisterSpanProcessor(bsp)
+//	defer trace.UnregisterSpanProcessor(bsp)
+package zipkin // import "go.opentelemetry.io/exporter/trace/zipkin"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/api/logger"
+	"go.opentelemetry.io/exporter/internal"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+const (
+	// DefaultEndpoint is the span collection URL of a local Zipkin
+	// server.
+	DefaultEndpoint = "http://localhost:9411/api/v2/spans"
+
+	// DefaultTimeout bounds a single attempt to send spans.
+	DefaultTimeout = 10 * time.Second
+
+	// maxErrorMessageSize caps how much of a failed response is included
+	// in the returned error.
+	maxErrorMessageSize = 1024
+)
+
+// Exporter is a trace.Exporter that sends spans to a Zipkin server.
+type Exporter struct {
+	endpoint string
+	local    *endpoint
+	client   *http.Client
+	timeout  time.Duration
+
+	maxAttempts int
+	backoff     time.Duration
+
+	reresolveInterval time.Duration
+}
+
+var (
+	_ trace.ContextExporter = (*Exporter)(nil)
+	_ trace.SpanExporter    = (*Exporter)(nil)
+)
+
+// Option configures an Exporter.
+type Option func(*Exporter)
+
+// WithEndpoint sets the URL spans are posted to. It defaults to
+// DefaultEndpoint.
+func WithEndpoint(url string) Option {
+	return func(e *Exporter) {
+		e.endpoint = url
+	}
+}
+
+// WithServiceName sets the service name of the local endpoint of the
+// spans.
+func WithServiceName(name string) Option {
+	return func(e *Exporter) {
+		e.local = &endpoint{ServiceName: name}
+	}
+}
+
+// WithTimeout bounds each attempt to send spans. It defaults to
+// DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(e *Exporter) {
+		e.timeout = timeout
+	}
+}
+
+// WithRetry makes the exporter try to send spans up to maxAttempts times
+// when the server cannot be reached or responds with a 429 or 5xx
+// status. The wait before the second attempt is backoff, and doubles
+// for every attempt after it. Spans are sent once by default.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(e *Exporter) {
+		e.maxAttempts = maxAttempts
+		e.backoff = backoff
+	}
+}
+
+// WithReresolveInterval makes the exporter close its connections to the
+// server once they are interval old, so that the endpoint is resolved
+// again on the next export. It is disabled by default.
+func WithReresolveInterval(interval time.Duration) Option {
+	return func(e *Exporter) {
+		e.reresolveInterval = interval
+	}
+}
+
+// NewExporter returns an Exporter configured with opts.
+func NewExporter(opts ...Option) *Exporter {
+	e := &Exporter{
+		endpoint:    DefaultEndpoint,
+		timeout:     DefaultTimeout,
+		maxAttempts: 1,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	e.client = &http.Client{
+		Transport: internal.RotatingTransport(transport, e.reresolveInterval),
+	}
+	return e
+}
+
+// ExportSpan sends a span to the server. Errors are dropped; register
+// the Exporter with trace.RegisterExporter to have them reported.
+func (e *Exporter) ExportSpan(sd *trace.SpanData) {
+	_ = e.ExportSpanWithContext(context.Background(), sd)
+}
+
+// ExportSpanWithContext sends a span to the server.
+func (e *Exporter) ExportSpanWithContext(ctx context.Context, sd *trace.SpanData) error {
+	return e.ExportSpans(ctx, []*trace.SpanData{sd})
+}
+
+// ExportSpans sends spans to the server in a single request, retrying
+// as configured with WithRetry.
+func (e *Exporter) ExportSpans(ctx context.Context, spans []*trace.SpanData) error {
+	body, err := marshalSpans(e.local, spans)
+	if err != nil {
+		return err
+	}
+
+	backoff := e.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := e.send(ctx, body)
+		if err == nil || !retry || attempt >= e.maxAttempts {
+			return err
+		}
+		logger.Warnf("zipkin: export attempt %d failed, retrying in %v: %v", attempt, backoff, err)
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// send posts body once and reports whether a failure may succeed when
+// retried.
+func (e *Exporter) send(ctx context.Context, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+	res, err := e.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorMessageSize))
+		retry := res.StatusCode == http.StatusTooManyRequests || res.StatusCode/100 == 5
+		return retry, fmt.Errorf("zipkin: server responded %s: %s", res.Status, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	return false, nil
+}
+
+// marshalSpans encodes spans as a Zipkin v2 JSON list with local as the
+// local endpoint of every span.
+func marshalSpans(local *endpoint, spans []*trace.SpanData) ([]byte, error) {
+	zs := make([]span, len(spans))
+	for i, sd := range spans {
+		zs[i] = toZipkin(sd, local)
+	}
+	return json.Marshal(zs)
+}
@@ -0,0 +1,127 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+func TestExportSpans(t *testing.T) {
+	var got []span
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("got Content-Type %q, want application/json", ct)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("request body is not a list of spans: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	start := time.Unix(1500000000, 0)
+	sd := &trace.SpanData{
+		SpanContext: core.SpanContext{
+			TraceID: core.TraceID{High: 0x0102030405060708, Low: 0x090a0b0c0d0e0f10},
+			SpanID:  0x1112131415161718,
+		},
+		ParentSpanID:  0x2122232425262728,
+		SpanKind:      apitrace.SpanKindServer,
+		Name:          "GET /users",
+		StartTime:     start,
+		EndTime:       start.Add(1500 * time.Microsecond),
+		Attributes:    map[string]interface{}{"http.status_code": core.Value{Type: core.INT64, Int64: 500}},
+		Status:        codes.Internal,
+		StatusMessage: "database unavailable",
+	}
+	e := NewExporter(WithEndpoint(srv.URL), WithServiceName("users"))
+	if err := e.ExportSpans(context.Background(), []*trace.SpanData{sd}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []span{{
+		TraceID:       "0102030405060708090a0b0c0d0e0f10",
+		ID:            "1112131415161718",
+		ParentID:      "2122232425262728",
+		Kind:          "SERVER",
+		Name:          "GET /users",
+		Timestamp:     1500000000000000,
+		Duration:      1500,
+		LocalEndpoint: &endpoint{ServiceName: "users"},
+		Tags: map[string]string{
+			"http.status_code": "500",
+			statusCodeTag:      "ERROR",
+			errorTag:           "database unavailable",
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("exported spans differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestExportSpansRetry(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		status   int
+		attempts int
+	}{
+		{"unavailable", http.StatusServiceUnavailable, 3},
+		{"too many requests", http.StatusTooManyRequests, 3},
+		{"bad request", http.StatusBadRequest, 1},
+	} {
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			http.Error(w, "try again", tt.status)
+		}))
+
+		e := NewExporter(WithEndpoint(srv.URL), WithRetry(3, time.Millisecond))
+		if err := e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}}); err == nil {
+			t.Errorf("%s: export succeeded, want error", tt.name)
+		}
+		if attempts != tt.attempts {
+			t.Errorf("%s: got %d attempts, want %d", tt.name, attempts, tt.attempts)
+		}
+		srv.Close()
+	}
+}
+
+func TestExportSpansTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	e := NewExporter(WithEndpoint(srv.URL), WithTimeout(10*time.Millisecond))
+	if err := e.ExportSpans(context.Background(), []*trace.SpanData{{Name: "span"}}); err == nil {
+		t.Error("export succeeded, want timeout")
+	}
+}
@@ -8,5 +8,6 @@ require (
 	github.com/google/go-cmp v0.3.0
 	github.com/hashicorp/golang-lru v0.5.3
 	github.com/lightstep/tracecontext.go v0.0.0-20181129014701-1757c391b1ac
+	golang.org/x/sync v0.0.0-20190423024810-112230192c58
 	google.golang.org/grpc v1.22.1
 )
@@ -0,0 +1,119 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package protowire appends protocol buffer fields to a buffer. It is
+// shared by the exporters that encode their messages by hand rather than
+// with generated code, so that binaries using them do not link the
+// protobuf runtime.
+package protowire // import "go.opentelemetry.io/internal/protowire"
+
+import "encoding/binary"
+
+// Wire types.
+const (
+	WireVarint  = 0
+	WireFixed64 = 1
+	WireBytes   = 2
+)
+
+// Encoder appends protocol buffer fields to Buf. Fields holding the zero
+// value are omitted, as in proto3.
+type Encoder struct {
+	Buf []byte
+}
+
+// Tag appends the key of a field.
+func (e *Encoder) Tag(field, wireType int) {
+	e.Varint(uint64(field)<<3 | uint64(wireType))
+}
+
+// Varint appends v in the varint encoding.
+func (e *Encoder) Varint(v uint64) {
+	for v >= 0x80 {
+		e.Buf = append(e.Buf, byte(v)|0x80)
+		v >>= 7
+	}
+	e.Buf = append(e.Buf, byte(v))
+}
+
+// Fixed64 appends v in little-endian byte order.
+func (e *Encoder) Fixed64(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	e.Buf = append(e.Buf, b[:]...)
+}
+
+// UintField appends a varint field.
+func (e *Encoder) UintField(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.Tag(field, WireVarint)
+	e.Varint(v)
+}
+
+// Fixed64Field appends a fixed64 field.
+func (e *Encoder) Fixed64Field(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.Tag(field, WireFixed64)
+	e.Fixed64(v)
+}
+
+// BytesField appends a bytes field.
+func (e *Encoder) BytesField(field int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	e.LengthDelimited(field, b)
+}
+
+// StringField appends a string field.
+func (e *Encoder) StringField(field int, s string) {
+	if s == "" {
+		return
+	}
+	e.LengthDelimited(field, []byte(s))
+}
+
+// LengthDelimited appends a length-delimited field even if b is empty,
+// as members of a oneof are written even when they hold the zero value.
+func (e *Encoder) LengthDelimited(field int, b []byte) {
+	e.Tag(field, WireBytes)
+	e.Varint(uint64(len(b)))
+	e.Buf = append(e.Buf, b...)
+}
+
+// Message appends an embedded message whose fields are written by f.
+func (e *Encoder) Message(field int, f func(e *Encoder)) {
+	var m Encoder
+	f(&m)
+	e.LengthDelimited(field, m.Buf)
+}
+
+// SpanID returns id as the 8 big-endian bytes used for span IDs.
+func SpanID(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
+
+// TraceID returns the 16 big-endian bytes used for trace IDs.
+func TraceID(high, low uint64) []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[:8], high)
+	binary.BigEndian.PutUint64(b[8:], low)
+	return b
+}
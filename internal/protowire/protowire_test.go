@@ -0,0 +1,45 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protowire
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		f    func(e *Encoder)
+		want []byte
+	}{
+		{"zero uint", func(e *Encoder) { e.UintField(1, 0) }, nil},
+		{"uint", func(e *Encoder) { e.UintField(1, 300) }, []byte{0x08, 0xac, 0x02}},
+		{"fixed64", func(e *Encoder) { e.Fixed64Field(2, 1) }, []byte{0x11, 1, 0, 0, 0, 0, 0, 0, 0}},
+		{"empty string", func(e *Encoder) { e.StringField(3, "") }, nil},
+		{"string", func(e *Encoder) { e.StringField(3, "hi") }, []byte{0x1a, 2, 'h', 'i'}},
+		{"empty oneof", func(e *Encoder) { e.LengthDelimited(3, nil) }, []byte{0x1a, 0}},
+		{"message", func(e *Encoder) {
+			e.Message(4, func(e *Encoder) { e.UintField(1, 1) })
+		}, []byte{0x22, 2, 0x08, 1}},
+		{"span id", func(e *Encoder) { e.BytesField(5, SpanID(1)) }, []byte{0x2a, 8, 0, 0, 0, 0, 0, 0, 0, 1}},
+	} {
+		var e Encoder
+		tt.f(&e)
+		if !bytes.Equal(e.Buf, tt.want) {
+			t.Errorf("%s: got %x, want %x", tt.name, e.Buf, tt.want)
+		}
+	}
+}
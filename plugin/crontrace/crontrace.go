@@ -0,0 +1,143 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crontrace traces runs of scheduled jobs.
+//
+// A run is not part of the trace of whatever scheduled it: the trigger,
+// e.g., a request enqueuing a delayed job, may have finished long before
+// the job runs, and a periodic job would otherwise add a span to the same
+// trace forever. Start begins a new trace for every run instead, and
+// links its root span to the trigger when the trigger's context was
+// saved with the job:
+//
+//	// When scheduling
+//	job.Trace = crontrace.Trigger(ctx)
+//	...
+//	// When the job runs
+//	err := crontrace.Do(ctx, crontrace.Run{
+//		Name:     "reindex",
+//		Schedule: "0 3 * * *",
+//		RunID:    job.ID,
+//		Trigger:  job.Trace,
+//	}, reindex)
+package crontrace // import "go.opentelemetry.io/plugin/crontrace"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/propagation"
+	"go.opentelemetry.io/api/trace"
+)
+
+var (
+	JobNameKey     = key.New("job.name")
+	JobScheduleKey = key.New("job.schedule")
+	JobRunIDKey    = key.New("job.run_id")
+)
+
+// instrumentationName identifies the spans of this package.
+const instrumentationName = "go.opentelemetry.io/plugin/crontrace"
+
+// RelationshipTriggeredBy labels the link from the span of a run to the
+// span that scheduled it.
+const RelationshipTriggeredBy = "triggered_by"
+
+// Carrier holds the W3C trace context fields of a trigger. It is a plain
+// string map so that it can be stored in any job payload.
+type Carrier map[string]string
+
+// Get implements propagation.Carrier.
+func (c Carrier) Get(key string) string {
+	return c[key]
+}
+
+// Set implements propagation.Carrier.
+func (c Carrier) Set(key string, value string) {
+	c[key] = value
+}
+
+// Trigger returns the trace context of the current span of ctx, to be
+// saved with the job it schedules.
+func Trigger(ctx context.Context) Carrier {
+	c := Carrier{}
+	propagation.Inject(ctx, propagation.TraceContext(), c)
+	return c
+}
+
+// Run describes a run of a scheduled job.
+type Run struct {
+	// Name names the job and the span of the run.
+	Name string
+	// Schedule is the schedule of the job, e.g., a cron expression.
+	// Optional.
+	Schedule string
+	// RunID identifies the run. Optional.
+	RunID string
+	// Trigger is the trace context returned by Trigger when the run was
+	// scheduled. Optional.
+	Trigger Carrier
+}
+
+// Start starts the root span of a new trace for run, linked to the span
+// of run.Trigger if it holds a valid trace context. A span current in ctx
+// is not the parent of the run either. The span has the attributes the
+// global AttributeGetter of the trace package extracts from run.
+func Start(ctx context.Context, run Run, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	attrs := []core.KeyValue{JobNameKey.String(run.Name)}
+	if run.Schedule != "" {
+		attrs = append(attrs, JobScheduleKey.String(run.Schedule))
+	}
+	if run.RunID != "" {
+		attrs = append(attrs, JobRunIDKey.String(run.RunID))
+	}
+	attrs = append(attrs, trace.ExtractAttributes(run)...)
+	opts = append([]trace.SpanOption{trace.WithAttributes(attrs...)}, opts...)
+
+	ctx, span := trace.GlobalProvider().Tracer(instrumentationName).Start(rootContext{ctx}, run.Name, opts...)
+	if run.Trigger != nil {
+		sc, _ := propagation.TraceContext().Extract(ctx, run.Trigger)
+		if sc.IsValid() {
+			trace.AddLink(span, trace.NewLink(sc, RelationshipTriggeredBy))
+		}
+	}
+	return ctx, span
+}
+
+// Do runs f within the span of run, recording the error f returns.
+func Do(ctx context.Context, run Run, f func(context.Context) error) error {
+	ctx, span := Start(ctx, run)
+	defer span.Finish()
+
+	if err := f(ctx); err != nil {
+		trace.RecordError(ctx, span, err)
+		return err
+	}
+	return nil
+}
+
+// rootContext hides the spans held by a context, whatever the key the
+// tracer stores them under, so that the next span started is a root.
+type rootContext struct {
+	context.Context
+}
+
+func (c rootContext) Value(key interface{}) interface{} {
+	v := c.Context.Value(key)
+	if _, ok := v.(trace.Span); ok {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,146 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crontrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/trace"
+)
+
+const (
+	traceparent = "00-0102030405060708090a0b0c0d0e0f10-1112131415161718-01"
+	spanID      = "1112131415161718"
+)
+
+// linkedSpan records the links added to it.
+type linkedSpan struct {
+	trace.NoopSpan
+	links []trace.Link
+}
+
+func (s *linkedSpan) AddLink(link trace.Link) {
+	s.links = append(s.links, link)
+}
+
+// recordingTracer records the context and options of the span it starts.
+type recordingTracer struct {
+	trace.NoopTracer
+	ctx  context.Context
+	opts trace.SpanOptions
+	span *linkedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	t.ctx = ctx
+	t.opts = trace.SpanOptions{}
+	for _, opt := range opts {
+		opt(&t.opts)
+	}
+	t.span = &linkedSpan{}
+	return trace.SetCurrentSpan(ctx, t.span), t.span
+}
+
+func TestStart(t *testing.T) {
+	tracer := &recordingTracer{}
+	trace.SetGlobalTracer(tracer)
+	defer trace.SetGlobalTracer(&recordingTracer{})
+
+	scheduler := trace.SetCurrentSpan(context.Background(), &linkedSpan{})
+	Start(scheduler, Run{
+		Name:     "reindex",
+		Schedule: "0 3 * * *",
+		RunID:    "run-7",
+		Trigger:  Carrier{"traceparent": traceparent},
+	})
+
+	if _, ok := trace.CurrentSpan(tracer.ctx).(*linkedSpan); ok {
+		t.Error("the span of the scheduler is current when the run starts, want none")
+	}
+	got := map[string]string{}
+	for _, kv := range tracer.opts.Attributes {
+		got[kv.Key.Variable.Name] = kv.Value.Emit()
+	}
+	if got["job.name"] != "reindex" || got["job.schedule"] != "0 3 * * *" || got["job.run_id"] != "run-7" {
+		t.Errorf("got attributes %v, want the name, schedule and run ID of the job", got)
+	}
+	if len(tracer.span.links) != 1 {
+		t.Fatalf("got %d links, want 1", len(tracer.span.links))
+	}
+	link := tracer.span.links[0]
+	if link.SpanIDString() != spanID || link.Relationship() != RelationshipTriggeredBy {
+		t.Errorf("got link to %s labeled %q, want %s labeled %q", link.SpanIDString(), link.Relationship(), spanID, RelationshipTriggeredBy)
+	}
+
+	Start(context.Background(), Run{Name: "cleanup", Trigger: Carrier{"traceparent": "garbage"}})
+	if len(tracer.span.links) != 0 || len(tracer.opts.Attributes) != 1 {
+		t.Errorf("got links %v and attributes %v, want no link and only the job name", tracer.span.links, tracer.opts.Attributes)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	sc := core.SpanContext{
+		TraceID:      core.TraceID{High: 1, Low: 2},
+		SpanID:       3,
+		TraceOptions: core.TraceOptionSampled,
+	}
+	ctx := trace.SetCurrentSpan(context.Background(), spanWithContext{sc: sc})
+	c := Trigger(ctx)
+	if c.Get("traceparent") == "" {
+		t.Fatalf("Trigger() = %v, want a traceparent", c)
+	}
+
+	tracer := &recordingTracer{}
+	trace.SetGlobalTracer(tracer)
+	defer trace.SetGlobalTracer(&recordingTracer{})
+	Start(context.Background(), Run{Name: "job", Trigger: c})
+	if len(tracer.span.links) != 1 || tracer.span.links[0].SpanContext != sc {
+		t.Errorf("got links %v, want a link to %v", tracer.span.links, sc)
+	}
+}
+
+type spanWithContext struct {
+	trace.NoopSpan
+	sc core.SpanContext
+}
+
+func (s spanWithContext) SpanContext() core.SpanContext {
+	return s.sc
+}
+
+func (s spanWithContext) Tracer() trace.Tracer {
+	return injectingTracer{}
+}
+
+type injectingTracer struct {
+	trace.NoopTracer
+}
+
+func (injectingTracer) Inject(ctx context.Context, span trace.Span, injector trace.Injector) {
+	injector.Inject(span.SpanContext(), nil)
+}
+
+func TestDo(t *testing.T) {
+	trace.SetGlobalTracer(&recordingTracer{})
+	defer trace.SetGlobalTracer(&recordingTracer{})
+
+	want := errors.New("disk full")
+	if err := Do(context.Background(), Run{Name: "job"}, func(context.Context) error { return want }); err != want {
+		t.Errorf("Do() = %v, want %v", err, want)
+	}
+}
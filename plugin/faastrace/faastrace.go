@@ -0,0 +1,173 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package faastrace continues traces in serverless functions triggered by
+// cloud events.
+//
+// Lambda handlers do not receive an *http.Request, so the trace context
+// sent by the caller has to be recovered from the event payload: the
+// headers of an API Gateway request, the message attributes of an SQS
+// message, or the detail of an EventBridge event. The helpers in this
+// package take those fields as plain Go values, so the package does not
+// depend on any cloud SDK.
+package faastrace // import "go.opentelemetry.io/plugin/faastrace"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/plugin/httptrace"
+)
+
+var (
+	FaaSTriggerKey   = key.New("faas.trigger")
+	FaaSExecutionKey = key.New("faas.execution")
+
+	MessagingSystemKey      = key.New("messaging.system")
+	MessagingDestinationKey = key.New("messaging.destination")
+	MessagingMessageIDKey   = key.New("messaging.message_id")
+
+	HTTPMethodKey = key.New("http.method")
+	HTTPRouteKey  = key.New("http.route")
+
+	CloudEventSourceKey = key.New("cloudevents.event_source")
+	CloudEventTypeKey   = key.New("cloudevents.event_type")
+)
+
+// instrumentationName identifies the spans of this package.
+const instrumentationName = "go.opentelemetry.io/plugin/faastrace"
+
+// Values of FaaSTriggerKey.
+const (
+	TriggerHTTP   = "http"
+	TriggerPubSub = "pubsub"
+)
+
+// Extract returns the Context Tags and SpanContext carried in W3C trace
+// context fields of a string map. Keys are matched case-insensitively,
+// since cloud services do not agree on the case of header names.
+func Extract(carrier map[string]string) ([]core.KeyValue, core.SpanContext) {
+	header := make(http.Header, len(carrier))
+	for k, v := range carrier {
+		header.Set(k, v)
+	}
+	return httptrace.ExtractHeaders(header)
+}
+
+// APIGatewayRequest holds the fields of an API Gateway proxy event used
+// to start a server span.
+type APIGatewayRequest struct {
+	// RequestID is the ID of the Lambda invocation.
+	RequestID  string
+	HTTPMethod string
+	// Resource is the route template that matched, e.g. "/users/{id}".
+	Resource string
+	Headers  map[string]string
+}
+
+// StartAPIGateway starts a server span for an API Gateway request,
+// continuing the trace sent in the request headers.
+func StartAPIGateway(ctx context.Context, name string, req APIGatewayRequest) (context.Context, trace.Span) {
+	return start(ctx, name, req.Headers, trace.SpanKindServer, req,
+		FaaSTriggerKey.String(TriggerHTTP),
+		FaaSExecutionKey.String(req.RequestID),
+		HTTPMethodKey.String(req.HTTPMethod),
+		HTTPRouteKey.String(req.Resource),
+	)
+}
+
+// SQSMessage holds the fields of an SQS message used to start a consumer
+// span.
+type SQSMessage struct {
+	MessageID string
+	// EventSourceARN is the ARN of the queue the message was read from.
+	EventSourceARN string
+	// MessageAttributes holds the string values of the message
+	// attributes.
+	MessageAttributes map[string]string
+}
+
+// StartSQS starts a consumer span for an SQS message, continuing the
+// trace sent in the message attributes.
+func StartSQS(ctx context.Context, name string, msg SQSMessage) (context.Context, trace.Span) {
+	return start(ctx, name, msg.MessageAttributes, trace.SpanKindConsumer, msg,
+		FaaSTriggerKey.String(TriggerPubSub),
+		MessagingSystemKey.String("aws_sqs"),
+		MessagingDestinationKey.String(msg.EventSourceARN),
+		MessagingMessageIDKey.String(msg.MessageID),
+	)
+}
+
+// EventBridgeEvent holds the fields of an EventBridge event used to start
+// a consumer span.
+type EventBridgeEvent struct {
+	ID         string
+	Source     string
+	DetailType string
+	// Detail is the raw JSON detail of the event. Top-level string fields
+	// named after the trace context headers carry the trace context.
+	Detail json.RawMessage
+}
+
+// StartEventBridge starts a consumer span for an EventBridge event,
+// continuing the trace sent in the event detail. A detail that is not a
+// JSON object starts a new trace.
+func StartEventBridge(ctx context.Context, name string, ev EventBridgeEvent) (context.Context, trace.Span) {
+	return start(ctx, name, detailCarrier(ev.Detail), trace.SpanKindConsumer, ev,
+		FaaSTriggerKey.String(TriggerPubSub),
+		MessagingSystemKey.String("aws_eventbridge"),
+		MessagingMessageIDKey.String(ev.ID),
+		CloudEventSourceKey.String(ev.Source),
+		CloudEventTypeKey.String(ev.DetailType),
+	)
+}
+
+func detailCarrier(detail json.RawMessage) map[string]string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(detail, &fields); err != nil {
+		return nil
+	}
+	carrier := make(map[string]string)
+	for k, v := range fields {
+		if s, ok := v.(string); ok {
+			carrier[k] = s
+		}
+	}
+	return carrier
+}
+
+// start starts a span of kind continuing the trace sent in carrier. The
+// attributes the global AttributeGetter extracts from obj are added to
+// attrs.
+func start(ctx context.Context, name string, carrier map[string]string, kind trace.SpanKind, obj interface{}, attrs ...core.KeyValue) (context.Context, trace.Span) {
+	tags, sc := Extract(carrier)
+
+	if len(tags) != 0 {
+		ctx = tag.WithMap(ctx, tag.FromContext(ctx).Apply(tag.MapUpdate{
+			MultiKV: tags,
+		}))
+	}
+
+	attrs = append(attrs, trace.ExtractAttributes(obj)...)
+	opts := []trace.SpanOption{trace.WithAttributes(attrs...), trace.WithSpanKind(kind)}
+	if sc.IsValid() {
+		opts = append(opts, trace.ChildOf(sc))
+	}
+	return trace.GlobalProvider().Tracer(instrumentationName).Start(ctx, name, opts...)
+}
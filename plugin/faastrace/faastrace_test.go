@@ -0,0 +1,157 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package faastrace
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/trace"
+)
+
+const (
+	traceparent = "00-0102030405060708090a0b0c0d0e0f10-1112131415161718-01"
+	traceID     = "0102030405060708090a0b0c0d0e0f10"
+	spanID      = "1112131415161718"
+)
+
+// recordingTracer records the options of the spans it starts.
+type recordingTracer struct {
+	trace.NoopTracer
+	name string
+	opts trace.SpanOptions
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	t.name = name
+	t.opts = trace.SpanOptions{}
+	for _, opt := range opts {
+		opt(&t.opts)
+	}
+	return ctx, trace.NoopSpan{}
+}
+
+func TestExtract(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		carrier map[string]string
+		valid   bool
+	}{
+		{"lower case", map[string]string{"traceparent": traceparent}, true},
+		{"canonical case", map[string]string{"Traceparent": traceparent}, true},
+		{"upper case", map[string]string{"TRACEPARENT": traceparent}, true},
+		{"missing", map[string]string{"content-type": "application/json"}, false},
+		{"malformed", map[string]string{"traceparent": "00-zz-1112131415161718-01"}, false},
+		{"nil", nil, false},
+	} {
+		_, sc := Extract(tt.carrier)
+		if sc.IsValid() != tt.valid {
+			t.Errorf("%s: got valid span context %v, want %v", tt.name, sc.IsValid(), tt.valid)
+			continue
+		}
+		if tt.valid && (sc.TraceIDString() != traceID || sc.SpanIDString() != spanID || !sc.IsSampled()) {
+			t.Errorf("%s: got span context %s/%s, want %s/%s sampled", tt.name,
+				sc.TraceIDString(), sc.SpanIDString(), traceID, spanID)
+		}
+	}
+}
+
+func TestDetailCarrier(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		detail string
+		want   map[string]string
+	}{
+		{"trace context", `{"traceparent": "` + traceparent + `", "order": "42"}`,
+			map[string]string{"traceparent": traceparent, "order": "42"}},
+		{"non-string fields", `{"traceparent": 1, "nested": {"traceparent": "x"}, "list": ["a"]}`,
+			map[string]string{}},
+		{"empty object", `{}`, map[string]string{}},
+		{"array", `["` + traceparent + `"]`, nil},
+		{"string", `"` + traceparent + `"`, nil},
+		{"invalid", `{"traceparent":`, nil},
+		{"empty", ``, nil},
+	} {
+		got := detailCarrier(json.RawMessage(tt.detail))
+		if (got == nil) != (tt.want == nil) || len(got) != len(tt.want) {
+			t.Errorf("%s: got carrier %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("%s: got %s=%q, want %q", tt.name, k, got[k], v)
+			}
+		}
+	}
+}
+
+func TestStart(t *testing.T) {
+	tracer := &recordingTracer{}
+	trace.SetGlobalTracer(tracer)
+	defer trace.SetGlobalTracer(&recordingTracer{})
+
+	attr := func(k core.Key) string {
+		for _, kv := range tracer.opts.Attributes {
+			if kv.Key == k {
+				return kv.Value.Emit()
+			}
+		}
+		return ""
+	}
+
+	StartEventBridge(context.Background(), "order placed", EventBridgeEvent{
+		ID:         "ev-1",
+		Source:     "shop",
+		DetailType: "OrderPlaced",
+		Detail:     json.RawMessage(`{"traceparent": "` + traceparent + `"}`),
+	})
+	if got := tracer.opts.Reference.SpanContext.SpanIDString(); got != spanID {
+		t.Errorf("EventBridge span parent = %q, want %q", got, spanID)
+	}
+	if tracer.opts.SpanKind != trace.SpanKindConsumer {
+		t.Errorf("EventBridge span kind = %v, want consumer", tracer.opts.SpanKind)
+	}
+	if got := attr(CloudEventTypeKey); got != "OrderPlaced" {
+		t.Errorf("cloudevents.event_type = %q, want OrderPlaced", got)
+	}
+
+	StartAPIGateway(context.Background(), "GET /users/{id}", APIGatewayRequest{
+		RequestID:  "req-1",
+		HTTPMethod: "GET",
+		Resource:   "/users/{id}",
+	})
+	if tracer.opts.Reference.SpanContext.IsValid() {
+		t.Error("API Gateway span without trace headers has a parent")
+	}
+	if tracer.opts.SpanKind != trace.SpanKindServer {
+		t.Errorf("API Gateway span kind = %v, want server", tracer.opts.SpanKind)
+	}
+	if got := attr(HTTPRouteKey); got != "/users/{id}" {
+		t.Errorf("http.route = %q, want /users/{id}", got)
+	}
+
+	StartSQS(context.Background(), "process", SQSMessage{
+		MessageID:         "msg-1",
+		MessageAttributes: map[string]string{"Traceparent": traceparent},
+	})
+	if got := tracer.opts.Reference.SpanContext.TraceIDString(); got != traceID {
+		t.Errorf("SQS span trace = %q, want %q", got, traceID)
+	}
+	if got := attr(MessagingMessageIDKey); got != "msg-1" {
+		t.Errorf("messaging.message_id = %q, want msg-1", got)
+	}
+}
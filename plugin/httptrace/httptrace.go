@@ -24,6 +24,7 @@ import (
 	"go.opentelemetry.io/api/core"
 	"go.opentelemetry.io/api/key"
 	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/api/trace"
 )
 
 const (
@@ -38,21 +39,65 @@ var (
 )
 
 // Returns the Attributes, Context Tags, and SpanContext that were encoded by Inject.
-func Extract(req *http.Request) ([]core.KeyValue, []core.KeyValue, core.SpanContext) {
-	tc, err := tracecontext.FromHeaders(req.Header)
-
+// The attributes include those the global AttributeGetter of the trace
+// package and getters extract from req.
+func Extract(req *http.Request, getters ...trace.AttributeGetter) ([]core.KeyValue, []core.KeyValue, core.SpanContext) {
+	tags, sc, err := extractHeaders(req.Header)
 	if err != nil {
 		return nil, nil, core.SpanContext{}
 	}
 
+	attrs := []core.KeyValue{
+		URLKey.String(req.URL.String()),
+		// Etc.
+	}
+	attrs = append(attrs, trace.ExtractAttributes(req, getters...)...)
+
+	return attrs, tags, sc
+}
+
+// HeaderAttribute returns an AttributeGetter setting k to the value of
+// header in an *http.Request, *http.Response or http.Header, when it is
+// present.
+func HeaderAttribute(k core.Key, header string) trace.AttributeGetter {
+	return trace.AttributeGetterFunc(func(obj interface{}) []core.KeyValue {
+		var h http.Header
+		switch obj := obj.(type) {
+		case *http.Request:
+			h = obj.Header
+		case *http.Response:
+			h = obj.Header
+		case http.Header:
+			h = obj
+		}
+		if v := h.Get(header); v != "" {
+			return []core.KeyValue{k.String(v)}
+		}
+		return nil
+	})
+}
+
+// ExtractHeaders returns the Context Tags and SpanContext that were encoded
+// in W3C trace context headers, which may come from a carrier other than
+// an HTTP request.
+func ExtractHeaders(header http.Header) ([]core.KeyValue, core.SpanContext) {
+	tags, sc, _ := extractHeaders(header)
+	return tags, sc
+}
+
+func extractHeaders(header http.Header) ([]core.KeyValue, core.SpanContext, error) {
+	tc, err := tracecontext.FromHeaders(header)
+
+	if err != nil {
+		return nil, core.SpanContext{}, err
+	}
+
 	var sc core.SpanContext
 	sc.SpanID = encoding.Uint64(tc.TraceParent.SpanID[0:8])
 	sc.TraceID.High = encoding.Uint64(tc.TraceParent.TraceID[0:8])
 	sc.TraceID.Low = encoding.Uint64(tc.TraceParent.TraceID[8:16])
-
-	attrs := []core.KeyValue{
-		URLKey.String(req.URL.String()),
-		// Etc.
+	if tc.TraceParent.Flags.Recorded {
+		sc.TraceOptions = core.TraceOptionSampled
 	}
 
 	var tags []core.KeyValue
@@ -66,7 +111,7 @@ func Extract(req *http.Request) ([]core.KeyValue, []core.KeyValue, core.SpanCont
 		tags = append(tags, key.New(ts.Tenant).String(ts.Value))
 	}
 
-	return attrs, tags, sc
+	return tags, sc, nil
 }
 
 type hinjector struct {
@@ -85,7 +130,7 @@ func (h hinjector) Inject(sc core.SpanContext, tags tag.Map) {
 	tc.TraceParent.Version = tracecontext.Version
 	tc.TraceParent.TraceID = tid
 	tc.TraceParent.SpanID = sid
-	tc.TraceParent.Flags.Recorded = true // Note: not implemented.
+	tc.TraceParent.Flags.Recorded = sc.IsSampled()
 
 	tags.Foreach(func(kv core.KeyValue) bool {
 		// TODO: implement MaxHops
@@ -0,0 +1,63 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httptrace
+
+import (
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/api/key"
+)
+
+func TestExtract(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/users", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", "00-0102030405060708090a0b0c0d0e0f10-1112131415161718-01")
+
+	attrs, _, sc := Extract(req)
+	if !sc.IsValid() {
+		t.Fatal("got an invalid span context")
+	}
+	if len(attrs) != 1 || attrs[0].Key != URLKey || attrs[0].Value.String != "http://example.com/users" {
+		t.Errorf("got attributes %v, want the request URL", attrs)
+	}
+
+	req.Header.Del("traceparent")
+	if attrs, tags, sc := Extract(req); attrs != nil || tags != nil || sc.IsValid() {
+		t.Errorf("got %v, %v, %v without trace context headers, want nothing", attrs, tags, sc)
+	}
+}
+
+func TestExtractHeaderAttribute(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/users", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", "00-0102030405060708090a0b0c0d0e0f10-1112131415161718-01")
+	req.Header.Set("X-API-Version", "2")
+	version := key.New("api.version")
+
+	attrs, _, _ := Extract(req, HeaderAttribute(version, "X-API-Version"), HeaderAttribute(key.New("missing"), "X-Missing"))
+	if len(attrs) != 2 || attrs[1].Key != version || attrs[1].Value.String != "2" {
+		t.Errorf("got attributes %v, want the URL and api.version=2", attrs)
+	}
+
+	res := &http.Response{Header: http.Header{"X-Api-Version": {"3"}}}
+	if got := HeaderAttribute(version, "X-API-Version").Attributes(res); len(got) != 1 || got[0].Value.String != "3" {
+		t.Errorf("got response attributes %v, want api.version=3", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httptrace
+
+import (
+	"net/http"
+
+	"github.com/lightstep/tracecontext.go/traceparent"
+
+	"go.opentelemetry.io/api/core"
+)
+
+// TraceResponseHeader is the response header of W3C Trace Context Level 2
+// that tells clients which trace and span handled their request, and
+// whether the server sampled it.
+const TraceResponseHeader = "traceresponse"
+
+// InjectResponse sets the traceresponse header describing the server span
+// sc. It must be called before the response header is written, e.g.,
+//
+//	httptrace.InjectResponse(trace.CurrentSpan(ctx).SpanContext(), w.Header())
+func InjectResponse(sc core.SpanContext, header http.Header) {
+	if !sc.IsValid() {
+		return
+	}
+	var tp traceparent.TraceParent
+	tp.Version = traceparent.Version
+	encoding.PutUint64(tp.TraceID[0:8], sc.TraceID.High)
+	encoding.PutUint64(tp.TraceID[8:16], sc.TraceID.Low)
+	encoding.PutUint64(tp.SpanID[0:8], sc.SpanID)
+	tp.Flags.Recorded = sc.IsSampled()
+	header.Set(TraceResponseHeader, tp.String())
+}
+
+// ExtractResponse returns the server span described by the traceresponse
+// header of res. It returns false if the header is missing or malformed,
+// e.g., because a proxy did not forward it.
+func ExtractResponse(res *http.Response) (core.SpanContext, bool) {
+	v := res.Header.Get(TraceResponseHeader)
+	if v == "" {
+		return core.SpanContext{}, false
+	}
+	tp, err := traceparent.ParseString(v)
+	if err != nil {
+		return core.SpanContext{}, false
+	}
+
+	var sc core.SpanContext
+	sc.SpanID = encoding.Uint64(tp.SpanID[0:8])
+	sc.TraceID.High = encoding.Uint64(tp.TraceID[0:8])
+	sc.TraceID.Low = encoding.Uint64(tp.TraceID[8:16])
+	if tp.Flags.Recorded {
+		sc.TraceOptions = core.TraceOptionSampled
+	}
+	return sc, sc.IsValid()
+}
@@ -0,0 +1,89 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httptrace
+
+import (
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+)
+
+func TestTraceResponseRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		sc     core.SpanContext
+		header string
+	}{
+		{
+			name: "sampled",
+			sc: core.SpanContext{
+				TraceID:      core.TraceID{High: 0x0102030405060708, Low: 0x090a0b0c0d0e0f10},
+				SpanID:       0x1112131415161718,
+				TraceOptions: core.TraceOptionSampled,
+			},
+			header: "00-0102030405060708090a0b0c0d0e0f10-1112131415161718-01",
+		},
+		{
+			name: "not sampled",
+			sc: core.SpanContext{
+				TraceID: core.TraceID{High: 1, Low: 2},
+				SpanID:  3,
+			},
+			header: "00-00000000000000010000000000000002-0000000000000003-00",
+		},
+	} {
+		res := &http.Response{Header: http.Header{}}
+		InjectResponse(tt.sc, res.Header)
+		if got := res.Header.Get(TraceResponseHeader); got != tt.header {
+			t.Errorf("%s: header = %q, want %q", tt.name, got, tt.header)
+		}
+		sc, ok := ExtractResponse(res)
+		if !ok || sc != tt.sc {
+			t.Errorf("%s: extracted %v, %t, want %v, true", tt.name, sc, ok, tt.sc)
+		}
+	}
+}
+
+func TestInjectResponseInvalid(t *testing.T) {
+	header := http.Header{}
+	InjectResponse(core.EmptySpanContext(), header)
+	if _, ok := header[http.CanonicalHeaderKey(TraceResponseHeader)]; ok {
+		t.Errorf("header set for an invalid span context: %v", header)
+	}
+}
+
+func TestExtractResponseMalformed(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		header string
+	}{
+		{"missing", ""},
+		{"garbage", "not a trace context"},
+		{"short trace ID", "00-0102030405060708-1112131415161718-01"},
+		{"non-hex span ID", "00-0102030405060708090a0b0c0d0e0f10-zz12131415161718-01"},
+		{"missing flags", "00-0102030405060708090a0b0c0d0e0f10-1112131415161718"},
+		{"zero trace ID", "00-00000000000000000000000000000000-1112131415161718-01"},
+		{"zero span ID", "00-0102030405060708090a0b0c0d0e0f10-0000000000000000-01"},
+	} {
+		res := &http.Response{Header: http.Header{}}
+		if tt.header != "" {
+			res.Header.Set(TraceResponseHeader, tt.header)
+		}
+		if sc, ok := ExtractResponse(res); ok {
+			t.Errorf("%s: extracted %v, want nothing", tt.name, sc)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package othttp
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/api/unit"
+	"go.opentelemetry.io/plugin/httptrace"
+)
+
+// ServerDuration is the measure of the duration of the HTTP requests
+// served, labeled with their status code.
+var ServerDuration = metric.NewFloat64Measure("http.server.duration",
+	metric.WithDescription("Duration of the HTTP requests served"),
+	metric.WithUnit(unit.Milliseconds),
+	metric.WithKeys(httptrace.HTTPStatus),
+)
+
+// RecordServerDuration records d, the duration of the request served in
+// span, on ServerDuration using the global meter. The span context of a
+// sampled span is recorded as the exemplar of the value, so that a slow
+// bucket of the histogram leads to the trace of a request that fell in
+// it. It is meant to be called with ServerStatus once the response is
+// written.
+func RecordServerDuration(ctx context.Context, span trace.Span, d time.Duration, statusCode int) {
+	measure := metric.GlobalMeter().GetFloat64Measure(ctx, ServerDuration)
+	ms := float64(d) / float64(time.Millisecond)
+	status := httptrace.HTTPStatus.Int(statusCode)
+	if sc := span.SpanContext(); sc.IsSampled() {
+		metric.RecordWithExemplar(ctx, measure, ms, sc, status)
+		return
+	}
+	measure.Record(ctx, ms, status)
+}
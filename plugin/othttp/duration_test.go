@@ -0,0 +1,82 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package othttp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/api/trace"
+)
+
+type exemplarMeter struct {
+	metric.Meter
+	measure *exemplarMeasure
+}
+
+func (m *exemplarMeter) GetFloat64Measure(ctx context.Context, measure *metric.Float64MeasureHandle, labels ...core.KeyValue) metric.Float64Measure {
+	return m.measure
+}
+
+type exemplarMeasure struct {
+	value    float64
+	exemplar core.SpanContext
+	labels   []core.KeyValue
+}
+
+func (m *exemplarMeasure) Record(ctx context.Context, value float64, labels ...core.KeyValue) {
+	m.value, m.labels = value, labels
+}
+
+func (m *exemplarMeasure) RecordExemplar(ctx context.Context, value float64, sc core.SpanContext, labels ...core.KeyValue) {
+	m.value, m.exemplar, m.labels = value, sc, labels
+}
+
+type contextSpan struct {
+	trace.NoopSpan
+	sc core.SpanContext
+}
+
+func (s contextSpan) SpanContext() core.SpanContext {
+	return s.sc
+}
+
+func TestRecordServerDuration(t *testing.T) {
+	sampled := core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 2, TraceOptions: core.TraceOptionSampled}
+	for _, tt := range []struct {
+		name string
+		sc   core.SpanContext
+		want core.SpanContext
+	}{
+		{"sampled", sampled, sampled},
+		{"unsampled", core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 2}, core.SpanContext{}},
+	} {
+		m := &exemplarMeter{measure: &exemplarMeasure{}}
+		metric.SetGlobalMeter(m)
+		RecordServerDuration(context.Background(), contextSpan{sc: tt.sc}, 1500*time.Microsecond, http.StatusOK)
+		metric.SetGlobalMeter(nil)
+
+		if m.measure.value != 1.5 || m.measure.exemplar != tt.want {
+			t.Errorf("%s: recorded %v with exemplar %+v, want 1.5 with %+v", tt.name, m.measure.value, m.measure.exemplar, tt.want)
+		}
+		if len(m.measure.labels) != 1 || m.measure.labels[0].Value.Int64 != http.StatusOK {
+			t.Errorf("%s: got labels %v, want the status code", tt.name, m.measure.labels)
+		}
+	}
+}
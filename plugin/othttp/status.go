@@ -0,0 +1,145 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package othttp sets the status of spans of HTTP servers and clients
+// from the status codes of their responses, and records the duration of
+// served requests with their spans as exemplars.
+package othttp // import "go.opentelemetry.io/plugin/othttp"
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/plugin/httptrace"
+)
+
+// StatusOption configures how an HTTP status code is mapped to a span
+// status by ServerStatus and ClientStatus.
+type StatusOption func(*statusConfig)
+
+type statusConfig struct {
+	// errorClasses holds the status classes (4 for 4xx, 5 for 5xx)
+	// that mark a span as failed.
+	errorClasses map[int]bool
+	// overrides holds individual status codes whose error
+	// classification differs from their class.
+	overrides map[int]bool
+}
+
+// WithErrorClasses replaces the set of status classes that are treated as
+// errors. Classes are given by their leading digit, e.g.
+// WithErrorClasses(5) treats only 5xx responses as errors.
+func WithErrorClasses(classes ...int) StatusOption {
+	return func(c *statusConfig) {
+		c.errorClasses = make(map[int]bool, len(classes))
+		for _, class := range classes {
+			c.errorClasses[class] = true
+		}
+	}
+}
+
+// WithNonErrorStatus treats the given status codes as successful
+// regardless of their class, e.g. WithNonErrorStatus(http.StatusNotFound).
+func WithNonErrorStatus(statusCodes ...int) StatusOption {
+	return func(c *statusConfig) {
+		for _, code := range statusCodes {
+			c.overrides[code] = false
+		}
+	}
+}
+
+// WithErrorStatus treats the given status codes as errors regardless of
+// their class.
+func WithErrorStatus(statusCodes ...int) StatusOption {
+	return func(c *statusConfig) {
+		for _, code := range statusCodes {
+			c.overrides[code] = true
+		}
+	}
+}
+
+// ServerStatus records the response status code on a server span and sets
+// the span status. By default only 5xx responses are errors, since 4xx
+// responses indicate a problem with the request rather than the server.
+func ServerStatus(span trace.Span, statusCode int, opts ...StatusOption) {
+	setStatus(span, statusCode, []int{5}, opts)
+}
+
+// ClientStatus records the response status code on a client span and sets
+// the span status. By default both 4xx and 5xx responses are errors.
+func ClientStatus(span trace.Span, statusCode int, opts ...StatusOption) {
+	setStatus(span, statusCode, []int{4, 5}, opts)
+}
+
+func setStatus(span trace.Span, statusCode int, defaultClasses []int, opts []StatusOption) {
+	c := &statusConfig{
+		overrides: map[int]bool{},
+	}
+	WithErrorClasses(defaultClasses...)(c)
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	isError, ok := c.overrides[statusCode]
+	if !ok {
+		isError = c.errorClasses[statusCode/100]
+	}
+
+	span.SetAttribute(httptrace.HTTPStatus.Int(statusCode))
+	if isError {
+		span.SetStatus(StatusCode(statusCode))
+	} else {
+		span.SetStatus(codes.OK)
+	}
+}
+
+// StatusCode maps an HTTP status code to the closest matching span status
+// code, without regard to whether the status counts as an error.
+func StatusCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499: // Client closed request.
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	switch {
+	case statusCode < 100:
+		return codes.Unknown
+	case statusCode < 400:
+		return codes.OK
+	case statusCode < 500:
+		return codes.InvalidArgument
+	case statusCode < 600:
+		return codes.Internal
+	}
+	return codes.Unknown
+}
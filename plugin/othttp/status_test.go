@@ -0,0 +1,120 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package othttp
+
+import (
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/plugin/httptrace"
+)
+
+type statusSpan struct {
+	trace.NoopSpan
+	status codes.Code
+	attrs  []core.KeyValue
+}
+
+func (s *statusSpan) SetStatus(status codes.Code) {
+	s.status = status
+}
+
+func (s *statusSpan) SetAttribute(attr core.KeyValue) {
+	s.attrs = append(s.attrs, attr)
+}
+
+func TestServerStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		statusCode int
+		opts       []StatusOption
+		want       codes.Code
+	}{
+		{"ok", http.StatusOK, nil, codes.OK},
+		{"redirect", http.StatusFound, nil, codes.OK},
+		{"not found", http.StatusNotFound, nil, codes.OK},
+		{"internal error", http.StatusInternalServerError, nil, codes.Internal},
+		{"unavailable", http.StatusServiceUnavailable, nil, codes.Unavailable},
+		{"4xx class", http.StatusNotFound, []StatusOption{WithErrorClasses(4, 5)}, codes.NotFound},
+		{"no classes", http.StatusInternalServerError, []StatusOption{WithErrorClasses()}, codes.OK},
+		{"error status", http.StatusTooManyRequests, []StatusOption{WithErrorStatus(http.StatusTooManyRequests)}, codes.ResourceExhausted},
+		{"non-error status", http.StatusNotImplemented, []StatusOption{WithNonErrorStatus(http.StatusNotImplemented)}, codes.OK},
+	} {
+		span := &statusSpan{}
+		ServerStatus(span, tt.statusCode, tt.opts...)
+		if span.status != tt.want {
+			t.Errorf("%s: got status %v, want %v", tt.name, span.status, tt.want)
+		}
+		if len(span.attrs) != 1 || span.attrs[0].Key != httptrace.HTTPStatus || span.attrs[0].Value.Int64 != int64(tt.statusCode) {
+			t.Errorf("%s: got attributes %v, want http.status=%d", tt.name, span.attrs, tt.statusCode)
+		}
+	}
+}
+
+func TestClientStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		statusCode int
+		opts       []StatusOption
+		want       codes.Code
+	}{
+		{"ok", http.StatusOK, nil, codes.OK},
+		{"bad request", http.StatusBadRequest, nil, codes.InvalidArgument},
+		{"not found", http.StatusNotFound, nil, codes.NotFound},
+		{"gateway timeout", http.StatusGatewayTimeout, nil, codes.DeadlineExceeded},
+		{"404 allowed", http.StatusNotFound, []StatusOption{WithNonErrorStatus(http.StatusNotFound)}, codes.OK},
+		{"5xx only", http.StatusConflict, []StatusOption{WithErrorClasses(5)}, codes.OK},
+	} {
+		span := &statusSpan{}
+		ClientStatus(span, tt.statusCode, tt.opts...)
+		if span.status != tt.want {
+			t.Errorf("%s: got status %v, want %v", tt.name, span.status, tt.want)
+		}
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	for _, tt := range []struct {
+		statusCode int
+		want       codes.Code
+	}{
+		{0, codes.Unknown},
+		{http.StatusContinue, codes.OK},
+		{http.StatusOK, codes.OK},
+		{http.StatusMovedPermanently, codes.OK},
+		{http.StatusBadRequest, codes.InvalidArgument},
+		{http.StatusUnauthorized, codes.Unauthenticated},
+		{http.StatusForbidden, codes.PermissionDenied},
+		{http.StatusNotFound, codes.NotFound},
+		{http.StatusConflict, codes.AlreadyExists},
+		{http.StatusTooManyRequests, codes.ResourceExhausted},
+		{499, codes.Canceled},
+		{http.StatusTeapot, codes.InvalidArgument},
+		{http.StatusInternalServerError, codes.Internal},
+		{http.StatusNotImplemented, codes.Unimplemented},
+		{http.StatusBadGateway, codes.Internal},
+		{http.StatusServiceUnavailable, codes.Unavailable},
+		{http.StatusGatewayTimeout, codes.DeadlineExceeded},
+		{600, codes.Unknown},
+	} {
+		if got := StatusCode(tt.statusCode); got != tt.want {
+			t.Errorf("StatusCode(%d) = %v, want %v", tt.statusCode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retrytrace records retries as events of the current span, so
+// that retry storms show in traces.
+//
+// Do retries a function with backoff. Code using a retry library records
+// the same events by calling Attempt from the library's retry callback,
+// and Done once it gives up or succeeds.
+package retrytrace // import "go.opentelemetry.io/plugin/retrytrace"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/trace"
+)
+
+// Messages of the events recorded.
+const (
+	AttemptEvent = "retry.attempt"
+	DoneEvent    = "retry.done"
+)
+
+var (
+	AttemptKey = key.New("retry.attempt")
+	DelayKey   = key.New("retry.delay_ms")
+	ErrorKey   = key.New("retry.error")
+	OutcomeKey = key.New("retry.outcome")
+)
+
+// Values of OutcomeKey.
+const (
+	OutcomeSuccess  = "success"
+	OutcomeFailure  = "failure"
+	OutcomeCanceled = "canceled"
+)
+
+// Attempt records that attempt, numbered from 1, failed with err and
+// will be retried after delay.
+func Attempt(ctx context.Context, attempt int, delay time.Duration, err error) {
+	span := trace.CurrentSpan(ctx)
+	if !span.IsRecordingEvents() {
+		return
+	}
+	attrs := []core.KeyValue{
+		AttemptKey.Int(attempt),
+		DelayKey.Int64(int64(delay / time.Millisecond)),
+	}
+	if err != nil {
+		attrs = append(attrs, ErrorKey.String(err.Error()))
+	}
+	span.Event(ctx, AttemptEvent, attrs...)
+}
+
+// Done records the outcome of a retried call after attempts attempts,
+// err being the error of the last one.
+func Done(ctx context.Context, attempts int, err error) {
+	span := trace.CurrentSpan(ctx)
+	if !span.IsRecordingEvents() {
+		return
+	}
+	outcome := OutcomeSuccess
+	switch {
+	case err == nil:
+	case err == context.Canceled || err == context.DeadlineExceeded:
+		outcome = OutcomeCanceled
+	default:
+		outcome = OutcomeFailure
+	}
+	attrs := []core.KeyValue{
+		AttemptKey.Int(attempts),
+		OutcomeKey.String(outcome),
+	}
+	if err != nil {
+		attrs = append(attrs, ErrorKey.String(err.Error()))
+	}
+	span.Event(ctx, DoneEvent, attrs...)
+}
+
+// Backoff returns the delay before the retry following attempt, numbered
+// from 1.
+type Backoff func(attempt int) time.Duration
+
+// Exponential returns a Backoff doubling the delay from base up to max.
+func Exponential(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// Do calls f until it succeeds, maxAttempts calls fail, or ctx is done,
+// waiting as backoff tells between attempts. It records each failed
+// attempt that is retried and the outcome as events of the current span
+// of ctx, and returns the error of the last attempt, or the error of ctx.
+func Do(ctx context.Context, maxAttempts int, backoff Backoff, f func(context.Context) error) error {
+	var err error
+	attempt := 0
+	for {
+		attempt++
+		if err = f(ctx); err == nil || attempt >= maxAttempts {
+			break
+		}
+		delay := backoff(attempt)
+		Attempt(ctx, attempt, delay, err)
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+			continue
+		case <-ctx.Done():
+			t.Stop()
+			err = ctx.Err()
+		}
+		break
+	}
+	Done(ctx, attempt, err)
+	return err
+}
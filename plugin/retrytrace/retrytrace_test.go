@@ -0,0 +1,112 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrytrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/trace"
+)
+
+type event struct {
+	msg   string
+	attrs map[core.Key]core.Value
+}
+
+type eventSpan struct {
+	trace.NoopSpan
+	events []event
+}
+
+func (s *eventSpan) IsRecordingEvents() bool {
+	return true
+}
+
+func (s *eventSpan) Event(ctx context.Context, msg string, attrs ...core.KeyValue) {
+	ev := event{msg: msg, attrs: make(map[core.Key]core.Value)}
+	for _, kv := range attrs {
+		ev.attrs[kv.Key] = kv.Value
+	}
+	s.events = append(s.events, ev)
+}
+
+func TestDo(t *testing.T) {
+	unavailable := errors.New("unavailable")
+	for _, tt := range []struct {
+		name     string
+		fails    int
+		attempts int
+		outcome  string
+	}{
+		{"first attempt", 0, 1, OutcomeSuccess},
+		{"after retries", 2, 3, OutcomeSuccess},
+		{"exhausted", 5, 3, OutcomeFailure},
+	} {
+		span := &eventSpan{}
+		ctx := trace.SetCurrentSpan(context.Background(), span)
+		calls := 0
+		err := Do(ctx, 3, Exponential(time.Microsecond, time.Millisecond), func(context.Context) error {
+			calls++
+			if calls <= tt.fails {
+				return unavailable
+			}
+			return nil
+		})
+		if (err != nil) != (tt.outcome == OutcomeFailure) {
+			t.Errorf("%s: Do() = %v; want outcome %s", tt.name, err, tt.outcome)
+		}
+		if len(span.events) != tt.attempts {
+			t.Fatalf("%s: got %d events; want %d", tt.name, len(span.events), tt.attempts)
+		}
+		for i, ev := range span.events[:tt.attempts-1] {
+			if ev.msg != AttemptEvent || ev.attrs[AttemptKey].Int64 != int64(i+1) || ev.attrs[ErrorKey].String != "unavailable" {
+				t.Errorf("%s: event %d = %+v; want attempt %d failed", tt.name, i, ev, i+1)
+			}
+		}
+		done := span.events[tt.attempts-1]
+		if done.msg != DoneEvent || done.attrs[OutcomeKey].String != tt.outcome || done.attrs[AttemptKey].Int64 != int64(tt.attempts) {
+			t.Errorf("%s: last event = %+v; want %s after %d attempts", tt.name, done, tt.outcome, tt.attempts)
+		}
+	}
+}
+
+func TestDoCanceled(t *testing.T) {
+	span := &eventSpan{}
+	ctx, cancel := context.WithCancel(trace.SetCurrentSpan(context.Background(), span))
+	err := Do(ctx, 10, Exponential(time.Hour, time.Hour), func(context.Context) error {
+		cancel()
+		return errors.New("unavailable")
+	})
+	if err != context.Canceled {
+		t.Errorf("Do() = %v; want %v", err, context.Canceled)
+	}
+	done := span.events[len(span.events)-1]
+	if done.attrs[OutcomeKey].String != OutcomeCanceled {
+		t.Errorf("outcome = %q; want %q", done.attrs[OutcomeKey].String, OutcomeCanceled)
+	}
+}
+
+func TestExponential(t *testing.T) {
+	b := Exponential(10*time.Millisecond, 50*time.Millisecond)
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 40 * time.Millisecond, 4: 50 * time.Millisecond, 10: 50 * time.Millisecond} {
+		if got := b(attempt); got != want {
+			t.Errorf("backoff(%d) = %v; want %v", attempt, got, want)
+		}
+	}
+}
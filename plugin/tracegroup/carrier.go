@@ -0,0 +1,80 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracegroup
+
+import (
+	"context"
+
+	"go.opentelemetry.io/api/trace"
+)
+
+// Carrier is a value sent over a channel along with the context of the
+// sender, so that the receiver can resume the sender's trace:
+//
+//	jobs := make(chan tracegroup.Carrier)
+//	...
+//	// Sender
+//	err := tracegroup.Send(ctx, jobs, job)
+//	...
+//	// Receiver
+//	for c := range jobs {
+//		ctx, span := c.Resume(ctx, "process")
+//		process(ctx, c.Value.(*Job))
+//		span.Finish()
+//	}
+type Carrier struct {
+	// Context is the context of the sender.
+	Context context.Context
+	Value   interface{}
+}
+
+// Wrap returns a Carrier holding v and ctx.
+func Wrap(ctx context.Context, v interface{}) Carrier {
+	return Carrier{Context: ctx, Value: v}
+}
+
+// Send sends v with ctx on ch. It returns the error of ctx if ctx is done
+// before the value is received.
+func Send(ctx context.Context, ch chan<- Carrier, v interface{}) error {
+	select {
+	case ch <- Wrap(ctx, v):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Resume starts a span named name as a child of the span of the sender.
+// The returned context holds the values of the sender's context, such as
+// its span and tags, but is canceled with ctx, the context of the
+// receiver, since the sender may be done by the time the value is
+// processed.
+func (c Carrier) Resume(ctx context.Context, name string, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	if c.Context != nil {
+		ctx = resumedContext{Context: ctx, values: c.Context}
+	}
+	return trace.GlobalProvider().Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// resumedContext takes its values from one context, and its deadline and
+// cancellation from another.
+type resumedContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c resumedContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
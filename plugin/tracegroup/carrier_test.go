@@ -0,0 +1,68 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracegroup
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCarrierResume(t *testing.T) {
+	tr := install()
+	sendCtx, root := tr.Start(context.Background(), "root")
+	sendCtx, cancelSend := context.WithCancel(sendCtx)
+
+	ch := make(chan Carrier, 1)
+	if err := Send(sendCtx, ch, 42); err != nil {
+		t.Fatal(err)
+	}
+	// The sender is done before the value is processed.
+	cancelSend()
+
+	recvCtx, cancelRecv := context.WithCancel(context.Background())
+	defer cancelRecv()
+	c := <-ch
+	ctx, span := c.Resume(recvCtx, "process")
+	if c.Value != 42 {
+		t.Errorf("got value %v, want 42", c.Value)
+	}
+	if s := span.(*testSpan); s.parent != root {
+		t.Errorf("resumed span has parent %v, want root", s.parent)
+	}
+	if ctx.Err() != nil {
+		t.Error("resumed context canceled with the sender's context")
+	}
+	cancelRecv()
+	if ctx.Err() == nil {
+		t.Error("resumed context not canceled with the receiver's context")
+	}
+}
+
+func TestCarrierResumeWithoutContext(t *testing.T) {
+	tr := install()
+	ctx, parent := tr.Start(context.Background(), "receiver")
+	_, span := Carrier{Value: 1}.Resume(ctx, "process")
+	if s := span.(*testSpan); s.parent != parent {
+		t.Errorf("span has parent %v, want the receiver's span", s.parent)
+	}
+}
+
+func TestSendCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Send(ctx, make(chan Carrier), 1); err != context.Canceled {
+		t.Errorf("Send() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracegroup runs concurrent tasks in their own child spans.
+//
+// Goroutines started with the context of the current span all record
+// into that span, so concurrent work cannot be told apart in the trace.
+// The helpers in this package start a child span per task and pass the
+// task a context scoped to it. For pipelines passing values between
+// long-running goroutines over channels, Carrier sends the context of
+// the sender along with each value.
+package tracegroup // import "go.opentelemetry.io/plugin/tracegroup"
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"go.opentelemetry.io/api/trace"
+)
+
+// instrumentationName identifies the spans of this package.
+const instrumentationName = "go.opentelemetry.io/plugin/tracegroup"
+
+// Group is an errgroup.Group that runs each task in a child span of the
+// span in the context it was created with.
+type Group struct {
+	group *errgroup.Group
+	ctx   context.Context
+}
+
+// WithContext returns a new Group and an associated context derived from
+// ctx, like errgroup.WithContext. The context is canceled the first time
+// a task returns an error or Wait returns.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	group, ctx := errgroup.WithContext(ctx)
+	return &Group{
+		group: group,
+		ctx:   ctx,
+	}, ctx
+}
+
+// Go calls f in a new goroutine within a child span named name. The span
+// records the error returned by f, which cancels the group's context.
+func (g *Group) Go(name string, f func(ctx context.Context) error) {
+	g.group.Go(func() error {
+		return trace.GlobalProvider().Tracer(instrumentationName).WithSpan(g.ctx, name, f)
+	})
+}
+
+// Wait blocks until all tasks have returned and returns the first
+// non-nil error, if any.
+func (g *Group) Wait() error {
+	return g.group.Wait()
+}
+
+// Go calls f in a new goroutine within a child span of the span in ctx.
+func Go(ctx context.Context, name string, f func(ctx context.Context)) {
+	go Job(ctx, name, f)()
+}
+
+// Job returns a function that calls f within a child span of the span in
+// ctx. It is meant for worker pools that accept jobs as func(): the span
+// starts when a worker runs the job rather than when it is submitted, so
+// it measures the job and not the time spent queued.
+func Job(ctx context.Context, name string, f func(ctx context.Context)) func() {
+	return func() {
+		ctx, span := trace.GlobalProvider().Tracer(instrumentationName).Start(ctx, name)
+		defer span.Finish()
+		f(ctx)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracegroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/api/trace"
+)
+
+type spanKey struct{}
+
+// testSpan records its name, its parent and how it ended.
+type testSpan struct {
+	trace.NoopSpan
+	name     string
+	parent   *testSpan
+	err      error
+	finished bool
+}
+
+func (s *testSpan) Finish() {
+	s.finished = true
+}
+
+// testTracer starts testSpans, taking the parent from the context.
+type testTracer struct {
+	trace.NoopTracer
+	mu    sync.Mutex
+	spans map[string]*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	parent, _ := ctx.Value(spanKey{}).(*testSpan)
+	s := &testSpan{name: name, parent: parent}
+	t.mu.Lock()
+	t.spans[name] = s
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (t *testTracer) WithSpan(ctx context.Context, name string, body func(context.Context) error) error {
+	ctx, span := t.Start(ctx, name)
+	defer span.Finish()
+	err := body(ctx)
+	span.(*testSpan).err = err
+	return err
+}
+
+// install makes a new testTracer the global tracer. The global tracer
+// cannot be set back to the NoopTracer, as it is an atomic.Value.
+func install() *testTracer {
+	tr := &testTracer{spans: map[string]*testSpan{}}
+	trace.SetGlobalTracer(tr)
+	return tr
+}
+
+func TestGroupPropagatesParent(t *testing.T) {
+	tr := install()
+	ctx, root := tr.Start(context.Background(), "root")
+
+	g, gctx := WithContext(ctx)
+	for _, name := range []string{"a", "b"} {
+		name := name
+		g.Go(name, func(ctx context.Context) error {
+			Job(ctx, name+".child", func(context.Context) {})()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if gctx.Value(spanKey{}) != root {
+		t.Error("group context lost the span of its parent context")
+	}
+
+	for _, name := range []string{"a", "b"} {
+		tr.mu.Lock()
+		s := tr.spans[name]
+		tr.mu.Unlock()
+		if s == nil || s.parent != root {
+			t.Errorf("span %q does not have root as parent", name)
+			continue
+		}
+		if !s.finished {
+			t.Errorf("span %q not finished", name)
+		}
+		if c := tr.spans[name+".child"]; c == nil || c.parent != s {
+			t.Errorf("span %q.child does not have %q as parent", name, name)
+		}
+	}
+}
+
+func TestGroupRecordsError(t *testing.T) {
+	tr := install()
+	errFailed := errors.New("failed")
+	g, ctx := WithContext(context.Background())
+	g.Go("fails", func(context.Context) error { return errFailed })
+	if err := g.Wait(); err != errFailed {
+		t.Errorf("Wait() = %v, want %v", err, errFailed)
+	}
+	if s := tr.spans["fails"]; s == nil || s.err != errFailed {
+		t.Errorf("span did not record the error")
+	}
+	if ctx.Err() == nil {
+		t.Error("group context not canceled after an error")
+	}
+}
+
+func TestGo(t *testing.T) {
+	tr := install()
+	ctx, root := tr.Start(context.Background(), "root")
+	done := make(chan *testSpan)
+	Go(ctx, "task", func(ctx context.Context) {
+		done <- ctx.Value(spanKey{}).(*testSpan)
+	})
+	s := <-done
+	if s.name != "task" || s.parent != root {
+		t.Errorf("task ran in span %q with parent %v, want task with root", s.name, s.parent)
+	}
+}
+
+func TestJobStartsSpanWhenRun(t *testing.T) {
+	tr := install()
+	ctx, root := tr.Start(context.Background(), "root")
+	job := Job(ctx, "job", func(ctx context.Context) {
+		if s := ctx.Value(spanKey{}).(*testSpan); s.parent != root {
+			t.Errorf("job span has parent %v, want root", s.parent)
+		}
+	})
+	if _, ok := tr.spans["job"]; ok {
+		t.Fatal("span started before the job ran")
+	}
+	job()
+	if s := tr.spans["job"]; s == nil || !s.finished {
+		t.Error("job span not finished after the job ran")
+	}
+}
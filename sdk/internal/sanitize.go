@@ -15,14 +15,20 @@
 package internal
 
 import (
+	"sort"
 	"strings"
-	"unicode"
+
+	"go.opentelemetry.io/api/core"
 )
 
 const labelKeySizeLimit = 100
 
 // Sanitize returns a string that is trunacated to 100 characters if it's too
 // long, and replaces non-alphanumeric characters to underscores.
+//
+// The result is a valid Prometheus label name: only ASCII letters and
+// digits are kept, and names that would start with a digit, or with the
+// "__" prefix reserved by Prometheus, get a "key" prefix.
 func Sanitize(s string) string {
 	if len(s) == 0 {
 		return s
@@ -31,7 +37,7 @@ func Sanitize(s string) string {
 		s = s[:labelKeySizeLimit]
 	}
 	s = strings.Map(sanitizeRune, s)
-	if unicode.IsDigit(rune(s[0])) {
+	if s[0] >= '0' && s[0] <= '9' {
 		s = "key_" + s
 	}
 	if s[0] == '_' {
@@ -40,11 +46,74 @@ func Sanitize(s string) string {
 	return s
 }
 
-// converts anything that is not a letter or digit to an underscore
+// converts anything that is not an ASCII letter or digit to an underscore
 func sanitizeRune(r rune) rune {
-	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+	if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
 		return r
 	}
 	// Everything else turns into an underscore
 	return '_'
 }
+
+// datadogTagKeySizeLimit leaves room for the ":value" part of a tag, which
+// Datadog limits to 200 characters in total.
+const datadogTagKeySizeLimit = 100
+
+// DatadogTagKey returns a valid Datadog tag key for key. Tag keys are
+// lowercased, characters outside [a-z0-9_./-] become underscores, keys
+// that do not start with a letter get a "key_" prefix and keys are
+// truncated to 100 characters.
+func DatadogTagKey(key string) string {
+	s := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '.', r == '/', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '_'
+	}, key)
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		s = "key_" + s
+	}
+	if len(s) > datadogTagKeySizeLimit {
+		s = s[:datadogTagKeySizeLimit]
+	}
+	return s
+}
+
+// SanitizeLabels returns labels with their keys passed through sanitize,
+// e.g., Sanitize for Prometheus.
+//
+// When several keys sanitize to the same key, e.g., "http.method" and
+// "http_method" for Prometheus, their values are joined with ";" into a
+// single label, ordered by the original keys. No value is dropped, and
+// the result does not depend on the order of labels. Labels are returned
+// sorted by sanitized key.
+func SanitizeLabels(labels []core.KeyValue, sanitize func(string) string) []core.KeyValue {
+	sorted := make([]core.KeyValue, len(labels))
+	copy(sorted, labels)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Key.Variable.Name < sorted[j].Key.Variable.Name
+	})
+
+	index := make(map[string]int, len(sorted))
+	out := make([]core.KeyValue, 0, len(sorted))
+	for _, kv := range sorted {
+		name := sanitize(kv.Key.Variable.Name)
+		if i, ok := index[name]; ok {
+			out[i].Value = core.Value{
+				Type:   core.STRING,
+				String: out[i].Value.Emit() + ";" + kv.Value.Emit(),
+			}
+			continue
+		}
+		index[name] = len(out)
+		kv.Key.Variable.Name = name
+		out = append(out, kv)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Key.Variable.Name < out[j].Key.Variable.Name
+	})
+	return out
+}
@@ -17,6 +17,9 @@ package internal
 import (
 	"strings"
 	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
 )
 
 func TestSanitize(t *testing.T) {
@@ -50,6 +53,16 @@ func TestSanitize(t *testing.T) {
 			input: "/0123456789",
 			want:  "key_0123456789",
 		},
+		{
+			name:  "replace non-ASCII letters",
+			input: "ключ",
+			want:  "key____",
+		},
+		{
+			name:  "reserved prefix",
+			input: "__name__",
+			want:  "key__name__",
+		},
 		{
 			name:  "valid input",
 			input: "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz_0123456789",
@@ -65,3 +78,45 @@ func TestSanitize(t *testing.T) {
 		})
 	}
 }
+
+func TestDatadogTagKey(t *testing.T) {
+	for _, tt := range []struct {
+		input, want string
+	}{
+		{"HTTP.Method", "http.method"},
+		{"env:prod", "env_prod"},
+		{"a/b-c", "a/b-c"},
+		{"_private", "key__private"},
+		{"9lives", "key_9lives"},
+		{strings.Repeat("a", 201), strings.Repeat("a", 100)},
+	} {
+		if got := DatadogTagKey(tt.input); got != tt.want {
+			t.Errorf("DatadogTagKey(%q) = %q; want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeLabelsCollision(t *testing.T) {
+	labels := []core.KeyValue{
+		key.New("http_method").String("b"),
+		key.New("status").Int64(200),
+		key.New("http.method").String("a"),
+	}
+	got := SanitizeLabels(labels, Sanitize)
+
+	want := map[string]string{
+		"http_method": "a;b",
+		"status":      "200",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d labels %v; want %d", len(got), got, len(want))
+	}
+	for _, kv := range got {
+		if w, ok := want[kv.Key.Variable.Name]; !ok || kv.Value.Emit() != w {
+			t.Errorf("label %s=%s; want %q", kv.Key.Variable.Name, kv.Value.Emit(), w)
+		}
+	}
+	if labels[2].Key.Variable.Name != "http.method" {
+		t.Error("SanitizeLabels modified its input")
+	}
+}
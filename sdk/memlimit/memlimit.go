@@ -0,0 +1,144 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memlimit holds the memory budget shared by the trace queues,
+// the metric aggregators, and the streaming buffer of the process.
+//
+// Components reserve the approximate number of bytes of the data they
+// hold, and release them once the data is exported or dropped. Under a
+// budget set with SetBudget, reservations start failing as usage grows,
+// lowest-value data first: span events once usage exceeds 80% of the
+// budget, unsampled spans once it exceeds 90%, and all data once it
+// would exceed the budget. Components shed the data whose reservation
+// fails.
+package memlimit // import "go.opentelemetry.io/sdk/memlimit"
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Priority ranks data by its value, lowest first.
+type Priority int
+
+const (
+	// Event is the priority of span events.
+	Event Priority = iota
+	// UnsampledSpan is the priority of spans recorded but not sampled.
+	UnsampledSpan
+	// SampledSpan is the priority of sampled spans and of the streaming
+	// events that make them up.
+	SampledSpan
+	// Metric is the priority of metric aggregators.
+	Metric
+
+	numPriorities
+)
+
+// shares are the percentages of the budget that data of each priority
+// may fill.
+var shares = [numPriorities]int64{
+	Event:         80,
+	UnsampledSpan: 90,
+	SampledSpan:   100,
+	Metric:        100,
+}
+
+var (
+	budget int64 // access atomically
+	used   int64 // access atomically
+	shed   [numPriorities]uint64
+)
+
+// SetBudget sets the memory budget, in bytes. A budget of zero, the
+// default, disables shedding; usage is tracked nonetheless.
+func SetBudget(bytes int64) {
+	if bytes < 0 {
+		bytes = 0
+	}
+	atomic.StoreInt64(&budget, bytes)
+}
+
+// Budget returns the memory budget, in bytes.
+func Budget() int64 {
+	return atomic.LoadInt64(&budget)
+}
+
+// Reserve reserves bytes for data of priority p. It returns false, and
+// reserves nothing, if the data has to be shed.
+func Reserve(p Priority, bytes int64) bool {
+	b := atomic.LoadInt64(&budget)
+	if b == 0 {
+		atomic.AddInt64(&used, bytes)
+		return true
+	}
+	limit := b
+	if p >= 0 && p < numPriorities {
+		limit = shareOf(b, shares[p])
+	}
+	for {
+		u := atomic.LoadInt64(&used)
+		if u+bytes > limit {
+			if p >= 0 && p < numPriorities {
+				atomic.AddUint64(&shed[p], 1)
+			}
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&used, u, u+bytes) {
+			return true
+		}
+	}
+}
+
+// shareOf returns percent of b, rounded down, without overflowing for
+// huge budgets.
+func shareOf(b, percent int64) int64 {
+	if b > math.MaxInt64/100 {
+		return b / 100 * percent
+	}
+	return b * percent / 100
+}
+
+// Release releases bytes reserved with Reserve.
+func Release(bytes int64) {
+	atomic.AddInt64(&used, -bytes)
+}
+
+// Used returns the number of bytes reserved.
+func Used() int64 {
+	return atomic.LoadInt64(&used)
+}
+
+// Shed returns the number of failed reservations of priority p.
+func Shed(p Priority) uint64 {
+	if p < 0 || p >= numPriorities {
+		return 0
+	}
+	return atomic.LoadUint64(&shed[p])
+}
+
+// String returns the name of p, e.g., "unsampled_span".
+func (p Priority) String() string {
+	switch p {
+	case Event:
+		return "event"
+	case UnsampledSpan:
+		return "unsampled_span"
+	case SampledSpan:
+		return "sampled_span"
+	case Metric:
+		return "metric"
+	}
+	return "unknown"
+}
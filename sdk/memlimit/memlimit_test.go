@@ -0,0 +1,77 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memlimit
+
+import (
+	"testing"
+)
+
+func TestReserveSheds(t *testing.T) {
+	defer SetBudget(0)
+	SetBudget(1000)
+
+	for _, tt := range []struct {
+		p     Priority
+		bytes int64
+		want  bool
+	}{
+		{SampledSpan, 700, true},
+		{Event, 100, true},
+		// Usage would exceed 80%: events are shed first.
+		{Event, 100, false},
+		{UnsampledSpan, 100, true},
+		// Usage would exceed 90%: unsampled spans are shed next.
+		{UnsampledSpan, 50, false},
+		{SampledSpan, 100, true},
+		{Metric, 1, false},
+	} {
+		before := Shed(tt.p)
+		if got := Reserve(tt.p, tt.bytes); got != tt.want {
+			t.Errorf("Reserve(%v, %d) at %d bytes = %v, want %v", tt.p, tt.bytes, Used(), got, tt.want)
+		}
+		if shed := Shed(tt.p) - before; (shed == 1) == tt.want {
+			t.Errorf("Reserve(%v, %d) counted %d shed", tt.p, tt.bytes, shed)
+		}
+	}
+
+	Release(Used())
+	if !Reserve(Event, 100) {
+		t.Error("Reserve failed after release")
+	}
+	Release(100)
+}
+
+func TestReserveWithoutBudget(t *testing.T) {
+	if !Reserve(Event, 1<<40) {
+		t.Error("Reserve failed without budget")
+	}
+	if got := Used(); got != 1<<40 {
+		t.Errorf("Used() = %d, want %d", got, 1<<40)
+	}
+	Release(1 << 40)
+}
+
+func TestReserveSmallBudget(t *testing.T) {
+	defer SetBudget(0)
+	// 80% of 50 bytes is 40, not 0.
+	SetBudget(50)
+	if !Reserve(Event, 40) {
+		t.Error("Reserve(Event, 40) failed under a budget of 50 bytes")
+	}
+	if Reserve(Event, 1) {
+		t.Error("Reserve(Event, 1) succeeded over 80% of the budget")
+	}
+	Release(40)
+}
@@ -0,0 +1,270 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package distinct estimates the number of distinct values recorded for
+// an instrument, e.g., unique users per endpoint, with HyperLogLog
+// sketches. A sketch takes 2^precision bytes whatever the number of
+// values, and never holds the values themselves.
+package distinct // import "go.opentelemetry.io/sdk/metric/aggregator/distinct"
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/sdk/memlimit"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+const (
+	// MinPrecision and MaxPrecision bound the precision of sketches.
+	MinPrecision = 4
+	MaxPrecision = 16
+
+	// DefaultPrecision is the precision of sketches made with a precision
+	// of zero. Sketches of this precision take 4KiB and have a standard
+	// error of about 1.6%.
+	DefaultPrecision = 12
+)
+
+// ErrPrecisionMismatch is returned when merging estimates of different
+// precisions.
+var ErrPrecisionMismatch = errors.New("distinct: merging estimates of different precisions")
+
+// Estimate is the state of a Sketch at a checkpoint.
+type Estimate struct {
+	Precision uint8
+	Registers []uint8
+}
+
+// Count returns the estimated number of distinct values.
+func (e Estimate) Count() uint64 {
+	m := float64(len(e.Registers))
+	if m == 0 {
+		return 0
+	}
+	var sum float64
+	zeros := 0
+	for _, r := range e.Registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := alpha(len(e.Registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Linear counting is more accurate for small cardinalities.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Merge adds the values counted by other to e, e.g., to count the
+// distinct values of several periods or processes.
+func (e *Estimate) Merge(other Estimate) error {
+	if e.Registers == nil {
+		e.Precision = other.Precision
+		e.Registers = append([]uint8(nil), other.Registers...)
+		return nil
+	}
+	if e.Precision != other.Precision {
+		return ErrPrecisionMismatch
+	}
+	for i, r := range other.Registers {
+		if r > e.Registers[i] {
+			e.Registers[i] = r
+		}
+	}
+	return nil
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Sketch counts distinct values. It is safe for concurrent use.
+type Sketch struct {
+	precision uint8
+
+	mu        sync.Mutex
+	registers []uint8
+}
+
+// New returns a Sketch of the given precision, clamped between
+// MinPrecision and MaxPrecision, or DefaultPrecision if it is zero.
+func New(precision uint8) *Sketch {
+	switch {
+	case precision == 0:
+		precision = DefaultPrecision
+	case precision < MinPrecision:
+		precision = MinPrecision
+	case precision > MaxPrecision:
+		precision = MaxPrecision
+	}
+	return &Sketch{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+// Update adds value to the sketch. Values of other types are added by
+// their core.Value.Emit representation.
+func (s *Sketch) Update(value string) {
+	h := hash(value)
+	p := s.precision
+	i := h >> (64 - p)
+	rank := uint8(bits.LeadingZeros64(h<<p|1<<(p-1))) + 1
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rank > s.registers[i] {
+		s.registers[i] = rank
+	}
+}
+
+// Checkpoint returns the estimate of the values added since the previous
+// checkpoint, and resets the sketch.
+func (s *Sketch) Checkpoint() Estimate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := Estimate{Precision: s.precision, Registers: s.registers}
+	s.registers = make([]uint8, len(s.registers))
+	return e
+}
+
+// hash returns a 64-bit hash of value. FNV-1a is finalized with the
+// mixer of SplitMix64, since HyperLogLog needs all bits well distributed.
+func hash(value string) uint64 {
+	f := fnv.New64a()
+	f.Write([]byte(value))
+	h := f.Sum64()
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// Dimension counts the distinct values recorded for an instrument per
+// label set, e.g., users per endpoint. It implements push.Collector,
+// reporting the count of each label set as an INT64 value.
+type Dimension struct {
+	handle    *metric.Handle
+	precision uint8
+
+	mu       sync.Mutex
+	sketches map[string]*labeledSketch
+
+	dropped uint64 // access atomically
+}
+
+type labeledSketch struct {
+	labels []core.KeyValue
+	sketch *Sketch
+}
+
+var _ push.Collector = (*Dimension)(nil)
+
+// NewDimension returns a Dimension for the instrument of handle whose
+// sketches have the given precision, as for New.
+func NewDimension(handle *metric.Handle, precision uint8) *Dimension {
+	return &Dimension{
+		handle:    handle,
+		precision: New(precision).precision,
+		sketches:  make(map[string]*labeledSketch),
+	}
+}
+
+// Update adds value to the sketch of labels. The value is dropped if the
+// memory budget of the memlimit package does not allow for the sketch
+// of a new label set.
+func (d *Dimension) Update(value string, labels ...core.KeyValue) {
+	key := labelsKey(labels)
+	d.mu.Lock()
+	ls, ok := d.sketches[key]
+	if !ok {
+		if !memlimit.Reserve(memlimit.Metric, sketchOverhead+1<<d.precision) {
+			d.mu.Unlock()
+			atomic.AddUint64(&d.dropped, 1)
+			return
+		}
+		ls = &labeledSketch{
+			labels: append([]core.KeyValue(nil), labels...),
+			sketch: New(d.precision),
+		}
+		d.sketches[key] = ls
+	}
+	d.mu.Unlock()
+	ls.sketch.Update(value)
+}
+
+// Dropped returns the number of values dropped because of the memory
+// budget.
+func (d *Dimension) Dropped() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+// Collect implements push.Collector. It returns the estimated number of
+// distinct values of every label set updated since the previous
+// collection, and releases the memory of their sketches.
+func (d *Dimension) Collect(ctx context.Context) []push.Record {
+	d.mu.Lock()
+	sketches := d.sketches
+	d.sketches = make(map[string]*labeledSketch)
+	d.mu.Unlock()
+
+	records := make([]push.Record, 0, len(sketches))
+	for _, ls := range sketches {
+		records = append(records, push.Record{
+			Handle: d.handle,
+			Labels: ls.labels,
+			Value: core.Value{
+				Type:  core.INT64,
+				Int64: int64(ls.sketch.Checkpoint().Count()),
+			},
+		})
+		memlimit.Release(sketchOverhead + 1<<d.precision)
+	}
+	return records
+}
+
+// sketchOverhead is the approximate size of a Sketch, in bytes, without
+// its registers.
+const sketchOverhead = 96
+
+// labelsKey returns a key identifying labels whatever their order.
+func labelsKey(labels []core.KeyValue) string {
+	kvs := make([]string, len(labels))
+	for i, kv := range labels {
+		kvs[i] = kv.Key.Variable.Name + "=" + kv.Value.Emit()
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\x00")
+}
@@ -0,0 +1,88 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distinct
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"testing"
+
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/metric"
+)
+
+func TestSketchCount(t *testing.T) {
+	for _, n := range []int{0, 10, 1000, 100000} {
+		s := New(0)
+		for i := 0; i < n; i++ {
+			// Every value is added twice.
+			s.Update("user-" + strconv.Itoa(i))
+			s.Update("user-" + strconv.Itoa(i))
+		}
+		got := float64(s.Checkpoint().Count())
+		if math.Abs(got-float64(n)) > 0.05*float64(n) {
+			t.Errorf("%d distinct values: estimated %v", n, got)
+		}
+		if c := s.Checkpoint().Count(); c != 0 {
+			t.Errorf("%d distinct values: estimated %d after Checkpoint, want 0", n, c)
+		}
+	}
+}
+
+func TestEstimateMerge(t *testing.T) {
+	a, b := New(10), New(10)
+	for i := 0; i < 2000; i++ {
+		a.Update(strconv.Itoa(i))
+		b.Update(strconv.Itoa(i + 1000))
+	}
+	var e Estimate
+	if err := e.Merge(a.Checkpoint()); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Merge(b.Checkpoint()); err != nil {
+		t.Fatal(err)
+	}
+	if got := float64(e.Count()); math.Abs(got-3000) > 0.1*3000 {
+		t.Errorf("merged estimate = %v, want about 3000", got)
+	}
+	if err := e.Merge(New(11).Checkpoint()); err != ErrPrecisionMismatch {
+		t.Errorf("Merge() with another precision = %v, want %v", err, ErrPrecisionMismatch)
+	}
+}
+
+func TestDimension(t *testing.T) {
+	users := metric.NewFloat64Gauge("distinct.test.users")
+	endpoint := key.New("endpoint")
+	d := NewDimension(&users.Handle, 0)
+	for i := 0; i < 100; i++ {
+		d.Update(strconv.Itoa(i), endpoint.String("/a"))
+		d.Update(strconv.Itoa(i%10), endpoint.String("/b"))
+	}
+
+	got := map[string]int64{}
+	for _, r := range d.Collect(context.Background()) {
+		if r.Handle != &users.Handle {
+			t.Errorf("got record of %v, want %v", r.Handle.Variable.Name, users.Handle.Variable.Name)
+		}
+		got[r.Labels[0].Value.Emit()] = r.Value.Int64
+	}
+	if len(got) != 2 || got["/a"] < 95 || got["/a"] > 105 || got["/b"] != 10 {
+		t.Errorf("got counts %v, want about 100 for /a and 10 for /b", got)
+	}
+	if records := d.Collect(context.Background()); len(records) != 0 {
+		t.Errorf("got %d records after Collect, want none", len(records))
+	}
+}
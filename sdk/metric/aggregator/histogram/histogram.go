@@ -0,0 +1,240 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package histogram aggregates the values of measure instruments into
+// distributions over explicit bucket boundaries.
+package histogram // import "go.opentelemetry.io/sdk/metric/aggregator/histogram"
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/sdk/memlimit"
+)
+
+// DefaultBoundaries are the default upper bounds of the buckets, suited
+// to latencies in milliseconds.
+var DefaultBoundaries = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Distribution is the state of a Histogram at a checkpoint.
+type Distribution struct {
+	// Boundaries are the upper bounds of the buckets, in increasing
+	// order. A value equal to a bound falls in the bucket it bounds.
+	Boundaries []float64
+
+	// Counts holds the number of values in each bucket, not cumulated,
+	// followed by the number of values above the last bound.
+	Counts []uint64
+
+	Sum   float64
+	Count uint64
+
+	// Exemplars holds the last exemplar recorded in each bucket since
+	// the previous checkpoint, indexed like Counts. It is nil if no
+	// exemplar was recorded.
+	Exemplars []Exemplar
+}
+
+// Exemplar is a value recorded with the span context of the operation it
+// measures, which links a bucket of the distribution to a trace. The
+// exemplar of a bucket without one is zero.
+type Exemplar struct {
+	Value       float64
+	SpanContext core.SpanContext
+	Time        time.Time
+}
+
+// Histogram aggregates values into buckets. It is safe for concurrent
+// use.
+type Histogram struct {
+	bounds []float64
+
+	mu    sync.Mutex
+	state Distribution
+
+	// shed is set for the no-op histograms Selector.New returns when
+	// the memory budget is exhausted.
+	shed bool
+	// reserved is the number of bytes reserved with the memlimit
+	// package, returned by Release. It is protected by mu.
+	reserved int64
+	// hasExemplars is set once the memory of the exemplars of the
+	// histogram is accounted for. It is protected by mu.
+	hasExemplars bool
+}
+
+// New returns a Histogram with the given bucket boundaries, which it
+// sorts. It uses DefaultBoundaries if none are given.
+func New(boundaries ...float64) *Histogram {
+	if len(boundaries) == 0 {
+		boundaries = DefaultBoundaries
+	}
+	bounds := append([]float64(nil), boundaries...)
+	sort.Float64s(bounds)
+	h := &Histogram{bounds: bounds}
+	h.reset()
+	return h
+}
+
+// Update adds value to the histogram.
+func (h *Histogram) Update(value float64) {
+	if h.shed {
+		return
+	}
+	bucket := sort.SearchFloat64s(h.bounds, value)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.state.Counts[bucket]++
+	h.state.Sum += value
+	h.state.Count++
+}
+
+// UpdateWithExemplar adds value to the histogram like Update, and keeps
+// sc as the exemplar of its bucket until the next checkpoint. The
+// exemplar is dropped if the memlimit budget does not allow for the
+// exemplars of the histogram.
+func (h *Histogram) UpdateWithExemplar(value float64, sc core.SpanContext) {
+	if h.shed {
+		return
+	}
+	now := time.Now()
+	bucket := sort.SearchFloat64s(h.bounds, value)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.state.Counts[bucket]++
+	h.state.Sum += value
+	h.state.Count++
+	if h.state.Exemplars == nil {
+		if !h.hasExemplars && !h.reserveExemplars() {
+			return
+		}
+		h.state.Exemplars = make([]Exemplar, len(h.state.Counts))
+	}
+	h.state.Exemplars[bucket] = Exemplar{Value: value, SpanContext: sc, Time: now}
+}
+
+// reserveExemplars reserves the memory of the exemplars of h once. It
+// returns false if the budget does not allow for them.
+func (h *Histogram) reserveExemplars() bool {
+	if h.reserved == 0 {
+		// h was not created by Selector.New, and is not accounted for.
+		h.hasExemplars = true
+		return true
+	}
+	n := exemplarSize * int64(len(h.bounds)+1)
+	if !memlimit.Reserve(memlimit.Metric, n) {
+		return false
+	}
+	h.reserved += n
+	h.hasExemplars = true
+	return true
+}
+
+// Checkpoint returns the distribution of the values added since the
+// previous checkpoint, and resets the histogram.
+func (h *Histogram) Checkpoint() Distribution {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	d := h.state
+	h.reset()
+	return d
+}
+
+// Release returns the memory reserved for h by Selector.New, and for its
+// exemplars, to the memlimit budget. It should be called once h is discarded; later calls
+// do nothing.
+func (h *Histogram) Release() {
+	h.mu.Lock()
+	n := h.reserved
+	h.reserved = 0
+	h.hasExemplars = false
+	h.mu.Unlock()
+	if n != 0 {
+		memlimit.Release(n)
+	}
+}
+
+// Shed returns whether h is a no-op histogram returned by Selector.New
+// because the memory budget was exhausted.
+func (h *Histogram) Shed() bool {
+	return h.shed
+}
+
+func (h *Histogram) reset() {
+	h.state = Distribution{
+		Boundaries: h.bounds,
+		Counts:     make([]uint64, len(h.bounds)+1),
+	}
+}
+
+// Selector chooses the bucket boundaries of the histograms of each
+// instrument.
+type Selector struct {
+	defaults []float64
+
+	mu     sync.RWMutex
+	byName map[string][]float64
+}
+
+// NewSelector returns a Selector giving histograms defaults as their
+// boundaries unless Set was called for their instrument. It uses
+// DefaultBoundaries if no defaults are given.
+func NewSelector(defaults ...float64) *Selector {
+	if len(defaults) == 0 {
+		defaults = DefaultBoundaries
+	}
+	return &Selector{
+		defaults: append([]float64(nil), defaults...),
+		byName:   make(map[string][]float64),
+	}
+}
+
+// Set sets the boundaries of the histograms of the instrument named name.
+func (s *Selector) Set(name string, boundaries ...float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byName[name] = append([]float64(nil), boundaries...)
+}
+
+// New returns a Histogram for the instrument of handle. Its memory is
+// reserved with the memlimit package until Release is called. If the
+// budget does not allow for another histogram, New returns a no-op
+// histogram that sheds the values meant for it, and whose checkpoints
+// are empty.
+func (s *Selector) New(handle *metric.Handle) *Histogram {
+	s.mu.RLock()
+	bounds, ok := s.byName[handle.Variable.Name]
+	s.mu.RUnlock()
+	if !ok {
+		bounds = s.defaults
+	}
+	h := New(bounds...)
+	n := histogramOverhead + 16*int64(len(bounds))
+	if !memlimit.Reserve(memlimit.Metric, n) {
+		h.shed = true
+		return h
+	}
+	h.reserved = n
+	return h
+}
+
+// histogramOverhead is the approximate size of a Histogram, in bytes,
+// without its buckets.
+const histogramOverhead = 128
+
+// exemplarSize is the approximate size of an Exemplar, in bytes.
+const exemplarSize = 56
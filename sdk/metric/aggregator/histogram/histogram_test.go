@@ -0,0 +1,170 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/sdk/memlimit"
+)
+
+func TestHistogramCheckpoint(t *testing.T) {
+	h := New(10, 1, 5)
+	for _, v := range []float64{0.5, 1, 3, 7, 10, 11, 100} {
+		h.Update(v)
+	}
+	want := Distribution{
+		Boundaries: []float64{1, 5, 10},
+		Counts:     []uint64{2, 1, 2, 2},
+		Sum:        132.5,
+		Count:      7,
+	}
+	if diff := cmp.Diff(want, h.Checkpoint()); diff != "" {
+		t.Errorf("Checkpoint() -want +got:\n%s", diff)
+	}
+
+	want = Distribution{Boundaries: []float64{1, 5, 10}, Counts: []uint64{0, 0, 0, 0}}
+	if diff := cmp.Diff(want, h.Checkpoint()); diff != "" {
+		t.Errorf("Checkpoint() after a checkpoint -want +got:\n%s", diff)
+	}
+}
+
+func TestHistogramExemplars(t *testing.T) {
+	h := New(1, 10)
+	first := core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 1}
+	last := core.SpanContext{TraceID: core.TraceID{Low: 2}, SpanID: 2}
+	h.UpdateWithExemplar(5, first)
+	h.Update(6)
+	h.UpdateWithExemplar(7, last)
+
+	d := h.Checkpoint()
+	if d.Count != 3 || d.Sum != 18 {
+		t.Errorf("Count, Sum = %d, %v, want 3, 18", d.Count, d.Sum)
+	}
+	if len(d.Exemplars) != len(d.Counts) {
+		t.Fatalf("got %d exemplars, want one per bucket", len(d.Exemplars))
+	}
+	if x := d.Exemplars[1]; x.Value != 7 || x.SpanContext != last || x.Time.IsZero() {
+		t.Errorf("exemplar of the bucket = %+v, want the last one", x)
+	}
+	if x := d.Exemplars[0]; x != (Exemplar{}) {
+		t.Errorf("exemplar of an empty bucket = %+v, want zero", x)
+	}
+	if d := h.Checkpoint(); d.Exemplars != nil {
+		t.Errorf("exemplars after a checkpoint = %v, want nil", d.Exemplars)
+	}
+}
+
+func TestHistogramExemplarsMemoryBudget(t *testing.T) {
+	defer memlimit.SetBudget(0)
+	s := NewSelector(1)
+	handle := &metric.NewFloat64Measure("rpc.exemplar.latency").Handle
+	before := memlimit.Used()
+	h := s.New(handle)
+	reserved := memlimit.Used()
+
+	memlimit.SetBudget(reserved + 1)
+	h.UpdateWithExemplar(1, core.SpanContext{SpanID: 1})
+	if d := h.Checkpoint(); d.Count != 1 || d.Exemplars != nil {
+		t.Errorf("got %d values and exemplars %v over the budget, want the value only", d.Count, d.Exemplars)
+	}
+
+	memlimit.SetBudget(0)
+	h.UpdateWithExemplar(1, core.SpanContext{SpanID: 1})
+	if d := h.Checkpoint(); d.Exemplars == nil {
+		t.Error("exemplar dropped within the budget")
+	}
+	if memlimit.Used() <= reserved {
+		t.Error("exemplars not reserved with the memlimit package")
+	}
+	h.Release()
+	if got := memlimit.Used(); got != before {
+		t.Errorf("Used() = %d after Release, want %d", got, before)
+	}
+}
+
+func TestHistogramConcurrentUpdates(t *testing.T) {
+	h := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				h.Update(float64(j))
+			}
+		}()
+	}
+	wg.Wait()
+	if got := h.Checkpoint().Count; got != 800 {
+		t.Errorf("Count = %d, want 800", got)
+	}
+}
+
+func TestSelector(t *testing.T) {
+	s := NewSelector(1, 2)
+	s.Set("rpc.latency", 100, 200, 300)
+
+	latency := metric.NewFloat64Measure("rpc.latency")
+	size := metric.NewFloat64Measure("rpc.size")
+	for _, tt := range []struct {
+		handle *metric.Handle
+		want   []float64
+	}{
+		{&latency.Handle, []float64{100, 200, 300}},
+		{&size.Handle, []float64{1, 2}},
+	} {
+		got := s.New(tt.handle).Checkpoint().Boundaries
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("boundaries of %s -want +got:\n%s", tt.handle.Variable.Name, diff)
+		}
+	}
+}
+
+func TestSelectorMemoryBudget(t *testing.T) {
+	defer memlimit.SetBudget(0)
+	s := NewSelector(1)
+	handle := &metric.NewFloat64Measure("rpc.latency").Handle
+
+	before := memlimit.Used()
+	h := s.New(handle)
+	if h.Shed() || memlimit.Used() == before {
+		t.Fatalf("histogram shed %v with %d bytes reserved, want reserved", h.Shed(), memlimit.Used()-before)
+	}
+	h.Release()
+	h.Release()
+	if got := memlimit.Used(); got != before {
+		t.Errorf("Used() = %d after Release, want %d", got, before)
+	}
+
+	memlimit.SetBudget(before + 1)
+	shed := s.New(handle)
+	if !shed.Shed() {
+		t.Fatal("histogram not shed over the memory budget")
+	}
+	shed.Update(1)
+	if got := shed.Checkpoint().Count; got != 0 {
+		t.Errorf("Count of a shed histogram = %d, want 0", got)
+	}
+	shed.Release()
+	if got := memlimit.Used(); got != before {
+		t.Errorf("Used() = %d after releasing a shed histogram, want %d", got, before)
+	}
+}
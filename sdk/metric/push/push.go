@@ -0,0 +1,251 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package push provides a controller that periodically collects metrics
+// and pushes them to exporters, for backends that cannot scrape the
+// process.
+//
+// The controller usually collects the instruments of the SDK's Provider,
+// and pushes them to the stdout or OTLP exporter:
+//
+//	p := sdkmetric.NewProvider()
+//	metric.SetGlobalProvider(p)
+//	c := push.New(p, []push.Exporter{otlp.NewExporter()}, push.WithPeriod(10*time.Second))
+//	c.Start()
+//	defer c.Stop()
+//
+// Any other Collector, e.g., a distinct.Dimension, can be collected with
+// Collectors.
+package push // import "go.opentelemetry.io/sdk/metric/push"
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/errorhandler"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/sdk/metric/aggregator/histogram"
+)
+
+const (
+	// DefaultPeriod is the default interval between pushes.
+	DefaultPeriod = 10 * time.Second
+
+	// DefaultTimeout is the default time a push may take.
+	DefaultTimeout = 5 * time.Second
+)
+
+// Record is the value of an instrument for a label set, as collected at
+// the end of a period. Measures aggregated as histograms have a
+// Distribution instead of a Value.
+type Record struct {
+	Handle       *metric.Handle
+	Labels       []core.KeyValue
+	Value        core.Value
+	Distribution *histogram.Distribution
+
+	// Library is the instrumentation library of the meter, obtained
+	// from a metric.Provider, that recorded the value.
+	Library Library
+
+	// StartTime and EndTime bound the period the value was recorded
+	// in. They are zero if the Collector does not know the period.
+	StartTime, EndTime time.Time
+}
+
+// Library identifies an instrumentation library. It is zero for the
+// meter of the application.
+type Library struct {
+	Name      string
+	Version   string
+	SchemaURL string
+}
+
+// NewLibrary returns the Library of the meter requested from a
+// metric.Provider with name and opts.
+func NewLibrary(name string, opts ...metric.MeterOption) Library {
+	c := metric.NewMeterConfig(opts...)
+	return Library{Name: name, Version: c.Version, SchemaURL: c.SchemaURL}
+}
+
+// Collector returns the records of the period that ended.
+type Collector interface {
+	Collect(ctx context.Context) []Record
+}
+
+// Collectors returns a Collector returning the records of all cs.
+func Collectors(cs ...Collector) Collector {
+	return collectors(cs)
+}
+
+type collectors []Collector
+
+func (cs collectors) Collect(ctx context.Context) []Record {
+	var records []Record
+	for _, c := range cs {
+		records = append(records, c.Collect(ctx)...)
+	}
+	return records
+}
+
+// Exporter pushes records to a backend.
+type Exporter interface {
+	Export(ctx context.Context, records []Record) error
+}
+
+// Option configures a Controller.
+type Option func(*Controller)
+
+// WithPeriod sets the interval between pushes. It defaults to
+// DefaultPeriod.
+func WithPeriod(d time.Duration) Option {
+	return func(c *Controller) {
+		if d > 0 {
+			c.period = d
+		}
+	}
+}
+
+// WithJitter delays each push by a random duration of up to d, so that
+// processes started together do not push at the same time.
+func WithJitter(d time.Duration) Option {
+	return func(c *Controller) {
+		if d > 0 {
+			c.jitter = d
+		}
+	}
+}
+
+// WithTimeout sets the time a push may take, collection and exports
+// included. It defaults to DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Controller) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// Controller collects records from a Collector every period and passes
+// them to its exporters. Export errors go to the errorhandler; they do
+// not stop the controller.
+type Controller struct {
+	collector Collector
+	exporters []Exporter
+	period    time.Duration
+	jitter    time.Duration
+	timeout   time.Duration
+
+	pushMu   sync.Mutex
+	start    sync.Once
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// New returns a Controller pushing the records of c to exporters. Call
+// Start to begin pushing.
+func New(c Collector, exporters []Exporter, opts ...Option) *Controller {
+	ctrl := &Controller{
+		collector: c,
+		exporters: append([]Exporter(nil), exporters...),
+		period:    DefaultPeriod,
+		timeout:   DefaultTimeout,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(ctrl)
+	}
+	return ctrl
+}
+
+// Start starts the background goroutine pushing every period. It is safe
+// to call more than once.
+func (c *Controller) Start() {
+	c.start.Do(func() {
+		go c.run()
+	})
+}
+
+// Stop stops the background goroutine, then pushes the records of the
+// last, partial period. It is safe to call more than once.
+func (c *Controller) Stop() {
+	c.stopOnce.Do(func() {
+		c.start.Do(func() { close(c.done) })
+		close(c.stop)
+		<-c.done
+		c.Push()
+	})
+}
+
+// Push collects records and passes them to the exporters now.
+func (c *Controller) Push() {
+	c.pushMu.Lock()
+	defer c.pushMu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	records := c.collector.Collect(ctx)
+	if len(records) == 0 {
+		return
+	}
+	for _, e := range c.exporters {
+		if err := e.Export(ctx, records); err != nil {
+			errorhandler.Handle(fmt.Errorf("push: dropped %d metric records: %v", len(records), err))
+		}
+	}
+}
+
+func (c *Controller) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !c.wait(c.delay()) {
+				return
+			}
+			c.Push()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *Controller) delay() time.Duration {
+	if c.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(c.jitter)))
+}
+
+// wait waits for d, and returns false if the controller stopped meanwhile.
+func (c *Controller) wait(d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-c.stop:
+		return false
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/errorhandler"
+	"go.opentelemetry.io/api/metric"
+)
+
+type counter struct {
+	mu sync.Mutex
+	n  int64
+}
+
+func (c *counter) Collect(ctx context.Context) []Record {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+	return []Record{{Handle: &metric.Handle{Type: metric.Cumulative}, Value: core.Value{Type: core.INT64, Int64: c.n}}}
+}
+
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []Record
+	err     error
+}
+
+func (e *recordingExporter) Export(ctx context.Context, records []Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = append(e.records, records...)
+	return e.err
+}
+
+func (e *recordingExporter) len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.records)
+}
+
+func TestControllerPushes(t *testing.T) {
+	e := &recordingExporter{}
+	c := New(&counter{}, []Exporter{e}, WithPeriod(time.Millisecond), WithJitter(time.Millisecond))
+	c.Start()
+	deadline := time.Now().Add(5 * time.Second)
+	for e.len() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	c.Stop()
+	n := e.len()
+	if n < 3 {
+		t.Fatalf("exported %d records, want at least 3", n)
+	}
+
+	c.Stop()
+	time.Sleep(5 * time.Millisecond)
+	if got := e.len(); got != n {
+		t.Errorf("exported %d records after Stop, want %d", got, n)
+	}
+}
+
+func TestControllerStopPushes(t *testing.T) {
+	e := &recordingExporter{}
+	c := New(&counter{}, []Exporter{e})
+	c.Start()
+	c.Stop()
+	if got := e.len(); got != 1 {
+		t.Errorf("exported %d records, want the last period pushed on Stop", got)
+	}
+}
+
+func TestControllerExportError(t *testing.T) {
+	var got error
+	errorhandler.Set(func(err error) { got = err })
+	defer errorhandler.Set(nil)
+
+	failing := &recordingExporter{err: errors.New("unavailable")}
+	ok := &recordingExporter{}
+	New(&counter{}, []Exporter{failing, ok}).Push()
+	if got == nil {
+		t.Error("export error was not handled")
+	}
+	if ok.len() != 1 {
+		t.Errorf("exported %d records after an error of another exporter, want 1", ok.len())
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metric is the SDK of the metric API. The meters of its
+// Provider aggregate the values recorded with each instrument and label
+// set over a collection period: counters are summed, gauges keep their
+// last value, and measures are aggregated into histograms. The Provider
+// is the push.Collector of a push.Controller, which exports the
+// aggregates of each period:
+//
+//	p := metric.NewProvider()
+//	apimetric.SetGlobalProvider(p)
+//	c := push.New(p, []push.Exporter{stdout.New()})
+//	c.Start()
+//	defer c.Stop()
+package metric // import "go.opentelemetry.io/sdk/metric"
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	apimetric "go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithHistogramSelector sets the Selector choosing the bucket boundaries
+// of the histograms of measures. It defaults to a Selector of
+// histogram.DefaultBoundaries.
+func WithHistogramSelector(s *histogram.Selector) Option {
+	return func(p *Provider) {
+		if s != nil {
+			p.selector = s
+		}
+	}
+}
+
+// Provider is an apimetric.Provider whose meters aggregate the values
+// recorded with their instruments. It is a push.Collector collecting the
+// aggregates of all its meters.
+type Provider struct {
+	selector *histogram.Selector
+
+	mu     sync.Mutex
+	meters map[push.Library]*Meter
+	order  []*Meter
+}
+
+var (
+	_ apimetric.Provider = (*Provider)(nil)
+	_ push.Collector     = (*Provider)(nil)
+)
+
+// NewProvider returns a Provider configured with opts.
+func NewProvider(opts ...Option) *Provider {
+	p := &Provider{meters: make(map[push.Library]*Meter)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.selector == nil {
+		p.selector = histogram.NewSelector()
+	}
+	return p
+}
+
+// Meter returns the meter of the named library. Calls with the same name
+// and options return the same meter.
+func (p *Provider) Meter(name string, opts ...apimetric.MeterOption) apimetric.Meter {
+	lib := push.NewLibrary(name, opts...)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m, ok := p.meters[lib]
+	if !ok {
+		m = newMeter(lib, p.selector)
+		p.meters[lib] = m
+		p.order = append(p.order, m)
+	}
+	return m
+}
+
+// Collect implements push.Collector. It returns the aggregates of the
+// meters of p, in the order they were created.
+func (p *Provider) Collect(ctx context.Context) []push.Record {
+	p.mu.Lock()
+	meters := append([]*Meter(nil), p.order...)
+	p.mu.Unlock()
+	var records []push.Record
+	for _, m := range meters {
+		records = append(records, m.Collect(ctx)...)
+	}
+	return records
+}
+
+// Meter is an apimetric.Meter aggregating the values recorded with its
+// instruments. It is a push.Collector.
+type Meter struct {
+	library  push.Library
+	selector *histogram.Selector
+
+	mu      sync.Mutex
+	start   time.Time
+	records map[string]*record
+}
+
+var (
+	_ apimetric.Meter = (*Meter)(nil)
+	_ push.Collector  = (*Meter)(nil)
+)
+
+func newMeter(lib push.Library, selector *histogram.Selector) *Meter {
+	return &Meter{
+		library:  lib,
+		selector: selector,
+		start:    time.Now(),
+		records:  make(map[string]*record),
+	}
+}
+
+// record is the aggregate of an instrument and label set over the
+// current period.
+type record struct {
+	handle *apimetric.Handle
+	labels []core.KeyValue
+
+	mu sync.Mutex
+	// collected is set once the period of the record ended. Updates of
+	// a collected record go to the record of the next period.
+	collected bool
+	sum       int64
+	last      float64
+	hist      *histogram.Histogram
+}
+
+// GetFloat64Gauge implements apimetric.Meter.
+func (m *Meter) GetFloat64Gauge(ctx context.Context, gauge *apimetric.Float64GaugeHandle, labels ...core.KeyValue) apimetric.Float64Gauge {
+	return boundGauge{m.bind(ctx, &gauge.Handle, labels)}
+}
+
+// GetInt64Counter implements apimetric.Meter.
+func (m *Meter) GetInt64Counter(ctx context.Context, counter *apimetric.Int64CounterHandle, labels ...core.KeyValue) apimetric.Int64Counter {
+	return boundCounter{m.bind(ctx, &counter.Handle, labels)}
+}
+
+// GetFloat64Measure implements apimetric.Meter.
+func (m *Meter) GetFloat64Measure(ctx context.Context, measure *apimetric.Float64MeasureHandle, labels ...core.KeyValue) apimetric.Float64Measure {
+	return boundMeasure{m.bind(ctx, &measure.Handle, labels)}
+}
+
+// RecordBatch implements apimetric.Meter.
+func (m *Meter) RecordBatch(ctx context.Context, labels []core.KeyValue, measurements ...apimetric.Measurement) {
+	labels = mergeLabels(tag.FromContext(ctx), labels, nil)
+	for _, ms := range measurements {
+		switch ms.Handle.Type {
+		case apimetric.Gauge:
+			m.update(ms.Handle, labels, func(r *record) { r.last = ms.Value.Float64 })
+		case apimetric.Cumulative:
+			if ms.Value.Int64 >= 0 {
+				m.update(ms.Handle, labels, func(r *record) { r.sum += ms.Value.Int64 })
+			}
+		case apimetric.Measure:
+			m.update(ms.Handle, labels, func(r *record) { r.hist.Update(ms.Value.Float64) })
+		}
+	}
+}
+
+// bind returns the instrument of handle bound to labels and to the tags
+// of ctx.
+func (m *Meter) bind(ctx context.Context, handle *apimetric.Handle, labels []core.KeyValue) instrument {
+	return instrument{m: m, handle: handle, labels: mergeLabels(tag.FromContext(ctx), labels, nil)}
+}
+
+// update applies f to the record of handle and labels of the current
+// period.
+func (m *Meter) update(handle *apimetric.Handle, labels []core.KeyValue, f func(r *record)) {
+	key := recordKey(handle, labels)
+	for {
+		m.mu.Lock()
+		r, ok := m.records[key]
+		if !ok {
+			r = &record{handle: handle, labels: labels}
+			if handle.Type == apimetric.Measure {
+				r.hist = m.selector.New(handle)
+			}
+			m.records[key] = r
+		}
+		m.mu.Unlock()
+
+		r.mu.Lock()
+		if !r.collected {
+			f(r)
+			r.mu.Unlock()
+			return
+		}
+		r.mu.Unlock()
+	}
+}
+
+// Collect implements push.Collector. It returns the aggregates of the
+// instruments and label sets updated since the previous collection, and
+// starts a new period.
+func (m *Meter) Collect(ctx context.Context) []push.Record {
+	m.mu.Lock()
+	records := m.records
+	m.records = make(map[string]*record)
+	start, end := m.start, time.Now()
+	m.start = end
+	m.mu.Unlock()
+
+	keys := make([]string, 0, len(records))
+	for k := range records {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]push.Record, 0, len(records))
+	for _, k := range keys {
+		r := records[k]
+		r.mu.Lock()
+		r.collected = true
+		if r.hist != nil && r.hist.Shed() {
+			// The memory budget did not allow for the histogram; its
+			// values were shed.
+			r.mu.Unlock()
+			continue
+		}
+		pr := push.Record{
+			Handle:    r.handle,
+			Labels:    r.labels,
+			Library:   m.library,
+			StartTime: start,
+			EndTime:   end,
+		}
+		switch r.handle.Type {
+		case apimetric.Gauge:
+			pr.Value = core.Value{Type: core.FLOAT64, Float64: r.last}
+		case apimetric.Cumulative:
+			pr.Value = core.Value{Type: core.INT64, Int64: r.sum}
+		case apimetric.Measure:
+			d := r.hist.Checkpoint()
+			pr.Distribution = &d
+			r.hist.Release()
+		}
+		r.mu.Unlock()
+		out = append(out, pr)
+	}
+	return out
+}
+
+// instrument is an instrument bound to a label set.
+type instrument struct {
+	m      *Meter
+	handle *apimetric.Handle
+	labels []core.KeyValue
+}
+
+type boundGauge struct{ instrument }
+
+// Set implements apimetric.Float64Gauge.
+func (g boundGauge) Set(ctx context.Context, value float64, labels ...core.KeyValue) {
+	g.m.update(g.handle, g.with(labels), func(r *record) { r.last = value })
+}
+
+type boundCounter struct{ instrument }
+
+// Add implements apimetric.Int64Counter. Negative values are ignored.
+func (c boundCounter) Add(ctx context.Context, value int64, labels ...core.KeyValue) {
+	if value < 0 {
+		return
+	}
+	c.m.update(c.handle, c.with(labels), func(r *record) { r.sum += value })
+}
+
+type boundMeasure struct{ instrument }
+
+var _ apimetric.ExemplarRecorder = boundMeasure{}
+
+// Record implements apimetric.Float64Measure.
+func (m boundMeasure) Record(ctx context.Context, value float64, labels ...core.KeyValue) {
+	m.m.update(m.handle, m.with(labels), func(r *record) { r.hist.Update(value) })
+}
+
+// RecordExemplar implements apimetric.ExemplarRecorder. The histogram of
+// the measure keeps sc as the exemplar of the bucket of value.
+func (m boundMeasure) RecordExemplar(ctx context.Context, value float64, sc core.SpanContext, labels ...core.KeyValue) {
+	m.m.update(m.handle, m.with(labels), func(r *record) { r.hist.UpdateWithExemplar(value, sc) })
+}
+
+// with returns the bound labels with labels added.
+func (i instrument) with(labels []core.KeyValue) []core.KeyValue {
+	if len(labels) == 0 {
+		return i.labels
+	}
+	return mergeLabels(nil, i.labels, labels)
+}
+
+// mergeLabels returns the tags of m, then the labels of bound, then those
+// of extra, keeping the last label of each key, ordered by key name.
+func mergeLabels(m tag.Map, bound, extra []core.KeyValue) []core.KeyValue {
+	byName := make(map[string]core.KeyValue)
+	if m != nil {
+		m.Foreach(func(kv core.KeyValue) bool {
+			byName[kv.Key.Variable.Name] = kv
+			return true
+		})
+	}
+	for _, kv := range bound {
+		byName[kv.Key.Variable.Name] = kv
+	}
+	for _, kv := range extra {
+		byName[kv.Key.Variable.Name] = kv
+	}
+	merged := make([]core.KeyValue, 0, len(byName))
+	for _, kv := range byName {
+		merged = append(merged, kv)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Key.Variable.Name < merged[j].Key.Variable.Name
+	})
+	return merged
+}
+
+// recordKey returns a key identifying the instrument of handle and the
+// labels ordered by mergeLabels.
+func recordKey(handle *apimetric.Handle, labels []core.KeyValue) string {
+	var b strings.Builder
+	b.WriteString(handle.Variable.Name)
+	for _, kv := range labels {
+		b.WriteByte(0)
+		b.WriteString(kv.Key.Variable.Name)
+		b.WriteByte('=')
+		b.WriteString(kv.Value.Emit())
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apimetric "go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/api/tag"
+	"go.opentelemetry.io/sdk/metric/aggregator/histogram"
+)
+
+func TestMeterAggregates(t *testing.T) {
+	ctx := tag.NewContext(context.Background(), tag.Insert(key.New("region").String("eu")))
+	counter := apimetric.NewInt64Counter("sdk.test.requests")
+	gauge := apimetric.NewFloat64Gauge("sdk.test.queue")
+	measure := apimetric.NewFloat64Measure("sdk.test.latency")
+	selector := histogram.NewSelector()
+	selector.Set(measure.Variable.Name, 1, 10)
+
+	p := NewProvider(WithHistogramSelector(selector))
+	m := p.Meter("lib", apimetric.WithInstrumentationVersion("1.0"))
+	if p.Meter("lib", apimetric.WithInstrumentationVersion("1.0")) != m {
+		t.Error("Meter returned a new meter for the same library")
+	}
+
+	c := m.GetInt64Counter(ctx, counter, key.New("path").String("/"))
+	c.Add(ctx, 2)
+	c.Add(ctx, -1)
+	c.Add(ctx, 3)
+	m.GetFloat64Gauge(ctx, gauge).Set(ctx, 7)
+	m.RecordBatch(ctx, nil, measure.M(0.5), measure.M(5), gauge.M(4))
+
+	records := p.Collect(ctx)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	for _, r := range records {
+		if r.Library.Name != "lib" || r.Library.Version != "1.0" {
+			t.Errorf("%s: library = %+v", r.Handle.Variable.Name, r.Library)
+		}
+		if r.StartTime.IsZero() || r.EndTime.Before(r.StartTime) {
+			t.Errorf("%s: period = [%v, %v]", r.Handle.Variable.Name, r.StartTime, r.EndTime)
+		}
+		switch r.Handle {
+		case &counter.Handle:
+			if r.Value.Int64 != 5 {
+				t.Errorf("counter = %d, want 5", r.Value.Int64)
+			}
+			if len(r.Labels) != 2 || r.Labels[0].Key.Variable.Name != "path" || r.Labels[1].Key.Variable.Name != "region" {
+				t.Errorf("counter labels = %v, want path and region", r.Labels)
+			}
+		case &gauge.Handle:
+			if r.Value.Float64 != 4 {
+				t.Errorf("gauge = %v, want the last value 4", r.Value.Float64)
+			}
+		case &measure.Handle:
+			if d := r.Distribution; d == nil || d.Count != 2 || d.Sum != 5.5 || d.Counts[0] != 1 || d.Counts[1] != 1 {
+				t.Errorf("measure distribution = %+v", d)
+			}
+		}
+	}
+
+	c.Add(ctx, 1)
+	records = p.Collect(ctx)
+	if len(records) != 1 || records[0].Value.Int64 != 1 {
+		t.Errorf("second period records = %+v, want the counter's delta 1", records)
+	}
+	if records := p.Collect(ctx); len(records) != 0 {
+		t.Errorf("got %d records of an idle period, want 0", len(records))
+	}
+}
+
+func TestMeasureRecordsExemplars(t *testing.T) {
+	ctx := context.Background()
+	measure := apimetric.NewFloat64Measure("sdk.test.exemplars")
+	p := NewProvider()
+	sc := core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 2, TraceOptions: core.TraceOptionSampled}
+	apimetric.RecordWithExemplar(ctx, p.Meter("").GetFloat64Measure(ctx, measure), 3, sc)
+
+	records := p.Collect(ctx)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	d := records[0].Distribution
+	if d.Count != 1 || d.Exemplars == nil {
+		t.Fatalf("distribution = %+v, want one value with its exemplar", d)
+	}
+	var found bool
+	for _, x := range d.Exemplars {
+		found = found || x.SpanContext == sc && x.Value == 3
+	}
+	if !found {
+		t.Errorf("exemplars = %+v, want one of %v", d.Exemplars, sc)
+	}
+}
+
+func TestMeterConcurrentCollect(t *testing.T) {
+	ctx := context.Background()
+	counter := apimetric.NewInt64Counter("sdk.test.concurrent")
+	p := NewProvider()
+	c := p.Meter("").GetInt64Counter(ctx, counter)
+
+	const n = 1000
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			c.Add(ctx, 1)
+		}
+	}()
+	var total int64
+	collect := func() {
+		for _, r := range p.Collect(ctx) {
+			total += r.Value.Int64
+		}
+	}
+	for i := 0; i < 10; i++ {
+		collect()
+	}
+	wg.Wait()
+	collect()
+	if total != n {
+		t.Errorf("collected %d additions, want %d", total, n)
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	m := tag.NewMap(tag.MapUpdate{MultiKV: []core.KeyValue{
+		key.New("a").String("tag"),
+		key.New("c").String("tag"),
+	}})
+	got := mergeLabels(m, []core.KeyValue{key.New("b").Int(1), key.New("c").String("bound")}, []core.KeyValue{key.New("a").String("call")})
+	want := []string{"a=call", "b=1", "c=bound"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, kv := range got {
+		if s := kv.Key.Variable.Name + "=" + kv.Value.Emit(); s != want[i] {
+			t.Errorf("label %d = %s, want %s", i, s, want[i])
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package view declares which labels of each instrument are kept, and
+// how its values are aggregated, before metrics are exported.
+//
+// Labels of high cardinality, e.g., user IDs, are dropped by listing the
+// other keys in the view of the instrument; the records that differ only
+// by dropped labels are then merged:
+//
+//	views := view.New(view.View{
+//		Instrument: "http.server.duration",
+//		Keys:       []core.Key{httptrace.HTTPStatus},
+//	})
+//	c := push.New(collector, []push.Exporter{views.Exporter(exporter)})
+package view // import "go.opentelemetry.io/sdk/metric/view"
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+// Aggregation is how the values of the records merged by a view are
+// combined.
+type Aggregation int
+
+const (
+	// Default aggregates gauges as LastValue, cumulatives as Sum, and
+	// measures as Histogram.
+	Default Aggregation = iota
+	// Sum adds the values.
+	Sum
+	// LastValue keeps the value of the last record.
+	LastValue
+	// Histogram merges the distributions.
+	Histogram
+	// Drop drops the records of the instrument.
+	Drop
+)
+
+// View is the view of the instrument named Instrument.
+type View struct {
+	Instrument string
+
+	// Keys are the label keys kept. All labels are kept when Keys is
+	// nil.
+	Keys []core.Key
+
+	Aggregation Aggregation
+}
+
+// Views holds the views of instruments. Instruments without a view keep
+// all their labels and their default aggregation.
+type Views struct {
+	byName map[string]View
+}
+
+// New returns the Views of views. A later view of an instrument replaces
+// an earlier one.
+func New(views ...View) *Views {
+	v := &Views{byName: make(map[string]View, len(views))}
+	for _, view := range views {
+		v.byName[view.Instrument] = view
+	}
+	return v
+}
+
+// Lookup returns the view of the instrument of handle, with its
+// aggregation resolved.
+func (v *Views) Lookup(handle *metric.Handle) View {
+	view, ok := v.byName[handle.Variable.Name]
+	if !ok {
+		view = View{Instrument: handle.Variable.Name}
+	}
+	if view.Aggregation == Default {
+		switch handle.Type {
+		case metric.Gauge:
+			view.Aggregation = LastValue
+		case metric.Measure:
+			view.Aggregation = Histogram
+		default:
+			view.Aggregation = Sum
+		}
+	}
+	return view
+}
+
+// Filter returns the labels of labels whose keys the view keeps.
+func (view View) Filter(labels []core.KeyValue) []core.KeyValue {
+	if view.Keys == nil {
+		return labels
+	}
+	kept := make([]core.KeyValue, 0, len(view.Keys))
+	for _, kv := range labels {
+		for _, k := range view.Keys {
+			if kv.Key.Variable.Name == k.Variable.Name {
+				kept = append(kept, kv)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// Apply filters the labels of records through the views of their
+// instruments, and merges the records left with the same library,
+// instrument and labels. The records of instruments whose view aggregation is Drop are
+// removed.
+func (v *Views) Apply(records []push.Record) []push.Record {
+	var out []push.Record
+	index := make(map[string]int)
+	for _, r := range records {
+		view := v.Lookup(r.Handle)
+		if view.Aggregation == Drop {
+			continue
+		}
+		r.Labels = view.Filter(r.Labels)
+		k := recordKey(r)
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(out)
+			if r.Distribution != nil {
+				d := cloneDistribution(*r.Distribution)
+				r.Distribution = &d
+			}
+			out = append(out, r)
+			continue
+		}
+		merge(&out[i], r, view.Aggregation)
+	}
+	return out
+}
+
+// Exporter returns an exporter applying the views to the records it
+// passes to next.
+func (v *Views) Exporter(next push.Exporter) push.Exporter {
+	return &exporter{views: v, next: next}
+}
+
+type exporter struct {
+	views *Views
+	next  push.Exporter
+}
+
+func (e *exporter) Export(ctx context.Context, records []push.Record) error {
+	records = e.views.Apply(records)
+	if len(records) == 0 {
+		return nil
+	}
+	return e.next.Export(ctx, records)
+}
+
+// recordKey returns a key identifying the library, instrument and labels
+// of r.
+func recordKey(r push.Record) string {
+	labels := make([]string, len(r.Labels))
+	for i, kv := range r.Labels {
+		labels[i] = kv.Key.Variable.Name + "=" + kv.Value.Emit()
+	}
+	sort.Strings(labels)
+	return strings.Join(append([]string{
+		r.Library.Name, r.Library.Version, r.Library.SchemaURL, r.Handle.Variable.Name,
+	}, labels...), "\x00")
+}
+
+// merge merges r into into.
+func merge(into *push.Record, r push.Record, agg Aggregation) {
+	switch agg {
+	case LastValue:
+		into.Value = r.Value
+		into.Distribution = r.Distribution
+	case Sum:
+		into.Value.Int64 += r.Value.Int64
+		into.Value.Uint64 += r.Value.Uint64
+		into.Value.Float64 += r.Value.Float64
+	case Histogram:
+		if into.Distribution == nil || r.Distribution == nil {
+			return
+		}
+		d := into.Distribution
+		if len(d.Counts) != len(r.Distribution.Counts) {
+			return
+		}
+		for i, c := range r.Distribution.Counts {
+			d.Counts[i] += c
+		}
+		d.Sum += r.Distribution.Sum
+		d.Count += r.Distribution.Count
+	}
+}
+
+func cloneDistribution(d histogram.Distribution) histogram.Distribution {
+	d.Counts = append([]uint64(nil), d.Counts...)
+	return d
+}
@@ -0,0 +1,129 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/metric"
+	"go.opentelemetry.io/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/sdk/metric/push"
+)
+
+var (
+	routeKey = key.New("route")
+	userKey  = key.New("user")
+
+	requests = metric.NewInt64Counter("view.requests")
+	latency  = metric.NewFloat64Measure("view.latency")
+	debug    = metric.NewFloat64Gauge("view.debug")
+)
+
+func int64Value(v int64) core.Value {
+	return core.Value{Type: core.INT64, Int64: v}
+}
+
+func TestLookupDefaults(t *testing.T) {
+	v := New()
+	for _, tt := range []struct {
+		handle *metric.Handle
+		want   Aggregation
+	}{
+		{&requests.Handle, Sum},
+		{&latency.Handle, Histogram},
+		{&debug.Handle, LastValue},
+	} {
+		if got := v.Lookup(tt.handle).Aggregation; got != tt.want {
+			t.Errorf("aggregation of %s = %d, want %d", tt.handle.Variable.Name, got, tt.want)
+		}
+	}
+}
+
+type recordingExporter struct {
+	records []push.Record
+}
+
+func (e *recordingExporter) Export(ctx context.Context, records []push.Record) error {
+	e.records = records
+	return nil
+}
+
+func TestExporterAppliesViews(t *testing.T) {
+	views := New(
+		View{Instrument: "view.requests", Keys: []core.Key{routeKey}},
+		View{Instrument: "view.latency", Keys: []core.Key{}},
+		View{Instrument: "view.debug", Aggregation: Drop},
+	)
+	dist := func(counts ...uint64) *histogram.Distribution {
+		d := histogram.Distribution{Boundaries: []float64{10}, Counts: counts}
+		for _, c := range counts {
+			d.Count += c
+		}
+		return &d
+	}
+	e := &recordingExporter{}
+	err := views.Exporter(e).Export(context.Background(), []push.Record{
+		{Handle: &requests.Handle, Labels: []core.KeyValue{routeKey.String("/a"), userKey.String("1")}, Value: int64Value(1)},
+		{Handle: &requests.Handle, Labels: []core.KeyValue{routeKey.String("/a"), userKey.String("2")}, Value: int64Value(2)},
+		{Handle: &requests.Handle, Labels: []core.KeyValue{routeKey.String("/b"), userKey.String("1")}, Value: int64Value(4)},
+		{Handle: &latency.Handle, Labels: []core.KeyValue{routeKey.String("/a")}, Distribution: dist(1, 0)},
+		{Handle: &latency.Handle, Labels: []core.KeyValue{routeKey.String("/b")}, Distribution: dist(2, 3)},
+		{Handle: &debug.Handle, Value: core.Value{Type: core.FLOAT64, Float64: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type summary struct {
+		Name   string
+		Labels int
+		Value  int64
+		Counts []uint64
+	}
+	var got []summary
+	for _, r := range e.records {
+		s := summary{Name: r.Handle.Variable.Name, Labels: len(r.Labels), Value: r.Value.Int64}
+		if r.Distribution != nil {
+			s.Counts = r.Distribution.Counts
+		}
+		got = append(got, s)
+	}
+	want := []summary{
+		{Name: "view.requests", Labels: 1, Value: 3},
+		{Name: "view.requests", Labels: 1, Value: 4},
+		{Name: "view.latency", Counts: []uint64{3, 3}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("exported records -want +got:\n%s", diff)
+	}
+}
+
+func TestApplyKeepsLibrariesApart(t *testing.T) {
+	views := New(View{Instrument: "view.requests", Keys: []core.Key{}})
+	otherLib := push.NewLibrary("othttp", metric.WithInstrumentationVersion("0.2"))
+	got := views.Apply([]push.Record{
+		{Handle: &requests.Handle, Value: int64Value(1)},
+		{Handle: &requests.Handle, Value: int64Value(2), Library: otherLib},
+		{Handle: &requests.Handle, Value: int64Value(4), Library: otherLib},
+	})
+	if len(got) != 2 || got[0].Value.Int64 != 1 || got[1].Value.Int64 != 6 || got[1].Library != otherLib {
+		t.Errorf("Apply() = %+v, want the records of each library merged apart", got)
+	}
+}
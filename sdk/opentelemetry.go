@@ -15,7 +15,50 @@
 // Package opentelemetry contains Go support for OpenTelemetry.
 package opentelemetry // import "go.opentelemetry.io/sdk"
 
+import (
+	"context"
+	"sync"
+)
+
 // Version is the current release version of OpenTelemetry in use.
 func Version() string {
 	return "0.1.0"
 }
+
+var (
+	shutdownMu    sync.Mutex
+	shutdownHooks []func(context.Context) error
+)
+
+// RegisterShutdownHook adds f to the functions Shutdown calls. SDK
+// packages buffering telemetry, e.g., go.opentelemetry.io/sdk/trace,
+// register one when they are imported, so that the application only
+// calls Shutdown, whichever packages it uses.
+func RegisterShutdownHook(f func(context.Context) error) {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	shutdownHooks = append(shutdownHooks, f)
+}
+
+// Shutdown flushes the telemetry buffered by the SDK and releases its
+// resources, calling the registered hooks in the reverse order of their
+// registration. Call it before the process exits, e.g.,
+//
+//	defer opentelemetry.Shutdown(context.Background())
+//
+// It returns the first error of a hook. The hooks are called once: a
+// second Shutdown does nothing.
+func Shutdown(ctx context.Context) error {
+	shutdownMu.Lock()
+	hooks := shutdownHooks
+	shutdownHooks = nil
+	shutdownMu.Unlock()
+
+	var err error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if e := hooks[i](ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,189 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipeline sets up the trace SDK in one call: resource detection,
+// sampling, a batch span processor exporting over OTLP, the global
+// tracer provider, and the propagators.
+//
+//	p, err := pipeline.New(context.Background(),
+//		pipeline.WithServiceName("checkout"),
+//		pipeline.WithSampleRatio(0.1),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer p.Shutdown()
+//	...
+//	ctx, opts := propagation.Extract(r.Context(), p.Propagator(), r.Header)
+package pipeline // import "go.opentelemetry.io/sdk/pipeline"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/propagation"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/exporter/trace/otlp"
+	"go.opentelemetry.io/sdk/resource"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// Option configures a Pipeline.
+type Option func(*config)
+
+type config struct {
+	exporter     trace.SpanExporter
+	otlpOpts     []otlp.Option
+	sampler      trace.Sampler
+	detectors    []resource.Detector
+	attributes   []core.KeyValue
+	propagators  []propagation.TextFormatPropagator
+	batchOptions []trace.BatchSpanProcessorOption
+}
+
+// WithOTLP exports spans over OTLP/HTTP to the collector at endpoint, as
+// the otlp.WithEndpoint option, configured further by opts. It is the
+// default exporter, with otlp.DefaultEndpoint.
+func WithOTLP(endpoint string, opts ...otlp.Option) Option {
+	return func(c *config) {
+		c.exporter = nil
+		c.otlpOpts = append([]otlp.Option{otlp.WithEndpoint(endpoint)}, opts...)
+	}
+}
+
+// WithExporter exports spans with e instead of OTLP.
+func WithExporter(e trace.SpanExporter) Option {
+	return func(c *config) {
+		c.exporter = e
+	}
+}
+
+// WithSampleRatio samples the given fraction of the traces started in
+// the process, and the traces whose remote parent is sampled. The SDK
+// default sampler is kept otherwise.
+func WithSampleRatio(fraction float64) Option {
+	return func(c *config) {
+		c.sampler = trace.ParentBased(trace.ProbabilitySampler(fraction))
+	}
+}
+
+// WithSampler samples spans with s.
+func WithSampler(s trace.Sampler) Option {
+	return func(c *config) {
+		c.sampler = s
+	}
+}
+
+// WithDetectors detects the resource with detectors instead of
+// resource.DefaultDetectors.
+func WithDetectors(detectors ...resource.Detector) Option {
+	return func(c *config) {
+		c.detectors = detectors
+	}
+}
+
+// WithResourceAttributes adds attrs to the detected resource. They take
+// precedence over the detected attributes.
+func WithResourceAttributes(attrs ...core.KeyValue) Option {
+	return func(c *config) {
+		c.attributes = append(c.attributes, attrs...)
+	}
+}
+
+// WithServiceName sets the service.name resource attribute.
+func WithServiceName(name string) Option {
+	return WithResourceAttributes(resource.ServiceNameKey.String(name))
+}
+
+// WithPropagators sets the propagators, by default W3C Trace Context and
+// Baggage.
+func WithPropagators(ps ...propagation.TextFormatPropagator) Option {
+	return func(c *config) {
+		c.propagators = ps
+	}
+}
+
+// WithBatchOptions configures the batch span processor.
+func WithBatchOptions(opts ...trace.BatchSpanProcessorOption) Option {
+	return func(c *config) {
+		c.batchOptions = append(c.batchOptions, opts...)
+	}
+}
+
+// Pipeline is a trace SDK set up by New.
+type Pipeline struct {
+	resource   *resource.Resource
+	propagator propagation.TextFormatPropagator
+	provider   *trace.Provider
+	bsp        *trace.BatchSpanProcessor
+}
+
+// New detects the resource, applies it and the sampler to the SDK
+// configuration, registers a batch span processor exporting spans, and
+// sets the global tracer provider. A failed resource detection does not
+// fail New: the resource found is used, and the error is returned with
+// the Pipeline.
+func New(ctx context.Context, opts ...Option) (*Pipeline, error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.exporter == nil {
+		c.exporter = otlp.NewExporter(c.otlpOpts...)
+	}
+	if len(c.propagators) == 0 {
+		c.propagators = []propagation.TextFormatPropagator{propagation.TraceContext(), propagation.Baggage()}
+	}
+
+	res, detectErr := resource.Detect(ctx, c.detectors...)
+	res = resource.Merge(resource.New(c.attributes...), res)
+
+	bsp, err := trace.NewBatchSpanProcessor(c.exporter, c.batchOptions...)
+	if err != nil {
+		return nil, err
+	}
+	trace.ApplyConfig(trace.Config{DefaultSampler: c.sampler, Resource: res})
+	trace.RegisterSpanProcessor(bsp)
+
+	p := &Pipeline{
+		resource:   res,
+		propagator: propagation.Composite(c.propagators...),
+		provider:   trace.NewProvider(),
+		bsp:        bsp,
+	}
+	apitrace.SetGlobalProvider(p.provider)
+	return p, detectErr
+}
+
+// Resource returns the resource of the spans.
+func (p *Pipeline) Resource() *resource.Resource {
+	return p.resource
+}
+
+// Propagator returns the composite of the propagators, to inject and
+// extract span contexts and tags.
+func (p *Pipeline) Propagator() propagation.TextFormatPropagator {
+	return p.propagator
+}
+
+// Provider returns the tracer provider, which is also the global one.
+func (p *Pipeline) Provider() apitrace.Provider {
+	return p.provider
+}
+
+// Shutdown unregisters the batch span processor, exporting the spans it
+// holds. It is meant to be deferred in main.
+func (p *Pipeline) Shutdown() {
+	trace.UnregisterSpanProcessor(p.bsp)
+}
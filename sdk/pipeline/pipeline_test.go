@@ -0,0 +1,79 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/resource"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (e *recordingExporter) ExportSpans(ctx context.Context, spans []*trace.SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	e := &recordingExporter{}
+	p, err := New(context.Background(),
+		WithExporter(e),
+		WithSampler(trace.AlwaysSample()),
+		WithDetectors(func(context.Context) (*resource.Resource, error) {
+			return resource.New(key.New("host.name").String("web-1")), errors.New("no container")
+		}),
+		WithServiceName("checkout"),
+	)
+	if err == nil {
+		t.Error("New() returned no error of the failed detector")
+	}
+	if p == nil {
+		t.Fatal("New() returned no pipeline")
+	}
+
+	_, span := apitrace.GlobalProvider().Tracer("lib").Start(context.Background(), "op")
+	span.Finish()
+	p.Shutdown()
+
+	if len(e.spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(e.spans))
+	}
+	attrs := map[string]string{}
+	for _, kv := range e.spans[0].Resource {
+		attrs[kv.Key.Variable.Name] = kv.Value.Emit()
+	}
+	if attrs["service.name"] != "checkout" || attrs["host.name"] != "web-1" {
+		t.Errorf("resource = %v, want the service name and the detected host", attrs)
+	}
+
+	h := http.Header{}
+	p.Propagator().Injector(h).Inject(span.SpanContext(), nil)
+	if h.Get("traceparent") == "" {
+		t.Error("default propagators did not inject traceparent")
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+const (
+	// EnvResourceAttributes is the environment variable read by
+	// EnvDetector. It holds comma-separated key=value pairs whose values
+	// may be percent-encoded, e.g., "service.version=1.2,team=payments".
+	EnvResourceAttributes = "OTEL_RESOURCE_ATTRIBUTES"
+
+	// EnvServiceName is the environment variable read by EnvDetector for
+	// the service name. It takes precedence over a service.name in
+	// EnvResourceAttributes.
+	EnvServiceName = "OTEL_SERVICE_NAME"
+)
+
+// Detector returns a resource describing some aspect of the environment.
+// A detector that finds nothing returns an empty resource and no error.
+type Detector func(ctx context.Context) (*Resource, error)
+
+// DefaultDetectors are the detectors used by Detect when it is given
+// none, in decreasing precedence.
+var DefaultDetectors = []Detector{EnvDetector, ProcessDetector, HostDetector, ContainerDetector}
+
+// Detect runs detectors, or DefaultDetectors if there are none, and
+// merges their resources. The resource of an earlier detector takes
+// precedence. Detect runs all the detectors even if some fail, and
+// returns the merge of the resources found along with the first error.
+func Detect(ctx context.Context, detectors ...Detector) (*Resource, error) {
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors
+	}
+	var r *Resource
+	var firstErr error
+	for _, d := range detectors {
+		if err := ctx.Err(); err != nil {
+			return r, err
+		}
+		res, err := d(ctx)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		r = Merge(r, res)
+	}
+	return r, firstErr
+}
+
+// ProcessDetector detects the attributes of Process.
+func ProcessDetector(ctx context.Context) (*Resource, error) {
+	return Process(), nil
+}
+
+// HostDetector detects the attributes of Host.
+func HostDetector(ctx context.Context) (*Resource, error) {
+	return Host(), nil
+}
+
+// EnvDetector detects the attributes set in EnvResourceAttributes and
+// EnvServiceName. Malformed pairs are skipped and reported in the error.
+func EnvDetector(ctx context.Context) (*Resource, error) {
+	r, err := parseAttributes(os.Getenv(EnvResourceAttributes))
+	if name := strings.TrimSpace(os.Getenv(EnvServiceName)); name != "" {
+		r = Merge(New(ServiceNameKey.String(name)), r)
+	}
+	return r, err
+}
+
+// parseAttributes parses the comma-separated key=value pairs of s.
+func parseAttributes(s string) (*Resource, error) {
+	var attrs []core.KeyValue
+	var invalid []string
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		k := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || k == "" {
+			invalid = append(invalid, pair)
+			continue
+		}
+		v, err := url.PathUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			invalid = append(invalid, pair)
+			continue
+		}
+		attrs = append(attrs, key.New(k).String(v))
+	}
+	var err error
+	if len(invalid) > 0 {
+		err = fmt.Errorf("resource: invalid %s pairs %q", EnvResourceAttributes, invalid)
+	}
+	return New(attrs...), err
+}
+
+// cgroupPath is the file ContainerDetector reads the container ID from.
+var cgroupPath = "/proc/self/cgroup"
+
+// containerIDPattern matches the container ID at the end of a cgroup
+// path, e.g., ".../docker/<id>" or ".../docker-<id>.scope".
+var containerIDPattern = regexp.MustCompile(`([0-9a-f]{64})(?:\.scope)?$`)
+
+// ContainerDetector detects the ID of the container the process runs in
+// from its cgroups. Outside of a container, or on systems without
+// cgroups, it returns an empty resource.
+func ContainerDetector(ctx context.Context) (*Resource, error) {
+	f, err := os.Open(cgroupPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resource: detecting container: %v", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if m := containerIDPattern.FindStringSubmatch(strings.TrimSpace(sc.Text())); m != nil {
+			return New(ContainerIDKey.String(m[1])), nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("resource: detecting container: %v", err)
+	}
+	return nil, nil
+}
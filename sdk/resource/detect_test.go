@@ -0,0 +1,105 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvDetector(t *testing.T) {
+	defer os.Unsetenv(EnvResourceAttributes)
+	defer os.Unsetenv(EnvServiceName)
+
+	os.Setenv(EnvResourceAttributes, "service.name=env, team = pay%20ments,broken,=x")
+	os.Setenv(EnvServiceName, "checkout")
+	r, err := EnvDetector(context.Background())
+	if err == nil {
+		t.Error("EnvDetector returned no error for malformed pairs")
+	}
+	got := map[string]string{}
+	for _, kv := range r.Attributes() {
+		got[kv.Key.Variable.Name] = kv.Value.Emit()
+	}
+	if len(got) != 2 || got["service.name"] != "checkout" || got["team"] != "pay ments" {
+		t.Errorf("got %v, want service.name=checkout and team=pay ments", got)
+	}
+}
+
+func TestContainerDetector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(prev string) { cgroupPath = prev }(cgroupPath)
+
+	const id = "8f3d2c7a1b9e4f6d0c5a3e2b1d9f8c7a6b5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c"
+	for _, tt := range []struct {
+		name, cgroup, want string
+	}{
+		{"docker", "12:memory:/docker/" + id + "\n", id},
+		{"systemd", "0::/system.slice/docker-" + id + ".scope\n", id},
+		{"no container", "0::/user.slice/user-1000.slice\n", ""},
+	} {
+		cgroupPath = filepath.Join(dir, tt.name)
+		if err := ioutil.WriteFile(cgroupPath, []byte(tt.cgroup), 0600); err != nil {
+			t.Fatal(err)
+		}
+		r, err := ContainerDetector(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		v, ok := r.Value(ContainerIDKey)
+		if ok != (tt.want != "") || ok && v.Emit() != tt.want {
+			t.Errorf("%s: container.id = %q, %v; want %q", tt.name, v.Emit(), ok, tt.want)
+		}
+	}
+
+	cgroupPath = filepath.Join(dir, "missing")
+	if r, err := ContainerDetector(context.Background()); err != nil || r.Len() != 0 {
+		t.Errorf("ContainerDetector without cgroups = %v, %v; want empty", r.Attributes(), err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	first := func(ctx context.Context) (*Resource, error) {
+		return New(ServiceNameKey.String("first")), nil
+	}
+	second := func(ctx context.Context) (*Resource, error) {
+		return New(ServiceNameKey.String("second"), HostNameKey.String("h")), os.ErrPermission
+	}
+	r, err := Detect(context.Background(), first, second)
+	if err != os.ErrPermission {
+		t.Errorf("Detect error = %v, want %v", err, os.ErrPermission)
+	}
+	if v, _ := r.Value(ServiceNameKey); v.Emit() != "first" {
+		t.Errorf("service.name = %q, want first", v.Emit())
+	}
+	if v, _ := r.Value(HostNameKey); v.Emit() != "h" {
+		t.Errorf("host.name = %q, want h", v.Emit())
+	}
+
+	r, err = Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Value(ProcessPIDKey); !ok {
+		t.Error("default detectors found no process.pid")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resource describes the entity producing telemetry, e.g., a
+// service running in a process on a host, as a set of attributes that
+// the SDK attaches to every exported span.
+package resource // import "go.opentelemetry.io/sdk/resource"
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+// Keys of the attributes describing common resources.
+var (
+	ServiceNameKey           = key.New("service.name")
+	ServiceVersionKey        = key.New("service.version")
+	HostNameKey              = key.New("host.name")
+	OSTypeKey                = key.New("os.type")
+	ContainerIDKey           = key.New("container.id")
+	ProcessPIDKey            = key.New("process.pid")
+	ProcessExecutableNameKey = key.New("process.executable.name")
+)
+
+// Resource is an immutable set of attributes with unique keys. A nil
+// *Resource is valid and empty.
+type Resource struct {
+	attrs []core.KeyValue
+}
+
+// New returns a resource with attrs. When a key appears more than once,
+// the last value wins.
+func New(attrs ...core.KeyValue) *Resource {
+	byName := make(map[string]int, len(attrs))
+	r := &Resource{attrs: make([]core.KeyValue, 0, len(attrs))}
+	for _, kv := range attrs {
+		if i, ok := byName[kv.Key.Variable.Name]; ok {
+			r.attrs[i] = kv
+			continue
+		}
+		byName[kv.Key.Variable.Name] = len(r.attrs)
+		r.attrs = append(r.attrs, kv)
+	}
+	sort.Slice(r.attrs, func(i, j int) bool {
+		return r.attrs[i].Key.Variable.Name < r.attrs[j].Key.Variable.Name
+	})
+	return r
+}
+
+// Merge returns a resource with the attributes of a and b. Where both
+// have a key, the value of a wins, so that a can override defaults in b.
+func Merge(a, b *Resource) *Resource {
+	if a.Len() == 0 {
+		return b
+	}
+	if b.Len() == 0 {
+		return a
+	}
+	attrs := make([]core.KeyValue, 0, a.Len()+b.Len())
+	attrs = append(attrs, b.attrs...)
+	attrs = append(attrs, a.attrs...)
+	return New(attrs...)
+}
+
+// Attributes returns the attributes of r sorted by key. The slice must
+// not be modified.
+func (r *Resource) Attributes() []core.KeyValue {
+	if r == nil {
+		return nil
+	}
+	return r.attrs
+}
+
+// Len returns the number of attributes of r.
+func (r *Resource) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.attrs)
+}
+
+// Value returns the value of k in r and whether r has k.
+func (r *Resource) Value(k core.Key) (core.Value, bool) {
+	for _, kv := range r.Attributes() {
+		if kv.Key.Variable.Name == k.Variable.Name {
+			return kv.Value, true
+		}
+	}
+	return core.Value{}, false
+}
+
+// Process returns a resource describing the current process: its PID and
+// executable name, as far as they are known.
+func Process() *Resource {
+	attrs := []core.KeyValue{ProcessPIDKey.Int(os.Getpid())}
+	if exe, err := os.Executable(); err == nil {
+		attrs = append(attrs, ProcessExecutableNameKey.String(filepath.Base(exe)))
+	}
+	return New(attrs...)
+}
+
+// Host returns a resource describing the host: its name, as far as it is
+// known, and operating system.
+func Host() *Resource {
+	attrs := []core.KeyValue{OSTypeKey.String(runtime.GOOS)}
+	if host, err := os.Hostname(); err == nil {
+		attrs = append(attrs, HostNameKey.String(host))
+	}
+	return New(attrs...)
+}
+
+// Service returns a resource with the service name and the attributes of
+// Process and Host.
+func Service(name string) *Resource {
+	return Merge(New(ServiceNameKey.String(name)), Merge(Process(), Host()))
+}
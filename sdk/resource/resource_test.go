@@ -0,0 +1,68 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+func TestNew(t *testing.T) {
+	a, b := key.New("a"), key.New("b")
+	r := New(b.Int(1), a.Int(2), b.Int(3))
+	want := []core.KeyValue{a.Int(2), b.Int(3)}
+	if diff := cmp.Diff(r.Attributes(), want); diff != "" {
+		t.Errorf("New: -got +want %s", diff)
+	}
+	if v, ok := r.Value(b); !ok || v.Emit() != "3" {
+		t.Errorf("Value(b) = %v, %v; want 3, true", v.Emit(), ok)
+	}
+	if _, ok := r.Value(key.New("c")); ok {
+		t.Error("Value(c) found a missing key")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b, c := key.New("a"), key.New("b"), key.New("c")
+	for _, tt := range []struct {
+		name string
+		a, b *Resource
+		want []core.KeyValue
+	}{
+		{"both nil", nil, nil, nil},
+		{"nil a", nil, New(a.Int(1)), []core.KeyValue{a.Int(1)}},
+		{"nil b", New(a.Int(1)), nil, []core.KeyValue{a.Int(1)}},
+		{"a wins", New(a.Int(1), b.Int(2)), New(b.Int(3), c.Int(4)), []core.KeyValue{a.Int(1), b.Int(2), c.Int(4)}},
+	} {
+		got := Merge(tt.a, tt.b)
+		if diff := cmp.Diff(got.Attributes(), tt.want); diff != "" {
+			t.Errorf("%s: -got +want %s", tt.name, diff)
+		}
+	}
+}
+
+func TestService(t *testing.T) {
+	r := Service("checkout")
+	if v, _ := r.Value(ServiceNameKey); v.Emit() != "checkout" {
+		t.Errorf("service.name = %q, want checkout", v.Emit())
+	}
+	if v, _ := r.Value(ProcessPIDKey); v.Emit() != key.New("pid").Int(os.Getpid()).Value.Emit() {
+		t.Errorf("process.pid = %q, want %d", v.Emit(), os.Getpid())
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAdaptiveInterval  = 10 * time.Second
+	defaultAdaptiveSmoothing = 0.5
+	defaultAdaptiveMaxNames  = 1000
+
+	// otherNames is the key under which the spans of names beyond the
+	// limit set by WithMaxNames are counted together. Span names cannot
+	// hold a NUL byte in practice.
+	otherNames = "\x00other"
+)
+
+// AdaptiveSamplerOption configures a sampler returned by AdaptiveSampler.
+type AdaptiveSamplerOption func(*adaptiveSampler)
+
+// WithAdjustmentInterval sets how often sampling probabilities are
+// recomputed. It defaults to 10 seconds, which is also used when d is not
+// positive.
+func WithAdjustmentInterval(d time.Duration) AdaptiveSamplerOption {
+	return func(s *adaptiveSampler) {
+		if d > 0 {
+			s.interval = d
+		}
+	}
+}
+
+// WithSmoothing sets the weight, between 0 and 1, given to the span rate
+// of the last interval against the rates seen before. Lower values react
+// slower to bursts. It defaults to 0.5.
+func WithSmoothing(alpha float64) AdaptiveSamplerOption {
+	return func(s *adaptiveSampler) {
+		s.alpha = alpha
+	}
+}
+
+// WithMinProbability sets the lowest probability the spans of a name are
+// sampled with, however busy the name is, so that rare or expensive
+// operations stay visible under load. It applies to names without a
+// floor set by WithNameFloor.
+func WithMinProbability(p float64) AdaptiveSamplerOption {
+	return func(s *adaptiveSampler) {
+		s.floor = p
+	}
+}
+
+// WithNameFloor sets the minimum sampling probability of spans named name.
+func WithNameFloor(name string, p float64) AdaptiveSamplerOption {
+	return func(s *adaptiveSampler) {
+		s.floors[name] = p
+	}
+}
+
+// WithMaxNames sets how many span names get a probability of their own.
+// Spans of further names share a single probability, so that span names
+// of high cardinality, such as ones holding IDs, do not grow the memory
+// of the sampler without bound. It defaults to 1000.
+func WithMaxNames(n int) AdaptiveSamplerOption {
+	return func(s *adaptiveSampler) {
+		if n > 0 {
+			s.maxNames = n
+		}
+	}
+}
+
+// AdaptiveSampler returns a Sampler that adjusts the sampling probability
+// of each span name to keep the total rate of sampled root spans close to
+// spansPerSecond.
+//
+// The budget is shared fairly: names whose traffic is below their share
+// are sampled completely, and the rest of the budget is split evenly
+// among the busier names. Rates are smoothed across intervals, and each
+// name's probability is kept above its floor.
+//
+// Like ProbabilitySampler, it samples spans whose parents are sampled, and
+// the decision for a trace depends only on its trace ID.
+func AdaptiveSampler(spansPerSecond float64, opts ...AdaptiveSamplerOption) Sampler {
+	return newAdaptiveSampler(spansPerSecond, time.Now, opts...).sample
+}
+
+type adaptiveSampler struct {
+	target   float64
+	interval time.Duration
+	alpha    float64
+	floor    float64
+	floors   map[string]float64
+	maxNames int
+	now      func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+	rates       map[string]float64
+	// bounds holds the traceIDHash upper bound of each name; names
+	// without one are sampled.
+	bounds map[string]uint64
+}
+
+func newAdaptiveSampler(target float64, now func() time.Time, opts ...AdaptiveSamplerOption) *adaptiveSampler {
+	s := &adaptiveSampler{
+		target:      target,
+		interval:    defaultAdaptiveInterval,
+		alpha:       defaultAdaptiveSmoothing,
+		floors:      make(map[string]float64),
+		maxNames:    defaultAdaptiveMaxNames,
+		now:         now,
+		windowStart: now(),
+		counts:      make(map[string]int),
+		rates:       make(map[string]float64),
+		bounds:      make(map[string]uint64),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *adaptiveSampler) sample(p SamplingParameters) SamplingDecision {
+	if p.ParentContext.IsSampled() {
+		return SamplingDecision{Sample: true}
+	}
+
+	s.mu.Lock()
+	if now := s.now(); now.Sub(s.windowStart) >= s.interval {
+		s.adjust(now.Sub(s.windowStart))
+		s.windowStart = now
+	}
+	name := s.trackedName(p.Name)
+	s.counts[name]++
+	bound, limited := s.bounds[name]
+	s.mu.Unlock()
+
+	return SamplingDecision{Sample: !limited || traceIDHash(p.TraceID) < bound}
+}
+
+// trackedName returns the key the spans named name are counted under.
+// s.mu must be held.
+func (s *adaptiveSampler) trackedName(name string) string {
+	if _, ok := s.counts[name]; ok {
+		return name
+	}
+	if _, ok := s.rates[name]; ok {
+		return name
+	}
+	// Names of the window that have a rate are counted twice, which
+	// only makes the limit stricter.
+	if len(s.counts)+len(s.rates) >= s.maxNames {
+		return otherNames
+	}
+	return name
+}
+
+// adjust recomputes the probabilities from the counts of the window that
+// lasted elapsed. s.mu must be held.
+func (s *adaptiveSampler) adjust(elapsed time.Duration) {
+	if elapsed <= 0 {
+		return
+	}
+	// Let names that went quiet decay instead of keeping their old rate
+	// forever.
+	for name := range s.rates {
+		if _, ok := s.counts[name]; !ok {
+			s.counts[name] = 0
+		}
+	}
+	type nameRate struct {
+		name string
+		rate float64
+	}
+	rates := make([]nameRate, 0, len(s.counts))
+	for name, count := range s.counts {
+		rate := float64(count) / elapsed.Seconds()
+		if prev, ok := s.rates[name]; ok {
+			rate = s.alpha*rate + (1-s.alpha)*prev
+		}
+		if rate < 1e-3 {
+			delete(s.rates, name)
+			delete(s.bounds, name)
+			continue
+		}
+		s.rates[name] = rate
+		rates = append(rates, nameRate{name, rate})
+	}
+	s.counts = make(map[string]int)
+
+	// Fill the budget from the quietest names up, so that each name gets
+	// min(rate, fair share of what is left).
+	sort.Slice(rates, func(i, j int) bool { return rates[i].rate < rates[j].rate })
+	budget := s.target
+	for i, nr := range rates {
+		share := budget / float64(len(rates)-i)
+		if nr.rate <= share {
+			delete(s.bounds, nr.name)
+			budget -= nr.rate
+			continue
+		}
+		budget -= share
+		p := share / nr.rate
+		floor, ok := s.floors[nr.name]
+		if !ok {
+			floor = s.floor
+		}
+		if p < floor {
+			p = floor
+		}
+		if p >= 1 {
+			delete(s.bounds, nr.name)
+			continue
+		}
+		s.bounds[nr.name] = uint64(p * (1 << 63))
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+)
+
+// sampleRate runs n root spans named name through s, with random trace
+// IDs, and returns the fraction sampled.
+func sampleRate(s *adaptiveSampler, name string, n int) float64 {
+	sampled := 0
+	for i := 0; i < n; i++ {
+		d := s.sample(SamplingParameters{
+			TraceID: core.TraceID{High: rand.Uint64(), Low: rand.Uint64()},
+			Name:    name,
+		})
+		if d.Sample {
+			sampled++
+		}
+	}
+	return float64(sampled) / float64(n)
+}
+
+func TestAdaptiveSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newAdaptiveSampler(100, func() time.Time { return now },
+		WithAdjustmentInterval(time.Second),
+		WithSmoothing(1),
+		WithNameFloor("rare", 0.5),
+	)
+
+	// Without history every span is sampled.
+	if got := sampleRate(s, "busy", 10000); got != 1 {
+		t.Fatalf("sampled %v of busy spans before the first adjustment; want 1", got)
+	}
+	sampleRate(s, "quiet", 10)
+	sampleRate(s, "rare", 1000)
+
+	// busy: 10000/s, quiet: 10/s, rare: 1000/s against a budget of 100/s.
+	now = now.Add(time.Second)
+	if got := sampleRate(s, "quiet", 10); got != 1 {
+		t.Errorf("sampled %v of quiet spans; want 1", got)
+	}
+	// Fair share of the remaining 90/s is 45/s per name.
+	if got, want := sampleRate(s, "busy", 10000), 45.0/10000; got < want/2 || got > want*2 {
+		t.Errorf("sampled %v of busy spans; want about %v", got, want)
+	}
+	if got := sampleRate(s, "rare", 1000); got < 0.4 || got > 0.6 {
+		t.Errorf("sampled %v of rare spans; want about the 0.5 floor", got)
+	}
+}
+
+func TestAdaptiveSamplerSampledParent(t *testing.T) {
+	s := AdaptiveSampler(0)
+	d := s(SamplingParameters{ParentContext: remoteSpanContext(), Name: "child"})
+	if !d.Sample {
+		t.Error("span with a sampled parent was not sampled")
+	}
+}
+
+func TestAdaptiveSamplerNonPositiveInterval(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Second} {
+		now := time.Unix(0, 0)
+		s := newAdaptiveSampler(1, func() time.Time { return now }, WithAdjustmentInterval(d))
+		if s.interval != defaultAdaptiveInterval {
+			t.Errorf("WithAdjustmentInterval(%v): got interval %v, want %v", d, s.interval, defaultAdaptiveInterval)
+		}
+		// Without a valid interval, every span would trigger an
+		// adjustment over no time, giving an infinite rate.
+		sampleRate(s, "busy", 10)
+		if got := sampleRate(s, "busy", 10); got != 1 {
+			t.Errorf("WithAdjustmentInterval(%v): sampled %v of spans within the first interval; want 1", d, got)
+		}
+	}
+}
+
+func TestAdaptiveSamplerMaxNames(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newAdaptiveSampler(10, func() time.Time { return now },
+		WithAdjustmentInterval(time.Second),
+		WithSmoothing(1),
+		WithMaxNames(2),
+	)
+	for i := 0; i < 100; i++ {
+		sampleRate(s, fmt.Sprintf("GET /users/%d", i), 100)
+	}
+	if len(s.counts) > 3 {
+		t.Errorf("tracking %d names; want at most 2 and the shared bucket", len(s.counts))
+	}
+
+	now = now.Add(time.Second)
+	// The untracked names share the budget of a single busy name.
+	if got := sampleRate(s, "GET /users/99", 10000); got > 0.01 {
+		t.Errorf("sampled %v of spans of an untracked name; want the shared probability", got)
+	}
+	if len(s.rates) > 3 {
+		t.Errorf("kept rates of %d names; want at most 2 and the shared bucket", len(s.rates))
+	}
+}
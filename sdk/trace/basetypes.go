@@ -27,6 +27,13 @@ type event struct {
 	msg        string
 	attributes []core.KeyValue
 	time       time.Time
+
+	// droppedAttributes is the number of attributes over
+	// Config.MaxAttributesPerEvent.
+	droppedAttributes int
+
+	// heartbeat is set for the event of WithHeartbeat.
+	heartbeat bool
 }
 
 var _ apievent.Event = &event{}
@@ -38,3 +45,14 @@ func (me *event) Message() string {
 func (me *event) Attributes() []core.KeyValue {
 	return me.attributes
 }
+
+// Time returns the time the event was recorded.
+func (me *event) Time() time.Time {
+	return me.time
+}
+
+// DroppedAttributeCount returns the number of attributes dropped because
+// of Config.MaxAttributesPerEvent.
+func (me *event) DroppedAttributeCount() int {
+	return me.droppedAttributes
+}
@@ -0,0 +1,374 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/logger"
+	"go.opentelemetry.io/sdk/memlimit"
+)
+
+const (
+	// DefaultMaxQueueSize is the default number of finished spans a
+	// BatchSpanProcessor buffers before dropping new ones.
+	DefaultMaxQueueSize = 2048
+
+	// DefaultScheduledDelay is the default interval between exports of a
+	// BatchSpanProcessor.
+	DefaultScheduledDelay = 5 * time.Second
+
+	// DefaultMaxExportBatchSize is the default maximum number of spans a
+	// BatchSpanProcessor passes to a single export.
+	DefaultMaxExportBatchSize = 512
+
+	// DefaultMaxConcurrentExports is the default number of exports a
+	// BatchSpanProcessor runs at the same time.
+	DefaultMaxConcurrentExports = 1
+)
+
+var (
+	errNilSpanExporter             = errors.New("trace: BatchSpanProcessor requires a non-nil SpanExporter")
+	errInvalidMaxQueueSize         = errors.New("trace: BatchSpanProcessor MaxQueueSize must be positive")
+	errInvalidScheduledDelay       = errors.New("trace: BatchSpanProcessor ScheduledDelay must be positive")
+	errInvalidMaxExportBatchSize   = errors.New("trace: BatchSpanProcessor MaxExportBatchSize must be positive")
+	errInvalidMaxConcurrentExports = errors.New("trace: BatchSpanProcessor MaxConcurrentExports must be positive")
+)
+
+// BatchSpanProcessorOptions configures a BatchSpanProcessor.
+type BatchSpanProcessorOptions struct {
+	// MaxQueueSize is the maximum number of spans buffered. Spans
+	// finished while the queue is full are dropped.
+	MaxQueueSize int
+
+	// ScheduledDelay is the interval between exports.
+	ScheduledDelay time.Duration
+
+	// MaxExportBatchSize is the maximum number of spans per export. An
+	// export starts early once this many spans are queued.
+	MaxExportBatchSize int
+
+	// MaxConcurrentExports is the maximum number of batches exported at
+	// the same time, when more than a batch is queued. Above one, the
+	// SpanExporter must be safe for concurrent use, and batches may
+	// arrive out of order.
+	MaxConcurrentExports int
+}
+
+// BatchSpanProcessorOption sets an option of a BatchSpanProcessor.
+type BatchSpanProcessorOption func(o *BatchSpanProcessorOptions)
+
+// WithMaxQueueSize sets the maximum number of spans buffered.
+func WithMaxQueueSize(size int) BatchSpanProcessorOption {
+	return func(o *BatchSpanProcessorOptions) {
+		o.MaxQueueSize = size
+	}
+}
+
+// WithScheduledDelay sets the interval between exports.
+func WithScheduledDelay(delay time.Duration) BatchSpanProcessorOption {
+	return func(o *BatchSpanProcessorOptions) {
+		o.ScheduledDelay = delay
+	}
+}
+
+// WithMaxExportBatchSize sets the maximum number of spans per export.
+func WithMaxExportBatchSize(size int) BatchSpanProcessorOption {
+	return func(o *BatchSpanProcessorOptions) {
+		o.MaxExportBatchSize = size
+	}
+}
+
+// WithMaxConcurrentExports sets the maximum number of batches exported at
+// the same time, e.g., to keep up with high span rates when each export
+// is an RPC.
+func WithMaxConcurrentExports(n int) BatchSpanProcessorOption {
+	return func(o *BatchSpanProcessorOptions) {
+		o.MaxConcurrentExports = n
+	}
+}
+
+// BatchSpanProcessor is an Exporter that buffers finished spans and passes
+// them to a SpanExporter in batches from a background goroutine, so that
+// slow exports add no latency to the code finishing spans.
+//
+// Register it with RegisterSpanProcessor, or RegisterExporter, and call
+// Shutdown before the program exits to export the buffered spans.
+type BatchSpanProcessor struct {
+	e SpanExporter
+	o BatchSpanProcessorOptions
+
+	mu       sync.Mutex
+	queue    []*SpanData
+	sizes    []int64 // reserved with memlimit, by span of queue
+	stopped  bool
+	dropped  uint64 // access atomically
+	exportMu sync.Mutex
+
+	kick     chan struct{}
+	flush    chan chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+var (
+	_ Exporter      = (*BatchSpanProcessor)(nil)
+	_ SpanProcessor = (*BatchSpanProcessor)(nil)
+)
+
+// NewBatchSpanProcessor returns a BatchSpanProcessor exporting to e and
+// starts its background goroutine. It returns an error if an option is
+// not positive.
+func NewBatchSpanProcessor(e SpanExporter, opts ...BatchSpanProcessorOption) (*BatchSpanProcessor, error) {
+	if e == nil {
+		return nil, errNilSpanExporter
+	}
+	o := BatchSpanProcessorOptions{
+		MaxQueueSize:         DefaultMaxQueueSize,
+		ScheduledDelay:       DefaultScheduledDelay,
+		MaxExportBatchSize:   DefaultMaxExportBatchSize,
+		MaxConcurrentExports: DefaultMaxConcurrentExports,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	switch {
+	case o.MaxQueueSize <= 0:
+		return nil, errInvalidMaxQueueSize
+	case o.ScheduledDelay <= 0:
+		return nil, errInvalidScheduledDelay
+	case o.MaxExportBatchSize <= 0:
+		return nil, errInvalidMaxExportBatchSize
+	case o.MaxConcurrentExports <= 0:
+		return nil, errInvalidMaxConcurrentExports
+	}
+	if o.MaxExportBatchSize > o.MaxQueueSize {
+		o.MaxExportBatchSize = o.MaxQueueSize
+	}
+
+	bsp := &BatchSpanProcessor{
+		e:     e,
+		o:     o,
+		kick:  make(chan struct{}, 1),
+		flush: make(chan chan struct{}),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	registerBatchSpanProcessor(bsp)
+	go bsp.run()
+	return bsp, nil
+}
+
+// ExportSpan queues sd for export. It never blocks; sd is dropped if the
+// queue is full, the memory budget is exceeded, or the processor was shut
+// down.
+func (bsp *BatchSpanProcessor) ExportSpan(sd *SpanData) {
+	size := spanDataSize(sd)
+	priority := memlimit.SampledSpan
+	if !sd.SpanContext.IsSampled() {
+		priority = memlimit.UnsampledSpan
+	}
+	if !memlimit.Reserve(priority, size) {
+		atomic.AddUint64(&bsp.dropped, 1)
+		logger.Debugf("trace: BatchSpanProcessor dropped span %q: memory budget exceeded", sd.Name)
+		return
+	}
+	bsp.mu.Lock()
+	if bsp.stopped || len(bsp.queue) >= bsp.o.MaxQueueSize {
+		bsp.mu.Unlock()
+		memlimit.Release(size)
+		atomic.AddUint64(&bsp.dropped, 1)
+		logger.Debugf("trace: BatchSpanProcessor dropped span %q: queue is full or shut down", sd.Name)
+		return
+	}
+	bsp.queue = append(bsp.queue, sd)
+	bsp.sizes = append(bsp.sizes, size)
+	full := len(bsp.queue) >= bsp.o.MaxExportBatchSize
+	bsp.mu.Unlock()
+
+	if full {
+		select {
+		case bsp.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// OnStart does nothing.
+func (bsp *BatchSpanProcessor) OnStart(sd *SpanData) {}
+
+// OnEnd queues sd for export.
+func (bsp *BatchSpanProcessor) OnEnd(sd *SpanData) {
+	bsp.ExportSpan(sd)
+}
+
+// DroppedSpans returns the number of spans dropped because the queue was
+// full, the memory budget was exceeded, or the processor was shut down.
+func (bsp *BatchSpanProcessor) DroppedSpans() uint64 {
+	return atomic.LoadUint64(&bsp.dropped)
+}
+
+// ForceFlush exports all queued spans and waits for the exports to
+// finish.
+func (bsp *BatchSpanProcessor) ForceFlush() {
+	done := make(chan struct{})
+	select {
+	case bsp.flush <- done:
+		<-done
+	case <-bsp.done:
+	}
+}
+
+// Shutdown stops accepting spans, exports the queued ones and waits for
+// the background goroutine to exit. It is safe to call more than once.
+func (bsp *BatchSpanProcessor) Shutdown() {
+	bsp.stopOnce.Do(func() {
+		bsp.mu.Lock()
+		bsp.stopped = true
+		bsp.mu.Unlock()
+		close(bsp.stop)
+		unregisterBatchSpanProcessor(bsp)
+	})
+	<-bsp.done
+}
+
+func (bsp *BatchSpanProcessor) run() {
+	defer close(bsp.done)
+	ticker := time.NewTicker(bsp.o.ScheduledDelay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			bsp.export()
+		case <-bsp.kick:
+			bsp.export()
+		case done := <-bsp.flush:
+			bsp.export()
+			close(done)
+		case <-bsp.stop:
+			bsp.export()
+			return
+		}
+	}
+}
+
+// export passes the queued spans to the exporter in batches, up to
+// MaxConcurrentExports at a time. Each batch is bounded by the configured
+// ExportTimeout and retried up to ExportRetries times when it times out.
+func (bsp *BatchSpanProcessor) export() {
+	bsp.exportMu.Lock()
+	defer bsp.exportMu.Unlock()
+	for {
+		bsp.mu.Lock()
+		var batches [][]*SpanData
+		n := 0
+		for len(batches) < bsp.o.MaxConcurrentExports && n < len(bsp.queue) {
+			size := len(bsp.queue) - n
+			if size > bsp.o.MaxExportBatchSize {
+				size = bsp.o.MaxExportBatchSize
+			}
+			batch := make([]*SpanData, size)
+			copy(batch, bsp.queue[n:])
+			batches = append(batches, batch)
+			n += size
+		}
+		bsp.mu.Unlock()
+		if n == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, batch := range batches[1:] {
+			wg.Add(1)
+			go func(batch []*SpanData) {
+				defer wg.Done()
+				bsp.exportBatch(batch)
+			}(batch)
+		}
+		bsp.exportBatch(batches[0])
+		wg.Wait()
+
+		// Remove the batches only now, so that DumpTrace sees spans
+		// while they are exported.
+		bsp.mu.Lock()
+		bsp.queue = bsp.queue[n:]
+		var size int64
+		for _, s := range bsp.sizes[:n] {
+			size += s
+		}
+		bsp.sizes = bsp.sizes[n:]
+		bsp.mu.Unlock()
+		memlimit.Release(size)
+	}
+}
+
+func (bsp *BatchSpanProcessor) exportBatch(batch []*SpanData) {
+	enrich(batch...)
+	err := exportWithRetry(func(ctx context.Context) error {
+		return bsp.e.ExportSpans(ctx, batch)
+	})
+	if err != nil {
+		dropSpans(len(batch), fmt.Errorf("dropped %d spans: %v", len(batch), err))
+	}
+}
+
+// queued returns the queued spans of a trace, or of every trace if all
+// is true.
+func (bsp *BatchSpanProcessor) queued(id core.TraceID, all bool) []*SpanData {
+	bsp.mu.Lock()
+	defer bsp.mu.Unlock()
+	var spans []*SpanData
+	for _, sd := range bsp.queue {
+		if all || sd.SpanContext.TraceID == id {
+			spans = append(spans, sd)
+		}
+	}
+	return spans
+}
+
+var (
+	batchSpanProcessorsMu sync.Mutex
+	batchSpanProcessors   = map[*BatchSpanProcessor]struct{}{}
+)
+
+func registerBatchSpanProcessor(bsp *BatchSpanProcessor) {
+	batchSpanProcessorsMu.Lock()
+	defer batchSpanProcessorsMu.Unlock()
+	batchSpanProcessors[bsp] = struct{}{}
+}
+
+func unregisterBatchSpanProcessor(bsp *BatchSpanProcessor) {
+	batchSpanProcessorsMu.Lock()
+	defer batchSpanProcessorsMu.Unlock()
+	delete(batchSpanProcessors, bsp)
+}
+
+// liveBatchSpanProcessors returns the BatchSpanProcessors not shut down.
+func liveBatchSpanProcessors() []*BatchSpanProcessor {
+	batchSpanProcessorsMu.Lock()
+	defer batchSpanProcessorsMu.Unlock()
+	bsps := make([]*BatchSpanProcessor, 0, len(batchSpanProcessors))
+	for bsp := range batchSpanProcessors {
+		bsps = append(bsps, bsp)
+	}
+	return bsps
+}
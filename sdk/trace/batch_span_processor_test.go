@@ -0,0 +1,235 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/errorhandler"
+)
+
+type batchExporter struct {
+	mu      sync.Mutex
+	batches [][]*SpanData
+	block   chan struct{}
+	err     error
+}
+
+func (e *batchExporter) ExportSpans(ctx context.Context, spans []*SpanData) error {
+	if e.block != nil {
+		<-e.block
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = append(e.batches, spans)
+	return e.err
+}
+
+func (e *batchExporter) spans() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n := 0
+	for _, b := range e.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func TestNewBatchSpanProcessorNilExporter(t *testing.T) {
+	if _, err := NewBatchSpanProcessor(nil); err == nil {
+		t.Error("NewBatchSpanProcessor(nil) returned no error")
+	}
+}
+
+func TestNewBatchSpanProcessorInvalidOptions(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opt  BatchSpanProcessorOption
+	}{
+		{"zero queue size", WithMaxQueueSize(0)},
+		{"negative queue size", WithMaxQueueSize(-1)},
+		{"zero delay", WithScheduledDelay(0)},
+		{"negative delay", WithScheduledDelay(-time.Second)},
+		{"zero batch size", WithMaxExportBatchSize(0)},
+		{"zero concurrent exports", WithMaxConcurrentExports(0)},
+	} {
+		if bsp, err := NewBatchSpanProcessor(&batchExporter{}, tt.opt); err == nil {
+			bsp.Shutdown()
+			t.Errorf("%s: NewBatchSpanProcessor returned no error", tt.name)
+		}
+	}
+}
+
+type timeoutBatchExporter struct {
+	attempts int
+}
+
+func (e *timeoutBatchExporter) ExportSpans(ctx context.Context, spans []*SpanData) error {
+	e.attempts++
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestBatchSpanProcessorExportRetries(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{ExportTimeout: time.Millisecond, ExportRetries: 2})
+	errorhandler.Set(func(error) {})
+	defer errorhandler.Set(nil)
+
+	e := &timeoutBatchExporter{}
+	bsp, err := NewBatchSpanProcessor(e, WithScheduledDelay(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bsp.ExportSpan(&SpanData{Name: "span"})
+	bsp.Shutdown()
+
+	if got, want := e.attempts, 3; got != want {
+		t.Errorf("got %d export attempts, want %d", got, want)
+	}
+}
+
+func TestBatchSpanProcessorForceFlush(t *testing.T) {
+	e := &batchExporter{}
+	bsp, err := NewBatchSpanProcessor(e, WithScheduledDelay(time.Hour), WithMaxExportBatchSize(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bsp.Shutdown()
+
+	for i := 0; i < 5; i++ {
+		bsp.ExportSpan(&SpanData{Name: "span"})
+	}
+	bsp.ForceFlush()
+
+	if got := e.spans(); got != 5 {
+		t.Errorf("exported %d spans; want 5", got)
+	}
+	for _, b := range e.batches {
+		if len(b) > 2 {
+			t.Errorf("exported a batch of %d spans; want at most 2", len(b))
+		}
+	}
+}
+
+type concurrentBatchExporter struct {
+	batchExporter
+	inflight, max int32
+}
+
+func (e *concurrentBatchExporter) ExportSpans(ctx context.Context, spans []*SpanData) error {
+	n := atomic.AddInt32(&e.inflight, 1)
+	defer atomic.AddInt32(&e.inflight, -1)
+	for {
+		max := atomic.LoadInt32(&e.max)
+		if n <= max || atomic.CompareAndSwapInt32(&e.max, max, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	return e.batchExporter.ExportSpans(ctx, spans)
+}
+
+func TestBatchSpanProcessorConcurrentExports(t *testing.T) {
+	e := &concurrentBatchExporter{}
+	bsp, err := NewBatchSpanProcessor(e, WithScheduledDelay(time.Hour),
+		WithMaxExportBatchSize(2), WithMaxConcurrentExports(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bsp.mu.Lock()
+	for i := 0; i < 11; i++ {
+		bsp.queue = append(bsp.queue, &SpanData{Name: "span"})
+		bsp.sizes = append(bsp.sizes, 0)
+	}
+	bsp.mu.Unlock()
+	bsp.Shutdown()
+
+	if got := e.spans(); got != 11 {
+		t.Errorf("exported %d spans; want 11", got)
+	}
+	if got := atomic.LoadInt32(&e.max); got != 3 {
+		t.Errorf("ran up to %d exports at the same time; want 3", got)
+	}
+}
+
+func TestBatchSpanProcessorQueueFull(t *testing.T) {
+	e := &batchExporter{block: make(chan struct{})}
+	bsp, err := NewBatchSpanProcessor(e, WithScheduledDelay(time.Hour), WithMaxQueueSize(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		bsp.ExportSpan(&SpanData{Name: "span"})
+	}
+	if got := bsp.DroppedSpans(); got != 1 {
+		t.Errorf("DroppedSpans() = %d; want 1", got)
+	}
+
+	close(e.block)
+	bsp.Shutdown()
+	if got := e.spans(); got != 2 {
+		t.Errorf("exported %d spans on Shutdown; want 2", got)
+	}
+	bsp.ExportSpan(&SpanData{Name: "late"})
+	if got := bsp.DroppedSpans(); got != 2 {
+		t.Errorf("DroppedSpans() = %d after Shutdown; want 2", got)
+	}
+	bsp.Shutdown()
+}
+
+func TestBatchSpanProcessorExportError(t *testing.T) {
+	var handled error
+	errorhandler.Set(func(err error) { handled = err })
+	defer errorhandler.Set(nil)
+
+	e := &batchExporter{err: errors.New("unavailable")}
+	bsp, err := NewBatchSpanProcessor(e, WithScheduledDelay(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := DroppedSpans()
+	bsp.ExportSpan(&SpanData{Name: "span"})
+	bsp.Shutdown()
+
+	if handled == nil {
+		t.Error("export error was not reported to the error handler")
+	}
+	if got := DroppedSpans() - before; got != 1 {
+		t.Errorf("DroppedSpans() grew by %d; want 1", got)
+	}
+}
+
+func TestDumpTraceQueuedSpans(t *testing.T) {
+	e := &batchExporter{}
+	bsp, err := NewBatchSpanProcessor(e, WithScheduledDelay(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bsp.Shutdown()
+
+	bsp.ExportSpan(&SpanData{SpanContext: remoteSpanContext(), Name: "queued"})
+	dump := DumpTrace(tid)
+	if len(dump) != 1 || dump[0].Name != "queued" {
+		t.Errorf("DumpTrace returned %v; want the queued span", dump)
+	}
+}
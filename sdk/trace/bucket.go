@@ -0,0 +1,58 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"math/bits"
+
+	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+// TraceBuckets is the number of buckets TraceIDBucket maps trace IDs into.
+const TraceBuckets = 100
+
+// TraceIDBucket maps a trace ID into a stable bucket in the range
+// [0, TraceBuckets). It uses the same hash as ProbabilitySampler, so every
+// service sharing a trace computes the same bucket, and a trace in bucket b
+// is sampled by ProbabilitySampler(f) whenever b < f*TraceBuckets.
+//
+// This is useful for applying feature flags and canary routing consistently
+// per trace, e.g.
+//
+//	if b, ok := trace.CurrentTraceBucket(ctx); ok && b < 5 {
+//	        // 5% of traces take the canary path.
+//	}
+func TraceIDBucket(id core.TraceID) int {
+	// Scale the 63-bit hash into [0, TraceBuckets) without overflowing.
+	hi, lo := bits.Mul64(traceIDHash(id), TraceBuckets)
+	return int(hi<<1 | lo>>63)
+}
+
+// CurrentTraceBucket returns the bucket of the trace ID of the current
+// span in ctx. It returns false if ctx carries no valid trace ID.
+func CurrentTraceBucket(ctx context.Context) (int, bool) {
+	var sc core.SpanContext
+	if s := fromContext(ctx); s != nil {
+		sc = s.spanContext
+	} else {
+		sc = apitrace.CurrentSpan(ctx).SpanContext()
+	}
+	if !sc.HasTraceID() {
+		return 0, false
+	}
+	return TraceIDBucket(sc.TraceID), true
+}
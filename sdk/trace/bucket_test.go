@@ -0,0 +1,72 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+func TestTraceIDBucket(t *testing.T) {
+	tests := []struct {
+		name string
+		high uint64
+		want int
+	}{
+		{name: "zero", high: 0, want: 0},
+		{name: "max", high: ^uint64(0), want: TraceBuckets - 1},
+		{name: "half", high: 1 << 63, want: TraceBuckets / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := core.TraceID{High: tt.high, Low: 1}
+			if got := TraceIDBucket(id); got != tt.want {
+				t.Errorf("TraceIDBucket() = %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceIDBucketMatchesSampler(t *testing.T) {
+	sampler := ProbabilitySampler(0.25)
+	for i := uint64(0); i < 1000; i++ {
+		id := core.TraceID{High: i * 0x9e3779b97f4a7c15, Low: i}
+		sampled := sampler(SamplingParameters{TraceID: id}).Sample
+		if inBucket := TraceIDBucket(id) < 25; inBucket != sampled {
+			t.Fatalf("trace %v: bucket %d, sampled %v", id, TraceIDBucket(id), sampled)
+		}
+	}
+}
+
+func TestCurrentTraceBucket(t *testing.T) {
+	if _, ok := CurrentTraceBucket(context.Background()); ok {
+		t.Error("CurrentTraceBucket: got bucket for context without span")
+	}
+
+	ctx, span := apitrace.GlobalTracer().Start(context.Background(), "bucket",
+		apitrace.ChildOf(remoteSpanContext()))
+	defer span.Finish()
+
+	got, ok := CurrentTraceBucket(ctx)
+	if !ok {
+		t.Fatal("CurrentTraceBucket: got no bucket for context with span")
+	}
+	if want := TraceIDBucket(tid); got != want {
+		t.Errorf("CurrentTraceBucket() = %d; want %d", got, want)
+	}
+}
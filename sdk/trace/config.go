@@ -15,9 +15,12 @@
 package trace
 
 import (
+	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"go.opentelemetry.io/sdk/trace/internal"
+	"go.opentelemetry.io/sdk/resource"
 )
 
 // Config represents the global tracing configuration.
@@ -25,20 +28,71 @@ type Config struct {
 	// DefaultSampler is the default sampler used when creating new spans.
 	DefaultSampler Sampler
 
-	// IDGenerator is for internal use only.
-	IDGenerator internal.IDGenerator
+	// IDGenerator generates the IDs of new spans.
+	IDGenerator IDGenerator
+
+	// Resource describes the entity producing the spans. It is merged
+	// into the Resource of every span; the resources given to a tracer
+	// with WithResources take precedence.
+	Resource *resource.Resource
 
 	// MaxEventsPerSpan is max number of message events per span
 	MaxEventsPerSpan int
 
+	// MaxEventsPerSecondPerSpan is max number of message events per second
+	// per span. Events over the rate are dropped. Zero means unlimited.
+	MaxEventsPerSecondPerSpan int
+
 	// MaxAnnotationEventsPerSpan is max number of attributes per span
 	MaxAttributesPerSpan int
 
+	// MaxAttributesPerEvent is max number of attributes per message
+	// event. Further attributes are dropped.
+	MaxAttributesPerEvent int
+
 	// MaxLinksPerSpan is max number of links per span
 	MaxLinksPerSpan int
+
+	// MaxSpanNameLength is the max length of span names in bytes. Longer
+	// names, e.g., names that accidentally include a full URL or SQL
+	// statement, are truncated and end with SpanNameTruncationMarker.
+	// Zero means unlimited. Use NoSpanNameLimit to remove a limit set
+	// before.
+	MaxSpanNameLength int
+
+	// ExportTimeout is the time a ContextExporter is given to export a
+	// span before the export is canceled.
+	ExportTimeout time.Duration
+
+	// ExportRetries is the number of times an export that timed out is
+	// retried before the span is dropped. Use NoExportRetries to disable
+	// retries that were enabled before.
+	ExportRetries int
+
+	// samplerGeneration counts the samplers given to ApplyConfig, so that
+	// Diff reports a new sampler made by the same constructor as the
+	// previous one.
+	samplerGeneration uint64
 }
 
-var configWriteMu sync.Mutex
+var (
+	configWriteMu sync.Mutex
+
+	// configSubscribers are the functions given to SubscribeConfig,
+	// guarded by configWriteMu.
+	configSubscribers = map[*configSubscriber]struct{}{}
+
+	// configNotifyMu is held while subscribers are called, without
+	// configWriteMu, so that subscribers can unsubscribe, and is taken
+	// before configWriteMu is released, so that changes are reported in
+	// order.
+	configNotifyMu sync.Mutex
+)
+
+type configSubscriber struct {
+	f       func(old, new Config)
+	removed int32 // access atomically
+}
 
 const (
 	// DefaultMaxEventsPerSpan is default max number of message events per span
@@ -47,8 +101,29 @@ const (
 	// DefaultMaxAttributesPerSpan is default max number of attributes per span
 	DefaultMaxAttributesPerSpan = 32
 
+	// DefaultMaxAttributesPerEvent is default max number of attributes per
+	// message event
+	DefaultMaxAttributesPerEvent = 32
+
 	// DefaultMaxLinksPerSpan is default max number of links per span
 	DefaultMaxLinksPerSpan = 32
+
+	// DefaultExportTimeout is default time given to a ContextExporter to
+	// export a span
+	DefaultExportTimeout = 30 * time.Second
+
+	// NoExportRetries is the value of Config.ExportRetries that sets the
+	// number of retries to zero, which a zero value, meaning "unchanged",
+	// cannot.
+	NoExportRetries = -1
+
+	// NoSpanNameLimit is the value of Config.MaxSpanNameLength that removes
+	// the limit on span name length.
+	NoSpanNameLimit = -1
+
+	// SpanNameTruncationMarker ends span names truncated because of
+	// Config.MaxSpanNameLength.
+	SpanNameTruncationMarker = "..."
 )
 
 // ApplyConfig applies changes to the global tracing configuration.
@@ -56,22 +131,116 @@ const (
 // Fields not provided in the given config are going to be preserved.
 func ApplyConfig(cfg Config) {
 	configWriteMu.Lock()
-	defer configWriteMu.Unlock()
 	c := *config.Load().(*Config)
 	if cfg.DefaultSampler != nil {
 		c.DefaultSampler = cfg.DefaultSampler
+		c.samplerGeneration++
 	}
 	if cfg.IDGenerator != nil {
 		c.IDGenerator = cfg.IDGenerator
 	}
+	if cfg.Resource != nil {
+		c.Resource = cfg.Resource
+	}
 	if cfg.MaxEventsPerSpan > 0 {
 		c.MaxEventsPerSpan = cfg.MaxEventsPerSpan
 	}
+	if cfg.MaxEventsPerSecondPerSpan > 0 {
+		c.MaxEventsPerSecondPerSpan = cfg.MaxEventsPerSecondPerSpan
+	}
 	if cfg.MaxAttributesPerSpan > 0 {
 		c.MaxAttributesPerSpan = cfg.MaxAttributesPerSpan
 	}
+	if cfg.MaxAttributesPerEvent > 0 {
+		c.MaxAttributesPerEvent = cfg.MaxAttributesPerEvent
+	}
 	if cfg.MaxLinksPerSpan > 0 {
 		c.MaxLinksPerSpan = cfg.MaxLinksPerSpan
 	}
+	if cfg.MaxSpanNameLength > 0 {
+		c.MaxSpanNameLength = cfg.MaxSpanNameLength
+	} else if cfg.MaxSpanNameLength == NoSpanNameLimit {
+		c.MaxSpanNameLength = 0
+	}
+	if cfg.ExportTimeout > 0 {
+		c.ExportTimeout = cfg.ExportTimeout
+	}
+	if cfg.ExportRetries > 0 {
+		c.ExportRetries = cfg.ExportRetries
+	} else if cfg.ExportRetries == NoExportRetries {
+		c.ExportRetries = 0
+	}
+	old := config.Load().(*Config)
 	config.Store(&c)
+	if len(old.Diff(c)) == 0 {
+		configWriteMu.Unlock()
+		return
+	}
+	subscribers := make([]*configSubscriber, 0, len(configSubscribers))
+	for s := range configSubscribers {
+		subscribers = append(subscribers, s)
+	}
+	configNotifyMu.Lock()
+	defer configNotifyMu.Unlock()
+	configWriteMu.Unlock()
+	for _, s := range subscribers {
+		if atomic.LoadInt32(&s.removed) == 0 {
+			s.f(*old, c)
+		}
+	}
+}
+
+// ConfigSnapshot returns a copy of the global tracing configuration.
+func ConfigSnapshot() Config {
+	return *config.Load().(*Config)
+}
+
+// SubscribeConfig calls f with the previous and the new configuration
+// every time ApplyConfig changes the global tracing configuration, or is
+// given a sampler, until the returned function is called. f is called by
+// ApplyConfig, which it must not call itself, and must return quickly. It
+// may unsubscribe, or subscribe other functions, which are called from
+// the next change on.
+func SubscribeConfig(f func(old, new Config)) (unsubscribe func()) {
+	s := &configSubscriber{f: f}
+	configWriteMu.Lock()
+	configSubscribers[s] = struct{}{}
+	configWriteMu.Unlock()
+	return func() {
+		atomic.StoreInt32(&s.removed, 1)
+		configWriteMu.Lock()
+		delete(configSubscribers, s)
+		configWriteMu.Unlock()
+	}
+}
+
+// Diff returns the names of the fields of c whose value differs in other,
+// in the order they are declared. DefaultSampler differs if ApplyConfig
+// was given a sampler in between, even one equal to the previous sampler.
+// Otherwise, samplers are compared by function,
+// so that samplers returned by the same constructor, e.g., two
+// ProbabilitySamplers, are considered the same.
+func (c Config) Diff(other Config) []string {
+	var fields []string
+	a, b := reflect.ValueOf(c), reflect.ValueOf(other)
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fa, fb := a.Field(i), b.Field(i)
+		var equal bool
+		if fa.Kind() == reflect.Func {
+			equal = fa.Pointer() == fb.Pointer()
+		} else {
+			equal = reflect.DeepEqual(fa.Interface(), fb.Interface())
+		}
+		if field.Name == "DefaultSampler" && c.samplerGeneration != other.samplerGeneration {
+			equal = false
+		}
+		if !equal {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
 }
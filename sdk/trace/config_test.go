@@ -17,6 +17,7 @@ package trace
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestApplyConfig(t *testing.T) {
@@ -79,3 +80,82 @@ func TestApplyConfig(t *testing.T) {
 
 	}
 }
+
+func TestConfigDiff(t *testing.T) {
+	a := Config{MaxEventsPerSpan: 1, DefaultSampler: AlwaysSample()}
+	b := a
+	if diff := a.Diff(b); len(diff) != 0 {
+		t.Errorf("Diff of equal configs = %v, want none", diff)
+	}
+	b.MaxEventsPerSpan = 2
+	b.DefaultSampler = NeverSample()
+	b.ExportTimeout = time.Second
+	want := []string{"DefaultSampler", "MaxEventsPerSpan", "ExportTimeout"}
+	if diff := a.Diff(b); !reflect.DeepEqual(diff, want) {
+		t.Errorf("Diff() = %v, want %v", diff, want)
+	}
+}
+
+func TestSubscribeConfig(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+
+	var calls int
+	var got Config
+	unsubscribe := SubscribeConfig(func(old, new Config) {
+		calls++
+		got = new
+		if old.MaxLinksPerSpan != prev.MaxLinksPerSpan {
+			t.Errorf("old MaxLinksPerSpan = %d, want %d", old.MaxLinksPerSpan, prev.MaxLinksPerSpan)
+		}
+	})
+	ApplyConfig(Config{MaxLinksPerSpan: prev.MaxLinksPerSpan + 1})
+	if calls != 1 || got.MaxLinksPerSpan != prev.MaxLinksPerSpan+1 {
+		t.Fatalf("got %d calls with %+v, want one with the new limit", calls, got)
+	}
+	if snap := ConfigSnapshot(); snap.MaxLinksPerSpan != got.MaxLinksPerSpan {
+		t.Errorf("ConfigSnapshot().MaxLinksPerSpan = %d, want %d", snap.MaxLinksPerSpan, got.MaxLinksPerSpan)
+	}
+
+	// Unchanged configurations are not reported.
+	ApplyConfig(Config{MaxLinksPerSpan: prev.MaxLinksPerSpan + 1})
+	unsubscribe()
+	ApplyConfig(Config{MaxLinksPerSpan: prev.MaxLinksPerSpan + 2})
+	if calls != 1 {
+		t.Errorf("got %d calls, want only the first change reported", calls)
+	}
+}
+
+func TestSubscribeConfigSampler(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+
+	var diffs [][]string
+	unsubscribe := SubscribeConfig(func(old, new Config) {
+		diffs = append(diffs, old.Diff(new))
+	})
+	defer unsubscribe()
+	ApplyConfig(Config{DefaultSampler: ProbabilitySampler(0.5)})
+	ApplyConfig(Config{DefaultSampler: ProbabilitySampler(0.1)})
+	want := [][]string{{"DefaultSampler"}, {"DefaultSampler"}}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("got diffs %v, want %v", diffs, want)
+	}
+}
+
+func TestSubscribeConfigUnsubscribe(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+
+	var calls int
+	var unsubscribe func()
+	unsubscribe = SubscribeConfig(func(old, new Config) {
+		calls++
+		unsubscribe()
+	})
+	ApplyConfig(Config{MaxLinksPerSpan: prev.MaxLinksPerSpan + 1})
+	ApplyConfig(Config{MaxLinksPerSpan: prev.MaxLinksPerSpan + 2})
+	if calls != 1 {
+		t.Errorf("got %d calls, want one before unsubscribing", calls)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/api/errorhandler"
+)
+
+var (
+	contextDiagnostics int32 // access atomically
+
+	activeSpansMu sync.Mutex
+	// activeSpans counts unfinished spans per goroutine ID.
+	activeSpans = map[uint64]int{}
+)
+
+// SetContextDiagnostics enables or disables detection of root spans
+// started from a context without a span, e.g., context.Background(), by
+// a goroutine that has unfinished spans. This usually means a context
+// was dropped somewhere inside a request and the trace is broken. Each
+// detected span is reported to the errorhandler package.
+//
+// Detection relies on goroutine IDs, which are expensive to look up, so
+// it is meant for development and testing only. Spans that are meant to
+// start a new trace can be marked with NewRootContext.
+func SetContextDiagnostics(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&contextDiagnostics, v)
+}
+
+type newRootKey struct{}
+
+// NewRootContext marks ctx as intentionally starting a new trace, which
+// suppresses the diagnostics enabled by SetContextDiagnostics.
+func NewRootContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, newRootKey{}, true)
+}
+
+func isNewRootContext(ctx context.Context) bool {
+	v, _ := ctx.Value(newRootKey{}).(bool)
+	return v
+}
+
+// checkContext tracks s as active in the current goroutine and reports
+// when a root span is started while the goroutine has other active spans.
+func checkContext(ctx context.Context, s *span, name string, isRoot bool) {
+	if atomic.LoadInt32(&contextDiagnostics) == 0 {
+		return
+	}
+	gid := goroutineID()
+	if gid == 0 {
+		return
+	}
+
+	activeSpansMu.Lock()
+	active := activeSpans[gid]
+	activeSpans[gid]++
+	activeSpansMu.Unlock()
+	atomic.StoreUint64(&s.goroutineID, gid)
+
+	if isRoot && active > 0 && !isNewRootContext(ctx) {
+		errorhandler.Handle(fmt.Errorf("span %q started from a context without a span while goroutine %d has %d active span(s)", name, gid, active))
+	}
+}
+
+// untrackSpan removes s from the active spans of the goroutine it was
+// started in.
+func untrackSpan(s *span) {
+	gid := atomic.SwapUint64(&s.goroutineID, 0)
+	if gid == 0 {
+		return
+	}
+	activeSpansMu.Lock()
+	defer activeSpansMu.Unlock()
+	if activeSpans[gid] <= 1 {
+		delete(activeSpans, gid)
+	} else {
+		activeSpans[gid]--
+	}
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID returns the ID of the current goroutine, or 0 if it cannot
+// be determined.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	if !bytes.HasPrefix(b, goroutinePrefix) {
+		return 0
+	}
+	b = b[len(goroutinePrefix):]
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// limitReports rate-limits the reports of spans that dropped attributes,
+// events or links because of their limits.
+var limitReports = &reportLimiter{interval: time.Second}
+
+// reportLimiter allows one report per interval and counts the others.
+type reportLimiter struct {
+	interval time.Duration
+
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+// allow reports whether a report can be made at now, along with the
+// number of reports suppressed since the last one made.
+func (l *reportLimiter) allow(now time.Time) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() && now.Sub(l.last) < l.interval {
+		l.suppressed++
+		return false, 0
+	}
+	suppressed := l.suppressed
+	l.last = now
+	l.suppressed = 0
+	return true, suppressed
+}
+
+// droppedAttributes counts the attributes evicted from spans because of
+// Config.MaxAttributesPerSpan. Access atomically.
+var droppedAttributes uint64
+
+// DroppedAttributes returns the number of attributes evicted from spans,
+// across all spans, because of Config.MaxAttributesPerSpan. The count of
+// each span is in SpanData.DroppedAttributeCount.
+func DroppedAttributes() uint64 {
+	return atomic.LoadUint64(&droppedAttributes)
+}
+
+// reportDropped reports to the errorhandler package that s dropped data
+// because of its limits, naming each limit hit, so that truncation is not
+// silent. It is called once, when s ends, and at most one span is
+// reported per second. s.mu must not be held.
+func reportDropped(s *span) {
+	s.mu.Lock()
+	var dropped []string
+	if s.lruAttributes != nil && s.lruAttributes.droppedCount > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d attributes (MaxAttributesPerSpan %d)", s.lruAttributes.droppedCount, s.cfg.MaxAttributesPerSpan))
+	}
+	if s.messageEvents != nil && s.messageEvents.droppedCount > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d events (MaxEventsPerSpan %d)", s.messageEvents.droppedCount, s.cfg.MaxEventsPerSpan))
+	}
+	if s.eventRateLimiter != nil && s.eventRateLimiter.droppedCount > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d events (MaxEventsPerSecondPerSpan %d)", s.eventRateLimiter.droppedCount, s.cfg.MaxEventsPerSecondPerSpan))
+	}
+	if s.droppedEventAttributes > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d event attributes (MaxAttributesPerEvent %d)", s.droppedEventAttributes, s.cfg.MaxAttributesPerEvent))
+	}
+	if s.links != nil && s.links.droppedCount > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d links (MaxLinksPerSpan %d)", s.links.droppedCount, s.cfg.MaxLinksPerSpan))
+	}
+	name := s.data.Name
+	s.mu.Unlock()
+	if len(dropped) == 0 {
+		return
+	}
+
+	ok, suppressed := limitReports.allow(time.Now())
+	if !ok {
+		return
+	}
+	msg := fmt.Sprintf("span %q dropped %s", name, strings.Join(dropped, ", "))
+	if suppressed > 0 {
+		msg += fmt.Sprintf(" (%d more spans dropped data since the last report)", suppressed)
+	}
+	errorhandler.Handle(errors.New(msg))
+}
@@ -0,0 +1,143 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/errorhandler"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+func TestContextDiagnostics(t *testing.T) {
+	var errs []error
+	errorhandler.Set(func(err error) { errs = append(errs, err) })
+	defer errorhandler.Set(nil)
+	SetContextDiagnostics(true)
+	defer SetContextDiagnostics(false)
+
+	tracer := apitrace.GlobalTracer()
+	ctx, request := tracer.Start(context.Background(), "request")
+
+	_, child := tracer.Start(ctx, "child")
+	child.Finish()
+	if len(errs) != 0 {
+		t.Fatalf("got errors %v for child span, want none", errs)
+	}
+
+	_, detached := tracer.Start(context.Background(), "detached")
+	detached.Finish()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors for detached span, want 1", len(errs))
+	}
+
+	_, root := tracer.Start(NewRootContext(context.Background()), "new-root")
+	root.Finish()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors for marked root span, want 1", len(errs))
+	}
+
+	request.Finish()
+	_, next := tracer.Start(context.Background(), "next-request")
+	next.Finish()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors after request finished, want 1", len(errs))
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	id := goroutineID()
+	if id == 0 {
+		t.Fatal("goroutineID() = 0")
+	}
+	other := make(chan uint64)
+	go func() { other <- goroutineID() }()
+	if got := <-other; got == id || got == 0 {
+		t.Errorf("goroutineID() in new goroutine = %d; want non-zero ID other than %d", got, id)
+	}
+}
+
+func TestReportDropped(t *testing.T) {
+	var errs []error
+	errorhandler.Set(func(err error) { errs = append(errs, err) })
+	defer errorhandler.Set(nil)
+	prevReports := limitReports
+	limitReports = &reportLimiter{interval: time.Hour}
+	defer func() { limitReports = prevReports }()
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AlwaysSample(), MaxAttributesPerSpan: 1, MaxEventsPerSpan: 1})
+
+	tr := &tracer{}
+	_, within := tr.Start(context.Background(), "within")
+	within.SetAttribute(key.New("a").Int(1))
+	within.Finish()
+	if len(errs) != 0 {
+		t.Fatalf("got errors %v for a span within its limits, want none", errs)
+	}
+
+	ctx := context.Background()
+	_, over := tr.Start(ctx, "over")
+	over.SetAttributes(key.New("a").Int(1), key.New("b").Int(2), key.New("c").Int(3))
+	over.Event(ctx, "first")
+	over.Event(ctx, "second")
+	over.Finish()
+	over.Finish()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+	msg := errs[0].Error()
+	for _, want := range []string{`span "over"`, "2 attributes (MaxAttributesPerSpan 1)", "1 events (MaxEventsPerSpan 1)"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("got error %q, want it to contain %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "links") {
+		t.Errorf("got error %q, want no links reported", msg)
+	}
+
+	_, again := tr.Start(ctx, "again")
+	again.SetAttributes(key.New("a").Int(1), key.New("b").Int(2))
+	again.Finish()
+	if len(errs) != 1 {
+		t.Errorf("got %d errors within the report interval, want 1", len(errs))
+	}
+}
+
+func TestReportLimiter(t *testing.T) {
+	l := &reportLimiter{interval: time.Second}
+	start := time.Unix(0, 0)
+	for _, tt := range []struct {
+		at         time.Duration
+		ok         bool
+		suppressed int
+	}{
+		{0, true, 0},
+		{100 * time.Millisecond, false, 0},
+		{500 * time.Millisecond, false, 0},
+		{time.Second, true, 2},
+		{1500 * time.Millisecond, false, 0},
+		{3 * time.Second, true, 1},
+	} {
+		ok, suppressed := l.allow(start.Add(tt.at))
+		if ok != tt.ok || suppressed != tt.suppressed {
+			t.Errorf("allow at %v = %v, %d, want %v, %d", tt.at, ok, suppressed, tt.ok, tt.suppressed)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/errorhandler"
+)
+
+var (
+	liveSpanTracking int32 // access atomically
+
+	liveSpansMu sync.Mutex
+	// liveSpans holds the unfinished recording spans of each trace.
+	liveSpans = map[core.TraceID]map[*span]struct{}{}
+)
+
+// SetLiveSpanTracking enables or disables tracking of unfinished spans,
+// which DumpTrace needs. Tracking adds a global lock to starting and
+// finishing recording spans.
+func SetLiveSpanTracking(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&liveSpanTracking, v)
+}
+
+// DumpTrace returns the spans of a trace that are buffered locally,
+// ordered by start time, for debug endpoints investigating a stuck
+// request. It returns the finished spans still queued in a
+// BatchSpanProcessor, and snapshots of the unfinished spans if
+// SetLiveSpanTracking was enabled when they were started. The EndTime of
+// the snapshots is zero.
+func DumpTrace(id core.TraceID) []*SpanData {
+	return dump(id, false)
+}
+
+// DumpAll returns the spans of every trace that are buffered locally, as
+// DumpTrace does for one trace, e.g., to save the telemetry that was not
+// exported yet when the process crashes.
+func DumpAll() []*SpanData {
+	return dump(core.TraceID{}, true)
+}
+
+func dump(id core.TraceID, all bool) []*SpanData {
+	liveSpansMu.Lock()
+	var spans []*span
+	for tid, trace := range liveSpans {
+		if !all && tid != id {
+			continue
+		}
+		for s := range trace {
+			spans = append(spans, s)
+		}
+	}
+	liveSpansMu.Unlock()
+
+	dump := make([]*SpanData, len(spans))
+	for i, s := range spans {
+		dump[i] = s.makeSpanData()
+	}
+
+	batchSpanProcessorsMu.Lock()
+	for bsp := range batchSpanProcessors {
+		dump = append(dump, bsp.queued(id, all)...)
+	}
+	batchSpanProcessorsMu.Unlock()
+	sort.Slice(dump, func(i, j int) bool {
+		return dump[i].StartTime.Before(dump[j].StartTime)
+	})
+	return dump
+}
+
+// WriteDump writes the spans returned by DumpAll to w as JSON, one span
+// per line. Unfinished spans have no "end" field.
+func WriteDump(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, sd := range DumpAll() {
+		if err := enc.Encode(newDumpedSpan(sd)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteDumpFile writes the spans returned by DumpAll to the file at path,
+// as WriteDump does, replacing the file if it exists.
+func WriteDumpFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteDump(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// DumpOnPanic writes the spans returned by DumpAll to the file at path if
+// the goroutine is panicking, then panics again. Defer it at the top of
+// main, or of goroutines that may panic:
+//
+//	defer trace.DumpOnPanic("/var/run/app/spans.json")
+//
+// Errors writing the file are passed to errorhandler.Handle.
+func DumpOnPanic(path string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if err := WriteDumpFile(path); err != nil {
+		errorhandler.Handle(fmt.Errorf("trace: dumping spans on panic: %v", err))
+	}
+	panic(r)
+}
+
+// dumpedSpan is the JSON representation of a span written by WriteDump.
+type dumpedSpan struct {
+	TraceID       string                 `json:"trace_id"`
+	SpanID        string                 `json:"span_id"`
+	ParentSpanID  string                 `json:"parent_span_id,omitempty"`
+	Name          string                 `json:"name"`
+	Kind          string                 `json:"kind,omitempty"`
+	Start         time.Time              `json:"start"`
+	End           *time.Time             `json:"end,omitempty"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Events        []dumpedEvent          `json:"events,omitempty"`
+	Status        string                 `json:"status,omitempty"`
+	StatusMessage string                 `json:"status_message,omitempty"`
+	Resource      map[string]interface{} `json:"resource,omitempty"`
+	Library       string                 `json:"library,omitempty"`
+}
+
+type dumpedEvent struct {
+	Time       time.Time              `json:"time"`
+	Message    string                 `json:"message"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+func newDumpedSpan(sd *SpanData) dumpedSpan {
+	d := dumpedSpan{
+		TraceID:       sd.SpanContext.TraceIDString(),
+		SpanID:        sd.SpanContext.SpanIDString(),
+		Name:          sd.Name,
+		Start:         sd.StartTime,
+		Attributes:    dumpedValues(sd.Attributes),
+		StatusMessage: sd.StatusMessage,
+		Resource:      dumpedAttributes(sd.Resource),
+		Library:       sd.InstrumentationLibrary.Name,
+	}
+	if sd.ParentSpanID != 0 {
+		d.ParentSpanID = fmt.Sprintf("%016x", sd.ParentSpanID)
+	}
+	if sd.SpanKind != 0 {
+		d.Kind = sd.SpanKind.String()
+	}
+	if !sd.EndTime.IsZero() {
+		end := sd.EndTime
+		d.End = &end
+	}
+	if sd.Status != 0 {
+		d.Status = sd.Status.String()
+	}
+	for _, ev := range sd.MessageEvents {
+		d.Events = append(d.Events, dumpedEvent{
+			Time:       ev.time,
+			Message:    ev.msg,
+			Attributes: dumpedAttributes(ev.attributes),
+		})
+	}
+	return d
+}
+
+// dumpedValues returns attrs with the core.Values of the snapshots of
+// unfinished spans replaced by the values they hold.
+func dumpedValues(attrs map[string]interface{}) map[string]interface{} {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		if cv, ok := v.(core.Value); ok {
+			v = dumpedValue(cv)
+		}
+		m[k] = v
+	}
+	return m
+}
+
+func dumpedAttributes(kvs []core.KeyValue) map[string]interface{} {
+	if len(kvs) == 0 {
+		return nil
+	}
+	m := make(map[string]interface{}, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key.Variable.Name] = dumpedValue(kv.Value)
+	}
+	return m
+}
+
+// dumpedValue returns the Go value held by v, or its string
+// representation for bytes and for floats JSON cannot represent.
+func dumpedValue(v core.Value) interface{} {
+	switch v.Type {
+	case core.BYTES:
+		return v.Emit()
+	case core.FLOAT32, core.FLOAT64:
+		if !finite(v.Float64) {
+			return v.Emit()
+		}
+	case core.FLOAT64S:
+		for _, f := range v.Float64s {
+			if !finite(f) {
+				return v.Emit()
+			}
+		}
+	}
+	return v.AsInterface()
+}
+
+func finite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// trackLiveSpan adds s to the live spans if tracking is enabled and s is
+// recording.
+func trackLiveSpan(s *span) {
+	if atomic.LoadInt32(&liveSpanTracking) == 0 || !s.IsRecordingEvents() {
+		return
+	}
+	s.live = true
+	id := s.spanContext.TraceID
+	liveSpansMu.Lock()
+	defer liveSpansMu.Unlock()
+	trace, ok := liveSpans[id]
+	if !ok {
+		trace = make(map[*span]struct{})
+		liveSpans[id] = trace
+	}
+	trace[s] = struct{}{}
+}
+
+// untrackLiveSpan removes s from the live spans.
+func untrackLiveSpan(s *span) {
+	if !s.live {
+		return
+	}
+	id := s.spanContext.TraceID
+	liveSpansMu.Lock()
+	defer liveSpansMu.Unlock()
+	delete(liveSpans[id], s)
+	if len(liveSpans[id]) == 0 {
+		delete(liveSpans, id)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+func TestDumpTrace(t *testing.T) {
+	SetLiveSpanTracking(true)
+	defer SetLiveSpanTracking(false)
+
+	ctx, root := apitrace.GlobalTracer().Start(context.Background(), "root",
+		apitrace.ChildOf(remoteSpanContext()))
+	_, child := apitrace.GlobalTracer().Start(ctx, "child")
+	child.Event(ctx, "waiting")
+
+	dump := DumpTrace(tid)
+	if len(dump) != 2 {
+		t.Fatalf("DumpTrace returned %d spans; want 2", len(dump))
+	}
+	if dump[0].Name != "root" || dump[1].Name != "child" {
+		t.Errorf("DumpTrace returned %q, %q; want root, child", dump[0].Name, dump[1].Name)
+	}
+	if len(dump[1].MessageEvents) != 1 {
+		t.Errorf("child snapshot has %d events; want 1", len(dump[1].MessageEvents))
+	}
+
+	child.Finish()
+	root.Finish()
+	if dump := DumpTrace(tid); len(dump) != 0 {
+		t.Errorf("DumpTrace returned %d spans after they finished; want 0", len(dump))
+	}
+}
+
+func TestWriteDump(t *testing.T) {
+	SetLiveSpanTracking(true)
+	defer SetLiveSpanTracking(false)
+
+	ctx, span := apitrace.GlobalTracer().Start(context.Background(), "dumped",
+		apitrace.ChildOf(remoteSpanContext()))
+	span.SetAttribute(key.New("k").String("v"))
+	span.Event(ctx, "waiting", key.New("n").Int(1))
+	defer span.Finish()
+
+	var buf bytes.Buffer
+	if err := WriteDump(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var got map[string]interface{}
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got["name"] != "dumped" {
+			continue
+		}
+		if got["trace_id"] != span.SpanContext().TraceIDString() || got["span_id"] != span.SpanContext().SpanIDString() {
+			t.Errorf("dumped span %v/%v, want %s/%s", got["trace_id"], got["span_id"],
+				span.SpanContext().TraceIDString(), span.SpanContext().SpanIDString())
+		}
+		if _, ok := got["end"]; ok {
+			t.Errorf("unfinished span has end %v", got["end"])
+		}
+		if attrs, _ := got["attributes"].(map[string]interface{}); attrs["k"] != "v" {
+			t.Errorf("dumped attributes %v, want k=v", got["attributes"])
+		}
+		if events, _ := got["events"].([]interface{}); len(events) != 1 {
+			t.Errorf("dumped events %v, want one", got["events"])
+		}
+		return
+	}
+	t.Error("the span was not dumped")
+}
@@ -0,0 +1,64 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ExportEnricher adds or modifies the attributes of finished spans before
+// they are exported, e.g., to add the git SHA or build ID of the binary.
+// The Resource of each span holds the resources of the tracer that
+// started it.
+//
+// Enrichers run once per batch, in the export path: on each batch of a
+// BatchSpanProcessor, and on each span passed to an exporter registered
+// with RegisterExporter, which is a batch of one. They add no cost to
+// starting spans and no cost at all to unsampled spans. The spans are
+// copies owned by the export and may be modified; their Attributes maps
+// may be nil.
+type ExportEnricher func(spans []*SpanData)
+
+var (
+	enricherMu sync.Mutex
+	enrichers  atomic.Value // []ExportEnricher
+)
+
+// RegisterExportEnricher adds e to the enrichers run before sampled spans
+// are exported. Enrichers run in the order they were registered.
+func RegisterExportEnricher(e ExportEnricher) {
+	enricherMu.Lock()
+	defer enricherMu.Unlock()
+	old, _ := enrichers.Load().([]ExportEnricher)
+	new := make([]ExportEnricher, len(old), len(old)+1)
+	copy(new, old)
+	enrichers.Store(append(new, e))
+}
+
+// ResetExportEnrichers removes all registered enrichers.
+func ResetExportEnrichers() {
+	enricherMu.Lock()
+	defer enricherMu.Unlock()
+	enrichers.Store([]ExportEnricher(nil))
+}
+
+// enrich runs the registered enrichers on a batch of spans.
+func enrich(spans ...*SpanData) {
+	es, _ := enrichers.Load().([]ExportEnricher)
+	for _, e := range es {
+		e(spans)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+func TestExportEnricher(t *testing.T) {
+	defer ResetExportEnrichers()
+
+	var gotResource []core.KeyValue
+	RegisterExportEnricher(func(spans []*SpanData) {
+		for _, sd := range spans {
+			gotResource = sd.Resource
+			if sd.Attributes == nil {
+				sd.Attributes = make(map[string]interface{})
+			}
+			sd.Attributes["build.id"] = key.New("build.id").String("abc123").Value
+		}
+	})
+	RegisterExportEnricher(func(spans []*SpanData) {
+		for _, sd := range spans {
+			if _, ok := sd.Attributes["build.id"]; !ok {
+				t.Error("enrichers did not run in registration order")
+			}
+		}
+	})
+
+	tr := (&tracer{}).WithResources(key.New("service").String("svc"))
+	_, span := tr.Start(context.Background(), "span0",
+		apitrace.ChildOf(remoteSpanContext()),
+		apitrace.WithRecordEvents(),
+	)
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := got.Attributes["build.id"]; !ok || v.(core.Value).String != "abc123" {
+		t.Errorf("build.id attribute = %v; want abc123", v)
+	}
+	if len(gotResource) != 1 || gotResource[0].Key.Variable.Name != "service" {
+		t.Errorf("enricher resource = %v; want the tracer's resources", gotResource)
+	}
+}
+
+func TestExportEnricherOncePerBatch(t *testing.T) {
+	defer ResetExportEnrichers()
+
+	var batches []int
+	RegisterExportEnricher(func(spans []*SpanData) {
+		batches = append(batches, len(spans))
+	})
+
+	e := &batchExporter{}
+	bsp, err := NewBatchSpanProcessor(e, WithScheduledDelay(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bsp.ExportSpan(&SpanData{Name: "a"})
+	bsp.ExportSpan(&SpanData{Name: "b"})
+	bsp.Shutdown()
+
+	if len(batches) != 1 || batches[0] != 2 {
+		t.Errorf("enricher saw batches of sizes %v; want one batch of 2", batches)
+	}
+}
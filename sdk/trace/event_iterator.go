@@ -0,0 +1,83 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"time"
+
+	"go.opentelemetry.io/api/core"
+)
+
+// MessageEvent is a message event recorded on a span.
+type MessageEvent interface {
+	Message() string
+	Attributes() []core.KeyValue
+	Time() time.Time
+	DroppedAttributeCount() int
+}
+
+// EventIterator iterates over the message events of a SpanData without
+// copying them, so that exporters can stream spans with many events.
+//
+//	it := sd.Events()
+//	for it.Next() {
+//		ev := it.Event()
+//		...
+//	}
+type EventIterator struct {
+	events []event
+	i      int
+}
+
+// Events returns an iterator over the message events of sd, oldest first.
+func (sd *SpanData) Events() EventIterator {
+	return EventIterator{events: sd.MessageEvents, i: -1}
+}
+
+// Next advances to the next event and reports whether there is one.
+func (it *EventIterator) Next() bool {
+	if it.i < len(it.events) {
+		it.i++
+	}
+	return it.i < len(it.events)
+}
+
+// Event returns the current event. It must only be called after Next
+// returned true.
+func (it *EventIterator) Event() MessageEvent {
+	return &it.events[it.i]
+}
+
+// Index returns the position of the current event among the events of
+// the span.
+func (it *EventIterator) Index() int {
+	return it.i
+}
+
+// Len returns the total number of events.
+func (it *EventIterator) Len() int {
+	return len(it.events)
+}
+
+// AddMessageEvent appends a message event to sd. It is meant for code
+// building SpanData outside of the SDK, e.g., adapters from other
+// pipelines.
+func (sd *SpanData) AddMessageEvent(t time.Time, msg string, attrs ...core.KeyValue) {
+	sd.MessageEvents = append(sd.MessageEvents, event{
+		msg:        msg,
+		attributes: attrs,
+		time:       t,
+	})
+}
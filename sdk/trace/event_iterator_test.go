@@ -0,0 +1,43 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"testing"
+)
+
+func TestEventIterator(t *testing.T) {
+	sd := &SpanData{MessageEvents: []event{{msg: "a"}, {msg: "b"}}}
+
+	var got []string
+	it := sd.Events()
+	for it.Next() {
+		if it.Index() != len(got) {
+			t.Errorf("Index() = %d; want %d", it.Index(), len(got))
+		}
+		got = append(got, it.Event().Message())
+	}
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("iterated over %v; want [a b]", got)
+	}
+	if it.Next() {
+		t.Error("Next() returned true after the last event")
+	}
+
+	empty := (&SpanData{}).Events()
+	if empty.Next() {
+		t.Error("Next() returned true for a span without events")
+	}
+}
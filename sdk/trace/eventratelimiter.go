@@ -0,0 +1,45 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import "time"
+
+// eventRateLimiter caps the number of events accepted per second using a
+// fixed one second window.
+type eventRateLimiter struct {
+	limit        int
+	windowStart  time.Time
+	count        int
+	droppedCount int
+}
+
+func newEventRateLimiter(limit int) *eventRateLimiter {
+	return &eventRateLimiter{
+		limit: limit,
+	}
+}
+
+func (rl *eventRateLimiter) allow(now time.Time) bool {
+	if now.Sub(rl.windowStart) >= time.Second {
+		rl.windowStart = now
+		rl.count = 0
+	}
+	if rl.count >= rl.limit {
+		rl.droppedCount++
+		return false
+	}
+	rl.count++
+	return true
+}
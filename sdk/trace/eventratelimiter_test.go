@@ -0,0 +1,41 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventRateLimiter(t *testing.T) {
+	rl := newEventRateLimiter(2)
+	start := time.Now()
+
+	for i, want := range []bool{true, true, false, false} {
+		if got := rl.allow(start.Add(time.Duration(i) * time.Millisecond)); got != want {
+			t.Errorf("allow #%d = %v; want %v", i, got, want)
+		}
+	}
+	if got, want := rl.droppedCount, 2; got != want {
+		t.Errorf("got drop count %d want %d", got, want)
+	}
+
+	if !rl.allow(start.Add(time.Second)) {
+		t.Error("allow in next window = false; want true")
+	}
+	if got, want := rl.droppedCount, 2; got != want {
+		t.Errorf("got drop count %d want %d", got, want)
+	}
+}
@@ -15,11 +15,15 @@
 package trace
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/errorhandler"
+	apitrace "go.opentelemetry.io/api/trace"
 	"google.golang.org/grpc/codes"
 )
 
@@ -34,11 +38,41 @@ type Exporter interface {
 	ExportSpan(s *SpanData)
 }
 
-type exportersMap map[Exporter]struct{}
+// SpanExporter exports finished spans, e.g., in a single RPC. It only
+// depends on SpanData, so exporters need not know how spans are recorded.
+//
+// Use it with NewBatchSpanProcessor, or with NewSimpleSpanProcessor to
+// export each span when it ends.
+type SpanExporter interface {
+	ExportSpans(ctx context.Context, spans []*SpanData) error
+}
+
+// ContextExporter is an Exporter whose exports may block, e.g., on an RPC.
+//
+// ExportSpanWithContext is called instead of ExportSpan with a context
+// that is canceled once the configured ExportTimeout elapses. The
+// exporter should abort any in-flight work when the context is done and
+// return the context's error. Exports run on a goroutine of the exporter,
+// not on the one finishing the span, and are retried up to ExportRetries
+// times when they time out.
+type ContextExporter interface {
+	Exporter
+	ExportSpanWithContext(ctx context.Context, s *SpanData) error
+}
+
+// exportQueueSize is the number of spans buffered for a ContextExporter
+// before new spans are dropped.
+const exportQueueSize = DefaultMaxQueueSize
+
+// exportersMap maps the registered exporters to the queues running the
+// exports of ContextExporters. Other exporters map to nil.
+type exportersMap map[Exporter]*exportQueue
 
 var (
 	exporterMu sync.Mutex
 	exporters  atomic.Value
+
+	droppedSpans uint64 // access atomically
 )
 
 // RegisterExporter adds to the list of Exporters that will receive sampled
@@ -49,35 +83,149 @@ func RegisterExporter(e Exporter) {
 	exporterMu.Lock()
 	defer exporterMu.Unlock()
 	new := make(exportersMap)
-	if old, ok := exporters.Load().(exportersMap); ok {
-		for k, v := range old {
-			new[k] = v
-		}
+	old, _ := exporters.Load().(exportersMap)
+	for k, v := range old {
+		new[k] = v
+	}
+	if _, ok := old[e]; ok {
+		return
+	}
+	var q *exportQueue
+	if ce, ok := e.(ContextExporter); ok {
+		q = newExportQueue(ce)
 	}
-	new[e] = struct{}{}
+	new[e] = q
 	exporters.Store(new)
 }
 
 // UnregisterExporter removes from the list of Exporters the Exporter that was
-// registered with the given name.
+// registered with the given name. For a ContextExporter, it waits until
+// the spans queued for it are exported.
 func UnregisterExporter(e Exporter) {
 	exporterMu.Lock()
-	defer exporterMu.Unlock()
 	new := make(exportersMap)
-	if old, ok := exporters.Load().(exportersMap); ok {
-		for k, v := range old {
-			new[k] = v
-		}
+	old, _ := exporters.Load().(exportersMap)
+	for k, v := range old {
+		new[k] = v
 	}
+	q := old[e]
 	delete(new, e)
 	exporters.Store(new)
+	exporterMu.Unlock()
+
+	if q != nil {
+		q.stop()
+	}
+}
+
+// DroppedSpans returns the number of spans a ContextExporter or a
+// BatchSpanProcessor failed to export, because of an error, because
+// every attempt timed out, or because its queue was full.
+func DroppedSpans() uint64 {
+	return atomic.LoadUint64(&droppedSpans)
+}
+
+// exportWithRetry calls export with a context bounded by the configured
+// ExportTimeout, and calls it again up to ExportRetries times while the
+// attempts time out.
+func exportWithRetry(export func(ctx context.Context) error) error {
+	cfg := config.Load().(*Config)
+	var err error
+	for attempt := 0; attempt <= cfg.ExportRetries; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ExportTimeout)
+		err = export(ctx)
+		// Check the context rather than err, which exporters may wrap.
+		timedOut := ctx.Err() == context.DeadlineExceeded
+		cancel()
+		if err == nil || !timedOut {
+			break
+		}
+	}
+	return err
+}
+
+// exportQueue runs the exports of a ContextExporter on a goroutine of its
+// own, so that export timeouts and retries add no latency to Finish.
+type exportQueue struct {
+	e ContextExporter
+
+	mu      sync.RWMutex
+	spans   chan *SpanData
+	stopped bool
+	done    chan struct{}
+}
+
+func newExportQueue(e ContextExporter) *exportQueue {
+	q := &exportQueue{
+		e:     e,
+		spans: make(chan *SpanData, exportQueueSize),
+		done:  make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// add queues sd for export. It never blocks; sd is dropped if the queue
+// is full or stopped.
+func (q *exportQueue) add(sd *SpanData) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if !q.stopped {
+		select {
+		case q.spans <- sd:
+			return
+		default:
+		}
+	}
+	dropSpans(1, fmt.Errorf("dropped span %q: export queue is full", sd.Name))
+}
+
+func (q *exportQueue) run() {
+	defer close(q.done)
+	for sd := range q.spans {
+		enrich(sd)
+		err := exportWithRetry(func(ctx context.Context) error {
+			return q.e.ExportSpanWithContext(ctx, sd)
+		})
+		if err != nil {
+			dropSpans(1, fmt.Errorf("dropped span %q: %v", sd.Name, err))
+		}
+	}
+}
+
+// stop exports the queued spans and waits for the goroutine to exit.
+func (q *exportQueue) stop() {
+	q.mu.Lock()
+	if !q.stopped {
+		q.stopped = true
+		close(q.spans)
+	}
+	q.mu.Unlock()
+	<-q.done
+}
+
+func dropSpans(n int, err error) {
+	atomic.AddUint64(&droppedSpans, uint64(n))
+	errorhandler.Handle(err)
+}
+
+// exportSpan passes sd to the exporter, or queues it for a
+// ContextExporter.
+func exportSpan(e Exporter, q *exportQueue, sd *SpanData) {
+	if q != nil {
+		q.add(sd)
+		return
+	}
+	enrich(sd)
+	e.ExportSpan(sd)
 }
 
-// SpanData contains all the information collected by a span.
+// SpanData contains all the information collected by a span. It is what
+// Exporters, SpanExporters and SpanProcessors receive.
 type SpanData struct {
 	SpanContext  core.SpanContext
 	ParentSpanID uint64
-	SpanKind     int
+	SpanKind     apitrace.SpanKind
 	Name         string
 	StartTime    time.Time
 	// The wall clock time of EndTime will be adjusted to always be offset
@@ -87,11 +235,57 @@ type SpanData struct {
 	Attributes               map[string]interface{}
 	MessageEvents            []event
 	Status                   codes.Code
+	StatusMessage            string
 	HasRemoteParent          bool
 	DroppedAttributeCount    int
 	DroppedMessageEventCount int
 	DroppedLinkCount         int
 
+	// Links holds the links added with apitrace.AddLink, oldest first.
+	Links []apitrace.Link
+
 	// ChildSpanCount holds the number of child span created for this span.
 	ChildSpanCount int
+
+	// Resource holds the resources of the tracer that started the span,
+	// merged with Config.Resource.
+	Resource []core.KeyValue
+
+	// InstrumentationLibrary identifies the library whose tracer started
+	// the span. It is zero for the tracer of the application.
+	InstrumentationLibrary InstrumentationLibrary
+
+	// ChildSpanDuration holds the summed duration of the recorded child
+	// spans started in this process that ended before this span. It is
+	// only computed after SetChildSpanDurations(true).
+	ChildSpanDuration time.Duration
+}
+
+// clone returns a copy of sd that shares no maps or slices with it.
+func (sd *SpanData) clone() *SpanData {
+	c := *sd
+	if sd.Attributes != nil {
+		c.Attributes = make(map[string]interface{}, len(sd.Attributes))
+		for k, v := range sd.Attributes {
+			c.Attributes[k] = v
+		}
+	}
+	if sd.Links != nil {
+		c.Links = append([]apitrace.Link(nil), sd.Links...)
+	}
+	if sd.MessageEvents != nil {
+		c.MessageEvents = append([]event(nil), sd.MessageEvents...)
+	}
+	return &c
+}
+
+// SelfTime returns the duration of the span minus the time spent in its
+// child spans, as recorded in ChildSpanDuration. Children running concurrently can account for more time
+// than the span itself, in which case SelfTime is zero.
+func (sd *SpanData) SelfTime() time.Duration {
+	self := sd.EndTime.Sub(sd.StartTime) - sd.ChildSpanDuration
+	if self < 0 {
+		return 0
+	}
+	return self
 }
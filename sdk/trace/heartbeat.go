@@ -0,0 +1,136 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+// HeartbeatEvent is the message of the event of spans started with
+// WithHeartbeat.
+const HeartbeatEvent = "heartbeat"
+
+// HeartbeatCountKey is the attribute of a heartbeat event holding the
+// number of heartbeats of the span so far, starting at 1.
+var HeartbeatCountKey = key.New("heartbeat.count")
+
+// HeartbeatProcessor is implemented by span processors that want partial
+// snapshots of long-running spans, e.g., to show them as alive before
+// they end. OnHeartbeat is called, like OnStart, with the state of a
+// sampled span started with WithHeartbeat at each heartbeat; its EndTime
+// is zero. It is called from the goroutine of a timer and should return
+// quickly.
+type HeartbeatProcessor interface {
+	OnHeartbeat(sd *SpanData)
+}
+
+// WithHeartbeat returns a SpanOption recording a heartbeat of the span
+// every interval until it ends, and passing a snapshot of the span to the
+// registered HeartbeatProcessors, so that backends can tell that a batch
+// job running for hours is alive. Intervals of zero or less disable the
+// heartbeats.
+//
+// The span holds a single HeartbeatEvent, moved to the time of the last
+// heartbeat, so that heartbeats do not push the other events out of
+// Config.MaxEventsPerSpan. It is not subject to the event rate limit or
+// the memory budget of events.
+func WithHeartbeat(interval time.Duration) apitrace.SpanOption {
+	return func(o *apitrace.SpanOptions) {
+		o.Custom = append(o.Custom, heartbeatOption{interval})
+	}
+}
+
+// startHeartbeat schedules the first heartbeat of s if it was started
+// with WithHeartbeat.
+func (s *span) startHeartbeat() {
+	if s.heartbeatInterval <= 0 || !s.IsRecordingEvents() {
+		return
+	}
+	s.mu.Lock()
+	s.heartbeat = time.AfterFunc(s.heartbeatInterval, s.beat)
+	s.mu.Unlock()
+}
+
+// stopHeartbeat stops the heartbeats of s. It is called when s ends.
+func (s *span) stopHeartbeat() {
+	s.mu.Lock()
+	if s.heartbeat != nil {
+		s.heartbeat.Stop()
+		s.heartbeat = nil
+	}
+	s.mu.Unlock()
+}
+
+// addHeartbeatEvent replaces the heartbeat event of s, if it still holds
+// one, with one at now. s.mu must be held.
+func (s *span) addHeartbeatEvent(now time.Time) {
+	q := s.events()
+	for i, v := range q.queue {
+		if ev, ok := v.(event); ok && ev.heartbeat {
+			q.queue = append(q.queue[:i], q.queue[i+1:]...)
+			break
+		}
+	}
+	q.add(event{
+		msg:        HeartbeatEvent,
+		attributes: []core.KeyValue{HeartbeatCountKey.Int(s.heartbeats)},
+		time:       now,
+		heartbeat:  true,
+	})
+}
+
+func (s *span) beat() {
+	s.mu.Lock()
+	if s.heartbeat == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.heartbeats++
+	s.heartbeat.Reset(s.heartbeatInterval)
+	s.addHeartbeatEvent(time.Now())
+	s.mu.Unlock()
+
+	if !s.spanContext.IsSampled() {
+		return
+	}
+	var procs []HeartbeatProcessor
+	for _, p := range loadProcessors() {
+		if hp, ok := p.(HeartbeatProcessor); ok {
+			procs = append(procs, hp)
+		}
+	}
+	if len(procs) == 0 {
+		return
+	}
+	sd := s.makeSpanData()
+	// The span may have ended while the event was added.
+	s.mu.Lock()
+	ended := s.heartbeat == nil
+	s.mu.Unlock()
+	if ended {
+		return
+	}
+	for i, p := range procs {
+		if i < len(procs)-1 {
+			p.OnHeartbeat(sd.clone())
+			continue
+		}
+		p.OnHeartbeat(sd)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+type heartbeatProcessor struct {
+	recordingProcessor
+	beats chan *SpanData
+}
+
+func (p *heartbeatProcessor) OnHeartbeat(sd *SpanData) {
+	select {
+	case p.beats <- sd:
+	default:
+	}
+}
+
+func TestWithHeartbeat(t *testing.T) {
+	p := &heartbeatProcessor{beats: make(chan *SpanData, 10)}
+	RegisterSpanProcessor(p)
+	defer UnregisterSpanProcessor(p)
+
+	_, span := apitrace.GlobalTracer().Start(context.Background(), "batch",
+		WithSampler(AlwaysSample()),
+		WithLimits(SpanLimits{MaxEventsPerSpan: 2}),
+		WithHeartbeat(5*time.Millisecond),
+	)
+	span.Event(context.Background(), "loaded")
+	for i := 0; i < 2; i++ {
+		select {
+		case sd := <-p.beats:
+			if !sd.EndTime.IsZero() {
+				t.Errorf("heartbeat %d has EndTime %v, want zero", i, sd.EndTime)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d heartbeats, want 2", i)
+		}
+	}
+	span.Finish()
+
+	if len(p.ended) != 1 {
+		t.Fatalf("got %d ended spans, want 1", len(p.ended))
+	}
+	// The heartbeats share one event, which leaves room for the others.
+	events := p.ended[0].MessageEvents
+	if len(events) != 2 || events[0].msg != "loaded" || events[1].msg != HeartbeatEvent {
+		t.Fatalf("got events %v, want loaded and a heartbeat", events)
+	}
+	if kv := events[1].attributes[0]; kv.Key != HeartbeatCountKey || kv.Value.Int64 < 2 {
+		t.Errorf("heartbeat event has attributes %v, want a count of at least 2", events[1].attributes)
+	}
+	if got := p.ended[0].DroppedMessageEventCount; got != 0 {
+		t.Errorf("DroppedMessageEventCount = %d, want 0", got)
+	}
+
+	// No heartbeat follows the end of the span.
+	for len(p.beats) > 0 {
+		<-p.beats
+	}
+	time.Sleep(20 * time.Millisecond)
+	if len(p.beats) != 0 {
+		t.Errorf("got %d heartbeats after the span ended", len(p.beats))
+	}
+}
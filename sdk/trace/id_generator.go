@@ -15,14 +15,40 @@
 package trace
 
 import (
+	crand "crypto/rand"
+	"encoding/binary"
 	"math/rand"
 	"sync"
 	"sync/atomic"
 
+	"github.com/hashicorp/golang-lru/simplelru"
+
 	"go.opentelemetry.io/api/core"
-	"go.opentelemetry.io/sdk/trace/internal"
 )
 
+// IDGenerator generates the trace and span IDs of new spans. It must be
+// safe for concurrent use and never return zero IDs.
+type IDGenerator interface {
+	NewTraceID() core.TraceID
+	NewSpanID() uint64
+}
+
+// NewIDGenerator returns the default IDGenerator. Its sequences are
+// seeded from crypto/rand, so that processes started at the same time
+// do not generate the same IDs.
+func NewIDGenerator() IDGenerator {
+	gen := &defaultIDGenerator{}
+	var rngSeed int64
+	for _, p := range []interface{}{
+		&rngSeed, &gen.traceIDAdd, &gen.nextSpanID, &gen.spanIDInc,
+	} {
+		_ = binary.Read(crand.Reader, binary.LittleEndian, p)
+	}
+	gen.traceIDRand = rand.New(rand.NewSource(rngSeed))
+	gen.spanIDInc |= 1
+	return gen
+}
+
 type defaultIDGenerator struct {
 	sync.Mutex
 
@@ -41,7 +67,7 @@ type defaultIDGenerator struct {
 	traceIDRand *rand.Rand
 }
 
-var _ internal.IDGenerator = &defaultIDGenerator{}
+var _ IDGenerator = &defaultIDGenerator{}
 
 // NewSpanID returns a non-zero span ID from a randomly-chosen sequence.
 func (gen *defaultIDGenerator) NewSpanID() uint64 {
@@ -53,15 +79,114 @@ func (gen *defaultIDGenerator) NewSpanID() uint64 {
 }
 
 // NewTraceID returns a non-zero trace ID from a randomly-chosen sequence.
-// mu should be held while this function is called.
 func (gen *defaultIDGenerator) NewTraceID() core.TraceID {
 	gen.Lock()
 	defer gen.Unlock()
 	// Construct the trace ID from two outputs of traceIDRand, with a constant
 	// added to each half for additional entropy.
-	tid := core.TraceID{
-		High: gen.traceIDRand.Uint64() + gen.traceIDAdd[0],
-		Low:  gen.traceIDRand.Uint64() + gen.traceIDAdd[1],
+	var tid core.TraceID
+	for tid.High == 0 && tid.Low == 0 {
+		tid = core.TraceID{
+			High: gen.traceIDRand.Uint64() + gen.traceIDAdd[0],
+			Low:  gen.traceIDRand.Uint64() + gen.traceIDAdd[1],
+		}
 	}
 	return tid
 }
+
+// NewDeterministicIDGenerator returns an IDGenerator whose IDs only
+// depend on seed, for tests that compare IDs. It must not be used in
+// production, where processes sharing a seed generate colliding IDs.
+func NewDeterministicIDGenerator(seed int64) IDGenerator {
+	return &deterministicIDGenerator{rand: rand.New(rand.NewSource(seed))}
+}
+
+type deterministicIDGenerator struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func (gen *deterministicIDGenerator) NewSpanID() uint64 {
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+	var id uint64
+	for id == 0 {
+		id = gen.rand.Uint64()
+	}
+	return id
+}
+
+func (gen *deterministicIDGenerator) NewTraceID() core.TraceID {
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+	var tid core.TraceID
+	for tid.High == 0 && tid.Low == 0 {
+		tid = core.TraceID{High: gen.rand.Uint64(), Low: gen.rand.Uint64()}
+	}
+	return tid
+}
+
+// TraceSpanIDGenerator is implemented by IDGenerators that generate span
+// IDs depending on the trace of the span. Spans are started with
+// NewSpanIDForTrace rather than NewSpanID when their generator
+// implements it.
+type TraceSpanIDGenerator interface {
+	NewSpanIDForTrace(traceID core.TraceID) uint64
+}
+
+// DefaultTrackedTraces is the number of traces whose span IDs are
+// tracked by the IDGenerator of NewUniqueSpanIDGenerator when a
+// non-positive number is given.
+const DefaultTrackedTraces = 1024
+
+// NewUniqueSpanIDGenerator returns an IDGenerator that never issues the
+// same span ID twice within a trace, for backends that drop spans with
+// duplicate IDs. It generates IDs with gen, and tracks the span IDs
+// issued for the traces most recently started spans in, up to traces of
+// them, so that uniqueness is only guaranteed for spans of a trace
+// started close enough in time.
+func NewUniqueSpanIDGenerator(gen IDGenerator, traces int) IDGenerator {
+	if traces <= 0 {
+		traces = DefaultTrackedTraces
+	}
+	u := &uniqueSpanIDGenerator{IDGenerator: gen}
+	u.issued, _ = simplelru.NewLRU(traces, nil)
+	return u
+}
+
+type uniqueSpanIDGenerator struct {
+	IDGenerator
+
+	mu     sync.Mutex
+	issued *simplelru.LRU // core.TraceID -> map[uint64]struct{}
+}
+
+var _ TraceSpanIDGenerator = &uniqueSpanIDGenerator{}
+
+// NewSpanIDForTrace returns a span ID not yet issued for traceID.
+func (gen *uniqueSpanIDGenerator) NewSpanIDForTrace(traceID core.TraceID) uint64 {
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+	var ids map[uint64]struct{}
+	if v, ok := gen.issued.Get(traceID); ok {
+		ids = v.(map[uint64]struct{})
+	} else {
+		ids = make(map[uint64]struct{})
+		gen.issued.Add(traceID, ids)
+	}
+	for {
+		id := gen.NewSpanID()
+		if _, dup := ids[id]; !dup {
+			ids[id] = struct{}{}
+			return id
+		}
+	}
+}
+
+// newSpanID returns a span ID for a span of traceID from gen.
+func newSpanID(gen IDGenerator, traceID core.TraceID) uint64 {
+	if tg, ok := gen.(TraceSpanIDGenerator); ok {
+		return tg.NewSpanIDForTrace(traceID)
+	}
+	return gen.NewSpanID()
+}
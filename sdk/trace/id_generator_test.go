@@ -0,0 +1,101 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+)
+
+func TestDeterministicIDGenerator(t *testing.T) {
+	a, b := NewDeterministicIDGenerator(1), NewDeterministicIDGenerator(1)
+	for i := 0; i < 10; i++ {
+		if ta, tb := a.NewTraceID(), b.NewTraceID(); ta != tb {
+			t.Fatalf("trace IDs %v and %v differ for the same seed", ta, tb)
+		}
+		if sa, sb := a.NewSpanID(), b.NewSpanID(); sa != sb || sa == 0 {
+			t.Fatalf("span IDs %d and %d, want the same non-zero ID", sa, sb)
+		}
+	}
+	if NewDeterministicIDGenerator(2).NewTraceID() == NewDeterministicIDGenerator(1).NewTraceID() {
+		t.Error("different seeds generated the same trace ID")
+	}
+}
+
+func TestIDGeneratorsDiffer(t *testing.T) {
+	// Generators of different processes are seeded independently.
+	a, b := NewIDGenerator(), NewIDGenerator()
+	if a.NewTraceID() == b.NewTraceID() {
+		t.Error("two default generators generated the same trace ID")
+	}
+	if a.NewSpanID() == b.NewSpanID() {
+		t.Error("two default generators generated the same span ID")
+	}
+}
+
+// cyclingIDGenerator issues the span IDs 1 to n over and over.
+type cyclingIDGenerator struct {
+	IDGenerator
+	n, next uint64
+}
+
+func (gen *cyclingIDGenerator) NewSpanID() uint64 {
+	gen.next = gen.next%gen.n + 1
+	return gen.next
+}
+
+func TestUniqueSpanIDGenerator(t *testing.T) {
+	gen := NewUniqueSpanIDGenerator(&cyclingIDGenerator{n: 3}, 1).(TraceSpanIDGenerator)
+	a, b := core.TraceID{Low: 1}, core.TraceID{Low: 2}
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 3; i++ {
+		id := gen.NewSpanIDForTrace(a)
+		if seen[id] {
+			t.Fatalf("span ID %d issued twice for the same trace", id)
+		}
+		seen[id] = true
+	}
+	// Tracking b evicts a, whose IDs may be issued again.
+	if id := gen.NewSpanIDForTrace(b); id == 0 {
+		t.Error("got zero span ID")
+	}
+	if id := gen.NewSpanIDForTrace(a); !seen[id] {
+		t.Errorf("got span ID %d, want a reused one once the trace is no longer tracked", id)
+	}
+}
+
+func TestProviderIDGenerator(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+
+	tr := NewProvider(WithIDGenerator(NewUniqueSpanIDGenerator(&cyclingIDGenerator{
+		IDGenerator: NewDeterministicIDGenerator(1),
+		n:           4,
+	}, 0))).Tracer("lib")
+	ctx, root := tr.Start(context.Background(), "root")
+	seen := map[uint64]bool{root.SpanContext().SpanID: true}
+	for i := 0; i < 3; i++ {
+		_, child := tr.Start(ctx, "child")
+		id := child.SpanContext().SpanID
+		if seen[id] {
+			t.Fatalf("span ID %d issued twice in trace %s", id, root.SpanContext().TraceIDString())
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+// SpanLimits are the fields of Config that bound the size of a span. SDK
+// implementations other than this one, e.g., the streaming SDK, read them
+// with ConfigSnapshot().SpanLimits(), so that limits are set once with
+// ApplyConfig whichever implementation records the spans. Zero means
+// unlimited.
+type SpanLimits struct {
+	MaxEventsPerSpan      int
+	MaxAttributesPerSpan  int
+	MaxAttributesPerEvent int
+	MaxLinksPerSpan       int
+	MaxSpanNameLength     int
+}
+
+// SpanLimits returns the span limits of c.
+func (c Config) SpanLimits() SpanLimits {
+	return SpanLimits{
+		MaxEventsPerSpan:      c.MaxEventsPerSpan,
+		MaxAttributesPerSpan:  c.MaxAttributesPerSpan,
+		MaxAttributesPerEvent: c.MaxAttributesPerEvent,
+		MaxLinksPerSpan:       c.MaxLinksPerSpan,
+		MaxSpanNameLength:     c.MaxSpanNameLength,
+	}
+}
+
+// TruncateName returns name truncated the way this SDK truncates span
+// names longer than MaxSpanNameLength.
+func (l SpanLimits) TruncateName(name string) string {
+	return truncateName(name, l.MaxSpanNameLength)
+}
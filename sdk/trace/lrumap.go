@@ -15,6 +15,8 @@
 package trace
 
 import (
+	"sync/atomic"
+
 	"github.com/hashicorp/golang-lru/simplelru"
 )
 
@@ -33,5 +35,15 @@ func (lm *lruMap) add(key, value interface{}) {
 	evicted := lm.simpleLruMap.Add(key, value)
 	if evicted {
 		lm.droppedCount++
+		atomic.AddUint64(&droppedAttributes, 1)
 	}
 }
+
+func (lm *lruMap) contains(key interface{}) bool {
+	return lm.simpleLruMap.Contains(key)
+}
+
+// remove removes key, which does not count as a dropped entry.
+func (lm *lruMap) remove(key interface{}) {
+	lm.simpleLruMap.Remove(key)
+}
@@ -0,0 +1,46 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"go.opentelemetry.io/api/core"
+)
+
+// Approximate sizes, in bytes, of the data reserved with the memlimit
+// package.
+const (
+	spanOverhead     = 512
+	eventOverhead    = 64
+	keyValueOverhead = 64
+)
+
+// eventSize returns the approximate size of an event.
+func eventSize(msg string, attrs []core.KeyValue) int64 {
+	n := eventOverhead + len(msg)
+	for _, kv := range attrs {
+		n += keyValueOverhead + len(kv.Key.Variable.Name) + len(kv.Value.String) + len(kv.Value.Bytes)
+	}
+	return int64(n)
+}
+
+// spanDataSize returns the approximate size of sd.
+func spanDataSize(sd *SpanData) int64 {
+	n := int64(spanOverhead + len(sd.Name) + len(sd.StatusMessage))
+	n += int64(keyValueOverhead * (len(sd.Attributes) + len(sd.Resource)))
+	for _, ev := range sd.MessageEvents {
+		n += eventSize(ev.msg, ev.attributes)
+	}
+	return n
+}
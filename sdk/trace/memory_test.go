@@ -0,0 +1,51 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/memlimit"
+)
+
+func TestMemoryBudgetShedsEvents(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+	defer memlimit.SetBudget(0)
+	base := memlimit.Used()
+	attr := apitrace.ExceptionMessageKey.String(strings.Repeat("x", 1000))
+	// Leaves room for a single event under the 80% share of events.
+	room := eventSize("kept", []core.KeyValue{attr}) * 3 / 2
+	memlimit.SetBudget((base + room) * 100 / 80)
+
+	s := startSpan()
+	s.Event(context.Background(), "kept", attr)
+	s.Event(context.Background(), "shed", attr)
+	got, err := endSpan(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.MessageEvents) != 1 || got.MessageEvents[0].msg != "kept" {
+		t.Errorf("got %d events, want only the one within the budget", len(got.MessageEvents))
+	}
+	if used := memlimit.Used(); used != base {
+		t.Errorf("memlimit.Used() = %d after the span ended, want %d", used, base)
+	}
+}
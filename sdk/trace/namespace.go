@@ -0,0 +1,82 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+// semanticPrefixes are the key prefixes of the semantic conventions, which
+// are not namespaced by default.
+var semanticPrefixes = []string{
+	"code.",
+	"db.",
+	"enduser.",
+	"exception.",
+	"faas.",
+	"http.",
+	"messaging.",
+	"net.",
+	"peer.",
+	"rpc.",
+	"service.",
+	"thread.",
+}
+
+// SemanticPrefixes returns the key prefixes of the semantic conventions
+// that WithAttributeNamespace leaves intact by default. The returned slice
+// is a copy and can be extended and passed back to WithAttributeNamespace.
+func SemanticPrefixes() []string {
+	return append([]string(nil), semanticPrefixes...)
+}
+
+// WithAttributeNamespace returns a copy of t that prefixes the keys of
+// span attributes with namespace, e.g., "app.", to keep the attributes of
+// different teams apart in the backend. Keys that already have the prefix
+// are left intact, and so are keys starting with one of exempt. If exempt
+// is omitted, the keys of the semantic conventions, such as http.method,
+// are exempt; see SemanticPrefixes.
+//
+// Tracers not created by this SDK are returned unchanged.
+func WithAttributeNamespace(t apitrace.Tracer, namespace string, exempt ...string) apitrace.Tracer {
+	tr, ok := t.(*tracer)
+	if !ok {
+		return t
+	}
+	if exempt == nil {
+		exempt = semanticPrefixes
+	}
+	c := *tr
+	c.attributeNamespace = namespace
+	c.namespaceExempt = append([]string(nil), exempt...)
+	return &c
+}
+
+func namespaceKey(namespace string, exempt []string, k core.Key) core.Key {
+	name := k.Variable.Name
+	if strings.HasPrefix(name, namespace) {
+		return k
+	}
+	for _, p := range exempt {
+		if strings.HasPrefix(name, p) {
+			return k
+		}
+	}
+	k.Variable.Name = namespace + name
+	return k
+}
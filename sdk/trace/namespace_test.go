@@ -0,0 +1,144 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+func TestWithAttributeNamespace(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{MaxAttributesPerSpan: DefaultMaxAttributesPerSpan})
+
+	tracer := WithAttributeNamespace(apitrace.GlobalTracer(), "app.")
+	_, span := tracer.Start(context.Background(), "span0",
+		apitrace.ChildOf(remoteSpanContext()),
+		apitrace.WithRecordEvents(),
+	)
+	span.SetAttributes(
+		key.New("user").String("alice"),
+		key.New("http.method").String("GET"),
+		key.New("app.cart").Int64(3),
+	)
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"app.user":    core.Value{Type: core.STRING, String: "alice"},
+		"http.method": core.Value{Type: core.STRING, String: "GET"},
+		"app.cart":    core.Value{Type: core.INT64, Int64: 3},
+	}
+	if diff := cmp.Diff(got.Attributes, want); diff != "" {
+		t.Errorf("namespaced attributes: -got +want %s", diff)
+	}
+}
+
+func TestWithAttributeNamespaceStartAttributes(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{MaxAttributesPerSpan: DefaultMaxAttributesPerSpan})
+
+	tracer := WithAttributeNamespace(apitrace.GlobalTracer(), "app.")
+	_, span := tracer.Start(context.Background(), "span0",
+		apitrace.ChildOf(remoteSpanContext()),
+		apitrace.WithRecordEvents(),
+		apitrace.WithAttributes(key.New("user").String("alice")),
+	)
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"app.user": core.Value{Type: core.STRING, String: "alice"},
+	}
+	if diff := cmp.Diff(got.Attributes, want); diff != "" {
+		t.Errorf("start attributes: -got +want %s", diff)
+	}
+}
+
+func TestWithAttributeNamespaceExempt(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{MaxAttributesPerSpan: DefaultMaxAttributesPerSpan})
+
+	tests := []struct {
+		name   string
+		exempt []string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "extended",
+			exempt: append(SemanticPrefixes(), "k8s."),
+			want: map[string]interface{}{
+				"http.method": core.Value{Type: core.STRING, String: "GET"},
+				"k8s.pod":     core.Value{Type: core.STRING, String: "web-1"},
+			},
+		},
+		{
+			name:   "overridden",
+			exempt: []string{"k8s."},
+			want: map[string]interface{}{
+				"app.http.method": core.Value{Type: core.STRING, String: "GET"},
+				"k8s.pod":         core.Value{Type: core.STRING, String: "web-1"},
+			},
+		},
+		{
+			name:   "none",
+			exempt: []string{},
+			want: map[string]interface{}{
+				"app.http.method": core.Value{Type: core.STRING, String: "GET"},
+				"app.k8s.pod":     core.Value{Type: core.STRING, String: "web-1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := WithAttributeNamespace(apitrace.GlobalTracer(), "app.", tt.exempt...)
+			_, span := tracer.Start(context.Background(), "span0",
+				apitrace.ChildOf(remoteSpanContext()),
+				apitrace.WithRecordEvents(),
+			)
+			span.SetAttributes(
+				key.New("http.method").String("GET"),
+				key.New("k8s.pod").String("web-1"),
+			)
+			got, err := endSpan(span)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got.Attributes, tt.want); diff != "" {
+				t.Errorf("namespaced attributes: -got +want %s", diff)
+			}
+		})
+	}
+}
+
+func TestWithAttributeNamespaceForeignTracer(t *testing.T) {
+	tracer := apitrace.NoopTracer{}
+	if got := WithAttributeNamespace(tracer, "app."); got != tracer {
+		t.Errorf("WithAttributeNamespace(NoopTracer) = %#v; want unchanged tracer", got)
+	}
+}
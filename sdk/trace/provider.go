@@ -0,0 +1,98 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"sync"
+
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+// InstrumentationLibrary identifies the library that recorded a span.
+type InstrumentationLibrary struct {
+	Name    string
+	Version string
+
+	// SchemaURL identifies the semantic conventions the attributes of
+	// the span follow.
+	SchemaURL string
+}
+
+// Provider is an apitrace.Provider handing out the tracers of the SDK.
+// Each library gets a tracer of its own, whose spans carry the name and
+// version of the library in SpanData.InstrumentationLibrary. Register
+// sets up a Provider as the global one.
+type Provider struct {
+	mu      sync.Mutex
+	tracers map[InstrumentationLibrary]*tracer
+
+	// eventRates are the rates given with WithEventSampling.
+	eventRates map[string]float64
+
+	// idGenerator is the generator given with WithIDGenerator.
+	idGenerator IDGenerator
+}
+
+var _ apitrace.Provider = &Provider{}
+
+// ProviderOption configures a Provider.
+type ProviderOption func(*Provider)
+
+// WithEventSampling keeps only a fraction of the message events of each
+// message in rates, e.g., 0.01 of the "cache.hit" events, independently of
+// the sampling of spans. Events with other messages are all kept. A rate
+// of zero or less drops all events with the message. Sampled out events
+// are not counted as dropped.
+func WithEventSampling(rates map[string]float64) ProviderOption {
+	return func(p *Provider) {
+		p.eventRates = make(map[string]float64, len(rates))
+		for msg, rate := range rates {
+			p.eventRates[msg] = rate
+		}
+	}
+}
+
+// WithIDGenerator makes the tracers of the Provider generate IDs with gen
+// instead of Config.IDGenerator, e.g., with the generator of
+// NewUniqueSpanIDGenerator.
+func WithIDGenerator(gen IDGenerator) ProviderOption {
+	return func(p *Provider) {
+		p.idGenerator = gen
+	}
+}
+
+// NewProvider returns a Provider.
+func NewProvider(opts ...ProviderOption) *Provider {
+	p := &Provider{tracers: make(map[InstrumentationLibrary]*tracer)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Tracer returns the tracer of the named library, the same one for each
+// name and version.
+func (p *Provider) Tracer(name string, opts ...apitrace.TracerOption) apitrace.Tracer {
+	c := apitrace.NewTracerConfig(opts...)
+	lib := InstrumentationLibrary{Name: name, Version: c.Version, SchemaURL: c.SchemaURL}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t, ok := p.tracers[lib]
+	if !ok {
+		t = &tracer{library: lib, eventRates: p.eventRates, idGenerator: p.idGenerator}
+		p.tracers[lib] = t
+	}
+	return t
+}
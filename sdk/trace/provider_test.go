@@ -0,0 +1,89 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+func TestProviderTracer(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+
+	p := NewProvider()
+	tr := p.Tracer("othttp", apitrace.WithInstrumentationVersion("0.1"))
+	if p.Tracer("othttp", apitrace.WithInstrumentationVersion("0.1")) != tr {
+		t.Error("Tracer returned a new tracer for the same library")
+	}
+	if p.Tracer("othttp") == tr {
+		t.Error("Tracer returned the same tracer for another version")
+	}
+
+	_, s := tr.Start(context.Background(), "request")
+	got, err := endSpan(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := InstrumentationLibrary{Name: "othttp", Version: "0.1"}
+	if got.InstrumentationLibrary != want {
+		t.Errorf("InstrumentationLibrary = %+v, want %+v", got.InstrumentationLibrary, want)
+	}
+}
+
+func TestRegisterSetsGlobalProvider(t *testing.T) {
+	Register()
+	if _, ok := apitrace.GlobalProvider().(*Provider); !ok {
+		t.Fatalf("GlobalProvider() = %T, want *Provider", apitrace.GlobalProvider())
+	}
+	if apitrace.GlobalProvider().Tracer("") != apitrace.GlobalTracer() {
+		t.Error("the unnamed tracer of the global provider is not the global tracer")
+	}
+}
+
+func TestProviderEventSampling(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+
+	p := NewProvider(WithEventSampling(map[string]float64{
+		"cache.hit":  0,
+		"cache.miss": 1,
+	}))
+	ctx, s := p.Tracer("cache").Start(context.Background(), "get")
+	for i := 0; i < 3; i++ {
+		s.Event(ctx, "cache.hit")
+	}
+	s.Event(ctx, "cache.miss")
+	s.Event(ctx, "evicted")
+	got, err := endSpan(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs []string
+	for it := got.Events(); it.Next(); {
+		msgs = append(msgs, it.Event().Message())
+	}
+	if len(msgs) != 2 || msgs[0] != "cache.miss" || msgs[1] != "evicted" {
+		t.Errorf("got events %q, want cache.miss and evicted", msgs)
+	}
+	if got.DroppedMessageEventCount != 0 {
+		t.Errorf("DroppedMessageEventCount = %d, want sampled out events not to count", got.DroppedMessageEventCount)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimitingSampler returns a Sampler that samples at most
+// spansPerSecond spans per second on average, however bursty the traffic
+// is.
+//
+// It uses a token bucket holding up to one second worth of spans, so that
+// a burst after a quiet second is sampled up to the rate before decisions
+// are throttled. Rates below one span per second still allow single
+// spans. Like ProbabilitySampler, it samples spans whose parents are
+// sampled, so that sampled traces are complete, but local child spans
+// take a token too, running the bucket into debt if it is empty: new
+// traces are not sampled until the debt is paid back. Spans of remote
+// sampled parents do not take tokens.
+func RateLimitingSampler(spansPerSecond float64) Sampler {
+	return newRateLimitingSampler(spansPerSecond, time.Now).sample
+}
+
+type rateLimitingSampler struct {
+	rate     float64
+	capacity float64
+	now      func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimitingSampler(rate float64, now func() time.Time) *rateLimitingSampler {
+	if !(rate > 0) {
+		rate = 0
+	}
+	capacity := rate
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &rateLimitingSampler{
+		rate:     rate,
+		capacity: capacity,
+		now:      now,
+		tokens:   capacity,
+		last:     now(),
+	}
+}
+
+func (s *rateLimitingSampler) sample(p SamplingParameters) SamplingDecision {
+	sampledParent := p.ParentContext.IsSampled()
+	if sampledParent && (p.HasRemoteParent || s.rate == 0) {
+		return SamplingDecision{Sample: true}
+	}
+	if s.rate == 0 {
+		return SamplingDecision{Sample: false}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if elapsed := now.Sub(s.last); elapsed > 0 {
+		s.tokens += elapsed.Seconds() * s.rate
+		if s.tokens > s.capacity {
+			s.tokens = s.capacity
+		}
+	}
+	s.last = now
+	if s.tokens < 1 && !sampledParent {
+		return SamplingDecision{Sample: false}
+	}
+	s.tokens--
+	return SamplingDecision{Sample: true}
+}
@@ -0,0 +1,128 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+)
+
+func sampleN(s *rateLimitingSampler, n int) int {
+	sampled := 0
+	for i := 0; i < n; i++ {
+		if s.sample(SamplingParameters{}).Sample {
+			sampled++
+		}
+	}
+	return sampled
+}
+
+func TestRateLimitingSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newRateLimitingSampler(10, func() time.Time { return now })
+
+	if got := sampleN(s, 100); got != 10 {
+		t.Errorf("burst: sampled %d, want 10", got)
+	}
+	now = now.Add(100 * time.Millisecond)
+	if got := sampleN(s, 100); got != 1 {
+		t.Errorf("after 100ms: sampled %d, want 1", got)
+	}
+	// The bucket holds at most one second worth of traces.
+	now = now.Add(time.Minute)
+	if got := sampleN(s, 100); got != 10 {
+		t.Errorf("after a quiet minute: sampled %d, want 10", got)
+	}
+}
+
+func TestRateLimitingSamplerSteadyRate(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newRateLimitingSampler(5, func() time.Time { return now })
+	sampleN(s, 5)
+	sampled := 0
+	for i := 0; i < 1000; i++ {
+		now = now.Add(10 * time.Millisecond)
+		sampled += sampleN(s, 1)
+	}
+	// 10 seconds at 5 per second.
+	if sampled < 49 || sampled > 51 {
+		t.Errorf("sampled %d over 10s, want 50", sampled)
+	}
+}
+
+func TestRateLimitingSamplerLowRate(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newRateLimitingSampler(0.5, func() time.Time { return now })
+	if got := sampleN(s, 10); got != 1 {
+		t.Errorf("sampled %d, want 1", got)
+	}
+	now = now.Add(time.Second)
+	if got := sampleN(s, 10); got != 0 {
+		t.Errorf("after 1s: sampled %d, want 0", got)
+	}
+	now = now.Add(time.Second)
+	if got := sampleN(s, 10); got != 1 {
+		t.Errorf("after 2s: sampled %d, want 1", got)
+	}
+}
+
+func TestRateLimitingSamplerParent(t *testing.T) {
+	now := time.Unix(0, 0)
+	for _, rate := range []float64{0, 1} {
+		s := newRateLimitingSampler(rate, func() time.Time { return now })
+		sampleN(s, 1)
+		parent := core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 1, TraceOptions: core.TraceOptionSampled}
+		if !s.sample(SamplingParameters{ParentContext: parent}).Sample {
+			t.Errorf("rate %v: span of a sampled parent not sampled", rate)
+		}
+		if s.sample(SamplingParameters{}).Sample {
+			t.Errorf("rate %v: root span sampled beyond the limit", rate)
+		}
+	}
+}
+
+func TestRateLimitingSamplerChildSpans(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newRateLimitingSampler(10, func() time.Time { return now })
+	parent := core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: 1, TraceOptions: core.TraceOptionSampled}
+
+	sampleN(s, 1)
+	for i := 0; i < 19; i++ {
+		if !s.sample(SamplingParameters{ParentContext: parent}).Sample {
+			t.Fatalf("local child span %d of a sampled parent not sampled", i)
+		}
+	}
+	// The trace took 20 tokens, 10 of them on credit.
+	now = now.Add(time.Second)
+	if got := sampleN(s, 100); got != 0 {
+		t.Errorf("after 1s: sampled %d, want 0 until the debt is paid back", got)
+	}
+	now = now.Add(100 * time.Millisecond)
+	if got := sampleN(s, 100); got != 1 {
+		t.Errorf("after 1.1s: sampled %d, want 1", got)
+	}
+
+	for i := 0; i < 100; i++ {
+		if !s.sample(SamplingParameters{ParentContext: parent, HasRemoteParent: true}).Sample {
+			t.Fatalf("span %d of a remote sampled parent not sampled", i)
+		}
+	}
+	now = now.Add(100 * time.Millisecond)
+	if got := sampleN(s, 100); got != 1 {
+		t.Errorf("spans of remote parents took tokens: sampled %d, want 1", got)
+	}
+}
@@ -0,0 +1,360 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rulesampler provides a Sampler whose policy is read from a
+// file of declarative rules, so that operators can change what is
+// sampled without redeploying the application.
+//
+// A rule file holds an ordered list of rules and a default strategy.
+// The first rule that matches a span decides how it is sampled:
+//
+//	{
+//	  "rules": [
+//	    {"name": "GET /health*", "strategy": {"type": "never"}},
+//	    {"kind": "consumer", "strategy": {"type": "probability", "probability": 0.001}},
+//	    {"attributes": {"http.route": "/checkout"}, "strategy": {"type": "always"}},
+//	    {"resource": {"service.tier": "batch"}, "strategy": {"type": "probability", "probability": 0.01}}
+//	  ],
+//	  "default": {"type": "probability", "probability": 0.1}
+//	}
+//
+// Files are decoded as JSON. Pass yaml.Unmarshal from gopkg.in/yaml.v2
+// to WithDecoder to read the same document from YAML.
+//
+// Load the file once and install the sampler, then reload it on SIGHUP or
+// when the file changes, using file change notifications where the
+// platform supports them:
+//
+//	s, err := rulesampler.Load("/etc/otel/sampling.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	trace.ApplyConfig(trace.Config{DefaultSampler: s.Sampler()})
+//	defer s.WatchSignal()()
+//	stop, err := s.WatchFileEvents()
+//	if err != nil {
+//		stop = s.WatchFile(10 * time.Second)
+//	}
+//	defer stop()
+package rulesampler // import "go.opentelemetry.io/sdk/trace/rulesampler"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// Strategy types.
+const (
+	StrategyAlways      = "always"
+	StrategyNever       = "never"
+	StrategyProbability = "probability"
+)
+
+// Config is the content of a rule file.
+type Config struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+
+	// Default samples the spans no rule matches. An empty Default
+	// samples nothing.
+	Default Strategy `json:"default" yaml:"default"`
+}
+
+// Rule matches spans and gives the strategy used to sample them. Every
+// field that is set has to match; a rule with no conditions matches
+// every span.
+type Rule struct {
+	// Name is a pattern matched against the span name. A '*' matches
+	// any sequence of characters, including '/', so "GET /api/*" matches
+	// "GET /api/v1/users"; a '?' matches a single character, and '\'
+	// escapes the character that follows it.
+	Name string `json:"name" yaml:"name"`
+	// Kind is the kind the span must have, e.g., "server" or "client".
+	Kind string `json:"kind" yaml:"kind"`
+	// Attributes maps attribute keys to the values the attributes passed
+	// to Start must have.
+	Attributes map[string]string `json:"attributes" yaml:"attributes"`
+	// Resource maps resource keys to the values the resources of the
+	// tracer must have.
+	Resource map[string]string `json:"resource" yaml:"resource"`
+
+	Strategy Strategy `json:"strategy" yaml:"strategy"`
+}
+
+// Strategy says how matching spans are sampled.
+type Strategy struct {
+	// Type is one of StrategyAlways, StrategyNever and
+	// StrategyProbability.
+	Type string `json:"type" yaml:"type"`
+	// Probability is the fraction of traces sampled by
+	// StrategyProbability.
+	Probability float64 `json:"probability" yaml:"probability"`
+}
+
+// Compile returns a Sampler applying the rules of cfg.
+func Compile(cfg Config) (trace.Sampler, error) {
+	rs, err := compile(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return rs.sample, nil
+}
+
+type ruleSet struct {
+	rules []compiledRule
+	def   trace.Sampler
+}
+
+type compiledRule struct {
+	name       string
+	kind       string
+	attributes map[string]string
+	resource   map[string]string
+	sampler    trace.Sampler
+}
+
+func compile(cfg Config) (*ruleSet, error) {
+	rs := &ruleSet{def: trace.NeverSample()}
+	if cfg.Default.Type != "" {
+		s, err := cfg.Default.sampler()
+		if err != nil {
+			return nil, fmt.Errorf("rulesampler: default: %v", err)
+		}
+		rs.def = s
+	}
+	for i, r := range cfg.Rules {
+		if err := validPattern(r.Name); err != nil {
+			return nil, fmt.Errorf("rulesampler: rule %d: name %q: %v", i, r.Name, err)
+		}
+		if r.Kind != "" && !validKind(r.Kind) {
+			return nil, fmt.Errorf("rulesampler: rule %d: unknown span kind %q", i, r.Kind)
+		}
+		s, err := r.Strategy.sampler()
+		if err != nil {
+			return nil, fmt.Errorf("rulesampler: rule %d: %v", i, err)
+		}
+		rs.rules = append(rs.rules, compiledRule{
+			name:       r.Name,
+			kind:       r.Kind,
+			attributes: r.Attributes,
+			resource:   r.Resource,
+			sampler:    s,
+		})
+	}
+	return rs, nil
+}
+
+func (s Strategy) sampler() (trace.Sampler, error) {
+	switch s.Type {
+	case StrategyAlways:
+		return trace.AlwaysSample(), nil
+	case StrategyNever:
+		return trace.NeverSample(), nil
+	case StrategyProbability:
+		if !(s.Probability >= 0 && s.Probability <= 1) {
+			return nil, fmt.Errorf("probability %v is not in [0, 1]", s.Probability)
+		}
+		return trace.ProbabilitySampler(s.Probability), nil
+	}
+	return nil, fmt.Errorf("unknown strategy type %q", s.Type)
+}
+
+func (rs *ruleSet) sample(p trace.SamplingParameters) trace.SamplingDecision {
+	for i := range rs.rules {
+		if r := &rs.rules[i]; r.matches(p) {
+			return r.sampler(p)
+		}
+	}
+	return rs.def(p)
+}
+
+func (r *compiledRule) matches(p trace.SamplingParameters) bool {
+	if r.name != "" {
+		if !match(r.name, p.Name) {
+			return false
+		}
+	}
+	if r.kind != "" && r.kind != p.Kind.String() {
+		return false
+	}
+	return matchAll(r.attributes, p.Attributes) && matchAll(r.resource, p.Resource)
+}
+
+func validKind(kind string) bool {
+	for k := apitrace.SpanKindUnspecified; k <= apitrace.SpanKindConsumer; k++ {
+		if k.String() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+var errBadPattern = errors.New("trailing backslash")
+
+func validPattern(pattern string) error {
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' {
+			if i == len(pattern)-1 {
+				return errBadPattern
+			}
+			i++
+		}
+	}
+	return nil
+}
+
+// match reports whether name matches pattern, where a '*' matches any
+// sequence of characters and a '?' any single character.
+func match(pattern, name string) bool {
+	// On a mismatch, retry from the last '*' with it matching one more
+	// character of name.
+	star, next := -1, 0
+	p, n := 0, 0
+	for n < len(name) {
+		if p < len(pattern) {
+			switch c := pattern[p]; c {
+			case '*':
+				star, next = p, n
+				p++
+				continue
+			case '?':
+				_, size := utf8.DecodeRuneInString(name[n:])
+				p++
+				n += size
+				continue
+			case '\\':
+				if p+1 < len(pattern) && pattern[p+1] == name[n] {
+					p += 2
+					n++
+					continue
+				}
+			default:
+				if c == name[n] {
+					p++
+					n++
+					continue
+				}
+			}
+		}
+		if star < 0 {
+			return false
+		}
+		_, size := utf8.DecodeRuneInString(name[next:])
+		next += size
+		p, n = star+1, next
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+func matchAll(want map[string]string, kvs []core.KeyValue) bool {
+	for k, v := range want {
+		found := false
+		for _, kv := range kvs {
+			if kv.Key.Variable.Name == k && kv.Value.Emit() == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// DecodeFunc decodes the content of a rule file into v.
+type DecodeFunc func(data []byte, v interface{}) error
+
+// Option configures a Sampler.
+type Option func(*Sampler)
+
+// WithDecoder sets the function decoding the rule file. The default
+// decodes JSON.
+func WithDecoder(d DecodeFunc) Option {
+	return func(s *Sampler) {
+		s.decode = d
+	}
+}
+
+// WithErrorHandler sets the function called with the errors of reloads
+// triggered by WatchSignal and WatchFile. The default logs them.
+func WithErrorHandler(h func(error)) Option {
+	return func(s *Sampler) {
+		s.onError = h
+	}
+}
+
+// Sampler samples spans by the rules of a file, which can be reloaded
+// while it is in use.
+type Sampler struct {
+	path    string
+	decode  DecodeFunc
+	onError func(error)
+
+	rules atomic.Value // *ruleSet
+}
+
+// Load reads the rules of the file at path.
+func Load(path string, opts ...Option) (*Sampler, error) {
+	s := &Sampler{
+		path:   path,
+		decode: json.Unmarshal,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the rules file again. If it cannot be read or holds
+// invalid rules, the rules in use are kept and an error is returned.
+func (s *Sampler) Reload() error {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("rulesampler: %v", err)
+	}
+	var cfg Config
+	if err := s.decode(data, &cfg); err != nil {
+		return fmt.Errorf("rulesampler: %s: %v", s.path, err)
+	}
+	rs, err := compile(cfg)
+	if err != nil {
+		return err
+	}
+	s.rules.Store(rs)
+	return nil
+}
+
+// Sampler returns the trace.Sampler applying the current rules.
+func (s *Sampler) Sampler() trace.Sampler {
+	return s.Sample
+}
+
+// Sample makes a sampling decision with the current rules.
+func (s *Sampler) Sample(p trace.SamplingParameters) trace.SamplingDecision {
+	return s.rules.Load().(*ruleSet).sample(p)
+}
@@ -0,0 +1,256 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rulesampler
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+const rules = `{
+  "rules": [
+    {"name": "GET /health*", "strategy": {"type": "never"}},
+    {"attributes": {"http.route": "/checkout"}, "strategy": {"type": "always"}},
+    {"resource": {"service.tier": "batch"}, "strategy": {"type": "probability", "probability": 0}}
+  ],
+  "default": {"type": "always"}
+}`
+
+func writeRules(t *testing.T, path, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tempRules(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "rulesampler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "rules.json")
+	writeRules(t, path, content)
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestRules(t *testing.T) {
+	s, err := Compile(Config{
+		Rules: []Rule{
+			{Name: "GET /health*", Strategy: Strategy{Type: StrategyNever}},
+			{Kind: "consumer", Strategy: Strategy{Type: StrategyNever}},
+			{Attributes: map[string]string{"http.route": "/checkout"}, Strategy: Strategy{Type: StrategyAlways}},
+			{Resource: map[string]string{"service.tier": "batch"}, Strategy: Strategy{Type: StrategyProbability}},
+		},
+		Default: Strategy{Type: StrategyAlways},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	route := key.New("http.route").String("/checkout")
+	batch := key.New("service.tier").String("batch")
+	for _, tt := range []struct {
+		name string
+		p    trace.SamplingParameters
+		want bool
+	}{
+		{"name", trace.SamplingParameters{Name: "GET /healthz", Attributes: []core.KeyValue{route}}, false},
+		{"kind", trace.SamplingParameters{Name: "process", Kind: apitrace.SpanKindConsumer, Attributes: []core.KeyValue{route}}, false},
+		{"other kind", trace.SamplingParameters{Name: "process", Kind: apitrace.SpanKindProducer}, true},
+		{"attributes", trace.SamplingParameters{Name: "POST /pay", Attributes: []core.KeyValue{route}, Resource: []core.KeyValue{batch}}, true},
+		{"resource", trace.SamplingParameters{Name: "job", Resource: []core.KeyValue{batch}}, false},
+		{"default", trace.SamplingParameters{Name: "job"}, true},
+	} {
+		if got := s(tt.p).Sample; got != tt.want {
+			t.Errorf("%s: got Sample %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"", "", true},
+		{"", "span", false},
+		{"span", "span", true},
+		{"span", "spans", false},
+		{"*", "", true},
+		{"*", "GET /api/v1/users", true},
+		{"GET /api/*", "GET /api/v1/users", true},
+		{"GET /api/*", "GET /api", false},
+		{"GET /*/users", "GET /api/v1/users", true},
+		{"GET /*/users", "GET /api/v1/users/1", false},
+		{"*/health*", "GET /healthz", true},
+		{"a*b*c", "aXbYbZc", true},
+		{"a*b*c", "aXbYbZ", false},
+		{"?", "é", true},
+		{"GET /?", "GET /ab", false},
+		{`\*`, "*", true},
+		{`\*`, "x", false},
+		{`\?*`, "?x", true},
+	} {
+		if got := match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, cfg := range []Config{
+		{Default: Strategy{Type: "sometimes"}},
+		{Rules: []Rule{{Strategy: Strategy{Type: StrategyProbability, Probability: 2}}}},
+		{Rules: []Rule{{Name: `GET \`, Strategy: Strategy{Type: StrategyAlways}}}},
+		{Rules: []Rule{{Name: "span"}}},
+		{Rules: []Rule{{Kind: "rpc", Strategy: Strategy{Type: StrategyAlways}}}},
+	} {
+		if _, err := Compile(cfg); err == nil {
+			t.Errorf("Compile(%+v) succeeded, want error", cfg)
+		}
+	}
+}
+
+func TestReloadKeepsRulesOnError(t *testing.T) {
+	path, cleanup := tempRules(t, rules)
+	defer cleanup()
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := trace.SamplingParameters{Name: "job"}
+	if !s.Sample(p).Sample {
+		t.Fatal("default strategy did not sample")
+	}
+
+	writeRules(t, path, `{"default": {"type": "never"}}`)
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Sample(p).Sample {
+		t.Error("sampled after reloading a never default")
+	}
+
+	writeRules(t, path, `{"default": {"type": "always"`)
+	if err := s.Reload(); err == nil {
+		t.Error("Reload of a truncated file succeeded, want error")
+	}
+	if s.Sample(p).Sample {
+		t.Error("rules changed after a failed reload")
+	}
+}
+
+func TestWithDecoder(t *testing.T) {
+	path, cleanup := tempRules(t, "default: never")
+	defer cleanup()
+
+	decodeErr := errors.New("not a rule file")
+	_, err := Load(path, WithDecoder(func(data []byte, v interface{}) error {
+		return decodeErr
+	}))
+	if err == nil {
+		t.Error("Load succeeded with a failing decoder")
+	}
+
+	s, err := Load(path, WithDecoder(func(data []byte, v interface{}) error {
+		v.(*Config).Default.Type = StrategyNever
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Sample(trace.SamplingParameters{}).Sample {
+		t.Error("sampled with a never default")
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	path, cleanup := tempRules(t, rules)
+	defer cleanup()
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := s.WatchFile(time.Millisecond)
+	defer stop()
+
+	writeRules(t, path, `{"default": {"type": "never"}}`)
+	p := trace.SamplingParameters{Name: "job"}
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Sample(p).Sample {
+		if time.Now().After(deadline) {
+			t.Fatal("rules were not reloaded after the file changed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchFileEvents(t *testing.T) {
+	path, cleanup := tempRules(t, rules)
+	defer cleanup()
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop, err := s.WatchFileEvents()
+	if err != nil {
+		if runtime.GOOS != "linux" {
+			t.Skip(err)
+		}
+		t.Fatal(err)
+	}
+	defer stop()
+
+	// Replace the file by renaming, like config management tools do.
+	next := path + ".next"
+	writeRules(t, next, `{"default": {"type": "never"}}`)
+	if err := os.Rename(next, path); err != nil {
+		t.Fatal(err)
+	}
+	p := trace.SamplingParameters{Name: "job"}
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Sample(p).Sample {
+		if time.Now().After(deadline) {
+			t.Fatal("rules were not reloaded after the file changed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchFileNonPositiveInterval(t *testing.T) {
+	path, cleanup := tempRules(t, rules)
+	defer cleanup()
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		s.WatchFile(interval)()
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rulesampler
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/api/logger"
+)
+
+// DefaultWatchInterval is the interval WatchFile uses when it is given a
+// non-positive one.
+const DefaultWatchInterval = 10 * time.Second
+
+// WatchSignal reloads the rules whenever the process receives one of
+// sigs, or SIGHUP if none are given. It returns a function that stops
+// watching.
+func (s *Sampler) WatchSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				s.reload()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// WatchFile checks the rules file every interval and reloads it when its
+// modification time or size changes. A non-positive interval is replaced
+// by DefaultWatchInterval. It returns a function that stops watching.
+//
+// The file is polled rather than watched for events so that files
+// replaced by renaming, as config management tools and Kubernetes
+// ConfigMap volumes do, are picked up as well.
+func (s *Sampler) WatchFile(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	last, _ := os.Stat(s.path)
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				last = s.reloadIfChanged(last)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
+
+// WatchFileEvents reloads the rules when the operating system reports a
+// change in the directory of the rules file and the file's modification
+// time or size changed. Unlike WatchFile, it picks up changes without
+// delay and without polling. It returns a function that stops watching,
+// or an error if notifications are not supported on the platform, in
+// which case WatchFile can be used instead.
+//
+// The directory rather than the file is watched so that files replaced
+// by renaming, as config management tools and Kubernetes ConfigMap
+// volumes do, are picked up as well.
+func (s *Sampler) WatchFileEvents() (stop func(), err error) {
+	return s.watchEvents()
+}
+
+// reloadIfChanged reloads the rules if the file changed since last and
+// returns its current state.
+func (s *Sampler) reloadIfChanged(last os.FileInfo) os.FileInfo {
+	fi, err := os.Stat(s.path)
+	if err != nil || unchanged(last, fi) {
+		return last
+	}
+	s.reload()
+	return fi
+}
+
+func unchanged(a, b os.FileInfo) bool {
+	return a != nil && a.ModTime().Equal(b.ModTime()) && a.Size() == b.Size()
+}
+
+func (s *Sampler) reload() {
+	err := s.Reload()
+	if err == nil {
+		return
+	}
+	if s.onError != nil {
+		s.onError(err)
+		return
+	}
+	logger.Errorf("rulesampler: %v", err)
+}
@@ -0,0 +1,52 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rulesampler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+func (s *Sampler) watchEvents() (func(), error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("rulesampler: inotify: %v", err)
+	}
+	const mask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_CREATE | syscall.IN_DELETE
+	if _, err := syscall.InotifyAddWatch(fd, filepath.Dir(s.path), mask); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("rulesampler: inotify: %v", err)
+	}
+	// The descriptor is non-blocking, so reads go through the runtime
+	// poller and closing the file stops the watcher.
+	f := os.NewFile(uintptr(fd), "inotify")
+	last, _ := os.Stat(s.path)
+	go func() {
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			if _, err := f.Read(buf); err != nil {
+				return
+			}
+			// Every event may mean a new file, so their content does
+			// not matter.
+			last = s.reloadIfChanged(last)
+		}
+	}()
+	return func() {
+		f.Close()
+	}, nil
+}
@@ -12,13 +12,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package internal provides trace internals.
-package internal
+// +build !linux
 
-import "go.opentelemetry.io/api/core"
+package rulesampler
 
-// IDGenerator allows custom generators for TraceId and SpanId.
-type IDGenerator interface {
-	NewTraceID() core.TraceID
-	NewSpanID() uint64
+import "errors"
+
+func (s *Sampler) watchEvents() (func(), error) {
+	return nil, errors.New("rulesampler: file change notifications are not supported on this platform")
 }
@@ -16,6 +16,7 @@ package trace
 
 import (
 	"go.opentelemetry.io/api/core"
+	apitrace "go.opentelemetry.io/api/trace"
 )
 
 const defaultSamplingProbability = 1e-4
@@ -30,16 +31,36 @@ type SamplingParameters struct {
 	SpanID          uint64
 	Name            string
 	HasRemoteParent bool
+	Kind            apitrace.SpanKind
+
+	// Attributes holds the attributes passed to Start with
+	// apitrace.WithAttributes, before any namespace is applied.
+	Attributes []core.KeyValue
+	// Resource holds the resources of the tracer starting the span, merged
+	// with Config.Resource.
+	Resource []core.KeyValue
 }
 
 // SamplingDecision is the value returned by a Sampler.
 type SamplingDecision struct {
 	Sample bool
+
+	// Verbose hints that a sampled span should record expensive details.
+	// Instrumentation reads it with apitrace.IsRecordingVerbose. Local
+	// child spans inherit it from their parent.
+	Verbose bool
+
+	// Attributes are set on the span if it is sampled, e.g., to record
+	// which policy sampled it. They are not subject to the attribute
+	// namespace of the tracer.
+	Attributes []core.KeyValue
 }
 
 // ProbabilitySampler returns a Sampler that samples a given fraction of traces.
 //
-// It also samples spans whose parents are sampled.
+// The decision is derived from the trace ID, so every span of a trace,
+// in any process using the same fraction, gets the same decision. It also
+// samples spans whose parents are sampled, whatever the fraction.
 func ProbabilitySampler(fraction float64) Sampler {
 	if !(fraction >= 0) {
 		fraction = 0
@@ -52,8 +73,34 @@ func ProbabilitySampler(fraction float64) Sampler {
 		if p.ParentContext.IsSampled() {
 			return SamplingDecision{Sample: true}
 		}
-		x := p.TraceID.High >> 1
-		return SamplingDecision{Sample: x < traceIDUpperBound}
+		return SamplingDecision{Sample: traceIDHash(p.TraceID) < traceIDUpperBound}
+	})
+}
+
+// traceIDHash returns the 63-bit value used to make trace-consistent
+// decisions, such as probability sampling, from a trace ID.
+func traceIDHash(id core.TraceID) uint64 {
+	return id.High >> 1
+}
+
+// VerboseSampler returns a Sampler that makes the decisions of s and marks
+// a given fraction of the traces it samples for verbose recording.
+//
+// The choice is made from the low half of the trace ID, so it is
+// consistent across a trace and independent of ProbabilitySampler.
+func VerboseSampler(s Sampler, fraction float64) Sampler {
+	verboseUpperBound := uint64(0)
+	if fraction >= 1 {
+		verboseUpperBound = 1 << 63
+	} else if fraction > 0 {
+		verboseUpperBound = uint64(fraction * (1 << 63))
+	}
+	return Sampler(func(p SamplingParameters) SamplingDecision {
+		d := s(p)
+		if d.Sample && p.TraceID.Low>>1 < verboseUpperBound {
+			d.Verbose = true
+		}
+		return d
 	})
 }
 
@@ -73,3 +120,65 @@ func NeverSample() Sampler {
 		return SamplingDecision{Sample: false}
 	}
 }
+
+// ParentBased returns a Sampler that follows the decision of the parent
+// of a span, sampled or not, and makes the decisions of root for spans
+// without a parent.
+func ParentBased(root Sampler) Sampler {
+	return func(p SamplingParameters) SamplingDecision {
+		if p.ParentContext.IsValid() {
+			return SamplingDecision{Sample: p.ParentContext.IsSampled()}
+		}
+		return root(p)
+	}
+}
+
+// AnnotatingSampler returns a Sampler that makes the decisions of s and
+// adds attrs to the attributes of the spans it samples, e.g., to tell
+// which of the samplers combined with Or sampled a span.
+func AnnotatingSampler(s Sampler, attrs ...core.KeyValue) Sampler {
+	return func(p SamplingParameters) SamplingDecision {
+		d := s(p)
+		if d.Sample {
+			d.Attributes = append(d.Attributes[:len(d.Attributes):len(d.Attributes)], attrs...)
+		}
+		return d
+	}
+}
+
+// And returns a Sampler that samples a span if all of samplers sample it.
+// The decision is verbose if any of them is, and carries the attributes
+// of all of them. It stops at the first sampler that does not sample.
+func And(samplers ...Sampler) Sampler {
+	return func(p SamplingParameters) SamplingDecision {
+		d := SamplingDecision{Sample: true}
+		for _, s := range samplers {
+			sd := s(p)
+			if !sd.Sample {
+				return SamplingDecision{Sample: false}
+			}
+			d.Verbose = d.Verbose || sd.Verbose
+			d.Attributes = append(d.Attributes, sd.Attributes...)
+		}
+		return d
+	}
+}
+
+// Or returns a Sampler that samples a span if any of samplers samples it,
+// with the decision of the first one that does, so that policies can be
+// listed by priority, e.g.,
+//
+//	Or(
+//		AnnotatingSampler(errorSampler, key.New("sampling.policy").String("errors")),
+//		ProbabilitySampler(0.01),
+//	)
+func Or(samplers ...Sampler) Sampler {
+	return func(p SamplingParameters) SamplingDecision {
+		for _, s := range samplers {
+			if d := s(p); d.Sample {
+				return d
+			}
+		}
+		return SamplingDecision{Sample: false}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+)
+
+func TestProbabilitySamplerFraction(t *testing.T) {
+	const n = 10000
+	for _, fraction := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		sampler := ProbabilitySampler(fraction)
+		sampled := 0
+		for i := uint64(0); i < n; i++ {
+			id := core.TraceID{High: i * 0x9e3779b97f4a7c15, Low: i}
+			if sampler(SamplingParameters{TraceID: id}).Sample {
+				sampled++
+			}
+		}
+		if got := float64(sampled) / n; math.Abs(got-fraction) > 0.02 {
+			t.Errorf("ProbabilitySampler(%v) sampled %v of the traces", fraction, got)
+		}
+	}
+}
+
+func TestProbabilitySamplerDeterministic(t *testing.T) {
+	a, b := ProbabilitySampler(0.3), ProbabilitySampler(0.3)
+	for i := uint64(0); i < 1000; i++ {
+		p := SamplingParameters{TraceID: core.TraceID{High: i * 0x9e3779b97f4a7c15, Low: i}}
+		if a(p).Sample != b(p).Sample || a(p).Sample != a(SamplingParameters{TraceID: p.TraceID, SpanID: i + 1}).Sample {
+			t.Fatalf("trace %v: decisions differ", p.TraceID)
+		}
+	}
+}
+
+func TestProbabilitySamplerParent(t *testing.T) {
+	id := core.TraceID{High: ^uint64(0), Low: 1}
+	for _, tt := range []struct {
+		name     string
+		fraction float64
+		parent   core.SpanContext
+		want     bool
+	}{
+		{"sampled parent", 0, core.SpanContext{TraceID: id, SpanID: 1, TraceOptions: core.TraceOptionSampled}, true},
+		{"unsampled parent", 0.5, core.SpanContext{TraceID: id, SpanID: 1}, false},
+		{"no parent", 0.5, core.SpanContext{}, false},
+		{"invalid fraction", math.NaN(), core.SpanContext{}, false},
+	} {
+		got := ProbabilitySampler(tt.fraction)(SamplingParameters{ParentContext: tt.parent, TraceID: id}).Sample
+		if got != tt.want {
+			t.Errorf("%s: Sample = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParentBased(t *testing.T) {
+	id := core.TraceID{High: 1, Low: 1}
+	sampled := core.SpanContext{TraceID: id, SpanID: 1, TraceOptions: core.TraceOptionSampled}
+	unsampled := core.SpanContext{TraceID: id, SpanID: 1}
+	for _, tt := range []struct {
+		name   string
+		root   Sampler
+		parent core.SpanContext
+		want   bool
+	}{
+		{"sampled parent", NeverSample(), sampled, true},
+		{"unsampled parent", AlwaysSample(), unsampled, false},
+		{"root sampled", AlwaysSample(), core.SpanContext{}, true},
+		{"root not sampled", NeverSample(), core.SpanContext{}, false},
+	} {
+		got := ParentBased(tt.root)(SamplingParameters{ParentContext: tt.parent, TraceID: id}).Sample
+		if got != tt.want {
+			t.Errorf("%s: Sample = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAndOr(t *testing.T) {
+	verbose := func(SamplingParameters) SamplingDecision {
+		return SamplingDecision{Sample: true, Verbose: true}
+	}
+	a := AnnotatingSampler(AlwaysSample(), key.New("policy").String("a"))
+	b := AnnotatingSampler(AlwaysSample(), key.New("policy").String("b"))
+	for _, tt := range []struct {
+		name     string
+		sampler  Sampler
+		sample   bool
+		verbose  bool
+		policies []string
+	}{
+		{"and none", And(), true, false, nil},
+		{"and all", And(a, verbose, b), true, true, []string{"a", "b"}},
+		{"and one not", And(a, NeverSample()), false, false, nil},
+		{"or none", Or(), false, false, nil},
+		{"or first", Or(a, b), true, false, []string{"a"}},
+		{"or second", Or(NeverSample(), b), true, false, []string{"b"}},
+		{"or verbose", Or(verbose, a), true, true, nil},
+		{"or nothing", Or(NeverSample(), NeverSample()), false, false, nil},
+	} {
+		d := tt.sampler(SamplingParameters{})
+		if d.Sample != tt.sample || d.Verbose != tt.verbose {
+			t.Errorf("%s: got Sample %v, Verbose %v, want %v, %v", tt.name, d.Sample, d.Verbose, tt.sample, tt.verbose)
+		}
+		var policies []string
+		for _, kv := range d.Attributes {
+			policies = append(policies, kv.Value.String)
+		}
+		if len(policies) != len(tt.policies) {
+			t.Errorf("%s: got policies %v, want %v", tt.name, policies, tt.policies)
+			continue
+		}
+		for i := range policies {
+			if policies[i] != tt.policies[i] {
+				t.Errorf("%s: got policies %v, want %v", tt.name, policies, tt.policies)
+				break
+			}
+		}
+	}
+}
+
+func TestAnnotatingSamplerNotSampled(t *testing.T) {
+	d := AnnotatingSampler(NeverSample(), key.New("policy").String("never"))(SamplingParameters{})
+	if d.Sample || len(d.Attributes) != 0 {
+		t.Errorf("got %+v, want an unsampled decision without attributes", d)
+	}
+}
+
+func TestSamplingDecisionAttributes(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AnnotatingSampler(AlwaysSample(), key.New("sampling.policy").String("all"))})
+
+	spans := make(exporter)
+	RegisterExporter(&spans)
+	defer UnregisterExporter(&spans)
+	_, span := (&tracer{}).Start(context.Background(), "annotated")
+	span.Finish()
+
+	v, ok := spans["annotated"].Attributes["sampling.policy"].(core.Value)
+	if !ok || v.String != "all" {
+		t.Errorf("got attributes %v, want sampling.policy=all", spans["annotated"].Attributes)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema migrates the attributes of spans between versions of
+// the semantic conventions at export time, so that instrumentation can
+// be upgraded one library at a time while the backend sees a single
+// version.
+//
+// Each Table renames attributes from one schema URL to the next. A
+// Migrator chains the tables up to its target version and renames the
+// attributes of each span according to the schema URL of the library
+// that recorded it:
+//
+//	m, err := schema.NewMigrator("https://opentelemetry.io/schemas/1.5.0",
+//		schema.Table{
+//			From:       "https://opentelemetry.io/schemas/1.4.0",
+//			To:         "https://opentelemetry.io/schemas/1.5.0",
+//			Attributes: map[string]string{"http.host": "net.host.name"},
+//		})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	trace.RegisterExportEnricher(m.Migrate)
+package schema // import "go.opentelemetry.io/sdk/trace/schema"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// Table renames attributes from the schema version From to the version To.
+type Table struct {
+	From string
+	To   string
+
+	// Attributes maps the keys of attributes in From to their keys in To.
+	Attributes map[string]string
+}
+
+// Migrator renames the attributes of spans to the target schema version.
+type Migrator struct {
+	target string
+
+	// renames maps each schema URL the tables lead from to the composed
+	// renames from that version to target.
+	renames map[string]map[string]string
+}
+
+// NewMigrator returns a Migrator to target using tables. Every table must
+// lead to target, possibly through other tables, and no two tables may
+// start from the same version.
+func NewMigrator(target string, tables ...Table) (*Migrator, error) {
+	next := make(map[string]Table, len(tables))
+	for _, t := range tables {
+		if t.From == t.To {
+			return nil, fmt.Errorf("schema: table from %q leads to itself", t.From)
+		}
+		if _, ok := next[t.From]; ok {
+			return nil, fmt.Errorf("schema: two tables from %q", t.From)
+		}
+		next[t.From] = t
+	}
+
+	m := &Migrator{target: target, renames: make(map[string]map[string]string)}
+	for from := range next {
+		renames := map[string]string{}
+		seen := map[string]bool{}
+		for v := from; v != target; v = next[v].To {
+			t, ok := next[v]
+			if !ok {
+				return nil, fmt.Errorf("schema: no table from %q, on the way from %q to %q", v, from, target)
+			}
+			if seen[v] {
+				return nil, fmt.Errorf("schema: tables from %q loop at %q", from, v)
+			}
+			seen[v] = true
+			renames = compose(renames, t.Attributes)
+		}
+		m.renames[from] = renames
+	}
+	return m, nil
+}
+
+// compose returns the renames of first followed by those of then.
+func compose(first, then map[string]string) map[string]string {
+	c := make(map[string]string, len(first)+len(then))
+	for k := range then {
+		if final := then[k]; final != k {
+			c[k] = final
+		}
+	}
+	for k, cur := range first {
+		final := cur
+		if v, ok := then[cur]; ok {
+			final = v
+		}
+		if final != k {
+			c[k] = final
+		} else {
+			delete(c, k)
+		}
+	}
+	return c
+}
+
+// Migrate renames the attributes of spans recorded by libraries following
+// an older schema version and sets their schema URL to the target. Spans
+// without a schema URL, or with one no table starts from, are left as
+// they are. If a span has both the old and the new key of an attribute,
+// the value of the new key is kept.
+//
+// Migrate has the signature of a trace.ExportEnricher.
+func (m *Migrator) Migrate(spans []*trace.SpanData) {
+	for _, sd := range spans {
+		renames, ok := m.renames[sd.InstrumentationLibrary.SchemaURL]
+		if !ok {
+			continue
+		}
+		for old, new := range renames {
+			v, ok := sd.Attributes[old]
+			if !ok {
+				continue
+			}
+			delete(sd.Attributes, old)
+			if _, exists := sd.Attributes[new]; !exists {
+				sd.Attributes[new] = v
+			}
+		}
+		sd.InstrumentationLibrary.SchemaURL = m.target
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+const (
+	v1 = "https://example.com/schemas/1"
+	v2 = "https://example.com/schemas/2"
+	v3 = "https://example.com/schemas/3"
+)
+
+var tables = []Table{
+	{From: v1, To: v2, Attributes: map[string]string{"http.host": "net.host", "db.type": "db.system"}},
+	{From: v2, To: v3, Attributes: map[string]string{"net.host": "net.host.name", "db.system": "db.type"}},
+}
+
+func TestMigrate(t *testing.T) {
+	m, err := NewMigrator(v3, tables...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	span := func(schemaURL string, attrs map[string]interface{}) *trace.SpanData {
+		return &trace.SpanData{
+			InstrumentationLibrary: trace.InstrumentationLibrary{SchemaURL: schemaURL},
+			Attributes:             attrs,
+		}
+	}
+	for _, tt := range []struct {
+		name      string
+		span      *trace.SpanData
+		want      map[string]interface{}
+		schemaURL string
+	}{
+		{"from v1", span(v1, map[string]interface{}{"http.host": "a", "db.type": "sql", "other": 1}),
+			map[string]interface{}{"net.host.name": "a", "db.type": "sql", "other": 1}, v3},
+		{"from v2", span(v2, map[string]interface{}{"net.host": "a", "http.host": "b"}),
+			map[string]interface{}{"net.host.name": "a", "http.host": "b"}, v3},
+		{"new key wins", span(v2, map[string]interface{}{"net.host": "old", "net.host.name": "new"}),
+			map[string]interface{}{"net.host.name": "new"}, v3},
+		{"current", span(v3, map[string]interface{}{"net.host": "a"}),
+			map[string]interface{}{"net.host": "a"}, v3},
+		{"no schema", span("", map[string]interface{}{"http.host": "a"}),
+			map[string]interface{}{"http.host": "a"}, ""},
+		{"no attributes", span(v1, nil), nil, v3},
+	} {
+		m.Migrate([]*trace.SpanData{tt.span})
+		if diff := cmp.Diff(tt.span.Attributes, tt.want); diff != "" {
+			t.Errorf("%s: -got +want %s", tt.name, diff)
+		}
+		if got := tt.span.InstrumentationLibrary.SchemaURL; got != tt.schemaURL {
+			t.Errorf("%s: SchemaURL = %q, want %q", tt.name, got, tt.schemaURL)
+		}
+	}
+}
+
+func TestNewMigratorErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		tables []Table
+	}{
+		{"gap", []Table{{From: v1, To: v2}}},
+		{"duplicate", []Table{{From: v1, To: v2}, {From: v1, To: v3}}},
+		{"self", []Table{{From: v1, To: v1}}},
+		{"loop", []Table{{From: v1, To: v2}, {From: v2, To: v1}}},
+	} {
+		if _, err := NewMigrator(v3, tt.tables...); err == nil {
+			t.Errorf("%s: NewMigrator returned no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+
+	opentelemetry "go.opentelemetry.io/sdk"
+)
+
+// Flusher is implemented by exporters and span processors that buffer
+// spans, e.g., to export them in batches.
+type Flusher interface {
+	// Flush exports the buffered spans and returns once they are.
+	Flush()
+}
+
+// Shutdowner is implemented by exporters holding resources, e.g.,
+// connections, to release when the SDK shuts down.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+func init() {
+	opentelemetry.RegisterShutdownHook(Shutdown)
+}
+
+// Flush exports the spans buffered by BatchSpanProcessors and by the
+// registered span processors and exporters implementing Flusher.
+func Flush() {
+	for _, bsp := range liveBatchSpanProcessors() {
+		bsp.ForceFlush()
+	}
+	ps, _ := processors.Load().([]SpanProcessor)
+	for _, p := range ps {
+		if f, ok := p.(Flusher); ok {
+			f.Flush()
+		}
+	}
+	es, _ := exporters.Load().(exportersMap)
+	for e := range es {
+		if f, ok := e.(Flusher); ok {
+			f.Flush()
+		}
+	}
+}
+
+// Shutdown unregisters and shuts down the span processors and exporters,
+// and shuts down the BatchSpanProcessors, which exports the spans they
+// buffer. It then shuts down the exporters implementing Shutdowner,
+// including those of BatchSpanProcessors, and returns the first error.
+// If ctx is done first, Shutdown returns its error and the shutdown
+// continues in the background.
+//
+// It is called by opentelemetry.Shutdown.
+func Shutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- shutdown(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func shutdown(ctx context.Context) error {
+	var shutdowners []Shutdowner
+	bsps := liveBatchSpanProcessors()
+	for _, bsp := range bsps {
+		if s, ok := bsp.e.(Shutdowner); ok {
+			shutdowners = append(shutdowners, s)
+		}
+	}
+
+	ps, _ := processors.Load().([]SpanProcessor)
+	for _, p := range ps {
+		UnregisterSpanProcessor(p)
+	}
+	es, _ := exporters.Load().(exportersMap)
+	for e := range es {
+		UnregisterExporter(e)
+		if s, ok := e.(Shutdowner); ok {
+			shutdowners = append(shutdowners, s)
+		}
+	}
+	for _, bsp := range bsps {
+		bsp.Shutdown()
+	}
+
+	var err error
+	for _, s := range shutdowners {
+		if e := s.Shutdown(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type shutdownExporter struct {
+	batchExporter
+	shutdowns int
+}
+
+func (e *shutdownExporter) Shutdown(ctx context.Context) error {
+	e.shutdowns++
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	e := &shutdownExporter{}
+	bsp, err := NewBatchSpanProcessor(e, WithScheduledDelay(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterSpanProcessor(bsp)
+	// A processor left unregistered is shut down too.
+	unregistered := &batchExporter{}
+	other, err := NewBatchSpanProcessor(unregistered, WithScheduledDelay(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		bsp.ExportSpan(&SpanData{Name: "span"})
+		other.ExportSpan(&SpanData{Name: "span"})
+	}
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	if got := e.spans(); got != 3 {
+		t.Errorf("exported %d spans; want 3", got)
+	}
+	if got := unregistered.spans(); got != 3 {
+		t.Errorf("exported %d spans of the unregistered processor; want 3", got)
+	}
+	if e.shutdowns != 1 {
+		t.Errorf("exporter shut down %d times; want 1", e.shutdowns)
+	}
+	if ps, _ := processors.Load().([]SpanProcessor); len(ps) != 0 {
+		t.Errorf("%d processors are still registered; want none", len(ps))
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package skew corrects the start times of spans that appear to begin
+// before their remote parent sent the request because the clocks of the
+// hosts drifted.
+//
+// Spans recorded in one process share a clock, so only the edge between a
+// span and its remote parent can be skewed. The remote parent is recorded
+// by another process, so its timing is carried in the propagated context
+// instead: the client calls WithSendTime before injecting the context of
+// an outgoing request, and the server calls Annotate on the span it starts
+// for the request, e.g.:
+//
+//	// Client
+//	ctx = skew.WithSendTime(ctx)
+//	tracer.Inject(ctx, span, injector)
+//
+//	// Server
+//	ctx, span := tracer.Start(ctx, "serve", apitrace.ChildOf(sc))
+//	skew.Annotate(ctx, span)
+//
+// When the server span starts before the request was sent, it and all of
+// its local descendants are shifted by the same offset to start at the
+// send time. The offset is bounded, so that a wrong send time does not
+// move spans arbitrarily far.
+package skew // import "go.opentelemetry.io/sdk/trace/skew"
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+var (
+	// SendTimeKey is the propagated tag set by WithSendTime. It holds the
+	// time a request was sent, in nanoseconds since the Unix epoch
+	// according to the clock of the client.
+	SendTimeKey = key.New("skew.send_time")
+
+	// ParentSendTimeKey is the attribute Annotate sets on the span of a
+	// request to the value of SendTimeKey.
+	ParentSendTimeKey = key.New("skew.parent_send_time")
+)
+
+// WithSendTime returns ctx with SendTimeKey set to the current time. The
+// tag propagates for one hop, to the server of the next request injected
+// from the returned context.
+func WithSendTime(ctx context.Context) context.Context {
+	return tag.NewContext(ctx, tag.Upsert(SendTimeKey.Int64(time.Now().UnixNano())).WithTTL(1))
+}
+
+// Annotate sets ParentSendTimeKey on span if ctx holds the SendTimeKey
+// tag extracted from an incoming request. Propagators that extract tags
+// as strings are supported.
+func Annotate(ctx context.Context, span apitrace.Span) {
+	if ns, ok := sendTime(tag.FromContext(ctx).Value(SendTimeKey)); ok {
+		span.SetAttribute(ParentSendTimeKey.Int64(ns))
+	}
+}
+
+func sendTime(v core.Value, ok bool) (int64, bool) {
+	if !ok {
+		return 0, false
+	}
+	switch v.Type {
+	case core.INT64:
+		return v.Int64, true
+	case core.STRING:
+		ns, err := strconv.ParseInt(v.String, 10, 64)
+		return ns, err == nil
+	}
+	return 0, false
+}
+
+// parentSendTime returns the ParentSendTimeKey attribute of sd.
+func parentSendTime(sd *trace.SpanData) (time.Time, bool) {
+	v, ok := sd.Attributes[ParentSendTimeKey.Variable.Name].(core.Value)
+	if !ok || v.Type != core.INT64 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, v.Int64), true
+}
+
+// Correct shifts the spans with a remote parent that start before the
+// ParentSendTimeKey attribute set by Annotate, moving each by at most
+// maxAdjustment. Local descendants of a shifted span in spans are
+// shifted with it. The spans are modified in place. Times of message
+// events are not adjusted.
+//
+// Correct returns the number of spans that were shifted.
+func Correct(spans []*trace.SpanData, maxAdjustment time.Duration) int {
+	byID := make(map[uint64]*trace.SpanData, len(spans))
+	children := make(map[uint64][]*trace.SpanData)
+	for _, sd := range spans {
+		byID[sd.SpanContext.SpanID] = sd
+	}
+	var roots []*trace.SpanData
+	for _, sd := range spans {
+		if _, ok := byID[sd.ParentSpanID]; ok && sd.ParentSpanID != 0 && !sd.HasRemoteParent {
+			children[sd.ParentSpanID] = append(children[sd.ParentSpanID], sd)
+		} else {
+			roots = append(roots, sd)
+		}
+	}
+
+	adjusted := 0
+	var walk func(sd *trace.SpanData, shift time.Duration)
+	walk = func(sd *trace.SpanData, shift time.Duration) {
+		if shift != 0 {
+			sd.StartTime = sd.StartTime.Add(shift)
+			sd.EndTime = sd.EndTime.Add(shift)
+			adjusted++
+		}
+		for _, c := range children[sd.SpanContext.SpanID] {
+			walk(c, shift)
+		}
+	}
+	for _, sd := range roots {
+		walk(sd, offset(sd, maxAdjustment))
+	}
+	return adjusted
+}
+
+// offset returns the shift that moves the start of a span with a remote
+// parent to the time the parent sent the request, bounded by max.
+func offset(sd *trace.SpanData, max time.Duration) time.Duration {
+	if !sd.HasRemoteParent {
+		return 0
+	}
+	sent, ok := parentSendTime(sd)
+	if !ok || !sd.StartTime.Before(sent) {
+		return 0
+	}
+	d := sent.Sub(sd.StartTime)
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// Exporter is a trace.Exporter that groups the spans of each local root,
+// i.e., each span without a local parent, with their local descendants,
+// corrects their skew with Correct and passes them to another Exporter.
+//
+// The spans of a local root are passed on when it ends, since its
+// descendants usually end before it does. Descendants ending after their
+// local root, and spans whose local root ended more than the configured
+// wait ago, are passed on after wait without correction.
+type Exporter struct {
+	next          trace.Exporter
+	maxAdjustment time.Duration
+	wait          time.Duration
+
+	mu     sync.Mutex
+	traces map[core.TraceID]*pending
+}
+
+type pending struct {
+	spans []*trace.SpanData
+	timer *time.Timer
+}
+
+var _ trace.Exporter = (*Exporter)(nil)
+
+// NewExporter returns an Exporter that passes spans to next after
+// correcting their skew by at most maxAdjustment. Spans whose local root
+// did not end are passed on after wait.
+func NewExporter(next trace.Exporter, maxAdjustment, wait time.Duration) *Exporter {
+	return &Exporter{
+		next:          next,
+		maxAdjustment: maxAdjustment,
+		wait:          wait,
+		traces:        make(map[core.TraceID]*pending),
+	}
+}
+
+// ExportSpan adds sd to the spans of its trace, and passes on those of
+// its local root if sd is one.
+func (e *Exporter) ExportSpan(sd *trace.SpanData) {
+	// Exporters must not modify the SpanData they receive.
+	c := *sd
+	id := sd.SpanContext.TraceID
+	localRoot := c.ParentSpanID == 0 || c.HasRemoteParent
+
+	e.mu.Lock()
+	p, ok := e.traces[id]
+	if !ok {
+		p = &pending{}
+		e.traces[id] = p
+		p.timer = time.AfterFunc(e.wait, func() { e.flushTrace(id) })
+	} else if !localRoot {
+		p.timer.Reset(e.wait)
+	}
+	p.spans = append(p.spans, &c)
+	var spans []*trace.SpanData
+	if localRoot {
+		spans = p.takeDescendants(c.SpanContext.SpanID)
+		if len(p.spans) == 0 {
+			p.timer.Stop()
+			delete(e.traces, id)
+		}
+	}
+	e.mu.Unlock()
+
+	e.export(spans)
+}
+
+// takeDescendants removes from p the span root and its local
+// descendants, and returns them.
+func (p *pending) takeDescendants(root uint64) []*trace.SpanData {
+	byID := make(map[uint64]*trace.SpanData, len(p.spans))
+	for _, sd := range p.spans {
+		byID[sd.SpanContext.SpanID] = sd
+	}
+	under := func(sd *trace.SpanData) bool {
+		for sd != nil {
+			if sd.SpanContext.SpanID == root {
+				return true
+			}
+			if sd.HasRemoteParent {
+				return false
+			}
+			sd = byID[sd.ParentSpanID]
+		}
+		return false
+	}
+	var taken, kept []*trace.SpanData
+	for _, sd := range p.spans {
+		if under(sd) {
+			taken = append(taken, sd)
+		} else {
+			kept = append(kept, sd)
+		}
+	}
+	p.spans = kept
+	return taken
+}
+
+// Flush passes on all pending spans.
+func (e *Exporter) Flush() {
+	e.mu.Lock()
+	ids := make([]core.TraceID, 0, len(e.traces))
+	for id := range e.traces {
+		ids = append(ids, id)
+	}
+	e.mu.Unlock()
+	for _, id := range ids {
+		e.flushTrace(id)
+	}
+}
+
+func (e *Exporter) flushTrace(id core.TraceID) {
+	e.mu.Lock()
+	p, ok := e.traces[id]
+	if ok {
+		delete(e.traces, id)
+		p.timer.Stop()
+	}
+	e.mu.Unlock()
+	if ok {
+		e.export(p.spans)
+	}
+}
+
+func (e *Exporter) export(spans []*trace.SpanData) {
+	Correct(spans, e.maxAdjustment)
+	for _, sd := range spans {
+		e.next.ExportSpan(sd)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skew
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/tag"
+	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+var base = time.Unix(1000, 0)
+
+func span(id, parent uint64, remote bool, start, end time.Duration) *trace.SpanData {
+	return &trace.SpanData{
+		SpanContext:     core.SpanContext{TraceID: core.TraceID{Low: 1}, SpanID: id},
+		ParentSpanID:    parent,
+		HasRemoteParent: remote,
+		StartTime:       base.Add(start),
+		EndTime:         base.Add(end),
+	}
+}
+
+// sentAt sets the send time of the remote parent of sd.
+func sentAt(sd *trace.SpanData, sent time.Duration) *trace.SpanData {
+	sd.Attributes = map[string]interface{}{
+		ParentSendTimeKey.Variable.Name: ParentSendTimeKey.Int64(base.Add(sent).UnixNano()).Value,
+	}
+	return sd
+}
+
+func TestCorrect(t *testing.T) {
+	ms := time.Millisecond
+	// Starts 20ms before its remote parent sent the request.
+	server := sentAt(span(2, 1, true, -20*ms, 50*ms), 0)
+	// A local child of the server, on the same skewed clock.
+	local := span(3, 2, false, -10*ms, 40*ms)
+	// Starts after the request was sent.
+	late := sentAt(span(4, 1, true, 60*ms, 110*ms), 50*ms)
+	// Has no send time.
+	unknown := span(5, 1, true, -time.Second, 0)
+
+	if n := Correct([]*trace.SpanData{local, late, server, unknown}, time.Second); n != 2 {
+		t.Errorf("Correct adjusted %d spans; want 2", n)
+	}
+	for _, tt := range []struct {
+		name       string
+		sd         *trace.SpanData
+		start, end time.Duration
+	}{
+		{"server", server, 0, 70 * ms},
+		{"local", local, 10 * ms, 60 * ms},
+		{"late", late, 60 * ms, 110 * ms},
+		{"unknown", unknown, -time.Second, 0},
+	} {
+		if got := tt.sd.StartTime.Sub(base); got != tt.start {
+			t.Errorf("%s start = %v; want %v", tt.name, got, tt.start)
+		}
+		if got := tt.sd.EndTime.Sub(base); got != tt.end {
+			t.Errorf("%s end = %v; want %v", tt.name, got, tt.end)
+		}
+	}
+}
+
+func TestCorrectBounded(t *testing.T) {
+	child := sentAt(span(2, 1, true, -time.Second, 0), 0)
+	Correct([]*trace.SpanData{child}, 100*time.Millisecond)
+	if got, want := child.StartTime.Sub(base), -900*time.Millisecond; got != want {
+		t.Errorf("child start = %v; want %v", got, want)
+	}
+}
+
+type annotatedSpan struct {
+	apitrace.NoopSpan
+	attrs []core.KeyValue
+}
+
+func (s *annotatedSpan) SetAttribute(kv core.KeyValue) {
+	s.attrs = append(s.attrs, kv)
+}
+
+func TestAnnotate(t *testing.T) {
+	before := time.Now().UnixNano()
+	ctx := WithSendTime(context.Background())
+	v, _ := tag.FromContext(ctx).Value(SendTimeKey)
+
+	// The server may extract the tag as a string.
+	for _, serverCtx := range []context.Context{
+		ctx,
+		tag.NewContext(context.Background(), tag.Insert(SendTimeKey.String(strconv.FormatInt(v.Int64, 10)))),
+	} {
+		s := &annotatedSpan{}
+		Annotate(serverCtx, s)
+		if len(s.attrs) != 1 || s.attrs[0].Key != ParentSendTimeKey || s.attrs[0].Value.Int64 < before {
+			t.Errorf("Annotate set %v, want %s after %d", s.attrs, ParentSendTimeKey.Variable.Name, before)
+		}
+	}
+
+	s := &annotatedSpan{}
+	Annotate(context.Background(), s)
+	if len(s.attrs) != 0 {
+		t.Errorf("Annotate set %v without a send time, want nothing", s.attrs)
+	}
+}
+
+type exporter []*trace.SpanData
+
+func (e *exporter) ExportSpan(sd *trace.SpanData) {
+	*e = append(*e, sd)
+}
+
+func TestExporter(t *testing.T) {
+	var got exporter
+	e := NewExporter(&got, time.Second, time.Hour)
+
+	// The service is not the root of the trace: its local root has a
+	// remote parent, and another request of the trace is in progress.
+	child := span(3, 2, false, -time.Millisecond, time.Millisecond)
+	e.ExportSpan(child)
+	e.ExportSpan(span(11, 10, false, 0, time.Millisecond))
+	if len(got) != 0 {
+		t.Fatalf("exported %d spans before the local root span; want 0", len(got))
+	}
+	e.ExportSpan(sentAt(span(2, 1, true, -2*time.Millisecond, time.Second), 0))
+	if len(got) != 2 {
+		t.Fatalf("exported %d spans; want the 2 of the local root", len(got))
+	}
+	if got[0].StartTime != base.Add(time.Millisecond) {
+		t.Errorf("child start = %v; want %v", got[0].StartTime, base.Add(time.Millisecond))
+	}
+	if child.StartTime == got[0].StartTime {
+		t.Error("the exported SpanData was modified")
+	}
+
+	e.Flush()
+	if len(got) != 3 {
+		t.Errorf("exported %d spans after Flush; want 3", len(got))
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slowspans provides a span processor that keeps the slowest
+// spans of each name and reports them periodically.
+//
+// It gives a quick view of where time goes before a tracing backend with
+// latency dashboards is in place:
+//
+//	p := slowspans.NewProcessor(5, time.Minute)
+//	trace.RegisterSpanProcessor(p)
+//	defer trace.UnregisterSpanProcessor(p)
+package slowspans // import "go.opentelemetry.io/sdk/trace/slowspans"
+
+import (
+	"container/heap"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/api/logger"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// Report holds the slowest spans of one name seen during a window.
+type Report struct {
+	Name string
+	// Spans are ordered from slowest to fastest.
+	Spans []*trace.SpanData
+}
+
+// ReportFunc receives the reports of a window that ended at end, ordered
+// by name.
+type ReportFunc func(end time.Time, reports []Report)
+
+// DefaultMaxNames is the default number of span names tracked in a
+// window.
+const DefaultMaxNames = 1000
+
+// Option configures a Processor.
+type Option func(*Processor)
+
+// WithReportFunc sets the function that receives the reports. The
+// default logs them at the logger.Info level.
+func WithReportFunc(f ReportFunc) Option {
+	return func(p *Processor) {
+		p.report = f
+	}
+}
+
+// WithMaxNames sets how many span names are tracked in a window. Spans
+// of further names are ignored until the window ends, so that span names
+// of high cardinality do not grow memory without bound. It defaults to
+// DefaultMaxNames.
+func WithMaxNames(n int) Option {
+	return func(p *Processor) {
+		if n > 0 {
+			p.maxNames = n
+		}
+	}
+}
+
+// Processor is a trace.SpanProcessor that keeps the n slowest spans of
+// each name and reports them at the end of every window.
+type Processor struct {
+	n        int
+	maxNames int
+	report   ReportFunc
+
+	mu      sync.Mutex
+	slowest map[string]*spanHeap
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+var _ trace.SpanProcessor = (*Processor)(nil)
+
+// NewProcessor returns a Processor that keeps the n slowest spans of each
+// name. If window is positive, the spans are reported and cleared every
+// window; otherwise they are only reported when Flush is called.
+func NewProcessor(n int, window time.Duration, opts ...Option) *Processor {
+	p := &Processor{
+		n:        n,
+		maxNames: DefaultMaxNames,
+		report:   logReport,
+		slowest:  make(map[string]*spanHeap),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if window > 0 {
+		go p.run(window)
+	} else {
+		close(p.done)
+	}
+	return p
+}
+
+// OnStart does nothing; spans are ranked when they end.
+func (p *Processor) OnStart(sd *trace.SpanData) {}
+
+// OnEnd records sd if it is among the n slowest spans of its name in the
+// current window.
+func (p *Processor) OnEnd(sd *trace.SpanData) {
+	if p.n <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h, ok := p.slowest[sd.Name]
+	if !ok {
+		if len(p.slowest) >= p.maxNames {
+			return
+		}
+		h = &spanHeap{}
+		p.slowest[sd.Name] = h
+	}
+	if h.Len() < p.n {
+		heap.Push(h, sd)
+		return
+	}
+	if duration(sd) > duration((*h)[0]) {
+		(*h)[0] = sd
+		heap.Fix(h, 0)
+	}
+}
+
+// Flush reports the spans of the current window and starts a new one.
+// Nothing is reported if no spans were recorded.
+func (p *Processor) Flush() {
+	p.mu.Lock()
+	slowest := p.slowest
+	p.slowest = make(map[string]*spanHeap)
+	p.mu.Unlock()
+
+	if len(slowest) == 0 {
+		return
+	}
+	reports := make([]Report, 0, len(slowest))
+	for name, h := range slowest {
+		spans := []*trace.SpanData(*h)
+		sort.Slice(spans, func(i, j int) bool {
+			return duration(spans[i]) > duration(spans[j])
+		})
+		reports = append(reports, Report{Name: name, Spans: spans})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Name < reports[j].Name
+	})
+	p.report(time.Now(), reports)
+}
+
+// Shutdown stops the periodic reports and reports the spans of the
+// current window. It is called by trace.UnregisterSpanProcessor.
+func (p *Processor) Shutdown() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+	p.Flush()
+}
+
+func (p *Processor) run(window time.Duration) {
+	defer close(p.done)
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.Flush()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func logReport(end time.Time, reports []Report) {
+	for _, r := range reports {
+		durations := make([]string, len(r.Spans))
+		for i, sd := range r.Spans {
+			durations[i] = duration(sd).String()
+		}
+		logger.Infof("slowest %q spans: %s", r.Name, strings.Join(durations, ", "))
+	}
+}
+
+func duration(sd *trace.SpanData) time.Duration {
+	return sd.EndTime.Sub(sd.StartTime)
+}
+
+// spanHeap is a min-heap of spans ordered by duration, so that the
+// fastest of the slowest spans is the one replaced.
+type spanHeap []*trace.SpanData
+
+func (h spanHeap) Len() int            { return len(h) }
+func (h spanHeap) Less(i, j int) bool  { return duration(h[i]) < duration(h[j]) }
+func (h spanHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *spanHeap) Push(x interface{}) { *h = append(*h, x.(*trace.SpanData)) }
+
+func (h *spanHeap) Pop() interface{} {
+	old := *h
+	sd := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return sd
+}
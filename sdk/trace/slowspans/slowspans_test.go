@@ -0,0 +1,132 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowspans
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/sdk/trace"
+)
+
+func span(name string, d time.Duration) *trace.SpanData {
+	start := time.Now()
+	return &trace.SpanData{Name: name, StartTime: start, EndTime: start.Add(d)}
+}
+
+func TestFlushReportsSlowestPerName(t *testing.T) {
+	var got []Report
+	e := NewProcessor(2, 0, WithReportFunc(func(_ time.Time, reports []Report) {
+		got = reports
+	}))
+
+	for _, d := range []time.Duration{3, 1, 5, 2, 4} {
+		e.OnEnd(span("b", d*time.Millisecond))
+	}
+	e.OnEnd(span("a", time.Second))
+	e.Flush()
+
+	if len(got) != 2 {
+		t.Fatalf("got %d reports, want 2", len(got))
+	}
+	if got[0].Name != "a" || len(got[0].Spans) != 1 {
+		t.Errorf("got report %q with %d spans, want \"a\" with 1", got[0].Name, len(got[0].Spans))
+	}
+	b := got[1]
+	if b.Name != "b" || len(b.Spans) != 2 {
+		t.Fatalf("got report %q with %d spans, want \"b\" with 2", b.Name, len(b.Spans))
+	}
+	for i, want := range []time.Duration{5 * time.Millisecond, 4 * time.Millisecond} {
+		if d := duration(b.Spans[i]); d != want {
+			t.Errorf("b.Spans[%d] duration = %v, want %v", i, d, want)
+		}
+	}
+}
+
+func TestFlushStartsNewWindow(t *testing.T) {
+	reports := 0
+	e := NewProcessor(1, 0, WithReportFunc(func(time.Time, []Report) {
+		reports++
+	}))
+	e.OnEnd(span("a", time.Millisecond))
+	e.Flush()
+	e.Flush()
+	if reports != 1 {
+		t.Errorf("got %d reports, want 1", reports)
+	}
+}
+
+func TestShutdownReportsCurrentWindow(t *testing.T) {
+	reported := make(chan []Report, 1)
+	e := NewProcessor(1, time.Hour, WithReportFunc(func(_ time.Time, reports []Report) {
+		reported <- reports
+	}))
+	e.OnEnd(span("a", time.Millisecond))
+	e.Shutdown()
+	select {
+	case r := <-reported:
+		if len(r) != 1 || r[0].Name != "a" {
+			t.Errorf("got %+v, want a report for \"a\"", r)
+		}
+	default:
+		t.Error("Shutdown did not report the current window")
+	}
+}
+
+func TestMaxNames(t *testing.T) {
+	var got []Report
+	p := NewProcessor(1, 0, WithMaxNames(2), WithReportFunc(func(_ time.Time, reports []Report) {
+		got = reports
+	}))
+	for _, name := range []string{"a", "b", "c", "a"} {
+		p.OnEnd(span(name, time.Millisecond))
+	}
+	p.Flush()
+	if len(got) != 2 || got[0].Name != "a" || got[1].Name != "b" {
+		t.Errorf("got %+v, want reports for the first 2 names", got)
+	}
+
+	// The limit applies per window.
+	p.OnEnd(span("c", time.Millisecond))
+	p.Flush()
+	if len(got) != 1 || got[0].Name != "c" {
+		t.Errorf("got %+v, want a report for \"c\"", got)
+	}
+}
+
+func TestRegisteredProcessor(t *testing.T) {
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	defer trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(1e-4)})
+
+	reported := make(chan []Report, 1)
+	p := NewProcessor(1, time.Hour, WithReportFunc(func(_ time.Time, reports []Report) {
+		reported <- reports
+	}))
+	trace.RegisterSpanProcessor(p)
+
+	_, sp := trace.Register().Start(context.Background(), "slow")
+	sp.Finish()
+	trace.UnregisterSpanProcessor(p)
+
+	select {
+	case r := <-reported:
+		if len(r) != 1 || r[0].Name != "slow" {
+			t.Errorf("got %+v, want a report for \"slow\"", r)
+		}
+	default:
+		t.Error("unregistering the processor did not report the span")
+	}
+}
@@ -16,19 +16,28 @@ package trace
 
 import (
 	"context"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"go.opentelemetry.io/api/core"
 	apievent "go.opentelemetry.io/api/event"
 	apitag "go.opentelemetry.io/api/tag"
 	apitrace "go.opentelemetry.io/api/trace"
 	"go.opentelemetry.io/sdk/internal"
+	"go.opentelemetry.io/sdk/memlimit"
 	"google.golang.org/grpc/codes"
 )
 
 // span implements apitrace.Span interface.
 type span struct {
+	// goroutineID is the goroutine that started the span when context
+	// diagnostics are enabled, otherwise 0. Please keep it as the first
+	// field so that it is aligned for atomic access on 32-bit machines.
+	goroutineID uint64
+
 	// data contains information recorded about the span.
 	//
 	// It will be non-nil if we are exporting the span or recording events for it.
@@ -38,6 +47,17 @@ type span struct {
 	mu          sync.Mutex // protects the contents of *data (but not the pointer value.)
 	spanContext core.SpanContext
 
+	// recorded is what data points to when the span is recording. Along
+	// with ctx, it is part of the span, so that starting and ending a span
+	// without attributes, events or links allocates only the span.
+	recorded SpanData
+	ctx      spanCtx
+
+	// cfg is the configuration the span was started with. It holds the
+	// limits of the attributes, events and links, whose stores are only
+	// created when the first one is added.
+	cfg *Config
+
 	// lruAttributes are capped at configured limit. When the capacity is reached an oldest entry
 	// is removed to create room for a new entry.
 	lruAttributes *lruMap
@@ -45,18 +65,59 @@ type span struct {
 	// messageEvents are stored in FIFO queue capped by configured limit.
 	messageEvents *evictedQueue
 
+	// eventRateLimiter caps the rate of message events. It is nil when the
+	// rate is unlimited.
+	eventRateLimiter *eventRateLimiter
+
 	// links are stored in FIFO queue capped by configured limit.
 	links *evictedQueue
 
+	// droppedEventAttributes is the number of attributes dropped from
+	// message events because of Config.MaxAttributesPerEvent.
+	droppedEventAttributes int
+
 	// spanStore is the spanStore this span belongs to, if any, otherwise it is nil.
 	//*spanStore
 	endOnce sync.Once
 
 	executionTracerTaskEnd func()          // ends the execution tracer span
 	tracer                 apitrace.Tracer // tracer used to create span.
+
+	// attributeNamespace prefixes the keys of attributes set on the span,
+	// except for the keys starting with one of namespaceExempt.
+	attributeNamespace string
+	namespaceExempt    []string
+
+	// eventRates are the sampling rates of message events by message.
+	eventRates map[string]float64
+
+	// reserved is the number of bytes reserved with the memlimit
+	// package for the span and its events, released when it ends.
+	reserved int64
+
+	// verbose is set when the sampler chose the span, or its local parent,
+	// for verbose recording.
+	verbose bool
+
+	// live is set when the span is tracked for DumpTrace.
+	live bool
+
+	// heartbeatInterval is the interval given to WithHeartbeat. The
+	// heartbeat timer is nil once the span ended; heartbeats counts the
+	// heartbeats so far. Both are protected by mu.
+	heartbeatInterval time.Duration
+	heartbeat         *time.Timer
+	heartbeats        int
+
+	// parent is the local span this span was started from, if child
+	// span durations are enabled. The duration of the span is added to
+	// it when the span ends.
+	parent *span
 }
 
 var _ apitrace.Span = &span{}
+var _ apitrace.LinkAdder = &span{}
+var _ apitrace.StatusMessageSetter = &span{}
 
 func (s *span) SpanContext() core.SpanContext {
 	if s == nil {
@@ -72,7 +133,17 @@ func (s *span) IsRecordingEvents() bool {
 	return s.data != nil
 }
 
+// IsRecordingVerbose implements apitrace.VerboseRecorder.
+func (s *span) IsRecordingVerbose() bool {
+	return s.IsRecordingEvents() && s.verbose
+}
+
 func (s *span) SetStatus(status codes.Code) {
+	s.SetStatusWithMessage(status, "")
+}
+
+// SetStatusWithMessage implements apitrace.StatusMessageSetter.
+func (s *span) SetStatusWithMessage(status codes.Code, message string) {
 	if s == nil {
 		return
 	}
@@ -81,6 +152,7 @@ func (s *span) SetStatus(status codes.Code) {
 	}
 	s.mu.Lock()
 	s.data.Status = status
+	s.data.StatusMessage = message
 	s.mu.Unlock()
 }
 
@@ -98,19 +170,57 @@ func (s *span) SetAttributes(attributes ...core.KeyValue) {
 	s.copyToCappedAttributes(attributes...)
 }
 
-// ModifyAttribute does nothing.
+// ModifyAttribute applies mutator to the attributes of the span. INSERT
+// only sets attributes not set yet, UPDATE only those already set.
+// Attributes over Config.MaxAttributesPerSpan evict the least recently
+// set ones, as for SetAttribute.
 func (s *span) ModifyAttribute(mutator apitag.Mutator) {
+	s.ModifyAttributes(mutator)
 }
 
-// ModifyAttributes does nothing.
+// ModifyAttributes applies mutators to the attributes of the span, in
+// order, as ModifyAttribute does.
 func (s *span) ModifyAttributes(mutators ...apitag.Mutator) {
+	if !s.IsRecordingEvents() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range mutators {
+		k := m.Key
+		if s.attributeNamespace != "" {
+			k = namespaceKey(s.attributeNamespace, s.namespaceExempt, k)
+		}
+		attrs := s.attributes()
+		switch m.MutatorOp {
+		case apitag.INSERT:
+			if !attrs.contains(k) {
+				attrs.add(k, m.Value)
+			}
+		case apitag.UPDATE:
+			if attrs.contains(k) {
+				attrs.add(k, m.Value)
+			}
+		case apitag.UPSERT:
+			attrs.add(k, m.Value)
+		case apitag.DELETE:
+			attrs.remove(k)
+		}
+	}
 }
 
 func (s *span) Finish() {
 	if s == nil {
 		return
 	}
+	// A deferred Finish records the panic unwinding the function that
+	// started the span, and lets it continue once the span is ended.
+	if recovered := recover(); recovered != nil {
+		apitrace.RecordPanic(context.Background(), s, recovered)
+		defer panic(recovered)
+	}
 
+	untrackSpan(s)
 	if s.executionTracerTaskEnd != nil {
 		s.executionTracerTaskEnd()
 	}
@@ -118,49 +228,172 @@ func (s *span) Finish() {
 		return
 	}
 	s.endOnce.Do(func() {
+		defer s.releaseMemory()
+		s.stopHeartbeat()
+		untrackLiveSpan(s)
+		reportDropped(s)
+		endTime := internal.MonotonicEndTime(s.data.StartTime)
+		if s.parent != nil {
+			s.parent.addChildDuration(endTime.Sub(s.data.StartTime))
+		}
+		if !s.spanContext.IsSampled() {
+			return
+		}
 		exp, _ := exporters.Load().(exportersMap)
-		mustExport := s.spanContext.IsSampled() && len(exp) > 0
-		//if s.spanStore != nil || mustExport {
-		if mustExport {
-			sd := s.makeSpanData()
-			sd.EndTime = internal.MonotonicEndTime(sd.StartTime)
-			//if s.spanStore != nil {
-			//	s.spanStore.finished(s, sd)
-			//}
-			if mustExport {
-				for e := range exp {
-					e.ExportSpan(sd)
-				}
+		procs := loadProcessors()
+		if len(exp) == 0 && len(procs) == 0 {
+			return
+		}
+		sd := s.makeSpanData()
+		sd.EndTime = endTime
+		// Every processor and exporter gets a copy of its own, since
+		// they may keep it and pass it to other goroutines.
+		consumers := len(procs) + len(exp)
+		own := func() *SpanData {
+			consumers--
+			if consumers == 0 {
+				return sd
 			}
+			return sd.clone()
+		}
+		for _, p := range procs {
+			p.OnEnd(own())
+		}
+		for e, q := range exp {
+			exportSpan(e, q, own())
 		}
 	})
 }
 
+// onStart passes the initial state of s to the registered processors.
+func (s *span) onStart() {
+	if !s.spanContext.IsSampled() {
+		return
+	}
+	procs := loadProcessors()
+	if len(procs) == 0 {
+		return
+	}
+	sd := s.makeSpanData()
+	for _, p := range procs {
+		p.OnStart(sd)
+	}
+}
+
 func (s *span) Tracer() apitrace.Tracer {
 	return s.tracer
 }
 
-func (s *span) AddEvent(ctx context.Context, event apievent.Event) {
-	if !s.IsRecordingEvents() {
+// AddEvent records ev at the time it happened if it implements
+// apievent.Timed, otherwise at the current time.
+func (s *span) AddEvent(ctx context.Context, ev apievent.Event) {
+	if !s.IsRecordingEvents() || !s.sampleEvent(ev.Message()) {
 		return
 	}
+	now := time.Now()
+	t := now
+	if timed, ok := ev.(apievent.Timed); ok {
+		t = timed.Time()
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.messageEvents.add(event)
+	if !s.allowEvent(now) || !s.reserveEvent(ev.Message(), ev.Attributes()) {
+		return
+	}
+	s.events().add(s.newEvent(t, ev.Message(), ev.Attributes()))
 }
 
 func (s *span) Event(ctx context.Context, msg string, attrs ...core.KeyValue) {
-	if !s.IsRecordingEvents() {
+	if !s.IsRecordingEvents() || !s.sampleEvent(msg) {
 		return
 	}
 	now := time.Now()
 	s.mu.Lock()
-	s.messageEvents.add(event{
-		msg:        msg,
-		attributes: attrs,
-		time:       now,
-	})
+	defer s.mu.Unlock()
+	if !s.allowEvent(now) || !s.reserveEvent(msg, attrs) {
+		return
+	}
+	s.events().add(s.newEvent(now, msg, attrs))
+}
+
+// newEvent returns an event keeping the first Config.MaxAttributesPerEvent
+// of attrs. s.mu must be held.
+func (s *span) newEvent(t time.Time, msg string, attrs []core.KeyValue) event {
+	ev := event{msg: msg, attributes: attrs, time: t}
+	if max := s.cfg.MaxAttributesPerEvent; max > 0 && len(attrs) > max {
+		ev.attributes = attrs[:max:max]
+		ev.droppedAttributes = len(attrs) - max
+		s.droppedEventAttributes += ev.droppedAttributes
+	}
+	return ev
+}
+
+// AddLink implements apitrace.LinkAdder. Links over Config.MaxLinksPerSpan
+// evict the oldest ones and are counted in SpanData.DroppedLinkCount.
+func (s *span) AddLink(link apitrace.Link) {
+	if !s.IsRecordingEvents() || !link.IsValid() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.links == nil {
+		s.links = newEvictedQueue(s.cfg.MaxLinksPerSpan)
+	}
+	s.links.add(link)
+}
+
+// allowEvent reports whether an event at time now is within the configured
+// event rate. s.mu must be held.
+func (s *span) allowEvent(now time.Time) bool {
+	return s.eventRateLimiter == nil || s.eventRateLimiter.allow(now)
+}
+
+// sampleEvent reports whether an event with message msg is kept by the
+// event sampling rates of the span.
+func (s *span) sampleEvent(msg string) bool {
+	rate, ok := s.eventRates[msg]
+	if !ok || rate >= 1 {
+		return true
+	}
+	return rate > 0 && rand.Float64() < rate
+}
+
+// reserveEvent reserves memory for an event, and reports whether the
+// event is kept under the memory budget. s.mu must be held.
+func (s *span) reserveEvent(msg string, attrs []core.KeyValue) bool {
+	n := eventSize(msg, attrs)
+	if !memlimit.Reserve(memlimit.Event, n) {
+		return false
+	}
+	s.reserved += n
+	return true
+}
+
+// releaseMemory releases the memory reserved for s.
+func (s *span) releaseMemory() {
+	s.mu.Lock()
+	n := s.reserved
+	s.reserved = 0
 	s.mu.Unlock()
+	memlimit.Release(n)
+}
+
+// events returns the message event queue of s, creating it on first use.
+// s.mu must be held.
+func (s *span) events() *evictedQueue {
+	if s.messageEvents == nil {
+		s.messageEvents = newEvictedQueue(s.cfg.MaxEventsPerSpan)
+	}
+	return s.messageEvents
+}
+
+// attributes returns the attribute map of s, creating it on first use.
+// s.mu must be held.
+func (s *span) attributes() *lruMap {
+	if s.lruAttributes == nil {
+		s.lruAttributes = newLruMap(s.cfg.MaxAttributesPerSpan)
+	}
+	return s.lruAttributes
 }
 
 // makeSpanData produces a SpanData representing the current state of the span.
@@ -170,14 +403,31 @@ func (s *span) makeSpanData() *SpanData {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	sd = *s.data
-	if s.lruAttributes.simpleLruMap.Len() > 0 {
-		sd.Attributes = s.lruAttributesToAttributeMap()
+	tr, _ := s.tracer.(*tracer)
+	sd.Resource = tr.resourceAttributes(s.cfg.Resource)
+	if tr != nil {
+		sd.InstrumentationLibrary = tr.library
+	}
+	if s.lruAttributes != nil {
+		if s.lruAttributes.simpleLruMap.Len() > 0 {
+			sd.Attributes = s.lruAttributesToAttributeMap()
+		}
 		sd.DroppedAttributeCount = s.lruAttributes.droppedCount
 	}
-	if len(s.messageEvents.queue) > 0 {
+	if s.messageEvents != nil && len(s.messageEvents.queue) > 0 {
 		sd.MessageEvents = s.interfaceArrayToMessageEventArray()
 		sd.DroppedMessageEventCount = s.messageEvents.droppedCount
 	}
+	if s.eventRateLimiter != nil {
+		sd.DroppedMessageEventCount += s.eventRateLimiter.droppedCount
+	}
+	if s.links != nil && len(s.links.queue) > 0 {
+		sd.Links = make([]apitrace.Link, len(s.links.queue))
+		for i, l := range s.links.queue {
+			sd.Links[i] = l.(apitrace.Link)
+		}
+		sd.DroppedLinkCount = s.links.droppedCount
+	}
 	return &sd
 }
 
@@ -192,7 +442,9 @@ func (s *span) interfaceArrayToMessageEventArray() []event {
 func (s *span) lruAttributesToAttributeMap() map[string]interface{} {
 	attributes := make(map[string]interface{})
 	for _, key := range s.lruAttributes.simpleLruMap.Keys() {
-		value, ok := s.lruAttributes.simpleLruMap.Get(key)
+		// Peek rather than Get, which would change the order of
+		// eviction when live spans are snapshot.
+		value, ok := s.lruAttributes.simpleLruMap.Peek(key)
 		if ok {
 			key := key.(core.Key)
 			attributes[key.Variable.Name] = value
@@ -205,7 +457,10 @@ func (s *span) copyToCappedAttributes(attributes ...core.KeyValue) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	for _, a := range attributes {
-		s.lruAttributes.add(a.Key, a.Value)
+		if s.attributeNamespace != "" {
+			a.Key = namespaceKey(s.attributeNamespace, s.namespaceExempt, a.Key)
+		}
+		s.attributes().add(a.Key, a.Value)
 	}
 }
 
@@ -218,39 +473,81 @@ func (s *span) addChild() {
 	s.mu.Unlock()
 }
 
-func startSpanInternal(name string, parent core.SpanContext, remoteParent bool, o apitrace.SpanOptions) *span {
+var childSpanDurations int32 // access atomically
+
+// SetChildSpanDurations enables or disables summing the durations of the
+// child spans of each span into SpanData.ChildSpanDuration, from which
+// SelfTime is computed. It is disabled by default, since it keeps every
+// span reachable from its children until they end.
+func SetChildSpanDurations(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&childSpanDurations, v)
+}
+
+// addChildDuration adds the duration of a child span that has ended to
+// the time accounted to children.
+func (s *span) addChildDuration(d time.Duration) {
+	if !s.IsRecordingEvents() {
+		return
+	}
+	s.mu.Lock()
+	s.data.ChildSpanDuration += d
+	s.mu.Unlock()
+}
+
+func startSpanInternal(tr *tracer, name string, parent core.SpanContext, remoteParent bool, o apitrace.SpanOptions) *span {
 	var noParent bool
+	var decision SamplingDecision
 	span := &span{}
 	span.spanContext = parent
 
 	cfg := config.Load().(*Config)
+	custom := customSpanOptions(o)
+	if custom.limits != nil {
+		c := *cfg
+		custom.limits.apply(&c)
+		cfg = &c
+	}
+	span.cfg = cfg
+	name = truncateName(name, cfg.MaxSpanNameLength)
 
+	ids := cfg.IDGenerator
+	if tr.idGenerator != nil {
+		ids = tr.idGenerator
+	}
 	if parent == core.EmptySpanContext() {
-		span.spanContext.TraceID = cfg.IDGenerator.NewTraceID()
+		span.spanContext.TraceID = ids.NewTraceID()
 		noParent = true
 	}
-	span.spanContext.SpanID = cfg.IDGenerator.NewSpanID()
+	span.spanContext.SpanID = newSpanID(ids, span.spanContext.TraceID)
 	sampler := cfg.DefaultSampler
 
-	// TODO: [rghetia] fix sampler
-	//if !hasParent || remoteParent || o.Sampler != nil {
-	if noParent || remoteParent {
+	if noParent || remoteParent || custom.sampler != nil {
 		// If this span is the child of a local span and no Sampler is set in the
 		// options, keep the parent's TraceOptions.
 		//
 		// Otherwise, consult the Sampler in the options if it is non-nil, otherwise
 		// the default sampler.
-		//if o.Sampler != nil {
-		//	sampler = o.Sampler
-		//}
-		sampled := sampler(SamplingParameters{
+		if custom.sampler != nil {
+			sampler = custom.sampler
+		}
+		decision = sampler(SamplingParameters{
 			ParentContext:   parent,
 			TraceID:         span.spanContext.TraceID,
 			SpanID:          span.spanContext.SpanID,
 			Name:            name,
-			HasRemoteParent: remoteParent}).Sample
-		if sampled {
+			HasRemoteParent: remoteParent,
+			Kind:            o.SpanKind,
+			Attributes:      o.Attributes,
+			Resource:        tr.resourceAttributes(cfg.Resource)})
+		if decision.Sample {
 			span.spanContext.TraceOptions = core.TraceOptionSampled
+			span.verbose = decision.Verbose
+		} else {
+			span.spanContext.TraceOptions &^= core.TraceOptionSampled
 		}
 	}
 
@@ -259,18 +556,33 @@ func startSpanInternal(name string, parent core.SpanContext, remoteParent bool,
 	if !span.spanContext.IsSampled() && !o.RecordEvent {
 		return span
 	}
+	// Unsampled spans are only recorded while the memory budget allows.
+	if !span.spanContext.IsSampled() {
+		n := int64(spanOverhead + len(name))
+		if !memlimit.Reserve(memlimit.UnsampledSpan, n) {
+			return span
+		}
+		span.reserved = n
+	}
 
-	span.data = &SpanData{
-		SpanContext: span.spanContext,
-		StartTime:   time.Now(),
-		// TODO;[rghetia] : fix spanKind
-		//SpanKind:        o.SpanKind,
+	span.recorded = SpanData{
+		SpanContext:     span.spanContext,
+		StartTime:       time.Now(),
+		SpanKind:        o.SpanKind,
 		Name:            name,
 		HasRemoteParent: remoteParent,
 	}
-	span.lruAttributes = newLruMap(cfg.MaxAttributesPerSpan)
-	span.messageEvents = newEvictedQueue(cfg.MaxEventsPerSpan)
-	span.links = newEvictedQueue(cfg.MaxLinksPerSpan)
+	span.data = &span.recorded
+	span.heartbeatInterval = custom.heartbeat
+	if len(decision.Attributes) > 0 {
+		attrs := span.attributes()
+		for _, a := range decision.Attributes {
+			attrs.add(a.Key, a.Value)
+		}
+	}
+	if cfg.MaxEventsPerSecondPerSpan > 0 {
+		span.eventRateLimiter = newEventRateLimiter(cfg.MaxEventsPerSecondPerSpan)
+	}
 
 	if !noParent {
 		span.data.ParentSpanID = parent.SpanID
@@ -286,3 +598,20 @@ func startSpanInternal(name string, parent core.SpanContext, remoteParent bool,
 
 	return span
 }
+
+// truncateName returns name cut to at most max bytes, ending with
+// SpanNameTruncationMarker if it was cut. It never splits a UTF-8 sequence.
+func truncateName(name string, max int) string {
+	if max <= 0 || len(name) <= max {
+		return name
+	}
+	marker := SpanNameTruncationMarker
+	if max < len(marker) {
+		marker = ""
+	}
+	n := max - len(marker)
+	for n > 0 && !utf8.RuneStart(name[n]) {
+		n--
+	}
+	return name[:n] + marker
+}
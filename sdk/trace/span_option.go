@@ -0,0 +1,102 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"time"
+
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+// samplerOption, limitsOption and heartbeatOption are the values
+// WithSampler, WithLimits and WithHeartbeat add to SpanOptions.Custom.
+type samplerOption struct {
+	s Sampler
+}
+
+type limitsOption struct {
+	l SpanLimits
+}
+
+type heartbeatOption struct {
+	interval time.Duration
+}
+
+// customOptions holds the options of this SDK given to Start.
+type customOptions struct {
+	sampler   Sampler
+	limits    *SpanLimits
+	heartbeat time.Duration
+}
+
+// WithSampler returns a SpanOption making s decide whether the span is
+// sampled, in place of Config.DefaultSampler, e.g., to never sample
+// health checks. Unlike the default sampler, which only decides for root
+// spans and spans with a remote parent, s also decides for the children
+// of local spans.
+func WithSampler(s Sampler) apitrace.SpanOption {
+	return func(o *apitrace.SpanOptions) {
+		o.Custom = append(o.Custom, samplerOption{s})
+	}
+}
+
+// WithLimits returns a SpanOption overriding the limits of Config for the
+// span, e.g., to allow more attributes on audit spans. Zero fields keep
+// the limits of Config; a MaxSpanNameLength of NoSpanNameLimit removes
+// the limit on the name.
+func WithLimits(l SpanLimits) apitrace.SpanOption {
+	return func(o *apitrace.SpanOptions) {
+		o.Custom = append(o.Custom, limitsOption{l})
+	}
+}
+
+// customSpanOptions returns the options of this SDK in o. The last one
+// of each kind wins.
+func customSpanOptions(o apitrace.SpanOptions) customOptions {
+	var c customOptions
+	for _, opt := range o.Custom {
+		switch opt := opt.(type) {
+		case samplerOption:
+			c.sampler = opt.s
+		case limitsOption:
+			l := opt.l
+			c.limits = &l
+		case heartbeatOption:
+			c.heartbeat = opt.interval
+		}
+	}
+	return c
+}
+
+// apply sets the non-zero limits of l in c.
+func (l SpanLimits) apply(c *Config) {
+	if l.MaxEventsPerSpan > 0 {
+		c.MaxEventsPerSpan = l.MaxEventsPerSpan
+	}
+	if l.MaxAttributesPerSpan > 0 {
+		c.MaxAttributesPerSpan = l.MaxAttributesPerSpan
+	}
+	if l.MaxAttributesPerEvent > 0 {
+		c.MaxAttributesPerEvent = l.MaxAttributesPerEvent
+	}
+	if l.MaxLinksPerSpan > 0 {
+		c.MaxLinksPerSpan = l.MaxLinksPerSpan
+	}
+	if l.MaxSpanNameLength > 0 {
+		c.MaxSpanNameLength = l.MaxSpanNameLength
+	} else if l.MaxSpanNameLength == NoSpanNameLimit {
+		c.MaxSpanNameLength = 0
+	}
+}
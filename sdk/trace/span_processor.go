@@ -0,0 +1,161 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// SpanProcessor observes the lifecycle of sampled spans, e.g., to export
+// them or to keep statistics.
+//
+// The methods are called synchronously by the goroutine starting or
+// ending the span, so they should return quickly. Each processor gets a
+// SpanData of its own, which it may keep and modify.
+type SpanProcessor interface {
+	// OnStart is called when a sampled span starts. The SpanData holds
+	// the state of the span at that point and has a zero EndTime.
+	OnStart(sd *SpanData)
+
+	// OnEnd is called when a sampled span ends.
+	OnEnd(sd *SpanData)
+
+	// Shutdown is called when the processor is unregistered. It should
+	// release resources and flush buffered spans.
+	Shutdown()
+}
+
+var (
+	processorMu sync.Mutex
+	processors  atomic.Value // []SpanProcessor
+)
+
+// RegisterSpanProcessor adds p to the processors that observe sampled
+// spans. Processors are called in the order they were registered.
+func RegisterSpanProcessor(p SpanProcessor) {
+	processorMu.Lock()
+	defer processorMu.Unlock()
+	old, _ := processors.Load().([]SpanProcessor)
+	new := make([]SpanProcessor, len(old), len(old)+1)
+	copy(new, old)
+	processors.Store(append(new, p))
+}
+
+// UnregisterSpanProcessor removes p from the registered processors and
+// shuts it down.
+func UnregisterSpanProcessor(p SpanProcessor) {
+	processorMu.Lock()
+	old, _ := processors.Load().([]SpanProcessor)
+	new := make([]SpanProcessor, 0, len(old))
+	found := false
+	for _, sp := range old {
+		if sp == p {
+			found = true
+			continue
+		}
+		new = append(new, sp)
+	}
+	processors.Store(new)
+	processorMu.Unlock()
+
+	if found {
+		p.Shutdown()
+	}
+}
+
+func loadProcessors() []SpanProcessor {
+	ps, _ := processors.Load().([]SpanProcessor)
+	return ps
+}
+
+// SpanFilter selects the spans passed to a processor.
+type SpanFilter func(sd *SpanData) bool
+
+type filterSpanProcessor struct {
+	SpanProcessor
+	filter SpanFilter
+}
+
+// FilterSpanProcessor returns a SpanProcessor that passes to p only the
+// spans filter returns true for, so that, e.g., only some spans go to an
+// expensive exporter while other processors see them all:
+//
+//	bsp, err := trace.NewBatchSpanProcessor(exporter)
+//	...
+//	trace.RegisterSpanProcessor(trace.FilterSpanProcessor(bsp, func(sd *trace.SpanData) bool {
+//		return strings.HasPrefix(sd.Name, "HTTP ")
+//	}))
+//
+// filter is called by both OnStart and OnEnd. For p to see both calls for
+// a span, filter should only depend on what is known when a span starts,
+// such as its name, resource and the attributes given to Start.
+// Unregistering the returned processor shuts down p.
+func FilterSpanProcessor(p SpanProcessor, filter SpanFilter) SpanProcessor {
+	return &filterSpanProcessor{SpanProcessor: p, filter: filter}
+}
+
+func (f *filterSpanProcessor) OnStart(sd *SpanData) {
+	if f.filter(sd) {
+		f.SpanProcessor.OnStart(sd)
+	}
+}
+
+func (f *filterSpanProcessor) OnEnd(sd *SpanData) {
+	if f.filter(sd) {
+		f.SpanProcessor.OnEnd(sd)
+	}
+}
+
+// OnHeartbeat passes sd to p if it is a HeartbeatProcessor and filter
+// returns true for sd.
+func (f *filterSpanProcessor) OnHeartbeat(sd *SpanData) {
+	if hp, ok := f.SpanProcessor.(HeartbeatProcessor); ok && f.filter(sd) {
+		hp.OnHeartbeat(sd)
+	}
+}
+
+// simpleSpanProcessor exports each span when it ends.
+type simpleSpanProcessor struct {
+	e SpanExporter
+}
+
+// NewSimpleSpanProcessor returns a SpanProcessor that passes each span to
+// e when it ends, on the goroutine ending the span. The export is bounded
+// by the configured ExportTimeout and retried like those of a
+// ContextExporter; spans whose export fails are dropped.
+//
+// It suits tests and exporters that return quickly. Use a
+// BatchSpanProcessor otherwise.
+func NewSimpleSpanProcessor(e SpanExporter) SpanProcessor {
+	return &simpleSpanProcessor{e: e}
+}
+
+func (p *simpleSpanProcessor) OnStart(sd *SpanData) {}
+
+func (p *simpleSpanProcessor) OnEnd(sd *SpanData) {
+	enrich(sd)
+	spans := []*SpanData{sd}
+	err := exportWithRetry(func(ctx context.Context) error {
+		return p.e.ExportSpans(ctx, spans)
+	})
+	if err != nil {
+		dropSpans(1, fmt.Errorf("dropped span %q: %v", sd.Name, err))
+	}
+}
+
+func (p *simpleSpanProcessor) Shutdown() {}
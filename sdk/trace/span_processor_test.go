@@ -0,0 +1,201 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/api/key"
+	apitrace "go.opentelemetry.io/api/trace"
+)
+
+type recordingProcessor struct {
+	started, ended []*SpanData
+	shutdown       int
+}
+
+func (p *recordingProcessor) OnStart(sd *SpanData) { p.started = append(p.started, sd) }
+func (p *recordingProcessor) OnEnd(sd *SpanData)   { p.ended = append(p.ended, sd) }
+func (p *recordingProcessor) Shutdown()            { p.shutdown++ }
+
+func TestSpanProcessor(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: ProbabilitySampler(0)})
+
+	p := &recordingProcessor{}
+	RegisterSpanProcessor(p)
+
+	_, span := apitrace.GlobalTracer().Start(context.Background(), "span0",
+		apitrace.ChildOf(remoteSpanContext()))
+	if len(p.started) != 1 || p.started[0].Name != "span0" || !p.started[0].EndTime.IsZero() {
+		t.Errorf("OnStart got %v; want the started span0", p.started)
+	}
+	span.Finish()
+	if len(p.ended) != 1 || p.ended[0].EndTime.IsZero() {
+		t.Errorf("OnEnd got %v; want the ended span0", p.ended)
+	}
+
+	// Unsampled spans are not passed to processors, even if they record
+	// events.
+	_, span = apitrace.GlobalTracer().Start(context.Background(), "span1",
+		apitrace.WithRecordEvents())
+	span.Finish()
+	if len(p.started) != 1 || len(p.ended) != 1 {
+		t.Errorf("got %d starts and %d ends; want the unsampled span1 skipped", len(p.started), len(p.ended))
+	}
+
+	UnregisterSpanProcessor(p)
+	if p.shutdown != 1 {
+		t.Errorf("Shutdown called %d times on unregister; want 1", p.shutdown)
+	}
+	_, span = apitrace.GlobalTracer().Start(context.Background(), "span2",
+		apitrace.ChildOf(remoteSpanContext()))
+	span.Finish()
+	if len(p.started) != 1 {
+		t.Errorf("unregistered processor saw %d starts; want 1", len(p.started))
+	}
+}
+
+func TestSpanProcessorsGetOwnSpanData(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: ProbabilitySampler(0)})
+
+	p1, p2 := &recordingProcessor{}, &recordingProcessor{}
+	RegisterSpanProcessor(p1)
+	RegisterSpanProcessor(p2)
+	defer UnregisterSpanProcessor(p1)
+	defer UnregisterSpanProcessor(p2)
+
+	_, span := apitrace.GlobalTracer().Start(context.Background(), "span0",
+		apitrace.ChildOf(remoteSpanContext()))
+	span.SetAttribute(key.New("k").String("v"))
+	span.Finish()
+
+	if len(p1.ended) != 1 || len(p2.ended) != 1 {
+		t.Fatalf("got %d and %d ends; want 1 each", len(p1.ended), len(p2.ended))
+	}
+	if p1.ended[0] == p2.ended[0] {
+		t.Fatal("processors share a SpanData")
+	}
+	p1.ended[0].Attributes["k"] = "changed"
+	if v := p2.ended[0].Attributes["k"]; v == "changed" {
+		t.Error("processors share the Attributes of a SpanData")
+	}
+}
+
+func TestBatchSpanProcessorOnEnd(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: ProbabilitySampler(0)})
+
+	e := &batchExporter{}
+	bsp, err := NewBatchSpanProcessor(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterSpanProcessor(bsp)
+
+	_, span := apitrace.GlobalTracer().Start(context.Background(), "sampled",
+		apitrace.ChildOf(remoteSpanContext()))
+	span.Finish()
+	_, span = apitrace.GlobalTracer().Start(context.Background(), "unsampled",
+		apitrace.WithRecordEvents())
+	span.Finish()
+
+	UnregisterSpanProcessor(bsp)
+	if got := e.spans(); got != 1 {
+		t.Errorf("exported %d spans; want only the sampled one", got)
+	}
+}
+
+func TestFilterSpanProcessor(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+
+	all := &recordingProcessor{}
+	server := &recordingProcessor{}
+	filtered := FilterSpanProcessor(server, func(sd *SpanData) bool {
+		return sd.Name == "server"
+	})
+	RegisterSpanProcessor(all)
+	defer UnregisterSpanProcessor(all)
+	RegisterSpanProcessor(filtered)
+
+	tr := &tracer{}
+	for _, name := range []string{"client", "server", "internal"} {
+		_, span := tr.Start(context.Background(), name)
+		span.Finish()
+	}
+	if len(all.started) != 3 || len(all.ended) != 3 {
+		t.Errorf("unfiltered processor got %d starts and %d ends, want 3 and 3", len(all.started), len(all.ended))
+	}
+	if len(server.started) != 1 || len(server.ended) != 1 || server.ended[0].Name != "server" {
+		t.Errorf("filtered processor got %v and %v, want only the server span", server.started, server.ended)
+	}
+
+	UnregisterSpanProcessor(filtered)
+	if server.shutdown != 1 {
+		t.Errorf("filtered processor shut down %d times, want 1", server.shutdown)
+	}
+}
+
+type recordingSpanExporter struct {
+	spans []*SpanData
+	err   error
+}
+
+func (e *recordingSpanExporter) ExportSpans(ctx context.Context, spans []*SpanData) error {
+	e.spans = append(e.spans, spans...)
+	return e.err
+}
+
+func TestSimpleSpanProcessor(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: ProbabilitySampler(0)})
+
+	e := &recordingSpanExporter{}
+	p := NewSimpleSpanProcessor(e)
+	RegisterSpanProcessor(p)
+	defer UnregisterSpanProcessor(p)
+
+	_, s := apitrace.GlobalTracer().Start(context.Background(), "span0",
+		apitrace.ChildOf(remoteSpanContext()),
+		apitrace.WithSpanKind(apitrace.SpanKindClient))
+	if len(e.spans) != 0 {
+		t.Fatalf("exported %d spans before the span ended; want 0", len(e.spans))
+	}
+	s.Finish()
+	if len(e.spans) != 1 {
+		t.Fatalf("exported %d spans; want 1", len(e.spans))
+	}
+	if got := e.spans[0]; got.Name != "span0" || got.SpanKind != apitrace.SpanKindClient || got.EndTime.IsZero() {
+		t.Errorf("exported %+v; want the ended client span0", got)
+	}
+
+	e.err = errors.New("unavailable")
+	dropped := DroppedSpans()
+	_, s = apitrace.GlobalTracer().Start(context.Background(), "span1",
+		apitrace.ChildOf(remoteSpanContext()))
+	s.Finish()
+	if got := DroppedSpans() - dropped; got != 1 {
+		t.Errorf("DroppedSpans() grew by %d after a failed export; want 1", got)
+	}
+}
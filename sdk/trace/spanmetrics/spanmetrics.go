@@ -0,0 +1,303 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spanmetrics provides a span processor that aggregates spans
+// into latency histograms served in the Prometheus text format.
+//
+// It is meant for services whose volume is too high to export spans: with
+// no exporter registered, spans are dropped once aggregated, and only the
+// metrics leave the process.
+//
+//	p := spanmetrics.Install(spanmetrics.WithAlwaysSample())
+//	http.Handle("/metrics", p)
+//
+// The histograms are named span_duration_seconds, with a span_name and a
+// status_code label, e.g.,
+//
+//	span_duration_seconds_bucket{span_name="GET /users",status_code="OK",le="0.1"} 42
+//
+// WithAttributeLabels adds labels holding span attributes, whose keys are
+// sanitized into valid Prometheus label names.
+package spanmetrics // import "go.opentelemetry.io/sdk/trace/spanmetrics"
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/sdk/internal"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// DefaultBuckets are the default upper bounds of the histogram buckets,
+// in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultMaxNames is the default number of span names with histograms of
+// their own.
+const DefaultMaxNames = 1000
+
+// OtherName is the span_name label of the spans of names beyond the
+// limit set by WithMaxNames.
+const OtherName = "_other"
+
+// Option configures a Processor.
+type Option func(*Processor)
+
+// WithBuckets sets the upper bounds of the histogram buckets, in seconds.
+// It defaults to DefaultBuckets.
+func WithBuckets(bounds ...float64) Option {
+	return func(p *Processor) {
+		p.bounds = append([]float64(nil), bounds...)
+		sort.Float64s(p.bounds)
+	}
+}
+
+// WithMaxNames sets how many span names get histograms of their own.
+// Spans of further names are aggregated under OtherName, so that span
+// names of high cardinality do not grow memory and scrapes without bound.
+// It defaults to DefaultMaxNames.
+func WithMaxNames(n int) Option {
+	return func(p *Processor) {
+		if n > 0 {
+			p.maxNames = n
+		}
+	}
+}
+
+// WithAttributeLabels adds a label to the histograms for each of keys,
+// holding the value of the span attribute of the key. Spans without the
+// attribute get an empty value.
+//
+// Label names are the keys sanitized into valid Prometheus label names,
+// e.g., http_route for http.route. The values of keys sanitized to the
+// same name are joined with ";". Empty keys, and keys sanitized to
+// span_name, status_code or le, which are taken, are ignored.
+func WithAttributeLabels(keys ...core.Key) Option {
+	return func(p *Processor) {
+		p.attributeKeys = append([]core.Key(nil), keys...)
+	}
+}
+
+// Processor is a trace.SpanProcessor that aggregates the durations of the
+// spans that end by name and status. It is an http.Handler serving the
+// histograms in the Prometheus text format.
+type Processor struct {
+	bounds        []float64
+	maxNames      int
+	attributeKeys []core.Key
+	alwaysSample  bool
+
+	mu     sync.Mutex
+	names  map[string]bool
+	series map[seriesKey]*histogram
+}
+
+type seriesKey struct {
+	name   string
+	status codes.Code
+	// labels holds the attribute labels in the text format, each
+	// preceded by a comma.
+	labels string
+}
+
+type histogram struct {
+	// counts holds the number of durations in each bucket, not
+	// cumulated, followed by the ones above the last bound.
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+var _ trace.SpanProcessor = (*Processor)(nil)
+
+// NewProcessor returns a Processor.
+func NewProcessor(opts ...Option) *Processor {
+	p := &Processor{
+		bounds:   DefaultBuckets,
+		maxNames: DefaultMaxNames,
+		names:    make(map[string]bool),
+		series:   make(map[seriesKey]*histogram),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithAlwaysSample makes Install set the default sampler of the SDK to
+// trace.AlwaysSample, so that the histograms count every span. It has no
+// effect on NewProcessor.
+func WithAlwaysSample() Option {
+	return func(p *Processor) {
+		p.alwaysSample = true
+	}
+}
+
+// Install returns a new Processor registered with the SDK.
+//
+// Only sampled spans reach processors, so the histograms count the spans
+// of the traces the sampler of the SDK samples, and undercount the others
+// unless the sampler samples every trace. The sampler is left alone
+// unless WithAlwaysSample is given.
+func Install(opts ...Option) *Processor {
+	p := NewProcessor(opts...)
+	if p.alwaysSample {
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	}
+	trace.RegisterSpanProcessor(p)
+	return p
+}
+
+// OnStart does nothing; spans are aggregated when they end.
+func (p *Processor) OnStart(sd *trace.SpanData) {}
+
+// OnEnd adds the duration of sd to the histogram of its name and status.
+func (p *Processor) OnEnd(sd *trace.SpanData) {
+	d := sd.EndTime.Sub(sd.StartTime).Seconds()
+	bucket := sort.SearchFloat64s(p.bounds, d)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	name := sd.Name
+	if !p.names[name] {
+		if len(p.names) >= p.maxNames {
+			name = OtherName
+		} else {
+			p.names[name] = true
+		}
+	}
+	k := seriesKey{name: name, status: sd.Status, labels: p.attributeLabels(sd)}
+	h, ok := p.series[k]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(p.bounds)+1)}
+		p.series[k] = h
+	}
+	h.counts[bucket]++
+	h.sum += d
+	h.count++
+}
+
+// attributeLabels returns the labels of the attributes of sd selected
+// with WithAttributeLabels, in the text format.
+func (p *Processor) attributeLabels(sd *trace.SpanData) string {
+	if len(p.attributeKeys) == 0 {
+		return ""
+	}
+	kvs := make([]core.KeyValue, 0, len(p.attributeKeys))
+	for _, k := range p.attributeKeys {
+		kv := core.KeyValue{Key: k, Value: core.Value{Type: core.STRING}}
+		switch v := sd.Attributes[k.Variable.Name].(type) {
+		case nil:
+		case core.Value:
+			kv.Value = v
+		default:
+			kv.Value.String = fmt.Sprint(v)
+		}
+		kvs = append(kvs, kv)
+	}
+	var b strings.Builder
+	for _, kv := range internal.SanitizeLabels(kvs, internal.Sanitize) {
+		switch name := kv.Key.Variable.Name; name {
+		case "", "span_name", "status_code", "le":
+			continue
+		default:
+			b.WriteString(`,` + name + `="` + escapeLabel(kv.Value.Emit()) + `"`)
+		}
+	}
+	return b.String()
+}
+
+// Shutdown does nothing.
+func (p *Processor) Shutdown() {}
+
+// ServeHTTP serves the histograms in the Prometheus text format.
+func (p *Processor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = p.Write(w)
+}
+
+// Write writes the histograms to w in the Prometheus text format, ordered
+// by span name and status.
+func (p *Processor) Write(w io.Writer) error {
+	p.mu.Lock()
+	keys := make([]seriesKey, 0, len(p.series))
+	series := make([]histogram, 0, len(p.series))
+	for k := range p.series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		if keys[i].status != keys[j].status {
+			return keys[i].status < keys[j].status
+		}
+		return keys[i].labels < keys[j].labels
+	})
+	for _, k := range keys {
+		h := p.series[k]
+		series = append(series, histogram{
+			counts: append([]uint64(nil), h.counts...),
+			sum:    h.sum,
+			count:  h.count,
+		})
+	}
+	p.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# HELP span_duration_seconds Duration of the spans that ended, by name and status.\n")
+	bw.WriteString("# TYPE span_duration_seconds histogram\n")
+	for i, k := range keys {
+		h := series[i]
+		labels := `span_name="` + escapeLabel(k.name) + `",status_code="` + k.status.String() + `"` + k.labels
+		var cumulative uint64
+		for b, bound := range p.bounds {
+			cumulative += h.counts[b]
+			writeSample(bw, "span_duration_seconds_bucket", labels+`,le="`+formatFloat(bound)+`"`, strconv.FormatUint(cumulative, 10))
+		}
+		writeSample(bw, "span_duration_seconds_bucket", labels+`,le="+Inf"`, strconv.FormatUint(h.count, 10))
+		writeSample(bw, "span_duration_seconds_sum", labels, formatFloat(h.sum))
+		writeSample(bw, "span_duration_seconds_count", labels, strconv.FormatUint(h.count, 10))
+	}
+	return bw.Flush()
+}
+
+func writeSample(w *bufio.Writer, name, labels, value string) {
+	w.WriteString(name)
+	w.WriteByte('{')
+	w.WriteString(labels)
+	w.WriteString("} ")
+	w.WriteString(value)
+	w.WriteByte('\n')
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value as the text format requires.
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,151 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+func span(name string, d time.Duration, status codes.Code) *trace.SpanData {
+	start := time.Unix(100, 0)
+	return &trace.SpanData{Name: name, StartTime: start, EndTime: start.Add(d), Status: status}
+}
+
+func TestWrite(t *testing.T) {
+	p := NewProcessor(WithBuckets(1, 0.1))
+	p.OnEnd(span("GET /users", 50*time.Millisecond, codes.OK))
+	p.OnEnd(span("GET /users", 100*time.Millisecond, codes.OK))
+	p.OnEnd(span("GET /users", 2*time.Second, codes.OK))
+	p.OnEnd(span("GET /users", 500*time.Millisecond, codes.NotFound))
+
+	var buf strings.Builder
+	if err := p.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP span_duration_seconds Duration of the spans that ended, by name and status.
+# TYPE span_duration_seconds histogram
+span_duration_seconds_bucket{span_name="GET /users",status_code="OK",le="0.1"} 2
+span_duration_seconds_bucket{span_name="GET /users",status_code="OK",le="1"} 2
+span_duration_seconds_bucket{span_name="GET /users",status_code="OK",le="+Inf"} 3
+span_duration_seconds_sum{span_name="GET /users",status_code="OK"} 2.15
+span_duration_seconds_count{span_name="GET /users",status_code="OK"} 3
+span_duration_seconds_bucket{span_name="GET /users",status_code="NotFound",le="0.1"} 0
+span_duration_seconds_bucket{span_name="GET /users",status_code="NotFound",le="1"} 1
+span_duration_seconds_bucket{span_name="GET /users",status_code="NotFound",le="+Inf"} 1
+span_duration_seconds_sum{span_name="GET /users",status_code="NotFound"} 0.5
+span_duration_seconds_count{span_name="GET /users",status_code="NotFound"} 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAttributeLabels(t *testing.T) {
+	p := NewProcessor(WithBuckets(1), WithAttributeLabels(
+		key.New("http.route"), key.New("http_route"), key.New("9lives"), key.New("le"),
+	))
+	sd := span("GET", time.Millisecond, codes.OK)
+	sd.Attributes = map[string]interface{}{
+		"http.route": key.New("http.route").String("/users").Value,
+		"http_route": key.New("http_route").String(`"quoted"`).Value,
+		"9lives":     key.New("9lives").Int64(9).Value,
+		"le":         key.New("le").String("taken").Value,
+	}
+	p.OnEnd(sd)
+	p.OnEnd(span("GET", time.Millisecond, codes.OK))
+
+	var buf strings.Builder
+	if err := p.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`span_duration_seconds_count{span_name="GET",status_code="OK",http_route="/users;\"quoted\"",key_9lives="9"} 1`,
+		`span_duration_seconds_count{span_name="GET",status_code="OK",http_route=";",key_9lives=""} 1`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("got\n%s\nwant it to contain %s", buf.String(), want)
+		}
+	}
+}
+
+func TestMaxNames(t *testing.T) {
+	p := NewProcessor(WithBuckets(1), WithMaxNames(1))
+	p.OnEnd(span("a", time.Millisecond, codes.OK))
+	p.OnEnd(span("b", time.Millisecond, codes.OK))
+	p.OnEnd(span("c", time.Millisecond, codes.OK))
+	p.OnEnd(span("a", time.Millisecond, codes.OK))
+
+	var buf strings.Builder
+	if err := p.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`span_duration_seconds_count{span_name="a",status_code="OK"} 2`,
+		`span_duration_seconds_count{span_name="_other",status_code="OK"} 2`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("got\n%s\nwant it to contain %s", buf.String(), want)
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	p := NewProcessor(WithBuckets())
+	p.OnEnd(span("say \"hi\"\\\n", time.Millisecond, codes.OK))
+	var buf strings.Builder
+	if err := p.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `span_name="say \"hi\"\\\n"`; !strings.Contains(buf.String(), want) {
+		t.Errorf("got\n%s\nwant it to contain %s", buf.String(), want)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	p := NewProcessor()
+	p.OnEnd(span("a", time.Millisecond, codes.OK))
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", ct)
+	}
+	if want := `span_duration_seconds_bucket{span_name="a",status_code="OK",le="0.005"} 1`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("got\n%s\nwant it to contain %s", rec.Body.String(), want)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	defer trace.ApplyConfig(trace.ConfigSnapshot())
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+	before := trace.ConfigSnapshot()
+
+	trace.UnregisterSpanProcessor(Install())
+	if diff := before.Diff(trace.ConfigSnapshot()); len(diff) != 0 {
+		t.Errorf("Install changed %v, want the configuration left alone", diff)
+	}
+
+	trace.UnregisterSpanProcessor(Install(WithAlwaysSample()))
+	if diff := before.Diff(trace.ConfigSnapshot()); len(diff) != 1 || diff[0] != "DefaultSampler" {
+		t.Errorf("Install(WithAlwaysSample()) changed %v, want [DefaultSampler]", diff)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testtrace provides helpers for testing instrumentation against
+// the spans exported by the SDK.
+package testtrace // import "go.opentelemetry.io/sdk/trace/testtrace"
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+// Option changes how Diff compares spans.
+type Option func(*options)
+
+type options struct {
+	timeTolerance time.Duration
+	ignoreTimes   bool
+	ignoreIDs     bool
+	ignoreFields  []string
+}
+
+// WithTimeTolerance treats timestamps and durations that are at most d
+// apart as equal.
+func WithTimeTolerance(d time.Duration) Option {
+	return func(o *options) {
+		o.timeTolerance = d
+	}
+}
+
+// IgnoreTimes ignores all timestamps and the time spent in child spans.
+func IgnoreTimes() Option {
+	return func(o *options) {
+		o.ignoreTimes = true
+	}
+}
+
+// IgnoreSpanIDs ignores the span ID and parent span ID, which are usually
+// random. Trace IDs are still compared.
+func IgnoreSpanIDs() Option {
+	return func(o *options) {
+		o.ignoreIDs = true
+	}
+}
+
+// IgnoreFields ignores the named fields of trace.SpanData.
+func IgnoreFields(names ...string) Option {
+	return func(o *options) {
+		o.ignoreFields = append(o.ignoreFields, names...)
+	}
+}
+
+// Diff returns a human-readable report of the differences between the
+// expected and actual spans, or an empty string if they are equal.
+//
+// Attributes are compared regardless of their order, and timestamps can be
+// compared with a tolerance or ignored entirely, which makes assertions on
+// exported spans much less brittle than comparing fields by hand.
+func Diff(expected, actual *trace.SpanData, opts ...Option) string {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return cmp.Diff(expected, actual, o.cmpOptions()...)
+}
+
+// DiffAll compares two lists of spans like Diff, in order.
+func DiffAll(expected, actual []*trace.SpanData, opts ...Option) string {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return cmp.Diff(expected, actual, o.cmpOptions()...)
+}
+
+func (o *options) cmpOptions() []cmp.Option {
+	var sd trace.SpanData
+	// Message events have an unexported type.
+	eventType := reflect.TypeOf(sd.MessageEvents).Elem()
+	copts := []cmp.Option{
+		cmp.AllowUnexported(reflect.Zero(eventType).Interface()),
+		cmpopts.SortSlices(func(a, b core.KeyValue) bool {
+			return a.Key.Variable.Name < b.Key.Variable.Name
+		}),
+		cmpopts.EquateEmpty(),
+	}
+
+	switch {
+	case o.ignoreTimes:
+		copts = append(copts,
+			cmpopts.IgnoreTypes(time.Time{}),
+			cmpopts.IgnoreFields(trace.SpanData{}, "ChildSpanDuration"),
+		)
+	case o.timeTolerance > 0:
+		tolerance := o.timeTolerance
+		copts = append(copts, cmp.Comparer(func(a, b time.Time) bool {
+			d := a.Sub(b)
+			return -tolerance <= d && d <= tolerance
+		}), cmp.Comparer(func(a, b time.Duration) bool {
+			d := a - b
+			return -tolerance <= d && d <= tolerance
+		}))
+	}
+	if o.ignoreIDs {
+		copts = append(copts,
+			cmpopts.IgnoreFields(core.SpanContext{}, "SpanID"),
+			cmpopts.IgnoreFields(trace.SpanData{}, "ParentSpanID"),
+		)
+	}
+	if len(o.ignoreFields) > 0 {
+		copts = append(copts, cmpopts.IgnoreFields(trace.SpanData{}, o.ignoreFields...))
+	}
+	return copts
+}
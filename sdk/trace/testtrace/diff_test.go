@@ -0,0 +1,106 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testtrace
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/sdk/trace"
+)
+
+func span(start time.Time, spanID uint64) *trace.SpanData {
+	return &trace.SpanData{
+		SpanContext: core.SpanContext{
+			TraceID: core.TraceID{High: 1, Low: 2},
+			SpanID:  spanID,
+		},
+		Name:      "span",
+		StartTime: start,
+		EndTime:   start.Add(time.Second),
+		Attributes: map[string]interface{}{
+			"a": core.Value{Type: core.STRING, String: "b"},
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		expected *trace.SpanData
+		actual   *trace.SpanData
+		opts     []Option
+		wantDiff bool
+	}{
+		{
+			name:     "equal",
+			expected: span(now, 1),
+			actual:   span(now, 1),
+		},
+		{
+			name:     "different times",
+			expected: span(now, 1),
+			actual:   span(now.Add(time.Millisecond), 1),
+			wantDiff: true,
+		},
+		{
+			name:     "within tolerance",
+			expected: span(now, 1),
+			actual:   span(now.Add(time.Millisecond), 1),
+			opts:     []Option{WithTimeTolerance(10 * time.Millisecond)},
+		},
+		{
+			name:     "outside tolerance",
+			expected: span(now, 1),
+			actual:   span(now.Add(time.Second), 1),
+			opts:     []Option{WithTimeTolerance(10 * time.Millisecond)},
+			wantDiff: true,
+		},
+		{
+			name:     "ignored times",
+			expected: span(now, 1),
+			actual:   span(now.Add(time.Hour), 1),
+			opts:     []Option{IgnoreTimes()},
+		},
+		{
+			name:     "different span IDs",
+			expected: span(now, 1),
+			actual:   span(now, 2),
+			wantDiff: true,
+		},
+		{
+			name:     "ignored span IDs",
+			expected: span(now, 1),
+			actual:   span(now, 2),
+			opts:     []Option{IgnoreSpanIDs()},
+		},
+		{
+			name:     "ignored fields",
+			expected: span(now, 1),
+			actual:   &trace.SpanData{SpanContext: span(now, 1).SpanContext, Name: "span", StartTime: now, EndTime: now.Add(time.Second)},
+			opts:     []Option{IgnoreFields("Attributes")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := Diff(tt.expected, tt.actual, tt.opts...)
+			if gotDiff := diff != ""; gotDiff != tt.wantDiff {
+				t.Errorf("Diff() = %q; want diff %v", diff, tt.wantDiff)
+			}
+		})
+	}
+}
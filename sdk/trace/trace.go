@@ -16,9 +16,6 @@ package trace
 
 import (
 	"context"
-	crand "crypto/rand"
-	"encoding/binary"
-	"math/rand"
 	"sync"
 	"sync/atomic"
 
@@ -28,37 +25,29 @@ import (
 var config atomic.Value // access atomically
 
 func init() {
-	gen := &defaultIDGenerator{}
-	// initialize traceID and spanID generators.
-	var rngSeed int64
-	for _, p := range []interface{}{
-		&rngSeed, &gen.traceIDAdd, &gen.nextSpanID, &gen.spanIDInc,
-	} {
-		_ = binary.Read(crand.Reader, binary.LittleEndian, p)
-	}
-	gen.traceIDRand = rand.New(rand.NewSource(rngSeed))
-	gen.spanIDInc |= 1
-
 	config.Store(&Config{
-		DefaultSampler:       ProbabilitySampler(defaultSamplingProbability),
-		IDGenerator:          gen,
-		MaxAttributesPerSpan: DefaultMaxAttributesPerSpan,
-		MaxEventsPerSpan:     DefaultMaxEventsPerSpan,
-		MaxLinksPerSpan:      DefaultMaxLinksPerSpan,
+		DefaultSampler:        ProbabilitySampler(defaultSamplingProbability),
+		IDGenerator:           NewIDGenerator(),
+		MaxAttributesPerSpan:  DefaultMaxAttributesPerSpan,
+		MaxAttributesPerEvent: DefaultMaxAttributesPerEvent,
+		MaxEventsPerSpan:      DefaultMaxEventsPerSpan,
+		MaxLinksPerSpan:       DefaultMaxLinksPerSpan,
+		ExportTimeout:         DefaultExportTimeout,
 	})
 }
 
 var tr *tracer
 var registerOnce sync.Once
 
-// Register registers tracer implementation as default Tracer.
-// It creates single instance of tracer and registers it once.
+// Register registers a Provider as the global Provider, and its unnamed
+// tracer as the default Tracer. It does so once and returns that tracer.
 // Recommended use is to call Register in main() of an
 // application before calling any tracing api.
 func Register() apitrace.Tracer {
 	registerOnce.Do(func() {
-		tr = &tracer{}
-		apitrace.SetGlobalTracer(tr)
+		p := NewProvider()
+		tr = p.Tracer("").(*tracer)
+		apitrace.SetGlobalProvider(p)
 	})
 	return tr
 }
@@ -70,6 +59,29 @@ func fromContext(ctx context.Context) *span {
 	return s
 }
 
+// currentSpanKey is the key of the current span of the API.
+var currentSpanKey = apitrace.CurrentSpanKey()
+
+// spanCtx is a context holding a span, which is also the current span of
+// the API, so that helpers like apitrace.CurrentSpan find it. It is part
+// of the span rather than made by context.WithValue, to save allocations.
+type spanCtx struct {
+	context.Context
+	span *span
+}
+
+func (c *spanCtx) Value(key interface{}) interface{} {
+	switch key {
+	case contextKey{}:
+		return c.span
+	case currentSpanKey:
+		return apitrace.Span(c.span)
+	}
+	return c.Context.Value(key)
+}
+
+// newContext returns a context holding s, derived from parent.
 func newContext(parent context.Context, s *span) context.Context {
-	return context.WithValue(parent, contextKey{}, s)
+	s.ctx = spanCtx{Context: parent, span: s}
+	return &s.ctx
 }
@@ -16,15 +16,23 @@ package trace
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/errorhandler"
+	apievent "go.opentelemetry.io/api/event"
 	"go.opentelemetry.io/api/key"
+	"go.opentelemetry.io/api/propagation"
+	"go.opentelemetry.io/api/tag"
 	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/resource"
 	"google.golang.org/grpc/codes"
 )
 
@@ -129,6 +137,25 @@ func TestSetSpanAttributes(t *testing.T) {
 	}
 }
 
+func TestStartSpanWithAttributes(t *testing.T) {
+	_, span := apitrace.GlobalTracer().Start(
+		context.Background(),
+		"span0",
+		apitrace.ChildOf(remoteSpanContext()),
+		apitrace.WithRecordEvents(),
+		apitrace.WithAttributes(key.New("key1").String("value1")),
+	)
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"key1": core.Value{Type: core.STRING, String: "value1"}}
+	if diff := cmp.Diff(got.Attributes, want); diff != "" {
+		t.Errorf("StartSpanWithAttributes: -got +want %s", diff)
+	}
+}
+
 func TestSetSpanAttributesOverLimit(t *testing.T) {
 	cfg := Config{MaxAttributesPerSpan: 2}
 	ApplyConfig(cfg)
@@ -161,6 +188,82 @@ func TestSetSpanAttributesOverLimit(t *testing.T) {
 	}
 }
 
+func TestStartSpanWithSampler(t *testing.T) {
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+	tr := apitrace.GlobalTracer()
+
+	ctx, parent := tr.Start(context.Background(), "parent")
+	defer parent.Finish()
+	for _, tt := range []struct {
+		name string
+		opts []apitrace.SpanOption
+		want bool
+	}{
+		{"default", nil, true},
+		{"never", []apitrace.SpanOption{WithSampler(NeverSample())}, false},
+		{"remote never", []apitrace.SpanOption{apitrace.ChildOf(remoteSpanContext()), WithSampler(NeverSample())}, false},
+		{"last wins", []apitrace.SpanOption{WithSampler(NeverSample()), WithSampler(AlwaysSample())}, true},
+	} {
+		_, span := tr.Start(ctx, "child", tt.opts...)
+		if got := span.SpanContext().IsSampled(); got != tt.want {
+			t.Errorf("%s: IsSampled() = %v, want %v", tt.name, got, tt.want)
+		}
+		span.Finish()
+	}
+}
+
+func TestStartSpanWithLimits(t *testing.T) {
+	ApplyConfig(Config{MaxAttributesPerSpan: 1})
+	defer ApplyConfig(Config{MaxAttributesPerSpan: DefaultMaxAttributesPerSpan})
+
+	_, span := apitrace.GlobalTracer().Start(context.Background(), "audit",
+		apitrace.ChildOf(remoteSpanContext()),
+		WithLimits(SpanLimits{MaxAttributesPerSpan: 3}),
+	)
+	span.SetAttributes(key.New("a").Int(1), key.New("b").Int(2), key.New("c").Int(3))
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Attributes) != 3 || got.DroppedAttributeCount != 0 {
+		t.Errorf("got %d attributes and %d dropped, want 3 and none", len(got.Attributes), got.DroppedAttributeCount)
+	}
+	if ConfigSnapshot().MaxAttributesPerSpan != 1 {
+		t.Errorf("WithLimits changed the global MaxAttributesPerSpan to %d", ConfigSnapshot().MaxAttributesPerSpan)
+	}
+}
+
+func TestModifySpanAttributesOverLimit(t *testing.T) {
+	cfg := Config{MaxAttributesPerSpan: 2}
+	ApplyConfig(cfg)
+	before := DroppedAttributes()
+
+	span := startSpan()
+	span.ModifyAttributes(
+		tag.Insert(key.New("key1").String("value1")),
+		tag.Insert(key.New("key1").String("ignored")), // key1 is set.
+		tag.Update(key.New("key2").String("ignored")), // key2 is not set.
+		tag.Upsert(key.New("key2").String("value2")),
+		tag.Upsert(key.New("key3").String("value3")), // Evicts key1.
+		tag.Delete(key.New("key2")),
+	)
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"key3": core.Value{Type: core.STRING, String: "value3"}}
+	if diff := cmp.Diff(got.Attributes, want); diff != "" {
+		t.Errorf("ModifyAttributes: -got +want %s", diff)
+	}
+	if got.DroppedAttributeCount != 1 {
+		t.Errorf("DroppedAttributeCount = %d, want 1", got.DroppedAttributeCount)
+	}
+	if got := DroppedAttributes() - before; got != 1 {
+		t.Errorf("DroppedAttributes() grew by %d, want 1", got)
+	}
+}
+
 func TestEvents(t *testing.T) {
 	span := startSpan()
 	k1v1 := key.New("key1").String("value1")
@@ -249,6 +352,73 @@ func TestEventsOverLimit(t *testing.T) {
 	}
 }
 
+type foreignEvent struct{}
+
+func (foreignEvent) Message() string             { return "foreign" }
+func (foreignEvent) Attributes() []core.KeyValue { return nil }
+
+func TestAddEvent(t *testing.T) {
+	s := startSpan()
+	at := time.Unix(1500000000, 0)
+	k1v1 := key.New("key1").String("value1")
+
+	s.AddEvent(context.Background(), apievent.NewTimed(at, "timed", k1v1))
+	s.AddEvent(context.Background(), foreignEvent{})
+	got, err := endSpan(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.MessageEvents) != 2 {
+		t.Fatalf("got %d events, want 2", len(got.MessageEvents))
+	}
+	if !checkTime(&got.MessageEvents[1].time) {
+		t.Error("expected nonzero Time for an event without one")
+	}
+	want := []event{
+		{msg: "timed", attributes: []core.KeyValue{k1v1}, time: at},
+		{msg: "foreign"},
+	}
+	if diff := cmp.Diff(got.MessageEvents, want, cmp.AllowUnexported(event{})); diff != "" {
+		t.Errorf("AddEvent: -got +want %s", diff)
+	}
+}
+
+func TestEventAttributesOverLimit(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{MaxAttributesPerEvent: 1})
+
+	var reports []string
+	errorhandler.Set(func(err error) { reports = append(reports, err.Error()) })
+	defer errorhandler.Set(nil)
+	prevReports := limitReports
+	limitReports = &reportLimiter{interval: time.Hour}
+	defer func() { limitReports = prevReports }()
+
+	s := startSpan()
+	k1v1 := key.New("key1").String("value1")
+	k2v2 := key.New("key2").String("value2")
+	s.Event(context.Background(), "foo", k1v1, k2v2)
+	s.AddEvent(context.Background(), apievent.NewTimed(time.Now(), "bar", k1v1, k2v2))
+	got, err := endSpan(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, ev := range got.MessageEvents {
+		if diff := cmp.Diff(ev.Attributes(), []core.KeyValue{k1v1}); diff != "" {
+			t.Errorf("event %d attributes: -got +want %s", i, diff)
+		}
+		if ev.DroppedAttributeCount() != 1 {
+			t.Errorf("event %d DroppedAttributeCount() = %d, want 1", i, ev.DroppedAttributeCount())
+		}
+	}
+	if len(reports) != 1 || !strings.Contains(reports[0], "2 event attributes (MaxAttributesPerEvent 1)") {
+		t.Errorf("reports = %q, want dropped event attributes", reports)
+	}
+}
+
 func TestSetSpanName(t *testing.T) {
 	want := "SpanName-1"
 	_, span := apitrace.GlobalTracer().Start(context.Background(), want,
@@ -268,6 +438,47 @@ func TestSetSpanName(t *testing.T) {
 	}
 }
 
+func TestSpanNameOverLimit(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{MaxSpanNameLength: 12})
+
+	_, s := apitrace.GlobalTracer().Start(context.Background(), "GET /users/42?expand=orders",
+		apitrace.ChildOf(remoteSpanContext()),
+	)
+	got, err := endSpan(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "GET /user..."; got.Name != want {
+		t.Errorf("span.Name: got %q; want %q", got.Name, want)
+	}
+
+	ApplyConfig(Config{MaxSpanNameLength: NoSpanNameLimit})
+	if got := config.Load().(*Config).MaxSpanNameLength; got != 0 {
+		t.Errorf("MaxSpanNameLength after NoSpanNameLimit = %d, want 0", got)
+	}
+}
+
+func TestTruncateName(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		max  int
+		want string
+	}{
+		{"short", 0, "short"},
+		{"short", 5, "short"},
+		{"longer name", 8, "longe..."},
+		{"longer name", 2, "lo"},
+		{"héllo", 5, "h..."},
+		{"日本語", 7, "日..."},
+	} {
+		if got := truncateName(tt.name, tt.max); got != tt.want {
+			t.Errorf("truncateName(%q, %d) = %q, want %q", tt.name, tt.max, got, tt.want)
+		}
+	}
+}
+
 func TestSetSpanStatus(t *testing.T) {
 	span := startSpan()
 	span.SetStatus(codes.Canceled)
@@ -291,6 +502,29 @@ func TestSetSpanStatus(t *testing.T) {
 	}
 }
 
+func TestSetSpanStatusWithMessage(t *testing.T) {
+	sp := startSpan()
+	apitrace.SetStatusWithMessage(sp, codes.Unavailable, "backend down")
+	got, err := endSpan(sp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != codes.Unavailable || got.StatusMessage != "backend down" {
+		t.Errorf("got status %v %q, want %v %q", got.Status, got.StatusMessage, codes.Unavailable, "backend down")
+	}
+
+	sp = startSpan()
+	apitrace.SetStatusWithMessage(sp, codes.Internal, "stale")
+	sp.SetStatus(codes.Aborted)
+	got, err = endSpan(sp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != codes.Aborted || got.StatusMessage != "" {
+		t.Errorf("got status %v %q, want SetStatus to clear the message", got.Status, got.StatusMessage)
+	}
+}
+
 func TestUnregisterExporter(t *testing.T) {
 	var te testExporter
 	RegisterExporter(&te)
@@ -475,6 +709,70 @@ func TestChildSpanCount(t *testing.T) {
 	}
 }
 
+func TestChildSpanDuration(t *testing.T) {
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+	SetChildSpanDurations(true)
+	defer SetChildSpanDurations(false)
+	spans := make(exporter)
+	RegisterExporter(&spans)
+	defer UnregisterExporter(&spans)
+	ctx, span0 := apitrace.GlobalTracer().Start(context.Background(), "parent")
+	ctx1, span1 := apitrace.GlobalTracer().Start(ctx, "span-1")
+	_, span2 := apitrace.GlobalTracer().Start(ctx1, "span-2")
+	time.Sleep(10 * time.Millisecond)
+	span2.Finish()
+	span1.Finish()
+	span0.Finish()
+	UnregisterExporter(&spans)
+
+	parent, child, grandchild := spans["parent"], spans["span-1"], spans["span-2"]
+	if got, want := parent.ChildSpanDuration, child.EndTime.Sub(child.StartTime); got != want {
+		t.Errorf("parent.ChildSpanDuration=%v; want %v", got, want)
+	}
+	if got, want := child.ChildSpanDuration, grandchild.EndTime.Sub(grandchild.StartTime); got != want {
+		t.Errorf("span-1.ChildSpanDuration=%v; want %v", got, want)
+	}
+	if got := grandchild.ChildSpanDuration; got != 0 {
+		t.Errorf("span-2.ChildSpanDuration=%v; want 0", got)
+	}
+	if got, want := child.SelfTime(), child.EndTime.Sub(child.StartTime)-child.ChildSpanDuration; got != want {
+		t.Errorf("span-1.SelfTime()=%v; want %v", got, want)
+	}
+	if got := grandchild.SelfTime(); got < 10*time.Millisecond {
+		t.Errorf("span-2.SelfTime()=%v; want at least 10ms", got)
+	}
+}
+
+func TestChildSpanDurationDisabled(t *testing.T) {
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+	spans := make(exporter)
+	RegisterExporter(&spans)
+	defer UnregisterExporter(&spans)
+
+	ctx, parent := apitrace.GlobalTracer().Start(context.Background(), "parent")
+	_, child := apitrace.GlobalTracer().Start(ctx, "child")
+	if child.(*span).parent != nil {
+		t.Error("child keeps its parent while child span durations are disabled")
+	}
+	child.Finish()
+	parent.Finish()
+	if got := spans["parent"].ChildSpanDuration; got != 0 {
+		t.Errorf("parent.ChildSpanDuration=%v; want 0", got)
+	}
+}
+
+func TestSelfTimeConcurrentChildren(t *testing.T) {
+	start := time.Now()
+	sd := &SpanData{
+		StartTime:         start,
+		EndTime:           start.Add(time.Second),
+		ChildSpanDuration: 2 * time.Second,
+	}
+	if got := sd.SelfTime(); got != 0 {
+		t.Errorf("SelfTime()=%v; want 0", got)
+	}
+}
+
 func TestNilSpanFinish(t *testing.T) {
 	var span *span
 	span.Finish()
@@ -522,3 +820,384 @@ func TestExecutionTracerTaskEnd(t *testing.T) {
 		t.Fatalf("Execution tracer task ended for %v spans; want %v", got, want)
 	}
 }
+
+func TestWithSpanRecordsError(t *testing.T) {
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+	var te testExporter
+	RegisterExporter(&te)
+	defer UnregisterExporter(&te)
+
+	err := apitrace.GlobalTracer().WithSpan(context.Background(), "failing", func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("WithSpan: got nil error")
+	}
+	if len(te.spans) != 1 {
+		t.Fatalf("got exported spans %#v, want one span", te.spans)
+	}
+	events := te.spans[0].MessageEvents
+	if len(events) != 1 || events[0].msg != apitrace.ExceptionEvent {
+		t.Fatalf("got events %#v, want one exception event", events)
+	}
+	want := []core.KeyValue{
+		apitrace.ExceptionTypeKey.String("*errors.errorString"),
+		apitrace.ExceptionMessageKey.String("boom"),
+	}
+	if diff := cmp.Diff(events[0].attributes, want); diff != "" {
+		t.Errorf("exception attributes: -got +want %s", diff)
+	}
+}
+
+func TestEventsOverRateLimit(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{MaxEventsPerSecondPerSpan: 2})
+
+	span := startSpan()
+	for i := 0; i < 5; i++ {
+		span.Event(context.Background(), fmt.Sprint("event", i))
+	}
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(got.MessageEvents), 2; got != want {
+		t.Errorf("got %d message events, want %d", got, want)
+	}
+	if got, want := got.DroppedMessageEventCount, 3; got != want {
+		t.Errorf("got dropped message event count %d, want %d", got, want)
+	}
+}
+
+type blockingExporter struct {
+	attempts int
+	// wrap makes the exporter wrap the context error, as RPC clients do.
+	wrap bool
+}
+
+func (e *blockingExporter) ExportSpan(s *SpanData) {
+	panic("ExportSpan called on ContextExporter")
+}
+
+func (e *blockingExporter) ExportSpanWithContext(ctx context.Context, s *SpanData) error {
+	e.attempts++
+	<-ctx.Done()
+	if e.wrap {
+		return fmt.Errorf("rpc error: %v", ctx.Err())
+	}
+	return ctx.Err()
+}
+
+func TestExportTimeout(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{ExportTimeout: time.Millisecond, ExportRetries: 2})
+	errorhandler.Set(func(error) {})
+	defer errorhandler.Set(nil)
+
+	for _, wrap := range []bool{false, true} {
+		e := blockingExporter{wrap: wrap}
+		RegisterExporter(&e)
+
+		dropped := DroppedSpans()
+		span := startSpan()
+		span.Finish()
+		// Wait for the queued export.
+		UnregisterExporter(&e)
+
+		if got, want := e.attempts, 3; got != want {
+			t.Errorf("wrap %v: got %d export attempts, want %d", wrap, got, want)
+		}
+		if got, want := DroppedSpans()-dropped, uint64(1); got != want {
+			t.Errorf("wrap %v: got %d dropped spans, want %d", wrap, got, want)
+		}
+	}
+}
+
+type gatedExporter struct {
+	gate     chan struct{}
+	exported chan *SpanData
+}
+
+func (e *gatedExporter) ExportSpan(s *SpanData) {}
+
+func (e *gatedExporter) ExportSpanWithContext(ctx context.Context, s *SpanData) error {
+	<-e.gate
+	e.exported <- s
+	return nil
+}
+
+func TestContextExportDoesNotBlockFinish(t *testing.T) {
+	e := gatedExporter{gate: make(chan struct{}), exported: make(chan *SpanData, 1)}
+	RegisterExporter(&e)
+	defer UnregisterExporter(&e)
+
+	span := startSpan()
+	finished := make(chan struct{})
+	go func() {
+		span.Finish()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Finish blocked on a ContextExporter")
+	}
+
+	close(e.gate)
+	if sd := <-e.exported; sd.Name != "span0" {
+		t.Errorf("exported span %q, want span0", sd.Name)
+	}
+}
+
+func TestNoExportRetries(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+
+	ApplyConfig(Config{ExportRetries: 3})
+	ApplyConfig(Config{})
+	if got := config.Load().(*Config).ExportRetries; got != 3 {
+		t.Errorf("ExportRetries = %d after an empty config, want 3", got)
+	}
+	ApplyConfig(Config{ExportRetries: NoExportRetries})
+	if got := config.Load().(*Config).ExportRetries; got != 0 {
+		t.Errorf("ExportRetries = %d after NoExportRetries, want 0", got)
+	}
+}
+
+func TestVerboseSampler(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+
+	ApplyConfig(Config{DefaultSampler: VerboseSampler(AlwaysSample(), 1)})
+	ctx, parent := apitrace.GlobalTracer().Start(context.Background(), "parent")
+	_, child := apitrace.GlobalTracer().Start(ctx, "child")
+	if !apitrace.IsRecordingVerbose(parent) {
+		t.Error("parent is not recording verbose, want verbose")
+	}
+	if !apitrace.IsRecordingVerbose(child) {
+		t.Error("child is not recording verbose, want verbose inherited from parent")
+	}
+
+	ApplyConfig(Config{DefaultSampler: VerboseSampler(AlwaysSample(), 0)})
+	_, span := apitrace.GlobalTracer().Start(context.Background(), "span")
+	if apitrace.IsRecordingVerbose(span) {
+		t.Error("span is recording verbose with fraction 0")
+	}
+
+	ApplyConfig(Config{DefaultSampler: VerboseSampler(NeverSample(), 1)})
+	_, span = apitrace.GlobalTracer().Start(context.Background(), "span")
+	if apitrace.IsRecordingVerbose(span) {
+		t.Error("unsampled span is recording verbose")
+	}
+}
+
+func TestSamplingParametersAttributes(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+
+	var got SamplingParameters
+	ApplyConfig(Config{DefaultSampler: func(p SamplingParameters) SamplingDecision {
+		got = p
+		return SamplingDecision{}
+	}})
+	tr := &tracer{resources: []core.KeyValue{key.New("service").String("checkout")}}
+	_, span := tr.Start(context.Background(), "span",
+		apitrace.WithAttributes(key.New("route").String("/pay")),
+		apitrace.WithSpanKind(apitrace.SpanKindServer),
+	)
+	span.Finish()
+
+	if got.Kind != apitrace.SpanKindServer {
+		t.Errorf("got sampling kind %v, want server", got.Kind)
+	}
+	if len(got.Attributes) != 1 || got.Attributes[0].Value.Emit() != "/pay" {
+		t.Errorf("got sampling attributes %v, want route=/pay", got.Attributes)
+	}
+	if len(got.Resource) != 1 || got.Resource[0].Value.Emit() != "checkout" {
+		t.Errorf("got sampling resource %v, want service=checkout", got.Resource)
+	}
+}
+
+func TestConfigResource(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{
+		DefaultSampler: AlwaysSample(),
+		Resource: resource.New(
+			resource.ServiceNameKey.String("default"),
+			resource.HostNameKey.String("host1"),
+		),
+	})
+
+	var sampled []core.KeyValue
+	tr := &tracer{}
+	tr.WithResources(resource.ServiceNameKey.String("checkout"))
+	want := []core.KeyValue{
+		resource.HostNameKey.String("host1"),
+		resource.ServiceNameKey.String("checkout"),
+	}
+	for i := 0; i < 2; i++ {
+		ApplyConfig(Config{DefaultSampler: func(p SamplingParameters) SamplingDecision {
+			sampled = p.Resource
+			return SamplingDecision{Sample: true}
+		}})
+		_, s := tr.Start(context.Background(), "span")
+		got, err := endSpan(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got.Resource, want); diff != "" {
+			t.Errorf("SpanData.Resource: -got +want %s", diff)
+		}
+		if diff := cmp.Diff(sampled, want); diff != "" {
+			t.Errorf("SamplingParameters.Resource: -got +want %s", diff)
+		}
+	}
+}
+
+func TestContinueRemoteTrace(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+
+	remote := remoteSpanContext()
+	h := http.Header{}
+	propagation.TraceContext().Injector(h).Inject(remote, nil)
+
+	ctx, opts := propagation.Extract(context.Background(), propagation.TraceContext(), h)
+	_, span := apitrace.GlobalTracer().Start(ctx, "server", opts...)
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SpanContext.TraceID != remote.TraceID || got.ParentSpanID != remote.SpanID || !got.HasRemoteParent {
+		t.Errorf("span %+v does not continue remote trace %+v", got, remote)
+	}
+}
+
+func TestFinishRecordsPanic(t *testing.T) {
+	span := startSpan()
+	var te testExporter
+	RegisterExporter(&te)
+	defer UnregisterExporter(&te)
+
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		defer span.Finish()
+		panic("boom")
+	}()
+
+	if recovered != "boom" {
+		t.Errorf("got recovered %v, want the panic to continue after Finish", recovered)
+	}
+	if len(te.spans) != 1 {
+		t.Fatalf("got %d exported spans, want 1", len(te.spans))
+	}
+	events := te.spans[0].MessageEvents
+	if len(events) != 1 || events[0].msg != apitrace.ExceptionEvent {
+		t.Fatalf("got events %v, want one exception event", events)
+	}
+	for _, kv := range events[0].attributes {
+		if kv.Key == apitrace.ExceptionStacktraceKey &&
+			!strings.HasPrefix(kv.Value.String, "go.opentelemetry.io/sdk/trace.TestFinishRecordsPanic.func1\n") {
+			t.Errorf("exception.stacktrace = %q; want the panicking frame first", kv.Value.String)
+		}
+	}
+}
+
+func TestStartFinishAllocs(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+
+	tr := &tracer{}
+	ctx := context.Background()
+	for _, sampler := range []Sampler{NeverSample(), AlwaysSample()} {
+		ApplyConfig(Config{DefaultSampler: sampler})
+		// Only the span is allocated, holding the context and the data.
+		allocs := testing.AllocsPerRun(100, func() {
+			_, span := tr.Start(ctx, "bare")
+			span.Finish()
+		})
+		if allocs > 1 {
+			t.Errorf("Start and Finish of a bare span allocated %v times, want at most 1", allocs)
+		}
+	}
+}
+
+func TestContextHoldsSpan(t *testing.T) {
+	ctx, s := (&tracer{}).Start(context.Background(), "span")
+	defer s.Finish()
+	if got := apitrace.CurrentSpan(ctx); got != s {
+		t.Errorf("CurrentSpan() = %v, want the started span", got)
+	}
+	if got := fromContext(ctx); got != s.(*span) {
+		t.Errorf("fromContext() = %v, want the started span", got)
+	}
+	type otherKey struct{}
+	ctx = context.WithValue(ctx, otherKey{}, 1)
+	if fromContext(ctx) != s.(*span) || ctx.Value(otherKey{}) != 1 {
+		t.Error("values of derived contexts lost")
+	}
+}
+
+func TestAddLink(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{MaxLinksPerSpan: 2})
+
+	span := startSpan()
+	for i := uint64(1); i <= 3; i++ {
+		apitrace.AddLink(span, apitrace.Link{
+			SpanContext: core.SpanContext{TraceID: core.TraceID{Low: i}, SpanID: i},
+			Attributes:  []core.KeyValue{key.New("i").Uint64(i)},
+		})
+	}
+	// Invalid span contexts are ignored.
+	apitrace.AddLink(span, apitrace.Link{})
+	got, err := endSpan(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Links) != 2 || got.Links[0].SpanID != 2 || got.Links[1].SpanID != 3 {
+		t.Errorf("got links %v, want the links to spans 2 and 3", got.Links)
+	}
+	if got.DroppedLinkCount != 1 {
+		t.Errorf("DroppedLinkCount = %d, want 1", got.DroppedLinkCount)
+	}
+}
+
+func TestFollowsFromLink(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: AlwaysSample()})
+
+	cause := remoteSpanContext()
+	_, s := apitrace.GlobalTracer().Start(context.Background(), "span", apitrace.FollowsFrom(cause))
+	got, err := endSpan(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SpanContext.TraceID == cause.TraceID || got.ParentSpanID != 0 {
+		t.Errorf("got span %v with parent %x, want a new trace", got.SpanContext, got.ParentSpanID)
+	}
+	if len(got.Links) != 1 || got.Links[0].SpanContext != cause || got.Links[0].Relationship() != apitrace.RelationshipFollowsFrom {
+		t.Errorf("got links %v, want a follows_from link to the cause", got.Links)
+	}
+}
+
+func TestAddLinkNotRecording(t *testing.T) {
+	prev := config.Load().(*Config)
+	defer config.Store(prev)
+	ApplyConfig(Config{DefaultSampler: NeverSample()})
+
+	_, s := (&tracer{}).Start(context.Background(), "unsampled")
+	apitrace.AddLink(s, apitrace.Link{SpanContext: remoteSpanContext()})
+	if s.(*span).links != nil {
+		t.Error("links recorded by a span that is not recording")
+	}
+	s.Finish()
+}
@@ -16,15 +16,37 @@ package trace
 
 import (
 	"context"
+	"sync/atomic"
 
 	"go.opentelemetry.io/api/core"
+	"go.opentelemetry.io/api/tag"
 	apitrace "go.opentelemetry.io/api/trace"
+	"go.opentelemetry.io/sdk/resource"
 )
 
 type tracer struct {
 	name      string
 	component string
 	resources []core.KeyValue
+
+	// library is the instrumentation library the tracer was requested
+	// for from a Provider.
+	library InstrumentationLibrary
+
+	// merged caches the resources merged with Config.Resource.
+	merged atomic.Value // mergedResources
+
+	// attributeNamespace prefixes the keys of span attributes, except
+	// for the keys starting with one of namespaceExempt.
+	attributeNamespace string
+	namespaceExempt    []string
+
+	// eventRates are the event sampling rates of the Provider of the
+	// tracer, by message.
+	eventRates map[string]float64
+
+	// idGenerator overrides Config.IDGenerator when it is set.
+	idGenerator IDGenerator
 }
 
 var _ apitrace.Tracer = &tracer{}
@@ -35,8 +57,8 @@ func (tr *tracer) Start(ctx context.Context, name string, o ...apitrace.SpanOpti
 	var remoteParent bool
 
 	//TODO [rghetia] : Add new option for parent. If parent is configured then use that parent.
-	for _, op := range o {
-		op(&opts)
+	if len(o) > 0 {
+		opts = spanOptions(o)
 	}
 
 	// TODO: [rghetia] ChildOfRelationship is used to indicate that the parent is remote
@@ -50,27 +72,63 @@ func (tr *tracer) Start(ctx context.Context, name string, o ...apitrace.SpanOpti
 		opts.Reference.RelationshipType == apitrace.ChildOfRelationship {
 		parent = opts.Reference.SpanContext
 		remoteParent = true
-	} else {
+	}
+	var localParent *span
+	if !remoteParent {
 		if p := fromContext(ctx); p != nil {
 			p.addChild()
 			parent = p.spanContext
+			localParent = p
 		}
 	}
 
-	span := startSpanInternal(name, parent, remoteParent, opts)
+	span := startSpanInternal(tr, name, parent, remoteParent, opts)
+	if span.IsRecordingEvents() && localParent.IsRecordingEvents() {
+		if atomic.LoadInt32(&childSpanDurations) != 0 {
+			span.parent = localParent
+		}
+		span.verbose = localParent.verbose
+	}
 	span.tracer = tr
+	span.attributeNamespace = tr.attributeNamespace
+	span.namespaceExempt = tr.namespaceExempt
+	span.eventRates = tr.eventRates
+	// Attributes given with WithAttributes are set on the span, like
+	// the ones set with SetAttributes after the span is started.
+	if len(opts.Attributes) > 0 {
+		span.SetAttributes(opts.Attributes...)
+	}
+	// A FollowsFrom reference does not make the span a child of the
+	// referenced one; it is kept as a link.
+	if opts.Reference.RelationshipType == apitrace.FollowsFromRelationship {
+		span.AddLink(apitrace.NewLink(opts.Reference.SpanContext, apitrace.RelationshipFollowsFrom))
+	}
+	checkContext(ctx, span, name, parent == core.EmptySpanContext())
+	trackLiveSpan(span)
+	span.onStart()
+	span.startHeartbeat()
 
 	ctx, end := startExecutionTracerTask(ctx, name)
 	span.executionTracerTaskEnd = end
 	return newContext(ctx, span), span
 }
 
+// spanOptions applies o. It is kept apart from Start, since the options
+// escape to the heap when given their address.
+func spanOptions(o []apitrace.SpanOption) apitrace.SpanOptions {
+	var opts apitrace.SpanOptions
+	for _, op := range o {
+		op(&opts)
+	}
+	return opts
+}
+
 func (tr *tracer) WithSpan(ctx context.Context, name string, body func(ctx context.Context) error) error {
 	ctx, span := tr.Start(ctx, name)
 	defer span.Finish()
 
 	if err := body(ctx); err != nil {
-		// TODO: set event with boolean attribute for error.
+		apitrace.RecordError(ctx, span, err)
 		return err
 	}
 	return nil
@@ -84,9 +142,32 @@ func (tr *tracer) WithService(name string) apitrace.Tracer {
 // WithResources does nothing and returns noop implementation of apitrace.Tracer.
 func (tr *tracer) WithResources(res ...core.KeyValue) apitrace.Tracer {
 	tr.resources = res
+	tr.merged.Store(mergedResources{})
 	return tr
 }
 
+type mergedResources struct {
+	base  *resource.Resource
+	attrs []core.KeyValue
+}
+
+// resourceAttributes returns the resources of tr merged with base, which
+// is usually Config.Resource. The merge is cached until base changes.
+func (tr *tracer) resourceAttributes(base *resource.Resource) []core.KeyValue {
+	if tr == nil {
+		return base.Attributes()
+	}
+	if base == nil {
+		return tr.resources
+	}
+	if m, ok := tr.merged.Load().(mergedResources); ok && m.base == base {
+		return m.attrs
+	}
+	attrs := resource.Merge(resource.New(tr.resources...), base).Attributes()
+	tr.merged.Store(mergedResources{base: base, attrs: attrs})
+	return attrs
+}
+
 // WithComponent does nothing and returns noop implementation of apitrace.Tracer.
 func (tr *tracer) WithComponent(component string) apitrace.Tracer {
 	tr.component = component
@@ -94,5 +175,5 @@ func (tr *tracer) WithComponent(component string) apitrace.Tracer {
 }
 
 func (tr *tracer) Inject(ctx context.Context, span apitrace.Span, injector apitrace.Injector) {
-	injector.Inject(span.SpanContext(), nil)
+	injector.Inject(span.SpanContext(), tag.FromContext(ctx))
 }